@@ -0,0 +1,45 @@
+// Package mailer sends the emails of the services : through SMTP in
+// production, to a file or to the logs in development
+package mailer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kit/kit/log"
+)
+
+// Message is an email, in plain text
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender sends the emails
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+var RecipientErr = errors.New("The email has no recipient")
+
+// Config picks the sender : SMTP when SMTPAddr is set, else the file sink
+// when File is set, else the logs
+type Config struct {
+	SMTPAddr     string
+	SMTPUser     string
+	SMTPPassword string
+	From         string
+	File         string
+}
+
+// New returns the sender picked by c
+func New(c Config, logger log.Logger) Sender {
+	switch {
+	case len(c.SMTPAddr) > 0:
+		return SMTPSender{Addr: c.SMTPAddr, User: c.SMTPUser, Password: c.SMTPPassword, From: c.From}
+	case len(c.File) > 0:
+		return NewFileSender(c.File)
+	}
+	return LogSender{Logger: log.With(logger, "sender", "log")}
+}
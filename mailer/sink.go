@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+// FileSender appends the emails to a file instead of sending them, to read
+// them when developing
+type FileSender struct {
+	Path string
+	mtx  *sync.Mutex
+}
+
+func NewFileSender(path string) FileSender {
+	return FileSender{Path: path, mtx: &sync.Mutex{}}
+}
+
+func (s FileSender) Send(_ context.Context, m Message) error {
+	if len(m.To) == 0 {
+		return RecipientErr
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n\n",
+		time.Now().Format(time.RFC1123Z), m.To, m.Subject, m.Body)
+	return err
+}
+
+// LogSender logs the emails instead of sending them
+type LogSender struct {
+	Logger log.Logger
+}
+
+func (s LogSender) Send(_ context.Context, m Message) error {
+	if len(m.To) == 0 {
+		return RecipientErr
+	}
+	return s.Logger.Log("mail", m.Subject, "to", m.To, "body", m.Body)
+}
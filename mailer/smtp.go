@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends the emails through an SMTP relay, authenticated when User
+// is set
+type SMTPSender struct {
+	Addr     string
+	User     string
+	Password string
+	From     string
+}
+
+func (s SMTPSender) Send(_ context.Context, m Message) error {
+	if len(m.To) == 0 {
+		return RecipientErr
+	}
+
+	// From may carry a display name, the envelope only takes the address
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if len(s.User) > 0 {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.User, s.Password, host)
+	}
+	return smtp.SendMail(s.Addr, auth, from.Address, []string{m.To}, s.format(m))
+}
+
+// format writes m as an RFC 5322 message
+func (s SMTPSender) format(m Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", headerValue(s.From))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerValue(m.To))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", headerValue(m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(strings.Replace(m.Body, "\n", "\r\n", -1))
+	return buf.Bytes()
+}
+
+// headerValue keeps a value from ending its header line
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
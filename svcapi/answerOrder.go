@@ -66,7 +66,7 @@ func AnswerOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.AnswerOrderEndpoint,
 		DecodeHTTPAnswerOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerOrder", logger), jwt.HTTPToContext()))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerOrder", logger), jwt.HTTPToContext(), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -86,6 +86,10 @@ func (mw serviceLoggingMiddleware) AnswerOrder(ctx context.Context, orderID int6
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) AnswerOrder(ctx context.Context, orderID int64, userID int64, answer bool) (svcdb.Order,error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return svcdb.Order{}, err
+	}
 	return mw.next.AnswerOrder(ctx, orderID, userID, answer)
 }
 
@@ -97,7 +101,7 @@ func (mw serviceInstrumentingMiddleware) AnswerOrder(ctx context.Context, orderI
 
 /*************** Main ***************/
 /* Main */
-func BuildAnswerOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+func BuildAnswerOrderEndpoint(svc IService, db svcdb.IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
 	var csEndpoint endpoint.Endpoint
 	{
 		csDuration := duration.With("method", "AnswerOrder")
@@ -106,6 +110,7 @@ func BuildAnswerOrderEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = AnswerOrderEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "AnswerOrder")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointIdempotencyMiddleware(db, "svcapi.AnswerOrder")(csEndpoint)
 		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
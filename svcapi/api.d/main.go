@@ -25,6 +25,8 @@ import (
 	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/prometheus"
 
+	"mailer"
+	"svcdb"
 	csvcdb "svcdb/client"
 	csvcevent "svcevent/client"
 	csvcpayment "svcpayment/client"
@@ -41,6 +43,12 @@ func main() {
 		zipkinKafkaAddr = flag.String("zipkin.kafka.addr", "", "Enable Zipkin tracing via a Kafka server host:port")
 		appdashAddr     = flag.String("appdash.addr", "", "Enable Appdash tracing via an Appdash server host:port")
 		lightstepToken  = flag.String("lightstep.token", "", "Enable LightStep tracing via a LightStep access token")
+		smtpAddr        = flag.String("mail.smtp.addr", "", "Send the emails via a SMTP server host:port")
+		smtpUser        = flag.String("mail.smtp.user", "", "User authenticating to the SMTP server")
+		smtpPassword    = flag.String("mail.smtp.password", "", "Password authenticating to the SMTP server")
+		mailFrom        = flag.String("mail.from", "NightLine <no-reply@nightline.fr>", "Sender of the emails")
+		mailFile        = flag.String("mail.file", "", "Without SMTP server, append the emails to this file instead of logging them")
+		appURL          = flag.String("app.url", "http://localhost:8080", "Base URL of the links mailed to the users")
 	)
 	flag.Parse()
 
@@ -71,7 +79,7 @@ func main() {
 	defer logger.Log("msg", "[SVCAPI END]")
 
 	/* Authentication */
-	// svcapi.InitKey()
+	svcapi.InitKey()
 
 	/* Metrics */
 	var createSoiree_all metrics.Counter
@@ -156,9 +164,11 @@ func main() {
 	}
 
 	/* Business domain */
+	var db svcdb.IService
 	var service svcapi.IService
 	{
-		svcdb, err := csvcdb.New("localhost:8044", tracer, logger)
+		var err error
+		db, err = csvcdb.New("localhost:8044", tracer, logger)
 		if err != nil {
 			logger.Log("err", err)
 			os.Exit(1)
@@ -176,9 +186,19 @@ func main() {
 			os.Exit(1)
 		}
 
-		service = svcapi.NewService(svcdb, svcevent, svcpayment)
+		svcapi.InitRevocationList(db)
+
+		mail := mailer.New(mailer.Config{
+			SMTPAddr:     *smtpAddr,
+			SMTPUser:     *smtpUser,
+			SMTPPassword: *smtpPassword,
+			From:         *mailFrom,
+			File:         *mailFile,
+		}, logger)
+
+		service = svcapi.NewService(db, svcevent, svcpayment, mail, *appURL)
 		service = svcapi.ServiceLoggingMiddleware(logger)(service)
-		service = svcapi.ServiceAuthenticationMiddleware()(service)
+		service = svcapi.ServiceAuthenticationMiddleware(db)(service)
 		service = svcapi.ServiceInstrumentingMiddleware(
 			createSoiree_all,
 		)(service)
@@ -188,6 +208,13 @@ func main() {
 	// Authentication
 	loginEndpoint := svcapi.BuildLoginEndpoint(service, logger, tracer, duration)
 	registerEndpoint := svcapi.BuildRegisterEndpoint(service, logger, tracer, duration)
+	refreshTokenEndpoint := svcapi.BuildRefreshTokenEndpoint(service, logger, tracer, duration)
+	logoutEndpoint := svcapi.BuildLogoutEndpoint(service, logger, tracer, duration)
+	logoutAllEndpoint := svcapi.BuildLogoutAllEndpoint(service, logger, tracer, duration)
+	requestPasswordResetEndpoint := svcapi.BuildRequestPasswordResetEndpoint(service, logger, tracer, duration)
+	resetPasswordEndpoint := svcapi.BuildResetPasswordEndpoint(service, logger, tracer, duration)
+	verifyEmailEndpoint := svcapi.BuildVerifyEmailEndpoint(service, logger, tracer, duration)
+	sendVerificationEndpoint := svcapi.BuildSendVerificationEndpoint(service, logger, tracer, duration)
 
 	// Users
 	searchUsersEndpoint := svcapi.BuildSearchUsersEndpoint(service, logger, tracer, duration)
@@ -203,6 +230,7 @@ func main() {
 
 	// Establishment
 	searchEstablishmentsEndpoint := svcapi.BuildSearchEstablishmentsEndpoint(service, logger, tracer, duration)
+	searchNearbyEndpoint := svcapi.BuildSearchNearbyEndpoint(service, logger, tracer, duration)
 	getAllEstablishmentsEndpoint := svcapi.BuildGetAllEstablishmentsEndpoint(service, logger, tracer, duration)
 	getEstablishmentTypesEndpoint := svcapi.BuildGetEstablishmentTypesEndpoint(service, logger, tracer, duration)
 	getEstablishmentEndpoint := svcapi.BuildGetEstablishmentEndpoint(service, logger, tracer, duration)
@@ -238,15 +266,24 @@ func main() {
 
 	/* Order */
 	getOrderEndpoint := svcapi.BuildGetOrderEndpoint(service, logger, tracer, duration)
-	createOrderEndpoint := svcapi.BuildCreateOrderEndpoint(service, logger, tracer, duration)
-	answerOrderEndpoint := svcapi.BuildAnswerOrderEndpoint(service, logger, tracer, duration)
+	createOrderEndpoint := svcapi.BuildCreateOrderEndpoint(service, db, logger, tracer, duration)
+	answerOrderEndpoint := svcapi.BuildAnswerOrderEndpoint(service, db, logger, tracer, duration)
+	cancelOrderEndpoint := svcapi.BuildCancelOrderEndpoint(service, logger, tracer, duration)
 	searchOrdersEndpoint := svcapi.BuildSearchOrdersEndpoint(service, logger, tracer, duration)
 
 	endpoints := svcapi.Endpoints{
 
 		// Authentication
-		LoginEndpoint:    loginEndpoint,
-		RegisterEndpoint: registerEndpoint,
+		LoginEndpoint:        loginEndpoint,
+		RegisterEndpoint:     registerEndpoint,
+		RefreshTokenEndpoint: refreshTokenEndpoint,
+		LogoutEndpoint:       logoutEndpoint,
+		LogoutAllEndpoint:    logoutAllEndpoint,
+
+		RequestPasswordResetEndpoint: requestPasswordResetEndpoint,
+		ResetPasswordEndpoint:        resetPasswordEndpoint,
+		VerifyEmailEndpoint:          verifyEmailEndpoint,
+		SendVerificationEndpoint:     sendVerificationEndpoint,
 
 		// Users
 		SearchUsersEndpoint:        searchUsersEndpoint,
@@ -262,6 +299,7 @@ func main() {
 
 		// Establishment
 		SearchEstablishmentsEndpoint:         searchEstablishmentsEndpoint,
+		SearchNearbyEndpoint:                 searchNearbyEndpoint,
 		GetAllEstablishmentsEndpoint:         getAllEstablishmentsEndpoint,
 		GetEstablishmentEndpoint:             getEstablishmentEndpoint,
 		GetMenuFromEstablishmentEndpoint:     getMenuFromEstablishmentEndpoint,
@@ -299,6 +337,7 @@ func main() {
 		GetOrderEndpoint: getOrderEndpoint,
 		CreateOrderEndpoint: createOrderEndpoint,
 		AnswerOrderEndpoint: answerOrderEndpoint,
+		CancelOrderEndpoint: cancelOrderEndpoint,
 		SearchOrdersEndpoint: searchOrdersEndpoint,
 	}
 
@@ -1,8 +1,17 @@
 package svcapi
 
 import (
+	"context"
+	"crypto/rsa"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"strconv"
+	"time"
+
+	stdjwt "github.com/dgrijalva/jwt-go"
+
+	"svcdb"
 )
 
 const (
@@ -10,32 +19,337 @@ const (
 	publicKeyPath  = "./key/app.pub"
 )
 
-var verifKey, signKey []byte
+// Roles carried by the tokens
+const (
+	RoleUser = "user"
+)
+
+// Lifetimes of the access tokens and of the refresh tokens of a session
+const (
+	tokenDuration   = time.Minute * 15
+	refreshDuration = time.Hour * 24 * 30
+)
+
+// Lifetimes of the tokens mailed to reset a password and to verify an email
+const (
+	resetDuration  = time.Hour
+	verifyDuration = time.Hour * 24
+)
+
+var verifKey *rsa.PublicKey
+var signKey *rsa.PrivateKey
+
+// revocations holds the revocation list checked by EndpointAuthenticationMiddleware
+var revocations svcdb.IService
 
 // InitKey Initialize the key used in authentication thanks to a rsa key saved
 func InitKey() {
-	var err error
-
-	signKey, err = ioutil.ReadFile(privateKeyPath)
+	signBytes, err := ioutil.ReadFile(privateKeyPath)
 	if err != nil {
 		log.Fatal("Error reading private key at : " + privateKeyPath)
 	}
+	signKey, err = stdjwt.ParseRSAPrivateKeyFromPEM(signBytes)
+	if err != nil {
+		log.Fatal("Error parsing private key : " + err.Error())
+	}
 
-	verifKey, err = ioutil.ReadFile(publicKeyPath)
+	verifBytes, err := ioutil.ReadFile(publicKeyPath)
 	if err != nil {
 		log.Fatal("Error reading public key : " + publicKeyPath)
 	}
+	verifKey, err = stdjwt.ParseRSAPublicKeyFromPEM(verifBytes)
+	if err != nil {
+		log.Fatal("Error parsing public key : " + err.Error())
+	}
+}
+
+// InitRevocationList sets where the revoked tokens are looked up
+func InitRevocationList(db svcdb.IService) {
+	revocations = db
+}
+
+/* Tokens */
+// Claims of the tokens signed by svcapi, the subject is the ID of the user
+type Claims struct {
+	Role string `json:"role"`
+	stdjwt.StandardClaims
+}
+
+// Identity is the authenticated caller, injected in the context of the
+// requests by EndpointAuthenticationMiddleware
+type Identity struct {
+	UserID    int64
+	Role      string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity authenticated for the request
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// NewToken signs a token of role for userID, valid for tokenDuration
+func NewToken(userID int64, role string) (string, time.Time, error) {
+	now := time.Now().UTC()
+	expires := now.Add(tokenDuration)
+
+	tokenID, err := svcdb.RandomToken()
+	if err != nil {
+		return "", expires, err
+	}
+	claims := Claims{
+		Role: role,
+		StandardClaims: stdjwt.StandardClaims{
+			Id:        tokenID,
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expires.Unix(),
+			Issuer:    "NightLine",
+		},
+	}
+	token, err := stdjwt.NewWithClaims(stdjwt.SigningMethodRS256, claims).SignedString(signKey)
+	return token, expires, err
+}
+
+// ParseToken verifies the signature and the expiry of token
+func ParseToken(token string) (Identity, error) {
+	var claims Claims
+
+	parsed, err := stdjwt.ParseWithClaims(token, &claims, func(t *stdjwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*stdjwt.SigningMethodRSA); !ok {
+			return nil, TokenError
+		}
+		return verifKey, nil
+	})
+	if err != nil || !parsed.Valid {
+		return Identity{}, TokenError
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || len(claims.Role) == 0 || len(claims.Id) == 0 {
+		return Identity{}, TokenError
+	}
+	return Identity{
+		UserID:    userID,
+		Role:      claims.Role,
+		TokenID:   claims.Id,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// checkRevoked fails with TokenError when the token of identity was revoked,
+// by a logout or a logout of all the devices of the user
+func checkRevoked(ctx context.Context, identity Identity) error {
+	if revocations == nil {
+		return nil
+	}
+	subject := svcdb.TokenSubject(svcdb.SubjectUser, identity.UserID)
+	revoked, err := revocations.IsTokenRevoked(ctx, subject, identity.TokenID, identity.IssuedAt)
+	if err != nil {
+		fmt.Println("checkRevoked (IsTokenRevoked) : " + err.Error())
+		return ConnError
+	} else if revoked {
+		return TokenError
+	}
+	return nil
+}
+
+/* Account tokens */
+// newAccountToken signs the token mailed to email for purpose, single use : it
+// is stored by svcdb until used or expired. The subject of the token is the
+// one of svcdb, the audience its purpose.
+func (s Service) newAccountToken(ctx context.Context, userID int64, email string, purpose string, duration time.Duration) (string, error) {
+	now := time.Now().UTC()
+	expires := now.Add(duration)
+	subject := svcdb.TokenSubject(svcdb.SubjectUser, userID)
+
+	tokenID, err := svcdb.RandomToken()
+	if err != nil {
+		return "", err
+	}
+	claims := stdjwt.StandardClaims{
+		Id:        tokenID,
+		Subject:   subject,
+		Audience:  purpose,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+		Issuer:    "NightLine",
+	}
+	token, err := stdjwt.NewWithClaims(stdjwt.SigningMethodRS256, claims).SignedString(signKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.svcdb.CreateAccountToken(ctx, svcdb.AccountToken{
+		Hash:    svcdb.HashToken(tokenID),
+		Subject: subject,
+		Purpose: purpose,
+		Email:   email,
+		Expires: expires,
+	})
+	if err != nil {
+		fmt.Println("newAccountToken (CreateAccountToken) : " + err.Error())
+		return "", dbToHTTPErr(err)
+	}
+	return token, nil
+}
+
+// parseAccountToken verifies token was signed by newAccountToken for purpose
+// and a user, and returns the hash under which svcdb stores it
+func parseAccountToken(token string, purpose string) (string, error) {
+	var claims stdjwt.StandardClaims
+
+	parsed, err := stdjwt.ParseWithClaims(token, &claims, func(t *stdjwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*stdjwt.SigningMethodRSA); !ok {
+			return nil, svcdb.AccountTokenErr
+		}
+		return verifKey, nil
+	})
+	if err != nil || !parsed.Valid || claims.Audience != purpose || len(claims.Id) == 0 {
+		return "", svcdb.AccountTokenErr
+	}
+	if kind, _, err := svcdb.ParseTokenSubject(claims.Subject); err != nil || kind != svcdb.SubjectUser {
+		return "", svcdb.AccountTokenErr
+	}
+	return svcdb.HashToken(claims.Id), nil
+}
+
+/* Sessions */
+// newSession opens a session of the user : an access token and the refresh
+// token stored to renew it
+func (s Service) newSession(ctx context.Context, userID int64) (svcdb.Session, error) {
+	var session svcdb.Session
+
+	refresh, stored, err := svcdb.NewRefreshToken(svcdb.TokenSubject(svcdb.SubjectUser, userID), refreshDuration)
+	if err != nil {
+		return session, err
+	}
+	if err = s.svcdb.CreateRefreshToken(ctx, stored); err != nil {
+		fmt.Println("newSession (CreateRefreshToken) : " + err.Error())
+		return session, dbToHTTPErr(err)
+	}
+	return signSession(userID, refresh)
+}
+
+// signSession signs the access token going with the refresh token
+func signSession(userID int64, refresh string) (svcdb.Session, error) {
+	token, expires, err := NewToken(userID, RoleUser)
+	if err != nil {
+		return svcdb.Session{}, err
+	}
+	return svcdb.Session{Token: token, RefreshToken: refresh, ExpiresAt: expires.Unix()}, nil
+}
+
+// authenticatedUser returns the user the request acts for : the authenticated
+// one. userID comes from the request, 0 when it doesn't name one, any other
+// user is forbidden.
+func authenticatedUser(ctx context.Context, userID int64) (int64, error) {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok || identity.Role != RoleUser {
+		return 0, TokenError
+	}
+	if userID != 0 && userID != identity.UserID {
+		return 0, AuthError
+	}
+	return identity.UserID, nil
+}
+
+// orderParticipant checks the authenticated user is one of the users of order
+func orderParticipant(ctx context.Context, order svcdb.Order) error {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return err
+	}
+	for _, user := range order.Users {
+		if user.User.ID == userID {
+			return nil
+		}
+	}
+	return AuthError
+}
+
+// verifiedUser checks the email of the authenticated user is verified
+func (s Service) verifiedUser(ctx context.Context) error {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return err
+	}
+	user, err := s.svcdb.GetUserByID(ctx, userID)
+	if err != nil {
+		fmt.Println("verifiedUser (GetUserByID) : " + err.Error())
+		return dbToHTTPErr(err)
+	} else if !user.Verified {
+		return UnverifiedError
+	}
+	return nil
+}
+
+// invitedUser checks the authenticated user is the one invited by invitationID
+func (mw serviceAuthenticationMiddleware) invitedUser(ctx context.Context, invitationID int64) error {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return err
+	}
+	invitation, err := mw.db.GetInvitation(ctx, invitationID)
+	if err != nil {
+		if dbToHTTPErr(err) == NotFoundError {
+			return AuthError
+		}
+		fmt.Println("invitedUser (GetInvitation) : " + err.Error())
+		return dbToHTTPErr(err)
+	} else if invitation.To.ID != userID {
+		return AuthError
+	}
+	return nil
+}
+
+// groupUser checks the authenticated user created groupID or, unless ownerOnly,
+// is one of its members
+func (mw serviceAuthenticationMiddleware) groupUser(ctx context.Context, groupID int64, ownerOnly bool) error {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return err
+	}
+	group, err := mw.db.GetGroup(ctx, groupID)
+	if err != nil {
+		if dbToHTTPErr(err) == NotFoundError {
+			return AuthError
+		}
+		fmt.Println("groupUser (GetGroup) : " + err.Error())
+		return dbToHTTPErr(err)
+	}
+	if group.Owner.ID == userID {
+		return nil
+	}
+	if !ownerOnly {
+		for _, user := range group.Users {
+			if user.ID == userID {
+				return nil
+			}
+		}
+	}
+	return AuthError
 }
 
 /* MW Authentication interface */
-func ServiceAuthenticationMiddleware() Middleware {
+// ServiceAuthenticationMiddleware checks the authenticated user may act on the
+// resources of the request, db being where they are looked up
+func ServiceAuthenticationMiddleware(db svcdb.IService) Middleware {
 	return func(next IService) IService {
 		return serviceAuthenticationMiddleware{
 			next: next,
+			db:   db,
 		}
 	}
 }
 
 type serviceAuthenticationMiddleware struct {
 	next IService
+	db   svcdb.IService
 }
@@ -0,0 +1,156 @@
+package svcapi
+
+import (
+	"context"
+	"testing"
+
+	"svcdb"
+)
+
+// searchService records the filter SearchOrders is called with
+type searchService struct {
+	Service
+	filter *svcdb.Order
+}
+
+func (s searchService) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	*s.filter = order
+	return nil, "", nil
+}
+
+// membership is a group of its owner and a member, a stranger out of it who
+// invited the owner to be friends
+type membership struct {
+	db         svcdb.MemoryService
+	owner      int64
+	member     int64
+	stranger   int64
+	group      int64
+	invitation int64
+}
+
+func newMembership(t *testing.T) membership {
+	var m membership
+	ctx := context.Background()
+
+	m.db = svcdb.NewMemoryService()
+	m.owner = m.db.SeedUser("owner@nightline.fr").ID
+	m.member = m.db.SeedUser("member@nightline.fr").ID
+	m.stranger = m.db.SeedUser("stranger@nightline.fr").ID
+
+	group, err := m.db.CreateGroup(ctx, svcdb.Group{Name: "Group"}, m.owner)
+	if err != nil {
+		t.Fatal("CreateGroup : " + err.Error())
+	}
+	_, invitationID, err := m.db.GroupInvite(ctx, group.ID, m.member)
+	if err != nil {
+		t.Fatal("GroupInvite : " + err.Error())
+	}
+	if err = m.db.GroupInvitationAccept(ctx, m.member, invitationID); err != nil {
+		t.Fatal("GroupInvitationAccept : " + err.Error())
+	}
+	_, invitationID, _, err = m.db.InviteFriend(ctx, m.stranger, m.owner)
+	if err != nil {
+		t.Fatal("InviteFriend : " + err.Error())
+	}
+
+	m.group, m.invitation = group.ID, invitationID
+	return m
+}
+
+func asUser(userID int64) context.Context {
+	return context.WithValue(context.Background(), identityKey{}, Identity{UserID: userID, Role: RoleUser})
+}
+
+func TestServiceAuthenticationGroupsAndInvitations(t *testing.T) {
+	cases := []struct {
+		name string
+		as   func(membership) int64
+		call func(context.Context, IService, membership) error
+		want error
+	}{
+		{"DeleteGroup by a member", func(m membership) int64 { return m.member }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroup(ctx, m.group)
+		}, AuthError},
+		{"DeleteGroup by the owner", func(m membership) int64 { return m.owner }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroup(ctx, m.group)
+		}, nil},
+		{"UpdateGroup by a stranger", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			_, err := svc.UpdateGroup(ctx, svcdb.Group{ID: m.group, Name: "Renamed"})
+			return err
+		}, AuthError},
+		{"UpdateGroup by the owner", func(m membership) int64 { return m.owner }, func(ctx context.Context, svc IService, m membership) error {
+			_, err := svc.UpdateGroup(ctx, svcdb.Group{ID: m.group, Name: "Renamed"})
+			return err
+		}, nil},
+		{"DeleteGroupMember of the owner by a member", func(m membership) int64 { return m.member }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroupMember(ctx, m.group, m.owner)
+		}, AuthError},
+		{"DeleteGroupMember of a member by a stranger", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroupMember(ctx, m.group, m.member)
+		}, AuthError},
+		{"DeleteGroupMember of a member by itself", func(m membership) int64 { return m.member }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroupMember(ctx, m.group, m.member)
+		}, nil},
+		{"DeleteGroupMember of a member by the owner", func(m membership) int64 { return m.owner }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.DeleteGroupMember(ctx, m.group, m.member)
+		}, nil},
+		{"GroupInvite by a stranger", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.GroupInvite(ctx, m.group, m.stranger)
+		}, AuthError},
+		{"InvitationAccept by a member", func(m membership) int64 { return m.member }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.InvitationAccept(ctx, m.invitation)
+		}, AuthError},
+		{"InvitationDecline by the sender", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.InvitationDecline(ctx, m.invitation)
+		}, AuthError},
+		{"InvitationDecline by the invited user", func(m membership) int64 { return m.owner }, func(ctx context.Context, svc IService, m membership) error {
+			return svc.InvitationDecline(ctx, m.invitation)
+		}, nil},
+		{"GetGroup by a stranger", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			_, err := svc.GetGroup(ctx, m.group)
+			return err
+		}, AuthError},
+		{"GetGroup by a member", func(m membership) int64 { return m.member }, func(ctx context.Context, svc IService, m membership) error {
+			_, err := svc.GetGroup(ctx, m.group)
+			return err
+		}, nil},
+		{"CreateNotification to another user", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			_, _, err := svc.CreateNotification(ctx, "order_progress", "Order cancelled", m.owner)
+			return err
+		}, AuthError},
+		{"GetUserFriends of another user", func(m membership) int64 { return m.stranger }, func(ctx context.Context, svc IService, m membership) error {
+			_, _, err := svc.GetUserFriends(ctx, m.owner, svcdb.Page{})
+			return err
+		}, AuthError},
+		{"GetUserFriends of the user", func(m membership) int64 { return m.owner }, func(ctx context.Context, svc IService, m membership) error {
+			_, _, err := svc.GetUserFriends(ctx, m.owner, svcdb.Page{})
+			return err
+		}, nil},
+	}
+
+	for _, c := range cases {
+		m := newMembership(t)
+		svc := ServiceAuthenticationMiddleware(m.db)(Service{svcdb: m.db})
+
+		if err := c.call(asUser(c.as(m)), svc, m); err != c.want {
+			t.Errorf("%s : got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestSearchOrdersOfTheUser(t *testing.T) {
+	var filter svcdb.Order
+	m := newMembership(t)
+	svc := ServiceAuthenticationMiddleware(m.db)(searchService{Service{svcdb: m.db}, &filter})
+
+	if _, _, err := svc.SearchOrders(asUser(m.member), svcdb.Order{}, svcdb.Page{}); err != nil {
+		t.Fatal("SearchOrders : " + err.Error())
+	}
+	if len(filter.Users) != 1 || filter.Users[0].User.ID != m.member {
+		t.Errorf("SearchOrders : got users %v, want only %d", filter.Users, m.member)
+	}
+	if _, _, err := svc.SearchOrders(context.Background(), svcdb.Order{}, svcdb.Page{}); err != TokenError {
+		t.Errorf("SearchOrders without token : got %v, want %v", err, TokenError)
+	}
+}
@@ -0,0 +1,145 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+	"svcpayment"
+)
+
+var OrderNotCancellableErr = errors.New("The order is over, it can't be cancelled anymore")
+
+/*************** Service ***************/
+// CancelOrder cancels the order of the user. Once it is being prepared the
+// establishment is asked to, the order is returned with its cancel_request set.
+func (s Service) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	order, err := s.svcpayment.CancelOrder(ctx, orderID, userID)
+	if err == nil {
+		return order, nil
+	}
+	fmt.Println("CancelOrder (CancelOrder) : " + err.Error())
+	switch err.Error() {
+	case svcpayment.NotParticipantErr.Error():
+		return order, AuthError
+	case svcpayment.OrderNotCancellableErr.Error():
+		return order, OrderNotCancellableErr
+	case "Target order not found":
+		return order, NotFoundError
+	}
+	return order, dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type cancelOrderRequest struct {
+	OrderID int64 `json:"order"`
+	UserID  int64 `json:"user"`
+}
+
+type cancelOrderResponse struct {
+	Order svcdb.Order `json:"order"`
+}
+
+func CancelOrderEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(cancelOrderRequest)
+		order, err := svc.CancelOrder(ctx, req.OrderID, req.UserID)
+		return cancelOrderResponse{Order: order}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPCancelOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request cancelOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, RequestError
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["OrderID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).OrderID = orderID
+
+	return request, nil
+}
+
+func DecodeHTTPCancelOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response cancelOrderResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func CancelOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/orders/{OrderID:[0-9]+}/cancel").Handler(httptransport.NewServer(
+		endpoints.CancelOrderEndpoint,
+		DecodeHTTPCancelOrderRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CancelOrder", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "cancelOrder",
+			"orderID", orderID,
+			"userID", userID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.CancelOrder(ctx, orderID, userID)
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return svcdb.Order{}, err
+	}
+	return mw.next.CancelOrder(ctx, orderID, userID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	return mw.next.CancelOrder(ctx, orderID, userID)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildCancelOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "CancelOrder")
+		csLogger := log.With(logger, "method", "CancelOrder")
+
+		csEndpoint = CancelOrderEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "CancelOrder")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
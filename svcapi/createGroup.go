@@ -125,6 +125,10 @@ func (mw serviceLoggingMiddleware) CreateGroup(ctx context.Context, group svcdb.
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateGroup(ctx context.Context, group svcdb.Group, ownerID int64) (svcdb.Group, error) {
+	var err error
+	if ownerID, err = authenticatedUser(ctx, ownerID); err != nil {
+		return group, err
+	}
 	return mw.next.CreateGroup(ctx, group, ownerID)
 }
 
@@ -145,6 +149,7 @@ func BuildCreateGroupEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = CreateGroupEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "CreateGroup")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -92,6 +92,9 @@ func (mw serviceLoggingMiddleware) CreateNotification(ctx context.Context, name,
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateNotification(ctx context.Context, name, text string, userID int64) (int32, int64, error) {
+	if _, err := authenticatedUser(ctx, userID); err != nil {
+		return 0, 0, err
+	}
 	return mw.next.CreateNotification(ctx, name, text, userID)
 }
 
@@ -112,6 +115,7 @@ func BuildCreateNotificationEndpoint(svc IService, logger log.Logger, tracer std
 		csEndpoint = CreateNotificationEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "CreateNotification")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
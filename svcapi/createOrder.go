@@ -22,6 +22,10 @@ import (
  
 /*************** Service ***************/
 func (s Service) CreateOrder(ctx context.Context, o svcdb.Order) (svcdb.Order, error) {
+	if err := s.verifiedUser(ctx); err != nil {
+		return svcdb.Order{}, err
+	}
+
 	order, err := s.svcpayment.CreateOrder(ctx, o)
 	return order, dbToHTTPErr(err)
 }
@@ -65,7 +69,7 @@ func CreateOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.CreateOrderEndpoint,
 		DecodeHTTPCreateOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateOrder", logger), jwt.HTTPToContext()))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateOrder", logger), jwt.HTTPToContext(), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -85,6 +89,9 @@ func (mw serviceLoggingMiddleware) CreateOrder(ctx context.Context, o svcdb.Orde
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateOrder(ctx context.Context, o svcdb.Order) (svcdb.Order,error) {
+	if err := orderParticipant(ctx, o); err != nil {
+		return svcdb.Order{}, err
+	}
 	return mw.next.CreateOrder(ctx, o)
 }
 
@@ -96,7 +103,7 @@ func (mw serviceInstrumentingMiddleware) CreateOrder(ctx context.Context, o svcd
 
 /*************** Main ***************/
 /* Main */
-func BuildCreateOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+func BuildCreateOrderEndpoint(svc IService, db svcdb.IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
 	var csEndpoint endpoint.Endpoint
 	{
 		csDuration := duration.With("method", "CreateOrder")
@@ -105,6 +112,7 @@ func BuildCreateOrderEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = CreateOrderEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "CreateOrder")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointIdempotencyMiddleware(db, "svcapi.CreateOrder")(csEndpoint)
 		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
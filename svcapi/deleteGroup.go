@@ -98,6 +98,9 @@ func (mw serviceLoggingMiddleware) DeleteGroup(ctx context.Context, groupID int6
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) DeleteGroup(ctx context.Context, groupID int64) error {
+	if err := mw.groupUser(ctx, groupID, true); err != nil {
+		return err
+	}
 	return mw.next.DeleteGroup(ctx, groupID)
 }
 
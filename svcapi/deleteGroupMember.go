@@ -106,6 +106,14 @@ func (mw serviceLoggingMiddleware) DeleteGroupMember(ctx context.Context, groupI
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) DeleteGroupMember(ctx context.Context, groupID, userID int64) error {
+	self, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return err
+	}
+	// members leave the group themselves, only the owner removes the others
+	if err = mw.groupUser(ctx, groupID, userID != self); err != nil {
+		return err
+	}
 	return mw.next.DeleteGroupMember(ctx, groupID, userID)
 }
 
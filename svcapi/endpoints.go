@@ -3,26 +3,31 @@ package svcapi
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/go-kit/kit/metrics"
-)
 
-var claims = &stdjwt.StandardClaims{
-	ExpiresAt: time.Now().UTC().Add(time.Hour * 1).Unix(), // Add 1 Hour
-	Issuer:    "NightLine",
-}
+	"svcdb"
+)
 
 /* Endpoints definition */
 type Endpoints struct {
 	/* Authentication */
-	RegisterEndpoint endpoint.Endpoint
-	LoginEndpoint    endpoint.Endpoint
+	RegisterEndpoint     endpoint.Endpoint
+	LoginEndpoint        endpoint.Endpoint
+	RefreshTokenEndpoint endpoint.Endpoint
+	LogoutEndpoint       endpoint.Endpoint
+	LogoutAllEndpoint    endpoint.Endpoint
+
+	RequestPasswordResetEndpoint endpoint.Endpoint
+	ResetPasswordEndpoint        endpoint.Endpoint
+	VerifyEmailEndpoint          endpoint.Endpoint
+	SendVerificationEndpoint     endpoint.Endpoint
 
 	/* User */
 	SearchUsersEndpoint        endpoint.Endpoint
@@ -44,6 +49,7 @@ type Endpoints struct {
 
 	/* Establishment */
 	SearchEstablishmentsEndpoint         endpoint.Endpoint
+	SearchNearbyEndpoint                 endpoint.Endpoint
 	GetAllEstablishmentsEndpoint         endpoint.Endpoint
 	GetEstablishmentEndpoint             endpoint.Endpoint
 	GetMenuFromEstablishmentEndpoint     endpoint.Endpoint
@@ -78,6 +84,7 @@ type Endpoints struct {
 	GetOrderEndpoint               endpoint.Endpoint
 	CreateOrderEndpoint            endpoint.Endpoint
 	AnswerOrderEndpoint            endpoint.Endpoint
+	CancelOrderEndpoint            endpoint.Endpoint
 	SearchOrdersEndpoint           endpoint.Endpoint
 }
 
@@ -98,15 +105,41 @@ func EndpointLoggingMiddleware(logger log.Logger) endpoint.Middleware {
 }
 
 /* Authentication Middleware */
+// EndpointAuthenticationMiddleware verifies the token put in the context by
+// jwt.HTTPToContext, checks it wasn't revoked and injects the identity it
+// carries
 func EndpointAuthenticationMiddleware() endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-			// defer func() {
-			// 	kf := func(token *stdjwt.Token) (interface{}, error) { return verifKey, nil }
-			// 	next = jwt.NewParser(kf, stdjwt.SigningMethodHS256, claims)(next)
-			// }()
+			token, ok := ctx.Value(jwt.JWTTokenContextKey).(string)
+			if !ok {
+				return nil, TokenError
+			}
+			identity, err := ParseToken(token)
+			if err != nil {
+				return nil, err
+			}
+			if err = checkRevoked(ctx, identity); err != nil {
+				return nil, err
+			}
+			return next(context.WithValue(ctx, identityKey{}, identity), request)
+		}
+	}
+}
 
-			return next(ctx, request)
+/* Idempotency Middleware */
+// EndpointIdempotencyMiddleware looks the idempotency key of the request up,
+// the keys of each user being kept apart. It goes inside
+// EndpointAuthenticationMiddleware, which injects the identity it reads.
+func EndpointIdempotencyMiddleware(db svcdb.IService, scope string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			identity, ok := IdentityFromContext(ctx)
+			if !ok {
+				return nil, TokenError
+			}
+			userScope := scope + ":" + strconv.FormatInt(identity.UserID, 10)
+			return svcdb.EndpointIdempotencyMiddleware(db, userScope)(next)(ctx, request)
 		}
 	}
 }
@@ -21,33 +21,45 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetAllEstablishments(ctx context.Context) ([]svcdb.Establishment, error) {
-	estabs, err := s.svcdb.GetEstablishments(ctx)
-	return estabs, dbToHTTPErr(err)
+func (s Service) GetAllEstablishments(ctx context.Context, page svcdb.Page) ([]svcdb.Establishment, string, error) {
+	estabs, next, err := s.svcdb.GetEstablishments(ctx, page.Bounded())
+	return estabs, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type getAllEstablishmentsRequest struct {
+	Page svcdb.Page `json:"page"`
 }
 
 type getAllEstablishmentsResponse struct {
 	Establishments []svcdb.Establishment `json:"establishments"`
+	NextCursor     string                `json:"next_cursor,omitempty"`
 }
 
 func GetAllEstablishmentsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		estabs, err := svc.GetAllEstablishments(ctx)
+		req := request.(getAllEstablishmentsRequest)
+		estabs, next, err := svc.GetAllEstablishments(ctx, req.Page)
 		if err != nil {
 			fmt.Println("Error GetAllEstablishmentsEndpoint : ", err.Error())
 			return nil, err
 		}
-		return getAllEstablishmentsResponse{Establishments: estabs}, nil
+		return getAllEstablishmentsResponse{Establishments: estabs, NextCursor: next}, nil
 	}
 }
 
 /*************** Transport ***************/
 func DecodeHTTPgetAllEstablishmentsRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	return nil, nil
+	var request getAllEstablishmentsRequest
+
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPgetAllEstablishmentsRequest : ", err.Error())
+		return nil, RequestError
+	}
+	(&request).Page = page
+
+	return request, nil
 }
 
 func DecodeHTTPgetAllEstablishmentsResponse(_ context.Context, r *http.Response) (interface{}, error) {
@@ -71,27 +83,27 @@ func GetAllEstablishmentsHTTPHandler(endpoints Endpoints, tracer stdopentracing.
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetAllEstablishments(ctx context.Context) ([]svcdb.Establishment, error) {
-	estabs, err := mw.next.GetAllEstablishments(ctx)
+func (mw serviceLoggingMiddleware) GetAllEstablishments(ctx context.Context, page svcdb.Page) ([]svcdb.Establishment, string, error) {
+	estabs, next, err := mw.next.GetAllEstablishments(ctx, page)
 
 	mw.logger.Log(
 		"method", "GetAllEstablishments",
 		"response", estabs,
 		"took", time.Since(time.Now()),
 	)
-	return estabs, err
+	return estabs, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetAllEstablishments(ctx context.Context) ([]svcdb.Establishment, error) {
-	return mw.next.GetAllEstablishments(ctx)
+func (mw serviceAuthenticationMiddleware) GetAllEstablishments(ctx context.Context, page svcdb.Page) ([]svcdb.Establishment, string, error) {
+	return mw.next.GetAllEstablishments(ctx, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetAllEstablishments(ctx context.Context) ([]svcdb.Establishment, error) {
-	return mw.next.GetAllEstablishments(ctx)
+func (mw serviceInstrumentingMiddleware) GetAllEstablishments(ctx context.Context, page svcdb.Page) ([]svcdb.Establishment, string, error) {
+	return mw.next.GetAllEstablishments(ctx, page)
 }
 
 /*************** Main ***************/
@@ -97,6 +97,9 @@ func (mw serviceLoggingMiddleware) GetGroup(ctx context.Context, groupID int64)
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetGroup(ctx context.Context, groupID int64) (svcdb.Group, error) {
+	if err := mw.groupUser(ctx, groupID, false); err != nil {
+		return svcdb.Group{}, err
+	}
 	return mw.next.GetGroup(ctx, groupID)
 }
 
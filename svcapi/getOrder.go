@@ -94,7 +94,14 @@ func (mw serviceLoggingMiddleware) GetOrder(ctx context.Context, orderID int64)
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetOrder(ctx context.Context, orderID int64) (svcdb.Order, error) {
-	return mw.next.GetOrder(ctx, orderID)
+	order, err := mw.next.GetOrder(ctx, orderID)
+	if err != nil {
+		return order, err
+	}
+	if err = orderParticipant(ctx, order); err != nil {
+		return svcdb.Order{}, err
+	}
+	return order, nil
 }
 
 /*************** Instrumenting ***************/
@@ -22,29 +22,31 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetRecommendation(ctx context.Context, userID int64) ([]svcdb.Establishment, error) {
-	estabs, err := s.svcdb.GetRecommendation(ctx, userID)
-	return estabs, dbToHTTPErr(err)
+func (s Service) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]svcdb.Recommendation, error) {
+	recos, err := s.svcdb.GetRecommendation(ctx, userID, lat, long)
+	return recos, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type getRecommendationRequest struct {
-	UserID int64 `json:"id"`
+	UserID int64   `json:"id"`
+	Lat    float64 `json:"lat,omitempty"`
+	Long   float64 `json:"long,omitempty"`
 }
 
 type getRecommendationResponse struct {
-	Establishments []svcdb.Establishment `json:"establishments"`
+	Recommendations []svcdb.Recommendation `json:"recommendations"`
 }
 
 func GetRecommendationEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getRecommendationRequest)
-		estabs, err := svc.GetRecommendation(ctx, req.UserID)
+		recos, err := svc.GetRecommendation(ctx, req.UserID, req.Lat, req.Long)
 		if err != nil {
 			fmt.Println("Error GetRecommendationEndpoint : ", err.Error())
 			return nil, err
 		}
-		return getRecommendationResponse{Establishments: estabs}, nil
+		return getRecommendationResponse{Recommendations: recos}, nil
 	}
 }
 
@@ -64,6 +66,13 @@ func DecodeHTTPgetRecommendationRequest(_ context.Context, r *http.Request) (int
 	}
 	(&request).UserID = userID
 
+	lat, long, err := svcdb.LocationFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetRecommendationRequest 3 : ", err.Error())
+		return nil, RequestError
+	}
+	(&request).Lat, (&request).Long = lat, long
+
 	return request, nil
 	
 }
@@ -89,27 +98,31 @@ func GetRecommendationHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tra
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetRecommendation(ctx context.Context, userID int64) ([]svcdb.Establishment, error) {
-	estabs, err := mw.next.GetRecommendation(ctx, userID)
+func (mw serviceLoggingMiddleware) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]svcdb.Recommendation, error) {
+	recos, err := mw.next.GetRecommendation(ctx, userID, lat, long)
 
 	mw.logger.Log(
 		"method", "GetRecommendation",
-		"response", estabs,
+		"response", recos,
 		"took", time.Since(time.Now()),
 	)
-	return estabs, err
+	return recos, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetRecommendation(ctx context.Context, userID int64) ([]svcdb.Establishment, error) {
-	return mw.next.GetRecommendation(ctx, userID)
+func (mw serviceAuthenticationMiddleware) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]svcdb.Recommendation, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return mw.next.GetRecommendation(ctx, userID, lat, long)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetRecommendation(ctx context.Context, userID int64) ([]svcdb.Establishment, error) {
-	return mw.next.GetRecommendation(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]svcdb.Recommendation, error) {
+	return mw.next.GetRecommendation(ctx, userID, lat, long)
 }
 
 /*************** Main ***************/
@@ -21,25 +21,27 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserFriends(ctx context.Context, userID int64) ([]svcdb.Profile, error) {
-	friends, err := s.svcdb.GetUserFriends(ctx, userID)
-	return friends, dbToHTTPErr(err)
+func (s Service) GetUserFriends(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Profile, string, error) {
+	friends, next, err := s.svcdb.GetUserFriends(ctx, userID, page.Bounded())
+	return friends, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type getUserFriendsRequest struct {
-	UserID int64 `json:"userID"`
+	UserID int64      `json:"userID"`
+	Page   svcdb.Page `json:"page"`
 }
 
 type getUserFriendsResponse struct {
-	Profile []svcdb.Profile `json:"friends"`
+	Profile    []svcdb.Profile `json:"friends"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 func GetUserFriendsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getUserFriendsRequest)
-		menu, err := svc.GetUserFriends(ctx, req.UserID)
-		return getUserFriendsResponse{Profile: menu}, err
+		menu, next, err := svc.GetUserFriends(ctx, req.UserID, req.Page)
+		return getUserFriendsResponse{Profile: menu, NextCursor: next}, err
 	}
 }
 
@@ -59,6 +61,13 @@ func DecodeHTTPGetUserFriendsRequest(_ context.Context, r *http.Request) (interf
 	}
 	(&request).UserID = userID
 
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetUserFriendsRequest 3 : ", err.Error())
+		return nil, RequestError
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -83,8 +92,8 @@ func GetUserFriendsHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID int64) ([]svcdb.Profile, error) {
-	menu, err := mw.next.GetUserFriends(ctx, userID)
+func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Profile, string, error) {
+	menu, next, err := mw.next.GetUserFriends(ctx, userID, page)
 
 	mw.logger.Log(
 		"method", "getUserFriends",
@@ -92,19 +101,23 @@ func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID in
 		"response", menu,
 		"took", time.Since(time.Now()),
 	)
-	return menu, err
+	return menu, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetUserFriends(ctx context.Context, userID int64) ([]svcdb.Profile, error) {
-	return mw.next.GetUserFriends(ctx, userID)
+func (mw serviceAuthenticationMiddleware) GetUserFriends(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Profile, string, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	return mw.next.GetUserFriends(ctx, userID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetUserFriends(ctx context.Context, userID int64) ([]svcdb.Profile, error) {
-	return mw.next.GetUserFriends(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetUserFriends(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Profile, string, error) {
+	return mw.next.GetUserFriends(ctx, userID, page)
 }
 
 /*************** Main ***************/
@@ -98,6 +98,10 @@ func (mw serviceLoggingMiddleware) GetUserGroups(ctx context.Context, userID int
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetUserGroups(ctx context.Context, userID int64) ([]svcdb.GroupArrayElement, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetUserGroups(ctx, userID)
 }
 
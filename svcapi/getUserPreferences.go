@@ -98,6 +98,10 @@ func (mw serviceLoggingMiddleware) GetUserPreferences(ctx context.Context, userI
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetUserPreferences(ctx context.Context, userID int64) ([]svcdb.Preference, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetUserPreferences(ctx, userID)
 }
 
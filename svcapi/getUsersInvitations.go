@@ -22,30 +22,32 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUsersInvitations(ctx context.Context, userID int64) ([]svcdb.Invitation, error) {
-	invitations, err := s.svcdb.GetUsersInvitations(ctx, userID)
-	return invitations, dbToHTTPErr(err)
+func (s Service) GetUsersInvitations(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Invitation, string, error) {
+	invitations, next, err := s.svcdb.GetUsersInvitations(ctx, userID, page.Bounded())
+	return invitations, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type getUsersInvitationsRequest struct {
-	UserID int64 `json:"userID"`
+	UserID int64      `json:"userID"`
+	Page   svcdb.Page `json:"page"`
 }
 
 type getUsersInvitationsResponse struct {
 	Invitations []svcdb.Invitation `json:"invitations"`
+	NextCursor  string             `json:"next_cursor,omitempty"`
 }
 
 func GetUsersInvitationsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 
 		req := request.(getUsersInvitationsRequest)
-		invitations, err := svc.GetUsersInvitations(ctx, req.UserID)
+		invitations, next, err := svc.GetUsersInvitations(ctx, req.UserID, req.Page)
 		if err != nil {
 			fmt.Println("Error GetUsersInvitationsEndpoint : ", err.Error())
-			return getUsersInvitationsResponse{invitations}, err
+			return getUsersInvitationsResponse{invitations, next}, err
 		}
-		return getUsersInvitationsResponse{invitations}, nil
+		return getUsersInvitationsResponse{invitations, next}, nil
 	}
 }
 
@@ -65,6 +67,13 @@ func DecodeHTTPgetUsersInvitationsRequest(_ context.Context, r *http.Request) (i
 	}
 	(&request).UserID = userID
 
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPgetUsersInvitationsRequest 3 : ", err.Error())
+		return nil, RequestError
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -88,27 +97,31 @@ func GetUsersInvitationsHTTPHandler(endpoints Endpoints, tracer stdopentracing.T
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetUsersInvitations(ctx context.Context, userID int64) ([]svcdb.Invitation, error) {
-	estabs, err := mw.next.GetUsersInvitations(ctx, userID)
+func (mw serviceLoggingMiddleware) GetUsersInvitations(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Invitation, string, error) {
+	estabs, next, err := mw.next.GetUsersInvitations(ctx, userID, page)
 
 	mw.logger.Log(
 		"method", "GetUsersInvitations",
 		"response", estabs,
 		"took", time.Since(time.Now()),
 	)
-	return estabs, err
+	return estabs, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetUsersInvitations(ctx context.Context, userID int64) ([]svcdb.Invitation, error) {
-	return mw.next.GetUsersInvitations(ctx, userID)
+func (mw serviceAuthenticationMiddleware) GetUsersInvitations(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Invitation, string, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	return mw.next.GetUsersInvitations(ctx, userID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetUsersInvitations(ctx context.Context, userID int64) ([]svcdb.Invitation, error) {
-	return mw.next.GetUsersInvitations(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetUsersInvitations(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Invitation, string, error) {
+	return mw.next.GetUsersInvitations(ctx, userID, page)
 }
 
 /*************** Main ***************/
@@ -99,6 +99,10 @@ func (mw serviceLoggingMiddleware) GroupInvitationAccept(ctx context.Context, us
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GroupInvitationAccept(ctx context.Context, userID, friendID int64) error {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return err
+	}
 	return mw.next.GroupInvitationAccept(ctx, userID, friendID)
 }
 
@@ -99,6 +99,10 @@ func (mw serviceLoggingMiddleware) GroupInvitationDecline(ctx context.Context, u
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GroupInvitationDecline(ctx context.Context, userID, friendID int64) error {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return err
+	}
 	return mw.next.GroupInvitationDecline(ctx, userID, friendID)
 }
 
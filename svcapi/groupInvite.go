@@ -116,6 +116,9 @@ func (mw serviceLoggingMiddleware) GroupInvite(ctx context.Context, groupID, fri
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GroupInvite(ctx context.Context, groupID, friendID int64) error {
+	if err := mw.groupUser(ctx, groupID, false); err != nil {
+		return err
+	}
 	return mw.next.GroupInvite(ctx, groupID, friendID)
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"svcdb"
 	"svcws"
 	"time"
 
@@ -34,7 +35,7 @@ func firstFriendSuccess(s Service, ctx context.Context, userID int64) {
 	}
 
 	// Get user friends count
-	userFriends, err := s.svcdb.GetUserFriends(ctx, userID)
+	userFriends, _, err := s.svcdb.GetUserFriends(ctx, userID, svcdb.Page{Limit: 2})
 	if err != nil || len(userFriends) != 1 {
 		fmt.Println(err.Error())
 		return
@@ -154,6 +155,9 @@ func (mw serviceLoggingMiddleware) InvitationAccept(ctx context.Context, invitat
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) InvitationAccept(ctx context.Context, invitationID int64) error {
+	if err := mw.invitedUser(ctx, invitationID); err != nil {
+		return err
+	}
 	return mw.next.InvitationAccept(ctx, invitationID)
 }
 
@@ -96,6 +96,9 @@ func (mw serviceLoggingMiddleware) InvitationDecline(ctx context.Context, invita
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) InvitationDecline(ctx context.Context, invitationID int64) error {
+	if err := mw.invitedUser(ctx, invitationID); err != nil {
+		return err
+	}
 	return mw.next.InvitationDecline(ctx, invitationID)
 }
 
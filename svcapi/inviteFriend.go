@@ -115,6 +115,10 @@ func (mw serviceLoggingMiddleware) InviteFriend(ctx context.Context, userID, fri
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) InviteFriend(ctx context.Context, userID, friendID int64) error {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return err
+	}
 	return mw.next.InviteFriend(ctx, userID, friendID)
 }
 
@@ -12,8 +12,6 @@ import (
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -23,31 +21,29 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) Login(ctx context.Context, old svcdb.User) (svcdb.User, string, error) {
+func (s Service) Login(ctx context.Context, old svcdb.User) (svcdb.User, svcdb.Session, error) {
 	var user svcdb.User
-	var token string
+	var session svcdb.Session
 
 	err := checkMandatoryUserParams(old)
 	if err != nil {
 		fmt.Println("Error Login 1 : ", err.Error())
-		return user, "", err
+		return user, session, err
 	}
 
 	user, err = s.svcdb.GetUser(ctx, old)
 	if err != nil {
 		fmt.Println("Error Login 2 : ", err.Error())
-		return user, "", dbToHTTPErr(err)
+		return user, session, dbToHTTPErr(err)
 	}
 
-	// Create a jwt token
-	signer := stdjwt.NewWithClaims(stdjwt.SigningMethodHS256, claims)
-	token, err = signer.SignedString(signKey)
+	session, err = s.newSession(ctx, user.ID)
 	if err != nil {
 		fmt.Println("Error Login 3 : ", err.Error())
-		return user, "", err
+		return user, session, err
 	}
 
-	return user, token, nil
+	return user, session, nil
 }
 
 func checkMandatoryUserParams(u svcdb.User) error {
@@ -64,8 +60,8 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	User  svcdb.User `json:"user"`
-	Token string     `json:"token"`
+	User svcdb.User `json:"user"`
+	svcdb.Session
 }
 
 func LoginEndpoint(svc IService) endpoint.Endpoint {
@@ -73,8 +69,8 @@ func LoginEndpoint(svc IService) endpoint.Endpoint {
 		var user svcdb.User
 		user = request.(LoginRequest).User
 
-		newUser, token, err := svc.Login(ctx, user)
-		return LoginResponse{User: newUser, Token: token}, err
+		newUser, session, err := svc.Login(ctx, user)
+		return LoginResponse{User: newUser, Session: session}, err
 	}
 }
 
@@ -110,28 +106,28 @@ func LoginHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
-	u, token, err := mw.next.Login(ctx, user)
+func (mw serviceLoggingMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
+	u, session, err := mw.next.Login(ctx, user)
 
 	mw.logger.Log(
 		"method", "Login",
-		"request", user,
-		"response", LoginResponse{User: u, Token: token},
+		"request", user.Redacted(),
+		"response", u.Redacted(),
 		"took", time.Since(time.Now()),
 	)
 
-	return u, token, err
+	return u, session, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
+func (mw serviceAuthenticationMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
 	return mw.next.Login(ctx, user)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
+func (mw serviceInstrumentingMiddleware) Login(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
 	return mw.next.Login(ctx, user)
 }
 
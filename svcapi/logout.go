@@ -0,0 +1,135 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+// Logout ends the session of the request : its access token is revoked until
+// it expires, and refreshToken, when given, can't renew it anymore
+func (s Service) Logout(ctx context.Context, refreshToken string) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return TokenError
+	}
+
+	var refreshHash string
+	if len(refreshToken) > 0 {
+		refreshHash = svcdb.HashToken(refreshToken)
+	}
+	err := s.svcdb.RevokeTokens(ctx, svcdb.Revocation{
+		Subject: svcdb.TokenSubject(svcdb.SubjectUser, identity.UserID),
+		TokenID: identity.TokenID,
+		Until:   identity.ExpiresAt,
+	}, refreshHash)
+	return dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type logoutResponse struct {
+}
+
+func LogoutEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(logoutRequest)
+		err := svc.Logout(ctx, req.RefreshToken)
+		return logoutResponse{}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPLogoutRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req logoutRequest
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPLogoutRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+func DecodeHTTPLogoutResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response logoutResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPLogoutResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func LogoutHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/logout").Handler(httptransport.NewServer(
+		endpoints.LogoutEndpoint,
+		DecodeHTTPLogoutRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "Logout", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	err := mw.next.Logout(ctx, refreshToken)
+
+	mw.logger.Log(
+		"method", "Logout",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	if _, err := authenticatedUser(ctx, 0); err != nil {
+		return err
+	}
+	return mw.next.Logout(ctx, refreshToken)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	return mw.next.Logout(ctx, refreshToken)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildLogoutEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "Logout")
+		csLogger := log.With(logger, "method", "Logout")
+
+		csEndpoint = LogoutEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "Logout")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
@@ -109,6 +109,10 @@ func (mw serviceLoggingMiddleware) RateEstablishment(ctx context.Context, estabI
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) RateEstablishment(ctx context.Context, estabID, userID, rate int64) (svcdb.Establishment, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return svcdb.Establishment{}, err
+	}
 	return mw.next.RateEstablishment(ctx, estabID, userID, rate)
 }
 
@@ -129,6 +133,7 @@ func BuildRateEstablishmentEndpoint(svc IService, logger log.Logger, tracer stdo
 		csEndpoint = RateEstablishmentEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "RateEstablishment")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
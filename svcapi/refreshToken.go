@@ -0,0 +1,138 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+// RefreshToken renews the session of refreshToken. The refresh token is used
+// once : the session goes on with the new one returned.
+func (s Service) RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error) {
+	var session svcdb.Session
+
+	if len(refreshToken) == 0 {
+		return session, RequestError
+	}
+
+	// The subject of next is the one of the used token, set by svcdb
+	refresh, next, err := svcdb.NewRefreshToken("", refreshDuration)
+	if err != nil {
+		fmt.Println("Error RefreshToken 1 : ", err.Error())
+		return session, err
+	}
+
+	used, err := s.svcdb.UseRefreshToken(ctx, svcdb.HashToken(refreshToken), next, time.Now().Add(tokenDuration))
+	if err != nil {
+		fmt.Println("Error RefreshToken 2 : ", err.Error())
+		return session, dbToHTTPErr(err)
+	}
+
+	kind, userID, err := svcdb.ParseTokenSubject(used.Subject)
+	if err != nil || kind != svcdb.SubjectUser {
+		return session, TokenError
+	}
+	return signSession(userID, refresh)
+}
+
+/*************** Endpoint ***************/
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type refreshTokenResponse struct {
+	svcdb.Session
+}
+
+func RefreshTokenEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(refreshTokenRequest)
+		session, err := svc.RefreshToken(ctx, req.RefreshToken)
+		return refreshTokenResponse{Session: session}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPRefreshTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req refreshTokenRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPRefreshTokenRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+func DecodeHTTPRefreshTokenResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response refreshTokenResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPRefreshTokenResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func RefreshTokenHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/token/refresh").Handler(httptransport.NewServer(
+		endpoints.RefreshTokenEndpoint,
+		DecodeHTTPRefreshTokenRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "RefreshToken", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error) {
+	session, err := mw.next.RefreshToken(ctx, refreshToken)
+
+	mw.logger.Log(
+		"method", "RefreshToken",
+		"expiresAt", session.ExpiresAt,
+		"took", time.Since(time.Now()),
+	)
+	return session, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error) {
+	return mw.next.RefreshToken(ctx, refreshToken)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error) {
+	return mw.next.RefreshToken(ctx, refreshToken)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildRefreshTokenEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "RefreshToken")
+		csLogger := log.With(logger, "method", "RefreshToken")
+
+		csEndpoint = RefreshTokenEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "RefreshToken")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
@@ -3,11 +3,10 @@ package svcapi
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -21,28 +20,31 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) Register(ctx context.Context, old svcdb.User) (svcdb.User, string, error) {
+func (s Service) Register(ctx context.Context, old svcdb.User) (svcdb.User, svcdb.Session, error) {
 	var user svcdb.User
-	var token string
+	var session svcdb.Session
 
 	err := checkMandatoryUserParams(old)
 	if err != nil {
-		return user, "", err
+		return user, session, err
 	}
 
 	user, err = s.svcdb.CreateUser(ctx, old)
 	if err != nil {
-		return user, "", dbToHTTPErr(err)
+		return user, session, dbToHTTPErr(err)
 	}
 
-	// Create a jwt token
-	signer := stdjwt.NewWithClaims(stdjwt.SigningMethodHS256, claims)
-	token, err = signer.SignedString(signKey)
+	session, err = s.newSession(ctx, user.ID)
 	if err != nil {
-		return user, "", err
+		return user, session, err
+	}
+
+	// the account exists anyway, the link can be mailed again by SendVerification
+	if err = s.sendVerification(ctx, user); err != nil {
+		fmt.Println("Register (sendVerification) : " + err.Error())
 	}
 
-	return user, token, nil
+	return user, session, nil
 }
 
 /*************** Endpoint ***************/
@@ -51,15 +53,15 @@ type RegisterRequest struct {
 }
 
 type RegisterResponse struct {
-	User  svcdb.User `json:"user"`
-	Token string     `json:"token"`
+	User svcdb.User `json:"user"`
+	svcdb.Session
 }
 
 func RegisterEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(RegisterRequest)
-		user, token, err := svc.Register(ctx, req.User)
-		return RegisterResponse{User: user, Token: token}, err
+		user, session, err := svc.Register(ctx, req.User)
+		return RegisterResponse{User: user, Session: session}, err
 	}
 }
 
@@ -93,27 +95,27 @@ func RegisterHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logg
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
-	newUser, token, err := mw.next.Register(ctx, user)
+func (mw serviceLoggingMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
+	newUser, session, err := mw.next.Register(ctx, user)
 
 	mw.logger.Log(
 		"method", "Register",
-		"request", user,
-		"request", RegisterResponse{User: newUser, Token: token},
+		"request", user.Redacted(),
+		"response", newUser.Redacted(),
 		"took", time.Since(time.Now()),
 	)
-	return newUser, token, err
+	return newUser, session, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
+func (mw serviceAuthenticationMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
 	return mw.next.Register(ctx, user)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, string, error) {
+func (mw serviceInstrumentingMiddleware) Register(ctx context.Context, user svcdb.User) (svcdb.User, svcdb.Session, error) {
 	return mw.next.Register(ctx, user)
 }
 
@@ -0,0 +1,143 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"mailer"
+	"svcdb"
+)
+
+/*************** Service ***************/
+// RequestPasswordReset mails a link to reset the password of the user of
+// email. An unknown email succeeds too, not to tell which emails have an account.
+func (s Service) RequestPasswordReset(ctx context.Context, email string) error {
+	if len(email) == 0 {
+		return RequestError
+	}
+
+	userID, err := s.svcdb.GetAccountByEmail(ctx, svcdb.SubjectUser, email)
+	if err != nil {
+		if dbToHTTPErr(err) == NotFoundError {
+			return nil
+		}
+		fmt.Println("RequestPasswordReset (GetAccountByEmail) : " + err.Error())
+		return dbToHTTPErr(err)
+	}
+
+	token, err := s.newAccountToken(ctx, userID, email, svcdb.PurposeResetPassword, resetDuration)
+	if err != nil {
+		return err
+	}
+	err = s.mail.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "NightLine - Reset your password",
+		Body: "To choose a new password, open the link below within the hour :\n\n" +
+			s.appURL + "/password/reset?token=" + url.QueryEscape(token) + "\n\n" +
+			"If you didn't ask for it, just ignore this email : your password is unchanged.\n",
+	})
+	if err != nil {
+		fmt.Println("RequestPasswordReset (Send) : " + err.Error())
+		return ConnError
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type requestPasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type requestPasswordResetResponse struct {
+}
+
+func RequestPasswordResetEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(requestPasswordResetRequest)
+		err := svc.RequestPasswordReset(ctx, req.Email)
+		return requestPasswordResetResponse{}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPRequestPasswordResetRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req requestPasswordResetRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPRequestPasswordResetRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+func DecodeHTTPRequestPasswordResetResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response requestPasswordResetResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPRequestPasswordResetResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func RequestPasswordResetHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/password/reset").Handler(httptransport.NewServer(
+		endpoints.RequestPasswordResetEndpoint,
+		DecodeHTTPRequestPasswordResetRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "RequestPasswordReset", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	err := mw.next.RequestPasswordReset(ctx, email)
+
+	mw.logger.Log(
+		"method", "RequestPasswordReset",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	return mw.next.RequestPasswordReset(ctx, email)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	return mw.next.RequestPasswordReset(ctx, email)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildRequestPasswordResetEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "RequestPasswordReset")
+		csLogger := log.With(logger, "method", "RequestPasswordReset")
+
+		csEndpoint = RequestPasswordResetEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "RequestPasswordReset")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
@@ -0,0 +1,137 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+// ResetPassword sets password with the token mailed by RequestPasswordReset.
+// Every session of the user ends, as after a LogoutAll.
+func (s Service) ResetPassword(ctx context.Context, token string, password string) error {
+	if len(token) == 0 || len(password) == 0 {
+		return RequestError
+	}
+
+	hash, err := parseAccountToken(token, svcdb.PurposeResetPassword)
+	if err != nil {
+		return err
+	}
+	used, err := s.svcdb.ResetPassword(ctx, hash, password)
+	if err != nil {
+		fmt.Println("ResetPassword (ResetPassword) : " + err.Error())
+		return dbToHTTPErr(err)
+	}
+
+	now := time.Now()
+	err = s.svcdb.RevokeTokens(ctx, svcdb.Revocation{
+		Subject: used.Subject,
+		Before:  now,
+		Until:   now.Add(tokenDuration),
+	}, "")
+	if err != nil {
+		fmt.Println("ResetPassword (RevokeTokens) : " + err.Error())
+	}
+	return dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type resetPasswordResponse struct {
+}
+
+func ResetPasswordEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetPasswordRequest)
+		err := svc.ResetPassword(ctx, req.Token, req.Password)
+		return resetPasswordResponse{}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPResetPasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req resetPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPResetPasswordRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+func DecodeHTTPResetPasswordResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response resetPasswordResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPResetPasswordResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ResetPasswordHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/password/reset/confirm").Handler(httptransport.NewServer(
+		endpoints.ResetPasswordEndpoint,
+		DecodeHTTPResetPasswordRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ResetPassword", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ResetPassword(ctx context.Context, token string, password string) error {
+	err := mw.next.ResetPassword(ctx, token, password)
+
+	mw.logger.Log(
+		"method", "ResetPassword",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) ResetPassword(ctx context.Context, token string, password string) error {
+	return mw.next.ResetPassword(ctx, token, password)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ResetPassword(ctx context.Context, token string, password string) error {
+	return mw.next.ResetPassword(ctx, token, password)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildResetPasswordEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ResetPassword")
+		csLogger := log.With(logger, "method", "ResetPassword")
+
+		csEndpoint = ResetPasswordEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ResetPassword")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
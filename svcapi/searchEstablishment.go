@@ -22,25 +22,27 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchEstablishments(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	responses, err := s.svcdb.SearchEstablishments(ctx, query)
-	return responses, dbToHTTPErr(err)
+func (s Service) SearchEstablishments(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	responses, next, err := s.svcdb.SearchEstablishments(ctx, query, page.Bounded())
+	return responses, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type SearchEstablishmentsRequest struct {
-	query string `json:"query"`
+	query string     `json:"query"`
+	Page  svcdb.Page `json:"page"`
 }
 
 type SearchEstablishmentsResponse struct {
 	Establishment []svcdb.SearchResponse `json:"establishments"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
 }
 
 func SearchEstablishmentsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(SearchEstablishmentsRequest)
-		Establishment, err := svc.SearchEstablishments(ctx, req.query)
-		return SearchEstablishmentsResponse{Establishment: Establishment}, err
+		Establishment, next, err := svc.SearchEstablishments(ctx, req.query, req.Page)
+		return SearchEstablishmentsResponse{Establishment: Establishment, NextCursor: next}, err
 	}
 }
 
@@ -62,6 +64,13 @@ func DecodeHTTPSearchEstablishmentsRequest(_ context.Context, r *http.Request) (
 		return nil, err
 	}
 
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchEstablishmentsRequest : ", err.Error())
+		return nil, RequestError
+	}
+	(&req).Page = page
+
 	return req, nil
 }
 
@@ -86,8 +95,8 @@ func SearchEstablishmentsHTTPHandler(endpoints Endpoints, tracer stdopentracing.
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchEstablishments(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	estabs, err := mw.next.SearchEstablishments(ctx, query)
+func (mw serviceLoggingMiddleware) SearchEstablishments(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	estabs, next, err := mw.next.SearchEstablishments(ctx, query, page)
 
 	mw.logger.Log(
 		"method", "SearchEstablishments",
@@ -95,19 +104,19 @@ func (mw serviceLoggingMiddleware) SearchEstablishments(ctx context.Context, que
 		"response", estabs,
 		"took", time.Since(time.Now()),
 	)
-	return estabs, err
+	return estabs, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) SearchEstablishments(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	return mw.next.SearchEstablishments(ctx, query)
+func (mw serviceAuthenticationMiddleware) SearchEstablishments(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	return mw.next.SearchEstablishments(ctx, query, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchEstablishments(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	return mw.next.SearchEstablishments(ctx, query)
+func (mw serviceInstrumentingMiddleware) SearchEstablishments(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	return mw.next.SearchEstablishments(ctx, query, page)
 }
 
 /*************** Main ***************/
@@ -121,6 +130,7 @@ func BuildSearchEstablishmentsEndpoint(svc IService, logger log.Logger, tracer s
 		csEndpoint = SearchEstablishmentsEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SearchEstablishments")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -115,6 +115,10 @@ func (mw serviceLoggingMiddleware) SearchFriends(ctx context.Context, query stri
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) SearchFriends(ctx context.Context, query string, userID int64) ([]svcdb.SearchResponse, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return mw.next.SearchFriends(ctx, query, userID)
 }
 
@@ -135,6 +139,7 @@ func BuildSearchFriendsEndpoint(svc IService, logger log.Logger, tracer stdopent
 		csEndpoint = SearchFriendsEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SearchFriends")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
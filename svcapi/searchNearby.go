@@ -0,0 +1,125 @@
+package svcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+func (s Service) SearchNearby(ctx context.Context, lat, long, radius float64, filters svcdb.NearbyFilters) ([]svcdb.NearbyResult, error) {
+	results, err := s.svcdb.SearchNearby(ctx, lat, long, radius, filters)
+	return results, dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type SearchNearbyRequest struct {
+	Lat     float64             `json:"lat"`
+	Long    float64             `json:"long"`
+	Radius  float64             `json:"radius"`
+	Filters svcdb.NearbyFilters `json:"filters"`
+}
+
+type SearchNearbyResponse struct {
+	Results []svcdb.NearbyResult `json:"results"`
+}
+
+func SearchNearbyEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SearchNearbyRequest)
+		results, err := svc.SearchNearby(ctx, req.Lat, req.Long, req.Radius, req.Filters)
+		return SearchNearbyResponse{Results: results}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPSearchNearbyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req SearchNearbyRequest
+
+	lat, long, radius, filters, err := svcdb.NearbyFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchNearbyRequest : ", err.Error())
+		return nil, RequestError
+	}
+	req = SearchNearbyRequest{Lat: lat, Long: long, Radius: radius, Filters: filters}
+
+	return req, nil
+}
+
+func DecodeHTTPSearchNearbyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response SearchNearbyResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error on DecodeHTTPSearchNearbyResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func SearchNearbyHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/search/nearby").Handler(httptransport.NewServer(
+		endpoints.SearchNearbyEndpoint,
+		DecodeHTTPSearchNearbyRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SearchNearby", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) SearchNearby(ctx context.Context, lat, long, radius float64, filters svcdb.NearbyFilters) ([]svcdb.NearbyResult, error) {
+	results, err := mw.next.SearchNearby(ctx, lat, long, radius, filters)
+
+	mw.logger.Log(
+		"method", "SearchNearby",
+		"lat", lat,
+		"long", long,
+		"radius", radius,
+		"results", len(results),
+		"took", time.Since(time.Now()),
+	)
+	return results, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) SearchNearby(ctx context.Context, lat, long, radius float64, filters svcdb.NearbyFilters) ([]svcdb.NearbyResult, error) {
+	return mw.next.SearchNearby(ctx, lat, long, radius, filters)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) SearchNearby(ctx context.Context, lat, long, radius float64, filters svcdb.NearbyFilters) ([]svcdb.NearbyResult, error) {
+	return mw.next.SearchNearby(ctx, lat, long, radius, filters)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildSearchNearbyEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "SearchNearby")
+		csLogger := log.With(logger, "method", "SearchNearby")
+
+		csEndpoint = SearchNearbyEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "SearchNearby")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
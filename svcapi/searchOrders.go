@@ -20,25 +20,27 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	orders, err := s.svcpayment.SearchOrders(ctx, order)
-	return orders, dbToHTTPErr(err)
+func (s Service) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	orders, next, err := s.svcpayment.SearchOrders(ctx, order, page.Bounded())
+	return orders, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type searchOrdersRequest struct {
 	Order		svcdb.Order	`json:"order"`
+	Page		svcdb.Page	`json:"page"`
 }
 
 type searchOrdersResponse struct {
 	Orders		[]svcdb.Order	`json:"orders"`
+	NextCursor	string	`json:"next_cursor,omitempty"`
 }
 
 func SearchOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchOrdersRequest)
-		orders, err := svc.SearchOrders(ctx, req.Order)
-		return searchOrdersResponse{orders}, err
+		orders, next, err := svc.SearchOrders(ctx, req.Order, req.Page)
+		return searchOrdersResponse{orders, next}, err
 	}
 }
 
@@ -71,26 +73,37 @@ func SearchOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
+func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchOrders",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchOrders(ctx, order)
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	return mw.next.SearchOrders(ctx, order)
+func (mw serviceAuthenticationMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	// a user only searches the orders they are part of
+	for _, user := range order.Users {
+		if user.User.ID == userID {
+			return mw.next.SearchOrders(ctx, order, page)
+		}
+	}
+	order.Users = append(order.Users, svcdb.UserOrder{User: svcdb.User{ID: userID}})
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	return mw.next.SearchOrders(ctx, order)
+func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Main ***************/
@@ -22,25 +22,27 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchUsers(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	responses, err := s.svcdb.SearchUsers(ctx, query)
-	return responses, dbToHTTPErr(err)
+func (s Service) SearchUsers(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	responses, next, err := s.svcdb.SearchUsers(ctx, query, page.Bounded())
+	return responses, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type SearchUsersRequest struct {
-	query string `json:"query"`
+	query string     `json:"query"`
+	Page  svcdb.Page `json:"page"`
 }
 
 type SearchUsersResponse struct {
-	User []svcdb.SearchResponse `json:"Users"`
+	User       []svcdb.SearchResponse `json:"Users"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
 }
 
 func SearchUsersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(SearchUsersRequest)
-		User, err := svc.SearchUsers(ctx, req.query)
-		return SearchUsersResponse{User: User}, err
+		User, next, err := svc.SearchUsers(ctx, req.query, req.Page)
+		return SearchUsersResponse{User: User, NextCursor: next}, err
 	}
 }
 
@@ -61,6 +63,13 @@ func DecodeHTTPSearchUsersRequest(_ context.Context, r *http.Request) (interface
 		return nil, err
 	}
 
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchUsersRequest : ", err.Error())
+		return nil, RequestError
+	}
+	(&req).Page = page
+
 	return req, nil
 }
 
@@ -84,8 +93,8 @@ func SearchUsersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchUsers(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	users, err := mw.next.SearchUsers(ctx, query)
+func (mw serviceLoggingMiddleware) SearchUsers(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	users, next, err := mw.next.SearchUsers(ctx, query, page)
 
 	mw.logger.Log(
 		"method", "SearchUsers",
@@ -93,19 +102,19 @@ func (mw serviceLoggingMiddleware) SearchUsers(ctx context.Context, query string
 		"response", users,
 		"took", time.Since(time.Now()),
 	)
-	return users, err
+	return users, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) SearchUsers(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	return mw.next.SearchUsers(ctx, query)
+func (mw serviceAuthenticationMiddleware) SearchUsers(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	return mw.next.SearchUsers(ctx, query, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchUsers(ctx context.Context, query string) ([]svcdb.SearchResponse, error) {
-	return mw.next.SearchUsers(ctx, query)
+func (mw serviceInstrumentingMiddleware) SearchUsers(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error) {
+	return mw.next.SearchUsers(ctx, query, page)
 }
 
 /*************** Main ***************/
@@ -119,6 +128,7 @@ func BuildSearchUsersEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = SearchUsersEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SearchUsers")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"mailer"
 	"svcevent"
 	"svcdb"
 	"svcpayment"
@@ -12,11 +13,18 @@ import (
 /* Service interface */
 type IService interface {
 	/* Authentication */
-	Login(ctx context.Context, old svcdb.User) (svcdb.User, string, error)
-	Register(ctx context.Context, old svcdb.User) (svcdb.User, string, error)
+	Login(ctx context.Context, old svcdb.User) (svcdb.User, svcdb.Session, error)
+	Register(ctx context.Context, old svcdb.User) (svcdb.User, svcdb.Session, error)
+	RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	SendVerification(ctx context.Context) error
 
 	/* User */
-	SearchUsers(ctx context.Context, query string) ([]svcdb.SearchResponse, error)
+	SearchUsers(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error)
 	SearchFriends(ctx context.Context, query string, userID int64) ([]svcdb.SearchResponse, error)
 	GetUser(ctx context.Context, userID int64) (svcdb.Profile, error)
 	GetUserPreferences(ctx context.Context, userID int64) ([]svcdb.Preference, error)
@@ -24,18 +32,19 @@ type IService interface {
 	UpdateUser(ctx context.Context, old svcdb.User) (svcdb.User, error)
 	UpdatePreferences(ctx context.Context, userID int64, preferences []string) ([]string, error)
 	UpdateStripeUser(ctx context.Context, user svcdb.User, token string) (svcdb.User, error)
-	GetRecommendation(ctx context.Context, userID int64) ([]svcdb.Establishment, error)
+	GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]svcdb.Recommendation, error)
 
 	/* Friends */
-	GetUsersInvitations(ctx context.Context, UserID int64) ([]svcdb.Invitation, error)
-	GetUserFriends(ctx context.Context, userID int64) ([]svcdb.Profile, error)
+	GetUsersInvitations(ctx context.Context, UserID int64, page svcdb.Page) ([]svcdb.Invitation, string, error)
+	GetUserFriends(ctx context.Context, userID int64, page svcdb.Page) ([]svcdb.Profile, string, error)
 	InviteFriend(ctx context.Context, userID, friendID int64) error
 	InvitationAccept(ctx context.Context, invitationID int64) error
 	InvitationDecline(ctx context.Context, invitationID int64) error
 
 	/* Establishment */
-	GetAllEstablishments(ctx context.Context) ([]svcdb.Establishment, error)
-	SearchEstablishments(ctx context.Context, query string) ([]svcdb.SearchResponse, error)
+	GetAllEstablishments(ctx context.Context, page svcdb.Page) ([]svcdb.Establishment, string, error)
+	SearchEstablishments(ctx context.Context, query string, page svcdb.Page) ([]svcdb.SearchResponse, string, error)
+	SearchNearby(ctx context.Context, lat, long, radius float64, filters svcdb.NearbyFilters) ([]svcdb.NearbyResult, error)
 	GetEstablishment(ctx context.Context, estabID int64) (svcdb.Establishment, error)
 	GetMenuFromEstablishment(ctx context.Context, estabID int64) ([]svcdb.Menu, error)
 	GetSoireeFromEstablishment(ctx context.Context, estabID int64) (svcdb.Soiree, error)
@@ -45,7 +54,7 @@ type IService interface {
 
 	/* Soiree */
 	SoireeJoin(ctx context.Context, UserID, SoireeID int64) (svcdb.Soiree, string, error)
-	SoireeOrder(ctx context.Context, UserID, ConsoID, SoireeID int64, Token string) (int64, error)
+	SoireeOrder(ctx context.Context, SoireeID int64, Sb ShoppingBasket, Token string) (svcdb.Order, error)
 	SoireeLeave(ctx context.Context, UserID, SoireeID int64, token string) error
 	GetMenuFromSoiree(ctx context.Context, soireeID int64) (svcdb.Menu, error)
 
@@ -68,15 +77,18 @@ type IService interface {
 	GetOrder(ctx context.Context, orderID int64) (svcdb.Order, error)
 	CreateOrder(ctx context.Context, o svcdb.Order) (svcdb.Order, error)
 	AnswerOrder(ctx context.Context, orderID int64, userID int64, answer bool) (svcdb.Order, error)
-	SearchOrders(ctx context.Context, o svcdb.Order) ([]svcdb.Order, error)
+	CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error)
+	SearchOrders(ctx context.Context, o svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error)
 }
 
 /* Errors definition */
 var (
-	ConnError     = errors.New("The server was acting as a gateway or proxy and received an invalid response from the upstream server.")
-	RequestError  = errors.New("The server cannot or will not process the request due to an apparent client error.")
-	AuthError     = errors.New("The user might not have the necessary permissions for a resource, or may need an account of some sort.")
-	NotFoundError = errors.New("The requested resource could not be found.")
+	ConnError       = errors.New("The server was acting as a gateway or proxy and received an invalid response from the upstream server.")
+	RequestError    = errors.New("The server cannot or will not process the request due to an apparent client error.")
+	AuthError       = errors.New("The user might not have the necessary permissions for a resource, or may need an account of some sort.")
+	NotFoundError   = errors.New("The requested resource could not be found.")
+	TokenError      = errors.New("The request needs a valid and unexpired authentication token.")
+	UnverifiedError = errors.New("The email of the account must be verified first.")
 )
 
 /* Misc */
@@ -101,16 +113,27 @@ func dbToHTTPErr(err error) error {
 	switch err.Error() {
 	case "EOF":
 		return NotFoundError
+	case svcdb.IdempotencyConflictErr.Error():
+		return svcdb.IdempotencyConflictErr
+	case svcdb.IdempotencyInProgressErr.Error():
+		return svcdb.IdempotencyInProgressErr
+	case svcdb.RefreshTokenErr.Error():
+		return TokenError
+	case svcdb.AccountTokenErr.Error():
+		return svcdb.AccountTokenErr
 	}
 	return err
 }
 
 /* Service implementation */
-func NewService(db svcdb.IService, event svcevent.IService, payment svcpayment.IService) IService {
+// NewService builds the service, the links mailed to the users point to appURL
+func NewService(db svcdb.IService, event svcevent.IService, payment svcpayment.IService, mail mailer.Sender, appURL string) IService {
 	return Service{
 		svcdb:    db,
 		svcevent: event,
 		svcpayment: payment,
+		mail:     mail,
+		appURL:   appURL,
 	}
 }
 
@@ -118,6 +141,8 @@ type Service struct {
 	svcdb    svcdb.IService
 	svcevent svcevent.IService
 	svcpayment svcpayment.IService
+	mail     mailer.Sender
+	appURL   string
 }
 
 /* Middleware interface */
@@ -1,12 +1,19 @@
 package svcapi
 
-// Buyers model
+import (
+	"errors"
+	"math"
+
+	"svcdb"
+)
+
+// Buyers model, Amount being the share of the basket the user pays
 type Buyers struct {
 	UserID int64   `json:"id"`
 	Amount float64 `json:"amount"`
 }
 
-// Drinks model
+// Drinks model, Amount being the number of drinks ordered
 type Drinks struct {
 	DrinkID int64   `json:"id"`
 	Amount  float64 `json:"amount"`
@@ -17,3 +24,89 @@ type ShoppingBasket struct {
 	Buyers []Buyers `json:"buyers"`
 	Drinks []Drinks `json:"drinks"`
 }
+
+/* Errors definition */
+var (
+	EmptyBasketErr    = errors.New("The shopping basket needs at least one buyer and one drink")
+	BasketAmountErr   = errors.New("Buyer shares must be positive and drink amounts positive whole numbers")
+	BuyerNotJoinedErr = errors.New("Every buyer must have joined the soiree")
+	DrinkNotInMenuErr = errors.New("Every drink must be on the menu of the soiree")
+)
+
+// merged sums the amounts of the buyers and drinks listed more than once,
+// keeping the first listed order
+func (sb ShoppingBasket) merged() (ShoppingBasket, error) {
+	var merged ShoppingBasket
+
+	if len(sb.Buyers) == 0 || len(sb.Drinks) == 0 {
+		return merged, EmptyBasketErr
+	}
+
+	buyers := make(map[int64]int)
+	for _, buyer := range sb.Buyers {
+		if buyer.Amount <= 0 || math.IsInf(buyer.Amount, 0) || math.IsNaN(buyer.Amount) {
+			return merged, BasketAmountErr
+		}
+		if i, ok := buyers[buyer.UserID]; ok {
+			merged.Buyers[i].Amount += buyer.Amount
+			continue
+		}
+		buyers[buyer.UserID] = len(merged.Buyers)
+		merged.Buyers = append(merged.Buyers, buyer)
+	}
+
+	drinks := make(map[int64]int)
+	for _, drink := range sb.Drinks {
+		if drink.Amount <= 0 || drink.Amount != math.Trunc(drink.Amount) || drink.Amount > math.MaxInt32 {
+			return merged, BasketAmountErr
+		}
+		if i, ok := drinks[drink.DrinkID]; ok {
+			merged.Drinks[i].Amount += drink.Amount
+			continue
+		}
+		drinks[drink.DrinkID] = len(merged.Drinks)
+		merged.Drinks = append(merged.Drinks, drink)
+	}
+	return merged, nil
+}
+
+// order builds the order of a merged basket from the soiree menu. Prices are
+// in cents, as charged : the total is split between the buyers in proportion
+// to their share, rounding on running totals so that the prices add up.
+func (sb ShoppingBasket) order(soiree svcdb.Soiree, menu []svcdb.Conso) (svcdb.Order, error) {
+	var order svcdb.Order
+	var shares, running float64
+	var charged int64
+
+	order.Soiree = soiree
+
+	consos := make(map[int64]svcdb.Conso)
+	for _, conso := range menu {
+		consos[conso.ID] = conso
+	}
+	for _, drink := range sb.Drinks {
+		conso, ok := consos[drink.DrinkID]
+		if !ok {
+			return order, DrinkNotInMenuErr
+		}
+		order.Consos = append(order.Consos, svcdb.ConsoOrder{Conso: conso, Amount: int64(drink.Amount)})
+		order.Price += conso.Price * int64(drink.Amount)
+	}
+
+	for _, buyer := range sb.Buyers {
+		shares += buyer.Amount
+	}
+	for i, buyer := range sb.Buyers {
+		running += buyer.Amount
+		total := order.Price
+		if i < len(sb.Buyers)-1 {
+			total = int64(math.Round(float64(order.Price) * running / shares))
+		}
+		order.Users = append(order.Users, svcdb.UserOrder{
+			User:  svcdb.User{ID: buyer.UserID},
+			Price: total - charged,
+		})
+		charged = total
+	}
+	return order, nil
+}
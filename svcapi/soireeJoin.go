@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -94,7 +95,7 @@ func SoireeJoinHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, lo
 		endpoints.SoireeJoinEndpoint,
 		DecodeHTTPSoireeJoinRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeJoin", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeJoin", logger), jwt.HTTPToContext()))...,
 	))
 	return route
 }
@@ -116,6 +117,10 @@ func (mw serviceLoggingMiddleware) SoireeJoin(ctx context.Context, UserID, Soire
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) SoireeJoin(ctx context.Context, UserID, SoireeID int64) (svcdb.Soiree, string, error) {
+	var err error
+	if UserID, err = authenticatedUser(ctx, UserID); err != nil {
+		return svcdb.Soiree{}, "", err
+	}
 	return mw.next.SoireeJoin(ctx, UserID, SoireeID)
 }
 
@@ -136,6 +141,7 @@ func BuildSoireeJoinEndpoint(svc IService, logger log.Logger, tracer stdopentrac
 		csEndpoint = SoireeJoinEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SoireeJoin")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
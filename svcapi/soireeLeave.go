@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -86,7 +87,7 @@ func SoireeLeaveHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.SoireeLeaveEndpoint,
 		DecodeHTTPSoireeLeaveRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeLeave", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeLeave", logger), jwt.HTTPToContext()))...,
 	))
 	return route
 }
@@ -107,6 +108,10 @@ func (mw serviceLoggingMiddleware) SoireeLeave(ctx context.Context, UserID, Soir
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) SoireeLeave(ctx context.Context, UserID, SoireeID int64, token string) error {
+	var err error
+	if UserID, err = authenticatedUser(ctx, UserID); err != nil {
+		return err
+	}
 	return mw.next.SoireeLeave(ctx, UserID, SoireeID, token)
 }
 
@@ -127,6 +132,7 @@ func BuildSoireeLeaveEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = SoireeLeaveEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SoireeLeave")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
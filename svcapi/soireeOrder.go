@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -16,25 +17,64 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /*************** Service ***************/
-func (s Service) SoireeOrder(ctx context.Context, UserID, ConsoID, SoireeID int64, Token string) (int64, error) {
+func (s Service) SoireeOrder(ctx context.Context, SoireeID int64, Sb ShoppingBasket, Token string) (svcdb.Order, error) {
+	var order svcdb.Order
+
+	if err := s.verifiedUser(ctx); err != nil {
+		return order, err
+	}
 
-	//TODO: vérifier token
-	//TODO: vérifier qu'il y ai bien un lien entre l'user et la soiree
-	//TODO: vérifier qu'il y ai bien un lien entre la soiree et la conso
+	basket, err := Sb.merged()
+	if err != nil {
+		return order, err
+	}
 
-	user, err := s.svcdb.GetUserByID(ctx, UserID)
 	soiree, err := s.svcdb.GetSoireeByID(ctx, SoireeID)
-	conso, err := s.svcdb.GetConsoByID(ctx, ConsoID)
+	if err != nil {
+		fmt.Println("SoireeOrder (GetSoireeByID) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	}
+
+	/* Every buyer is in the soiree */
+	present, err := s.svcdb.GetConnectedFriends(ctx, SoireeID)
+	if err != nil {
+		fmt.Println("SoireeOrder (GetConnectedFriends) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	}
+	joined := make(map[int64]bool)
+	for _, profile := range present {
+		joined[profile.ID] = true
+	}
+	for _, buyer := range basket.Buyers {
+		if !joined[buyer.UserID] {
+			return order, BuyerNotJoinedErr
+		}
+	}
+
+	/* Every drink is on the soiree menu */
+	menu, err := s.svcdb.GetMenuFromSoiree(ctx, SoireeID)
+	if err != nil {
+		fmt.Println("SoireeOrder (GetMenuFromSoiree) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	}
+	consos, err := s.svcdb.GetMenuConsos(ctx, menu.ID)
+	if err != nil {
+		fmt.Println("SoireeOrder (GetMenuConsos) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	}
 
-	orderID, err := s.svcdb.UserOrder(ctx, user, soiree, conso)
+	order, err = basket.order(soiree, consos)
 	if err != nil {
-		return 0, dbToHTTPErr(err)
+		return order, err
 	}
 
-	return orderID, nil
+	order, err = s.svcpayment.CreateOrder(ctx, order)
+	return order, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
@@ -50,20 +90,19 @@ type SoireeOrderRequest struct {
 }
 
 type SoireeOrderResponse struct {
-	OrderID int64 `json:"orderID"`
+	OrderID int64       `json:"orderID"`
+	Order   svcdb.Order `json:"order"`
 }
 
 func SoireeOrderEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		// req := request.(SoireeOrderRequest)
-		// orderID, err := svc.SoireeOrder(ctx, req.UserID, req.ConsoID, req.SoireeID, req.Token)
-
-		// if err != nil {
-		// 	fmt.Println("Error SoireeOrderEndpoint : ", err.Error())
-		// 	return nil, err
-		// }
-
-		return SoireeOrderResponse{OrderID: -1}, nil
+		req := request.(SoireeOrderRequest)
+		order, err := svc.SoireeOrder(ctx, req.SoireeID, req.Sb, req.Token)
+		if err != nil {
+			fmt.Println("Error SoireeOrderEndpoint : ", err.Error())
+			return nil, err
+		}
+		return SoireeOrderResponse{OrderID: order.ID, Order: order}, nil
 	}
 }
 
@@ -105,35 +144,45 @@ func SoireeOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.SoireeOrderEndpoint,
 		DecodeHTTPSoireeOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeOrder", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SoireeOrder", logger), jwt.HTTPToContext()))...,
 	))
 	return route
 }
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SoireeOrder(ctx context.Context, UserID, ConsoID, SoireeID int64, Token string) (int64, error) {
-	OrderID, err := mw.next.SoireeOrder(ctx, UserID, ConsoID, SoireeID, Token)
+func (mw serviceLoggingMiddleware) SoireeOrder(ctx context.Context, SoireeID int64, Sb ShoppingBasket, Token string) (svcdb.Order, error) {
+	order, err := mw.next.SoireeOrder(ctx, SoireeID, Sb, Token)
 
 	mw.logger.Log(
 		"method", "SoireeOrder",
-		"request", SoireeOrderRequest{SoireeID: SoireeID, Token: Token},
-		"response", OrderID,
+		"request", SoireeOrderRequest{SoireeID: SoireeID, Sb: Sb},
+		"response", order.ID,
 		"took", time.Since(time.Now()),
 	)
-	return OrderID, err
+	return order, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) SoireeOrder(ctx context.Context, UserID, ConsoID, SoireeID int64, Token string) (int64, error) {
-	return mw.next.SoireeOrder(ctx, UserID, ConsoID, SoireeID, Token)
+func (mw serviceAuthenticationMiddleware) SoireeOrder(ctx context.Context, SoireeID int64, Sb ShoppingBasket, Token string) (svcdb.Order, error) {
+	userID, err := authenticatedUser(ctx, 0)
+	if err != nil {
+		return svcdb.Order{}, err
+	}
+	// the one ordering is one of the buyers
+	for _, buyer := range Sb.Buyers {
+		if buyer.UserID == userID {
+			return mw.next.SoireeOrder(ctx, SoireeID, Sb, Token)
+		}
+	}
+	return svcdb.Order{}, AuthError
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SoireeOrder(ctx context.Context, UserID, ConsoID, SoireeID int64, Token string) (int64, error) {
-	return mw.next.SoireeOrder(ctx, UserID, ConsoID, SoireeID, Token)
+func (mw serviceInstrumentingMiddleware) SoireeOrder(ctx context.Context, SoireeID int64, Sb ShoppingBasket, Token string) (svcdb.Order, error) {
+	return mw.next.SoireeOrder(ctx, SoireeID, Sb, Token)
 }
 
 /*************** Main ***************/
@@ -147,6 +196,7 @@ func BuildSoireeOrderEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = SoireeOrderEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "SoireeOrder")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -14,6 +14,8 @@ import (
 
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /* HTTP handlers & routing */
@@ -27,6 +29,13 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	/* Authentication */
 	LoginHTTPHandler(endpoints, tracer, logger, r, options)
 	RegisterHTTPHandler(endpoints, tracer, logger, r, options)
+	RefreshTokenHTTPHandler(endpoints, tracer, logger, r, options)
+	LogoutHTTPHandler(endpoints, tracer, logger, r, options)
+	LogoutAllHTTPHandler(endpoints, tracer, logger, r, options)
+	RequestPasswordResetHTTPHandler(endpoints, tracer, logger, r, options)
+	ResetPasswordHTTPHandler(endpoints, tracer, logger, r, options)
+	VerifyEmailHTTPHandler(endpoints, tracer, logger, r, options)
+	SendVerificationHTTPHandler(endpoints, tracer, logger, r, options)
 
 	/* User */
 	SearchUsersHTTPHandler(endpoints, tracer, logger, r, options)
@@ -48,6 +57,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 	/* Establishment */
 	SearchEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
+	SearchNearbyHTTPHandler(endpoints, tracer, logger, r, options)
 	GetAllEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
 	GetEstablishmentHTTPHandler(endpoints, tracer, logger, r, options)
 	GetMenuFromEstablishmentHTTPHandler(endpoints, tracer, logger, r, options)
@@ -81,6 +91,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	GetOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	CreateOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	AnswerOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	CancelOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	SearchOrdersHTTPHandler(endpoints, tracer, logger, r, options)
 	
 	return r
@@ -96,7 +107,7 @@ func setDefaultHeaders(w http.ResponseWriter) http.ResponseWriter {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Idempotency-Key")
 	return w
 }
 
@@ -108,12 +119,18 @@ func errorEncoder(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case ConnError:
 		code = http.StatusBadGateway
-	case AuthError:
+	case AuthError, UnverifiedError:
 		code = http.StatusForbidden
-	case RequestError:
+	case TokenError:
+		code = http.StatusUnauthorized
+	case RequestError, EmptyBasketErr, BasketAmountErr, BuyerNotJoinedErr, DrinkNotInMenuErr, svcdb.AccountTokenErr:
 		code = http.StatusBadRequest
 	case NotFoundError:
 		code = http.StatusNotFound
+	case OrderNotCancellableErr, svcdb.IdempotencyInProgressErr:
+		code = http.StatusConflict
+	case svcdb.IdempotencyConflictErr:
+		code = http.StatusUnprocessableEntity
 	}
 
 	w.WriteHeader(code)
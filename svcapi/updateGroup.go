@@ -109,6 +109,9 @@ func (mw serviceLoggingMiddleware) UpdateGroup(ctx context.Context, Groups svcdb
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdateGroup(ctx context.Context, Groups svcdb.Group) (svcdb.Group, error) {
+	if err := mw.groupUser(ctx, Groups.ID, true); err != nil {
+		return svcdb.Group{}, err
+	}
 	return mw.next.UpdateGroup(ctx, Groups)
 }
 
@@ -129,6 +132,7 @@ func BuildUpdateGroupEndpoint(svc IService, logger log.Logger, tracer stdopentra
 		csEndpoint = UpdateGroupEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "UpdateGroup")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -113,6 +113,10 @@ func (mw serviceLoggingMiddleware) UpdatePreferences(ctx context.Context, userID
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdatePreferences(ctx context.Context, userID int64, preferences []string) ([]string, error) {
+	var err error
+	if userID, err = authenticatedUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return mw.next.UpdatePreferences(ctx, userID, preferences)
 }
 
@@ -133,6 +137,7 @@ func BuildUpdatePreferencesEndpoint(svc IService, logger log.Logger, tracer stdo
 		csEndpoint = UpdatePreferencesEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "UpdatePreferences")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
@@ -139,6 +139,10 @@ func (mw serviceLoggingMiddleware) UpdateStripeUser(ctx context.Context, user sv
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdateStripeUser(ctx context.Context, user svcdb.User, token string) (svcdb.User, error) {
+	var err error
+	if user.ID, err = authenticatedUser(ctx, user.ID); err != nil {
+		return svcdb.User{}, err
+	}
 	return mw.next.UpdateStripeUser(ctx, user, token)
 }
 
@@ -159,6 +163,7 @@ func BuildUpdateStripeUserEndpoint(svc IService, logger log.Logger, tracer stdop
 		csEndpoint = UpdateStripeUserEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "UpdateStripeUser")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
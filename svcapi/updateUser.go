@@ -9,6 +9,7 @@ import (
 	"svcws"
 	"time"
 
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -35,7 +36,7 @@ func profileCompleteSuccess(s Service, ctx context.Context, userID int64) {
 	}
 
 	// Get user friends count
-	userFriends, err := s.svcdb.GetUserFriends(ctx, userID)
+	userFriends, _, err := s.svcdb.GetUserFriends(ctx, userID, svcdb.Page{Limit: 1})
 	if err != nil || len(userFriends) < 1 {
 		fmt.Println(err.Error())
 		return
@@ -133,7 +134,7 @@ func UpdateUserHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, lo
 		endpoints.UpdateUserEndpoint,
 		DecodeHTTPUpdateUserRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateUser", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateUser", logger), jwt.HTTPToContext()))...,
 	))
 	return route
 }
@@ -145,7 +146,7 @@ func (mw serviceLoggingMiddleware) UpdateUser(ctx context.Context, user svcdb.Us
 
 	mw.logger.Log(
 		"method", "UpdateUser",
-		"request", user,
+		"request", user.Redacted(),
 		"response", newUser,
 		"took", time.Since(time.Now()),
 	)
@@ -155,6 +156,10 @@ func (mw serviceLoggingMiddleware) UpdateUser(ctx context.Context, user svcdb.Us
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdateUser(ctx context.Context, user svcdb.User) (svcdb.User, error) {
+	var err error
+	if user.ID, err = authenticatedUser(ctx, user.ID); err != nil {
+		return svcdb.User{}, err
+	}
 	return mw.next.UpdateUser(ctx, user)
 }
 
@@ -175,6 +180,7 @@ func BuildUpdateUserEndpoint(svc IService, logger log.Logger, tracer stdopentrac
 		csEndpoint = UpdateUserEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "UpdateUser")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
 	return csEndpoint
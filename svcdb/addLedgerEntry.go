@@ -0,0 +1,194 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// AddLedgerEntry records a money movement. The ledger holds one entry per kind
+// and charge reference, or per refund when entry.Refund is set : recording it
+// again, e.g. when a webhook reports what svcpayment did already, returns the
+// first entry.
+func (s Service) AddLedgerEntry(ctx context.Context, entry LedgerEntry) (LedgerEntry, error) {
+	var stored LedgerEntry
+
+	if len(entry.Kind) == 0 || len(entry.Reference) == 0 {
+		return stored, RequestError
+	}
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	if len(entry.Currency) == 0 {
+		entry.Currency = DefaultCurrency
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("AddLedgerEntry (WaitConnection) : " + err.Error())
+		return stored, err
+	}
+	defer CloseConnection(conn)
+
+	key := `{Kind: {kind}, Reference: {reference}}`
+	if len(entry.Refund) > 0 {
+		key = `{Kind: {kind}, Reference: {reference}, Refund: {refund}}`
+	}
+	data, _, _, err := conn.QueryNeoAll(`
+		MERGE (l:LEDGER `+key+`)
+		ON CREATE SET l.Amount = {amount}, l.Currency = {currency}, l.Order = {order},
+			l.User = {user}, l.Pro = {pro}, l.Soiree = {soiree}, l.Date = {date}
+		RETURN l`, map[string]interface{}{
+		"kind":      entry.Kind,
+		"reference": entry.Reference,
+		"refund":    entry.Refund,
+		"amount":    entry.Amount,
+		"currency":  entry.Currency,
+		"order":     entry.OrderID,
+		"user":      entry.UserID,
+		"pro":       entry.ProID,
+		"soiree":    entry.SoireeID,
+		"date":      formatTime(entry.Date),
+	})
+	if err != nil {
+		fmt.Println("AddLedgerEntry (QueryNeoAll) : " + err.Error())
+		return stored, err
+	} else if len(data) == 0 {
+		return stored, RequestError
+	}
+	(&stored).NodeToLedgerEntry(data[0][0].(graph.Node))
+	return stored, nil
+}
+
+/*************** Endpoint ***************/
+type addLedgerEntryRequest struct {
+	Entry LedgerEntry `json:"entry"`
+}
+
+type addLedgerEntryResponse struct {
+	Entry LedgerEntry `json:"entry"`
+	Err   string      `json:"err,omitempty"`
+}
+
+func AddLedgerEntryEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addLedgerEntryRequest)
+		entry, err := svc.AddLedgerEntry(ctx, req.Entry)
+		if err != nil {
+			fmt.Println("Error AddLedgerEntryEndpoint : ", err.Error())
+			return addLedgerEntryResponse{Entry: entry, Err: err.Error()}, nil
+		}
+		return addLedgerEntryResponse{Entry: entry, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPAddLedgerEntryRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request addLedgerEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPAddLedgerEntryRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPAddLedgerEntryResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response addLedgerEntryResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPAddLedgerEntryResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func AddLedgerEntryHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/ledger").Handler(httptransport.NewServer(
+		endpoints.AddLedgerEntryEndpoint,
+		DecodeHTTPAddLedgerEntryRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AddLedgerEntry", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) AddLedgerEntry(ctx context.Context, entry LedgerEntry) (LedgerEntry, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "addLedgerEntry",
+			"kind", entry.Kind,
+			"reference", entry.Reference,
+			"amount", entry.Amount,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.AddLedgerEntry(ctx, entry)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) AddLedgerEntry(ctx context.Context, entry LedgerEntry) (LedgerEntry, error) {
+	v, err := mw.next.AddLedgerEntry(ctx, entry)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildAddLedgerEntryEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "AddLedgerEntry")
+		csLogger := log.With(logger, "method", "AddLedgerEntry")
+
+		csEndpoint = AddLedgerEntryEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "AddLedgerEntry")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) AddLedgerEntry(ctx context.Context, entry LedgerEntry) (LedgerEntry, error) {
+	var stored LedgerEntry
+
+	request := addLedgerEntryRequest{Entry: entry}
+	response, err := e.AddLedgerEntryEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error AddLedgerEntry : ", err.Error())
+		return stored, err
+	}
+	return response.(addLedgerEntryResponse).Entry, str2err(response.(addLedgerEntryResponse).Err)
+}
+
+func ClientAddLedgerEntry(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/ledger"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPAddLedgerEntryResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "AddLedgerEntry")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -25,7 +25,7 @@ import (
 func (s Service) AddSuccess(c context.Context, userID int64, successValue string) error {
 	var stmt golangNeo4jBoltDriver.Stmt
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("AddSuccess (WaitConnection) : " + err.Error())
 		return err
@@ -1,18 +1,269 @@
 package svcdb
 
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
 // Consommation
 type dataPointC struct {
-	
+	ConsoID		int64		`json:"consoID"`
+	Conso		string		`json:"conso"`
+	Bucket		time.Time	`json:"bucket"`	// hour the orders were issued
+	Quantity	int64		`json:"quantity"`
+	Revenue		int64		`json:"revenue"`	// cents
 }
 
 type AnalyseC struct {
-	ID		int64		`json:"id"`
-	Soiree	int64		`json:"soiree"`
-	Type	string		`json:"type"`
-	
+	ID			int64			`json:"id"`
+	Soiree		int64			`json:"soiree"`
+	Type		string			`json:"type"`
+	Values		[]dataPointC	`json:"values"`	// per conso and per bucket
+	Quantity	int64			`json:"quantity"`
+	Revenue		int64			`json:"revenue"`	// cents
+}
+
+// analysesC gathers the consos of completed orders per soiree, then per conso
+// and per hour, in the order soirees are first seen
+type analysesC struct {
+	analyses	[]AnalyseC
+	soirees		map[int64]int
+	points		map[int64]map[int64]map[time.Time]int
+}
+
+func newAnalysesC() analysesC {
+	return analysesC{
+		soirees:	make(map[int64]int),
+		points:		make(map[int64]map[int64]map[time.Time]int),
+	}
+}
+
+func (a *analysesC) add(soireeID int64, conso Conso, issued time.Time, amount int64) {
+	i, ok := a.soirees[soireeID]
+	if !ok {
+		i = len(a.analyses)
+		a.soirees[soireeID] = i
+		a.analyses = append(a.analyses, AnalyseC{Soiree: soireeID, Type: "Consommation"})
+		a.points[soireeID] = make(map[int64]map[time.Time]int)
+	}
+	analyse := &a.analyses[i]
+
+	bucket := issued.Truncate(time.Hour)
+	if a.points[soireeID][conso.ID] == nil {
+		a.points[soireeID][conso.ID] = make(map[time.Time]int)
+	}
+	j, ok := a.points[soireeID][conso.ID][bucket]
+	if !ok {
+		j = len(analyse.Values)
+		a.points[soireeID][conso.ID][bucket] = j
+		analyse.Values = append(analyse.Values, dataPointC{ConsoID: conso.ID, Conso: conso.Name, Bucket: bucket})
+	}
+
+	revenue := amount * conso.Price
+	analyse.Values[j].Quantity += amount
+	analyse.Values[j].Revenue += revenue
+	analyse.Quantity += amount
+	analyse.Revenue += revenue
+}
+
+// result sorts every analyse values by bucket, then by conso
+func (a analysesC) result() []AnalyseC {
+	for _, analyse := range a.analyses {
+		values := analyse.Values
+		sort.SliceStable(values, func(i, j int) bool {
+			if !values[i].Bucket.Equal(values[j].Bucket) {
+				return values[i].Bucket.Before(values[j].Bucket)
+			}
+			return values[i].ConsoID < values[j].ConsoID
+		})
+	}
+	return a.analyses
 }
 
 // Frequentation
+type dataPointF struct {
+	Hour		time.Time	`json:"hour"`
+	Present		int64		`json:"present"`	// users there at some point of the hour
+	Arrivals	int64		`json:"arrivals"`
+}
+
+type AnalyseF struct {
+	ID				int64			`json:"id"`
+	Soiree			int64			`json:"soiree"`
+	Type			string			`json:"type"`
+	From			time.Time		`json:"from"`
+	To				time.Time		`json:"to"`
+	Values			[]dataPointF	`json:"values"`	// per hour
+	Peak			int64			`json:"peak"`
+	PeakAt			time.Time		`json:"peakAt"`
+	AverageDwell	float64			`json:"averageDwell"`	// minutes
+	Visits			int64			`json:"visits"`
+}
+
+const MaxAnalyseRange = 31 * 24 * time.Hour
+
+var InvalidRangeErr = errors.New("Invalid date range")
+
+// presenceEvent is a JOIN or LEAVE of a user to a soiree
+type presenceEvent struct {
+	UserID		int64
+	SoireeID	int64
+	Join		bool
+	When		time.Time
+}
+
+// visit is the time a user spent in a soiree, from a JOIN to the next LEAVE
+type visit struct {
+	Begin	time.Time
+	End		time.Time
+}
+
+// visits pairs JOIN and LEAVE events, a user who never left being counted
+// until the end of the soiree (or now if it is still running)
+func visits(events []presenceEvent, soirees map[int64]Soiree, now time.Time) []visit {
+	var ret []visit
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].When.Before(events[j].When)
+	})
+
+	type key struct{ user, soiree int64 }
+	open := make(map[key]time.Time)
+	var order []key
+	for _, event := range events {
+		k := key{event.UserID, event.SoireeID}
+		begin, opened := open[k]
+		if event.Join && !opened {
+			open[k] = event.When
+			order = append(order, k)
+		} else if !event.Join && opened {
+			ret = append(ret, visit{begin, event.When})
+			delete(open, k)
+		}
+	}
+	for _, k := range order {
+		if begin, opened := open[k]; opened {
+			end := now
+			if soiree, ok := soirees[k.soiree]; ok && soiree.End.Before(now) {
+				end = soiree.End
+			}
+			if end.Before(begin) {
+				end = begin
+			}
+			ret = append(ret, visit{begin, end})
+			delete(open, k)
+		}
+	}
+	return ret
+}
+
+// frequentation computes the hourly curve, the peak occupancy and the average
+// dwell of the visits overlapping [from, to)
+func frequentation(visits []visit, from, to time.Time) AnalyseF {
+	var analyse AnalyseF
+	var dwell time.Duration
+
+	analyse.Type = "Frequentation"
+	analyse.From, analyse.To = from, to
+	for hour := from.Truncate(time.Hour); hour.Before(to); hour = hour.Add(time.Hour) {
+		analyse.Values = append(analyse.Values, dataPointF{Hour: hour})
+	}
+
+	type point struct {
+		When	time.Time
+		Delta	int64
+	}
+	var points []point
+	for _, v := range visits {
+		if !v.Begin.Before(to) || v.End.Before(from) {
+			continue
+		}
+		arrived := !v.Begin.Before(from)
+		if arrived {
+			analyse.Visits++
+			dwell += v.End.Sub(v.Begin)
+		}
+
+		begin, end := v.Begin, v.End
+		if begin.Before(from) {
+			begin = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		for i := range analyse.Values {
+			hour := analyse.Values[i].Hour
+			next := hour.Add(time.Hour)
+			if begin.Before(next) && (end.After(hour) || !begin.Before(hour)) {
+				analyse.Values[i].Present++
+			}
+			if arrived && !begin.Before(hour) && begin.Before(next) {
+				analyse.Values[i].Arrivals++
+			}
+		}
+		points = append(points, point{begin, 1}, point{end, -1})
+	}
+
+	if analyse.Visits > 0 {
+		analyse.AverageDwell = dwell.Minutes() / float64(analyse.Visits)
+	}
+
+	// Departures are counted before arrivals happening at the same time
+	sort.SliceStable(points, func(i, j int) bool {
+		if !points[i].When.Equal(points[j].When) {
+			return points[i].When.Before(points[j].When)
+		}
+		return points[i].Delta < points[j].Delta
+	})
+	var current int64
+	for _, p := range points {
+		current += p.Delta
+		if current > analyse.Peak {
+			analyse.Peak = current
+			analyse.PeakAt = p.When
+		}
+	}
+	return analyse
+}
+
+// RangeFromRequest reads the from and to RFC3339 query parameters, the last
+// 24 hours by default
+func RangeFromRequest(r *http.Request) (time.Time, time.Time, error) {
+	var err error
+
+	to := time.Now()
+	query := r.URL.Query()
+	if len(query.Get("to")) > 0 {
+		if to, err = time.Parse(time.RFC3339, query.Get("to")); err != nil {
+			return to, to, InvalidRangeErr
+		}
+	}
+	from := to.Add(-24 * time.Hour)
+	if len(query.Get("from")) > 0 {
+		if from, err = time.Parse(time.RFC3339, query.Get("from")); err != nil {
+			return from, to, InvalidRangeErr
+		}
+	}
+	if !validRange(from, to) {
+		return from, to, InvalidRangeErr
+	}
+	return from, to, nil
+}
+
+// EncodeRangeToRequest sets the query parameters read by RangeFromRequest
+func EncodeRangeToRequest(r *http.Request, from, to time.Time) {
+	query := r.URL.Query()
+	query.Set("from", from.Format(time.RFC3339))
+	query.Set("to", to.Format(time.RFC3339))
+	r.URL.RawQuery = query.Encode()
+}
+
+func validRange(from, to time.Time) bool {
+	return to.After(from) && to.Sub(from) <= MaxAnalyseRange
+}
+
 
 // Population
 type AnalyseP struct {
@@ -21,3 +272,37 @@ type AnalyseP struct {
 	Type	string		`json:"type"`
 	Values	map[string]int64	`json:"values"`
 }
+
+// Menu stats
+type MenuStat struct {
+	Menu				int64		`json:"menu"`
+	From				time.Time	`json:"from"`
+	To					time.Time	`json:"to"`
+	Orders				int64		`json:"orders"`		// issued in the range
+	Completed			int64		`json:"completed"`
+	Failed				int64		`json:"failed"`
+	Pending				int64		`json:"pending"`
+	Revenue				int64		`json:"revenue"`		// cents, of completed orders
+	AveragePreparation	float64		`json:"averagePreparation"`	// minutes, from Ready to Completed
+	prepared			int64
+}
+
+// add counts an order issued in the range, ready and completed being the
+// dates its steps were opened, zero when not reached
+func (stat *MenuStat) add(order Order, ready, completed time.Time) {
+	stat.Orders++
+	switch order.Done {
+	case "true":
+		stat.Completed++
+		stat.Revenue += order.Price
+		if !ready.IsZero() && !completed.IsZero() && !completed.Before(ready) {
+			stat.prepared++
+			preparation := completed.Sub(ready).Minutes()
+			stat.AveragePreparation += (preparation - stat.AveragePreparation) / float64(stat.prepared)
+		}
+	case "false":
+		stat.Failed++
+	default:
+		stat.Pending++
+	}
+}
@@ -24,7 +24,7 @@ import (
 func (s Service) AnswerOrder(ctx context.Context, orderID int64, userID int64, answer bool) (Order, error) {
     var order Order
 
-    conn, err := WaitConnection(5)
+    conn, err := WaitConnection(ctx, 5)
     if err != nil {
 		fmt.Println("AnswerOrder (WaitConnection) : " + err.Error())
 		return order, err
@@ -0,0 +1,206 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+// CancelledStep is the STEP recording who cancelled an order, it is not part
+// of OrderWorkflow and is created already closed
+const CancelledStep = "Cancelled"
+
+var StepNotOpenErr = errors.New("Target step is not open anymore")
+
+/*************** Service ***************/
+// CancelOrder fails the order if step is still its open step, and records the
+// cancellation by userID in place of any pending RequestCancel. Checking the step in the same transaction keeps a
+// concurrent PutOrder from moving the order past it meanwhile.
+func (s Service) CancelOrder(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("CancelOrder (WaitConnection) : " + err.Error())
+		return order, err
+	}
+	defer CloseConnection(conn)
+
+	reqs := []string{`
+        MATCH (o:ORDER)-[:DONE]->(st:STEP)
+        WHERE ID(o) = {oid} AND (NOT EXISTS(o.Done) OR o.Done = "")
+        AND st.Name = {stname} AND (NOT EXISTS(st.Result) OR st.Result = "")
+        SET o.Done = "false", st.Result = "false"
+        REMOVE o.CancelRequest
+        RETURN ID(o)
+    `, `
+        MATCH (o:ORDER) WHERE ID(o) = {oid}
+        CREATE (st:STEP {Name: {stname}, Date: {stdate}, Result: "true", By: {uid}}),
+        (o)-[:DONE]->(st)
+        RETURN ID(st)
+    `}
+	argss := []map[string]interface{}{{
+		"oid":    orderID,
+		"stname": step,
+	}, {
+		"oid":    orderID,
+		"stname": CancelledStep,
+		"stdate": formatTime(time.Now()),
+		"uid":    userID,
+	}}
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		_, err := ExecBatch(conn, reqs, argss)
+		return err
+	})
+	if err == io.EOF {
+		return order, StepNotOpenErr
+	} else if err != nil {
+		fmt.Println("CancelOrder (Transaction) : " + err.Error())
+		return order, err
+	}
+
+	order, err = s.GetOrder(ctx, orderID)
+	if err != nil {
+		fmt.Println("CancelOrder (GetOrder) : " + err.Error())
+		return order, err
+	}
+	return order, nil
+}
+
+/*************** Endpoint ***************/
+type cancelOrderRequest struct {
+	OrderID int64  `json:"order"`
+	UserID  int64  `json:"user"`
+	Step    string `json:"step"`
+}
+
+type cancelOrderResponse struct {
+	Order Order  `json:"order"`
+	Err   string `json:"err,omitempty"`
+}
+
+func CancelOrderEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(cancelOrderRequest)
+		order, err := svc.CancelOrder(ctx, req.OrderID, req.UserID, req.Step)
+		if err != nil {
+			fmt.Println("Error CancelOrderEndpoint : ", err.Error())
+			return cancelOrderResponse{Order: order, Err: err.Error()}, nil
+		}
+		return cancelOrderResponse{Order: order, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPCancelOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request cancelOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPCancelOrderRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPCancelOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response cancelOrderResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPCancelOrderResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func CancelOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/cancel").Handler(httptransport.NewServer(
+		endpoints.CancelOrderEndpoint,
+		DecodeHTTPCancelOrderRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CancelOrder", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "cancelOrder",
+			"orderID", orderID,
+			"userID", userID,
+			"step", step,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.CancelOrder(ctx, orderID, userID, step)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	v, err := mw.next.CancelOrder(ctx, orderID, userID, step)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildCancelOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "CancelOrder")
+		csLogger := log.With(logger, "method", "CancelOrder")
+
+		csEndpoint = CancelOrderEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "CancelOrder")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) CancelOrder(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+
+	request := cancelOrderRequest{OrderID: orderID, UserID: userID, Step: step}
+	response, err := e.CancelOrderEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error CancelOrder : ", err.Error())
+		return order, err
+	}
+	return response.(cancelOrderResponse).Order, str2err(response.(cancelOrderResponse).Err)
+}
+
+func ClientCancelOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/cancel"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPCancelOrderResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "CancelOrder")(ceEndpoint)
+	return ceEndpoint, nil
+}
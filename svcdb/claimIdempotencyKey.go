@@ -0,0 +1,218 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// ClaimIdempotencyKey claims key for the request of fingerprint. When the key
+// is claimed already, false is returned with what is stored for it. Keys older
+// than IdempotencyTTL, and keys left without response for IdempotencyLease,
+// are claimed again.
+func (s Service) ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string) (IdempotentResponse, bool, error) {
+	var stored IdempotentResponse
+	var claimed bool
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("ClaimIdempotencyKey (WaitConnection) : " + err.Error())
+		return stored, false, err
+	}
+	defer CloseConnection(conn)
+
+	now := time.Now()
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		_, err := conn.ExecNeo(`
+			MATCH (k:IDEMPOTENCY {Key: {key}})
+			WHERE k.Date < {expired} OR (k.Response = "" AND k.Date < {abandoned})
+			DELETE k`, map[string]interface{}{
+			"key":       key,
+			"expired":   formatTime(now.Add(-IdempotencyTTL)),
+			"abandoned": formatTime(now.Add(-IdempotencyLease)),
+		})
+		if err != nil {
+			return err
+		}
+
+		data, _, _, err := conn.QueryNeoAll(`
+			MERGE (k:IDEMPOTENCY {Key: {key}})
+			ON CREATE SET k.Fingerprint = {fingerprint}, k.Date = {date}, k.Response = "", k.Claimed = true
+			ON MATCH SET k.Claimed = false
+			RETURN k, k.Claimed`, map[string]interface{}{
+			"key":         key,
+			"fingerprint": fingerprint,
+			"date":        formatTime(now),
+		})
+		if err != nil {
+			return err
+		} else if len(data) == 0 {
+			return fmt.Errorf("No idempotency key %q", key)
+		}
+		(&stored).NodeToIdempotentResponse(data[0][0].(graph.Node))
+		claimed, _ = data[0][1].(bool)
+		return nil
+	})
+	if isConstraintViolation(err) {
+		/* A concurrent retry claimed the key first */
+		return storedIdempotencyKey(conn, key)
+	} else if err != nil {
+		fmt.Println("ClaimIdempotencyKey (Transaction) : " + err.Error())
+		return stored, false, err
+	}
+	return stored, claimed, nil
+}
+
+// storedIdempotencyKey returns what is stored for key, claimed by another
+// request
+func storedIdempotencyKey(conn bolt.Conn, key string) (IdempotentResponse, bool, error) {
+	var stored IdempotentResponse
+
+	data, _, _, err := conn.QueryNeoAll(`MATCH (k:IDEMPOTENCY {Key: {key}}) RETURN k`, map[string]interface{}{
+		"key": key,
+	})
+	if err != nil {
+		fmt.Println("storedIdempotencyKey (QueryNeoAll) : " + err.Error())
+		return stored, false, err
+	} else if len(data) == 0 {
+		return stored, false, fmt.Errorf("No idempotency key %q", key)
+	}
+	(&stored).NodeToIdempotentResponse(data[0][0].(graph.Node))
+	return stored, false, nil
+}
+
+/*************** Endpoint ***************/
+type claimIdempotencyKeyRequest struct {
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type claimIdempotencyKeyResponse struct {
+	Stored  IdempotentResponse `json:"stored"`
+	Claimed bool               `json:"claimed"`
+	Err     string             `json:"err,omitempty"`
+}
+
+func ClaimIdempotencyKeyEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(claimIdempotencyKeyRequest)
+		stored, claimed, err := svc.ClaimIdempotencyKey(ctx, req.Key, req.Fingerprint)
+		if err != nil {
+			fmt.Println("Error ClaimIdempotencyKeyEndpoint : ", err.Error())
+			return claimIdempotencyKeyResponse{Stored: stored, Claimed: claimed, Err: err.Error()}, nil
+		}
+		return claimIdempotencyKeyResponse{Stored: stored, Claimed: claimed, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPClaimIdempotencyKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request claimIdempotencyKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPClaimIdempotencyKeyRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPClaimIdempotencyKeyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response claimIdempotencyKeyResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPClaimIdempotencyKeyResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ClaimIdempotencyKeyHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/idempotency/claim").Handler(httptransport.NewServer(
+		endpoints.ClaimIdempotencyKeyEndpoint,
+		DecodeHTTPClaimIdempotencyKeyRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ClaimIdempotencyKey", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string) (IdempotentResponse, bool, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "claimIdempotencyKey",
+			"key", key,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.ClaimIdempotencyKey(ctx, key, fingerprint)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string) (IdempotentResponse, bool, error) {
+	v, claimed, err := mw.next.ClaimIdempotencyKey(ctx, key, fingerprint)
+	mw.ints.Add(1)
+	return v, claimed, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildClaimIdempotencyKeyEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ClaimIdempotencyKey")
+		csLogger := log.With(logger, "method", "ClaimIdempotencyKey")
+
+		csEndpoint = ClaimIdempotencyKeyEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ClaimIdempotencyKey")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string) (IdempotentResponse, bool, error) {
+	var stored IdempotentResponse
+
+	request := claimIdempotencyKeyRequest{Key: key, Fingerprint: fingerprint}
+	response, err := e.ClaimIdempotencyKeyEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error ClaimIdempotencyKey : ", err.Error())
+		return stored, false, err
+	}
+	r := response.(claimIdempotencyKeyResponse)
+	return r.Stored, r.Claimed, str2err(r.Err)
+}
+
+func ClientClaimIdempotencyKey(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/idempotency/claim"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPClaimIdempotencyKeyResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ClaimIdempotencyKey")(ceEndpoint)
+	return ceEndpoint, nil
+}
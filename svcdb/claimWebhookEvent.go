@@ -0,0 +1,171 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// ClaimWebhookEvent records an event received from the payment provider and
+// returns false when it was already recorded, i.e. when it is a retry of an
+// event handled before.
+func (s Service) ClaimWebhookEvent(ctx context.Context, eventID string, eventType string) (bool, error) {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("ClaimWebhookEvent (WaitConnection) : " + err.Error())
+		return false, err
+	}
+	defer CloseConnection(conn)
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MERGE (e:WEBHOOK {EventID: {id}})
+		ON CREATE SET e.Type = {type}, e.Date = {date}, e.Claimed = true
+		ON MATCH SET e.Claimed = false
+		RETURN e.Claimed`, map[string]interface{}{
+		"id":   eventID,
+		"type": eventType,
+		"date": formatTime(time.Now()),
+	})
+	if isConstraintViolation(err) {
+		/* A concurrent delivery of the event recorded it first */
+		return false, nil
+	} else if err != nil {
+		fmt.Println("ClaimWebhookEvent (QueryNeoAll) : " + err.Error())
+		return false, err
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	claimed, _ := data[0][0].(bool)
+	return claimed, nil
+}
+
+/*************** Endpoint ***************/
+type claimWebhookEventRequest struct {
+	EventID string `json:"event"`
+	Type    string `json:"type"`
+}
+
+type claimWebhookEventResponse struct {
+	Claimed bool   `json:"claimed"`
+	Err     string `json:"err,omitempty"`
+}
+
+func ClaimWebhookEventEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(claimWebhookEventRequest)
+		claimed, err := svc.ClaimWebhookEvent(ctx, req.EventID, req.Type)
+		if err != nil {
+			fmt.Println("Error ClaimWebhookEventEndpoint : ", err.Error())
+			return claimWebhookEventResponse{Claimed: claimed, Err: err.Error()}, nil
+		}
+		return claimWebhookEventResponse{Claimed: claimed, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPClaimWebhookEventRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request claimWebhookEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPClaimWebhookEventRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPClaimWebhookEventResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response claimWebhookEventResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPClaimWebhookEventResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ClaimWebhookEventHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/webhooks/claim").Handler(httptransport.NewServer(
+		endpoints.ClaimWebhookEventEndpoint,
+		DecodeHTTPClaimWebhookEventRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ClaimWebhookEvent", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ClaimWebhookEvent(ctx context.Context, eventID string, eventType string) (bool, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "claimWebhookEvent",
+			"eventID", eventID,
+			"type", eventType,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.ClaimWebhookEvent(ctx, eventID, eventType)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ClaimWebhookEvent(ctx context.Context, eventID string, eventType string) (bool, error) {
+	v, err := mw.next.ClaimWebhookEvent(ctx, eventID, eventType)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildClaimWebhookEventEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ClaimWebhookEvent")
+		csLogger := log.With(logger, "method", "ClaimWebhookEvent")
+
+		csEndpoint = ClaimWebhookEventEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ClaimWebhookEvent")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) ClaimWebhookEvent(ctx context.Context, eventID string, eventType string) (bool, error) {
+	request := claimWebhookEventRequest{EventID: eventID, Type: eventType}
+	response, err := e.ClaimWebhookEventEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error ClaimWebhookEvent : ", err.Error())
+		return false, err
+	}
+	return response.(claimWebhookEventResponse).Claimed, str2err(response.(claimWebhookEventResponse).Err)
+}
+
+func ClientClaimWebhookEvent(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/webhooks/claim"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPClaimWebhookEventResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ClaimWebhookEvent")(ceEndpoint)
+	return ceEndpoint, nil
+}
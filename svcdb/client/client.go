@@ -26,6 +26,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 	clientGetProByID, err := svcdb.ClientGetProByID(u, logger, tracer)
 	clientGetProBySoiree, err := svcdb.ClientGetProBySoiree(u, logger, tracer)
 	clientUpdatePro, err := svcdb.ClientUpdatePro(u, logger, tracer)
+	clientUpdateProStripeStatus, err := svcdb.ClientUpdateProStripeStatus(u, logger, tracer)
 	clientGetProEstablishments, err := svcdb.ClientGetProEstablishments(u, logger, tracer)
 
 	/* User */
@@ -42,6 +43,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 	/* Friends */
 	clientGetUserFriends, err := svcdb.ClientGetUserFriends(u, logger, tracer)
 	clientGetUsersInvitations, err := svcdb.ClientGetUsersInvitations(u, logger, tracer)
+	clientGetInvitation, err := svcdb.ClientGetInvitation(u, logger, tracer)
 	clientInviteFriend, err := svcdb.ClientInviteFriend(u, logger, tracer)
 	clientInvitationAccept, err := svcdb.ClientInvitationAccept(u, logger, tracer)
 	clientInvitationDecline, err := svcdb.ClientInvitationDecline(u, logger, tracer)
@@ -53,6 +55,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 	clientCreateEstablishment, err := svcdb.ClientCreateEstablishment(u, logger, tracer)
 	clientGetEstablishment, err := svcdb.ClientGetEstablishment(u, logger, tracer)
 	clientSearchEstablishment, err := svcdb.ClientSearchEstablishments(u, logger, tracer)
+	clientSearchNearby, err := svcdb.ClientSearchNearby(u, logger, tracer)
 	clientGetEstablishments, err := svcdb.ClientGetEstablishments(u, logger, tracer)
 	clientGetEstablishmentFromMenu, err := svcdb.ClientGetEstablishmentFromMenu(u, logger, tracer)
 	clientGetMenuFromEstablishment, err := svcdb.ClientGetMenuFromEstablishment(u, logger, tracer)
@@ -80,6 +83,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 	clientGetEstablishmentMenus, err := svcdb.ClientGetEstablishmentMenus(u, logger, tracer)
 	clientGetMenuConsos, err := svcdb.ClientGetMenuConsos(u, logger, tracer)
 	clientGetMenuFromSoiree, err := svcdb.ClientGetMenuFromSoiree(u, logger, tracer)
+	clientImportMenus, err := svcdb.ClientImportMenus(u, logger, tracer)
 
 	/* Groups */
 	clientGetGroupEndpoint, err := svcdb.ClientGetGroup(u, logger, tracer)
@@ -100,7 +104,13 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 	clientPutOrderEndpoint, err := svcdb.ClientPutOrder(u, logger, tracer)
 	clientAnswerOrderEndpoint, err := svcdb.ClientAnswerOrder(u, logger, tracer)
 	clientFailOrderEndpoint, err := svcdb.ClientFailOrder(u, logger, tracer)
+	clientCancelOrderEndpoint, err := svcdb.ClientCancelOrder(u, logger, tracer)
+	clientRequestCancelEndpoint, err := svcdb.ClientRequestCancel(u, logger, tracer)
+	clientGetPendingOrdersEndpoint, err := svcdb.ClientGetPendingOrders(u, logger, tracer)
 	clientUpdateOrderReferenceEndpoint, err := svcdb.ClientUpdateOrderReference(u, logger, tracer)
+	clientGetOrderByReferenceEndpoint, err := svcdb.ClientGetOrderByReference(u, logger, tracer)
+	clientGetChargedOrdersEndpoint, err := svcdb.ClientGetChargedOrders(u, logger, tracer)
+	clientFlagOrderEndpoint, err := svcdb.ClientFlagOrder(u, logger, tracer)
 
 	clientUserOrder, err := svcdb.ClientUserOrder(u, logger, tracer)
 	clientGetOrdersBySoiree, err := svcdb.ClientGetOrdersBySoiree(u, logger, tracer)
@@ -118,6 +128,33 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 
 	/* Analyse */
 	clientGetAnalysePEndpoint, err := svcdb.ClientGetAnalyseP(u, logger, tracer)
+	clientGetAnalyseCEndpoint, err := svcdb.ClientGetAnalyseC(u, logger, tracer)
+	clientGetAnalyseFEndpoint, err := svcdb.ClientGetAnalyseF(u, logger, tracer)
+	clientGetMenuStatEndpoint, err := svcdb.ClientGetMenuStat(u, logger, tracer)
+
+	/* Webhook */
+	clientClaimWebhookEventEndpoint, err := svcdb.ClientClaimWebhookEvent(u, logger, tracer)
+	clientReleaseWebhookEventEndpoint, err := svcdb.ClientReleaseWebhookEvent(u, logger, tracer)
+
+	/* Idempotency */
+	clientClaimIdempotencyKeyEndpoint, err := svcdb.ClientClaimIdempotencyKey(u, logger, tracer)
+	clientStoreIdempotencyKeyEndpoint, err := svcdb.ClientStoreIdempotencyKey(u, logger, tracer)
+
+	/* Ledger */
+	clientAddLedgerEntryEndpoint, err := svcdb.ClientAddLedgerEntry(u, logger, tracer)
+	clientGetLedgerEndpoint, err := svcdb.ClientGetLedger(u, logger, tracer)
+
+	/* Tokens */
+	clientCreateRefreshTokenEndpoint, err := svcdb.ClientCreateRefreshToken(u, logger, tracer)
+	clientUseRefreshTokenEndpoint, err := svcdb.ClientUseRefreshToken(u, logger, tracer)
+	clientRevokeTokensEndpoint, err := svcdb.ClientRevokeTokens(u, logger, tracer)
+	clientIsTokenRevokedEndpoint, err := svcdb.ClientIsTokenRevoked(u, logger, tracer)
+	clientCreateAccountTokenEndpoint, err := svcdb.ClientCreateAccountToken(u, logger, tracer)
+
+	/* Accounts */
+	clientGetAccountByEmailEndpoint, err := svcdb.ClientGetAccountByEmail(u, logger, tracer)
+	clientResetPasswordEndpoint, err := svcdb.ClientResetPassword(u, logger, tracer)
+	clientVerifyEmailEndpoint, err := svcdb.ClientVerifyEmail(u, logger, tracer)
 
 	return svcdb.Endpoints{
 		/* Pro */
@@ -127,6 +164,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 		GetProByIDEndpoint:           clientGetProByID,
 		GetProBySoireeEndpoint:       clientGetProBySoiree,
 		UpdateProEndpoint:            clientUpdatePro,
+		UpdateProStripeStatusEndpoint: clientUpdateProStripeStatus,
 		GetProEstablishmentsEndpoint: clientGetProEstablishments,
 
 		/* User */
@@ -145,6 +183,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 		GetUserFriendsEndpoint:      clientGetUserFriends,
 		InviteFriendEndpoint:        clientInviteFriend,
 		GetUsersInvitationsEndpoint: clientGetUsersInvitations,
+		GetInvitationEndpoint:       clientGetInvitation,
 		InvitationAcceptEndpoint:    clientInvitationAccept,
 		InvitationDeclineEndpoint:   clientInvitationDecline,
 		UsersConnectedEndpoint:      clientUsersConnected,
@@ -153,6 +192,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 		/* Establishment */
 		CreateEstablishmentEndpoint:      clientCreateEstablishment,
 		SearchEstablishmentsEndpoint:     clientSearchEstablishment,
+		SearchNearbyEndpoint:             clientSearchNearby,
 		GetEstablishmentsEndpoint:        clientGetEstablishments,
 		GetEstablishmentEndpoint:         clientGetEstablishment,
 		GetEstablishmentFromMenuEndpoint: clientGetEstablishmentFromMenu,
@@ -184,6 +224,7 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 		CreateMenuEndpoint:             clientCreateMenuEndpoint,
 		CreateConsoEndpoint:            clientCreateConsoEndpoint,
 		GetMenuFromSoireeEndpoint:      clientGetMenuFromSoiree,
+		ImportMenusEndpoint:            clientImportMenus,
 		GetConsoByOrderIDEndpoint:      clientGetConsoByOrderID,
 
 		/* Group */
@@ -205,7 +246,13 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 		PutOrderEndpoint:     clientPutOrderEndpoint,
 		AnswerOrderEndpoint:  clientAnswerOrderEndpoint,
 		FailOrderEndpoint:    clientFailOrderEndpoint,
+		CancelOrderEndpoint:  clientCancelOrderEndpoint,
+		RequestCancelEndpoint:        clientRequestCancelEndpoint,
+		GetPendingOrdersEndpoint: clientGetPendingOrdersEndpoint,
 		UpdateOrderReferenceEndpoint: clientUpdateOrderReferenceEndpoint,
+		GetOrderByReferenceEndpoint:  clientGetOrderByReferenceEndpoint,
+		GetChargedOrdersEndpoint:     clientGetChargedOrdersEndpoint,
+		FlagOrderEndpoint:            clientFlagOrderEndpoint,
 
 		/* Conversation */
 		GetLastMessagesEndpoint:     clientGetLastMessages,
@@ -218,6 +265,33 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcd
 
 		/* Analyse */
 		GetAnalysePEndpoint: clientGetAnalysePEndpoint,
+		GetAnalyseCEndpoint: clientGetAnalyseCEndpoint,
+		GetAnalyseFEndpoint: clientGetAnalyseFEndpoint,
+		GetMenuStatEndpoint: clientGetMenuStatEndpoint,
+
+		/* Webhook */
+		ClaimWebhookEventEndpoint:   clientClaimWebhookEventEndpoint,
+		ReleaseWebhookEventEndpoint: clientReleaseWebhookEventEndpoint,
+
+		/* Idempotency */
+		ClaimIdempotencyKeyEndpoint: clientClaimIdempotencyKeyEndpoint,
+		StoreIdempotencyKeyEndpoint: clientStoreIdempotencyKeyEndpoint,
+
+		/* Ledger */
+		AddLedgerEntryEndpoint: clientAddLedgerEntryEndpoint,
+		GetLedgerEndpoint:      clientGetLedgerEndpoint,
+
+		/* Tokens */
+		CreateRefreshTokenEndpoint: clientCreateRefreshTokenEndpoint,
+		UseRefreshTokenEndpoint:    clientUseRefreshTokenEndpoint,
+		RevokeTokensEndpoint:       clientRevokeTokensEndpoint,
+		IsTokenRevokedEndpoint:     clientIsTokenRevokedEndpoint,
+		CreateAccountTokenEndpoint: clientCreateAccountTokenEndpoint,
+
+		/* Accounts */
+		GetAccountByEmailEndpoint: clientGetAccountByEmailEndpoint,
+		ResetPasswordEndpoint:     clientResetPasswordEndpoint,
+		VerifyEmailEndpoint:       clientVerifyEmailEndpoint,
 	}, nil
 
 }
@@ -4,13 +4,14 @@ import (
 	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
 )
 
-// Conso model
+// Conso model. Price is in cents, sent as price_cents since the price field
+// held euros.
 type Conso struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Price       float64 `json:"price"`
-	Description string  `json:"description"`
-	Picture     string  `json:"picture,omitempty"`
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Price       int64  `json:"price_cents"`
+	Description string `json:"description"`
+	Picture     string `json:"picture,omitempty"`
 }
 
 // Consos Conso array
@@ -19,11 +20,7 @@ type Consos []Conso
 func (c *Conso) NodeToConso(node graph.Node) {
 	c.ID = node.NodeIdentity
 	c.Name = node.Properties["Name"].(string)
-	if price, ok := node.Properties["Price"].(float64); ok {
-		c.Price = price
-	} else if price, ok := node.Properties["Price"].(int64); ok {
-		c.Price = float64(price)
-	}
+	c.Price = consoMoney(node.Properties)
 	if node.Properties["Desc"] != nil {
 		c.Description = node.Properties["Desc"].(string)
 	}
@@ -0,0 +1,40 @@
+package svcdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// uniqueProperties are the keys the claims MERGE on. Without a uniqueness
+// constraint two concurrent MERGE can both create their node, and both claim.
+var uniqueProperties = []storedProperty{
+	{Label: "IDEMPOTENCY", Key: "Key"},
+	{Label: "WEBHOOK", Key: "EventID"},
+}
+
+// CreateConstraints creates the uniqueness constraints of uniqueProperties,
+// the ones existing already being left as is. It is run when svcdb starts.
+func CreateConstraints(ctx context.Context) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("CreateConstraints (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	for _, property := range uniqueProperties {
+		_, err = conn.ExecNeo(`CREATE CONSTRAINT ON (n:`+property.Label+`) ASSERT n.`+property.Key+` IS UNIQUE`, nil)
+		if err != nil {
+			fmt.Println("CreateConstraints (ExecNeo) : " + err.Error())
+			return DriverErr{Op: "CreateConstraints " + property.Label + "." + property.Key, Err: err}
+		}
+	}
+	return nil
+}
+
+// isConstraintViolation tells whether err is Neo4j refusing a node that breaks
+// a uniqueness constraint, i.e. a concurrent request created it first
+func isConstraintViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "ConstraintValidationFailed")
+}
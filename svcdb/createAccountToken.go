@@ -0,0 +1,203 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// CreateAccountToken stores a token about to be mailed. It replaces the tokens
+// of the same subject and purpose, only the last one mailed can be used. The
+// account tokens that expired meanwhile are purged.
+func (s Service) CreateAccountToken(ctx context.Context, token AccountToken) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("CreateAccountToken (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		_, err := conn.ExecNeo(`
+			MATCH (t:ACCOUNT_TOKEN)
+			WHERE t.Expires < {now} OR (t.Subject = {subject} AND t.Purpose = {purpose})
+			DELETE t`, map[string]interface{}{
+			"now":     formatTime(time.Now()),
+			"subject": token.Subject,
+			"purpose": token.Purpose,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = conn.ExecNeo(`
+			CREATE (:ACCOUNT_TOKEN {Hash: {hash}, Subject: {subject}, Purpose: {purpose}, Email: {email}, Expires: {expires}})`, map[string]interface{}{
+			"hash":    token.Hash,
+			"subject": token.Subject,
+			"purpose": token.Purpose,
+			"email":   token.Email,
+			"expires": formatTime(token.Expires),
+		})
+		return err
+	})
+	if err != nil {
+		fmt.Println("CreateAccountToken (Transaction) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// useAccountToken consumes the account token of hash stored for purpose. It is
+// read and deleted by the same statement, a token can't be used twice.
+func useAccountToken(conn bolt.Conn, hash string, purpose string) (AccountToken, error) {
+	var token AccountToken
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (t:ACCOUNT_TOKEN {Hash: {hash}, Purpose: {purpose}}) WHERE t.Expires >= {now}
+		WITH t, t.Subject AS subject, t.Email AS email, t.Expires AS expires
+		DELETE t
+		RETURN subject, email, expires`, map[string]interface{}{
+		"hash":    hash,
+		"purpose": purpose,
+		"now":     formatTime(time.Now()),
+	})
+	if err != nil {
+		return token, err
+	} else if len(data) == 0 {
+		return token, AccountTokenErr
+	}
+	token.Hash = hash
+	token.Purpose = purpose
+	token.Subject, _ = data[0][0].(string)
+	token.Email, _ = data[0][1].(string)
+	token.Expires = storedTime("useAccountToken", "Expires", data[0][2])
+	return token, nil
+}
+
+/*************** Endpoint ***************/
+type createAccountTokenRequest struct {
+	Token AccountToken `json:"token"`
+}
+
+type createAccountTokenResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func CreateAccountTokenEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createAccountTokenRequest)
+		err := svc.CreateAccountToken(ctx, req.Token)
+		if err != nil {
+			fmt.Println("Error CreateAccountTokenEndpoint : ", err.Error())
+			return createAccountTokenResponse{Err: err.Error()}, nil
+		}
+		return createAccountTokenResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPCreateAccountTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request createAccountTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPCreateAccountTokenRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPCreateAccountTokenResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response createAccountTokenResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPCreateAccountTokenResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func CreateAccountTokenHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/tokens/account").Handler(httptransport.NewServer(
+		endpoints.CreateAccountTokenEndpoint,
+		DecodeHTTPCreateAccountTokenRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateAccountToken", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) CreateAccountToken(ctx context.Context, token AccountToken) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "createAccountToken",
+			"subject", token.Subject,
+			"purpose", token.Purpose,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.CreateAccountToken(ctx, token)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) CreateAccountToken(ctx context.Context, token AccountToken) error {
+	err := mw.next.CreateAccountToken(ctx, token)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildCreateAccountTokenEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "CreateAccountToken")
+		csLogger := log.With(logger, "method", "CreateAccountToken")
+
+		csEndpoint = CreateAccountTokenEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "CreateAccountToken")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) CreateAccountToken(ctx context.Context, token AccountToken) error {
+	response, err := e.CreateAccountTokenEndpoint(ctx, createAccountTokenRequest{Token: token})
+	if err != nil {
+		fmt.Println("Error CreateAccountToken : ", err.Error())
+		return err
+	}
+	return str2err(response.(createAccountTokenResponse).Err)
+}
+
+func ClientCreateAccountToken(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/tokens/account"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPCreateAccountTokenResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "CreateAccountToken")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -24,7 +24,7 @@ import (
 func (s Service) CreateConso(ctx context.Context, establishmentID, menuID int64, c Conso) (Conso, error) {
 	var conso Conso
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("CreateConso (WaitConnection) : " + err.Error())
 		return conso, err
@@ -37,6 +37,7 @@ func (s Service) CreateConso(ctx context.Context, establishmentID, menuID int64,
 			Name: {name},
 			Desc: {desc},
 			Price: {price},
+			Currency: {currency},
 			Picture: {picture}
 		}),
 		(et)-[:GOT]->(c),
@@ -50,12 +51,13 @@ func (s Service) CreateConso(ctx context.Context, establishmentID, menuID int64,
 	}
 
 	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"eid":     establishmentID,
-		"mid":     menuID,
-		"name":    c.Name,
-		"desc":    c.Description,
-		"price":   c.Price,
-		"picture": c.Picture,
+		"eid":      establishmentID,
+		"mid":      menuID,
+		"name":     c.Name,
+		"desc":     c.Description,
+		"price":    c.Price,
+		"currency": DefaultCurrency,
+		"picture":  c.Picture,
 	})
 
 	if err != nil {
@@ -21,10 +21,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) CreateEstablishment(_ context.Context, e Establishment, proID int64) (Establishment, error) {
+func (s Service) CreateEstablishment(ctx context.Context, e Establishment, proID int64) (Establishment, error) {
 	var establishment Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("CreateEstablishment (WaitConnection) : " + err.Error())
 		return establishment, err
@@ -24,7 +24,7 @@ import (
 func (s Service) CreateGroup(c context.Context, g Group, userID int64) (Group, error) {
 	var group Group
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreateGroup (WaitConnection) : " + err.Error())
 		return group, err
@@ -24,7 +24,7 @@ import (
 func (s Service) CreateMenu(c context.Context, establishmentID int64, u Menu) (Menu, error) {
 	var menu Menu
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreateMenu (WaitConnection) : " + err.Error())
 		return menu, err
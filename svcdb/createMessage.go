@@ -29,7 +29,7 @@ func (s Service) CreateMessage(c context.Context, nodeType string, m Message) (M
 	var message Message
 	var stmt golangNeo4jBoltDriver.Stmt
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreateMessage (WaitConnection) : " + err.Error())
 		return message, err
@@ -76,7 +76,7 @@ func (s Service) CreateMessage(c context.Context, nodeType string, m Message) (M
 		"initID":   m.From,
 		"friendID": m.To,
 		"message":  m.Text,
-		"time":     time.Now().Format(timeForm),
+		"time":     formatTime(time.Now()),
 	})
 
 	if err != nil {
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
 	"errors"
 
 	"time"
 
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -24,91 +25,91 @@ import (
 /*************** Service ***************/
 func (s Service) CreateOrder(ctx context.Context, o Order) (Order, error) {
 	var order Order
-	var req, reqMatch, reqWhere, reqCreate string
+	var reqs []string
+	var argss []map[string]interface{}
 	var sumPrice int64
 
-	i := 0
-	args := make(map[string]interface{})
-
-	// soiree, err := s.GetSoireeByID(
+	/* Price check */
+	for _, user := range o.Users {
+		sumPrice += user.Price
+	}
+	if sumPrice != o.Price {
+		return order, errors.New("CreateOrder (Price Check) : sum of price in users != order.Price")
+	}
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("CreateOrder (WaitConnection) : " + err.Error())
 		return order, err
 	}
 	defer CloseConnection(conn)
 
-	/* Init */
-	reqMatch = `MATCH (so:SOIREE)`
-	reqWhere = `WHERE ID(so) = {soid}`
-	reqCreate = `
+	/* Order */
+	reqs = append(reqs, `
+        MATCH (so:SOIREE) WHERE ID(so) = {soid}
         CREATE (o:ORDER {Price: {price}}), (st:STEP {Name: {stname}, Date: {stdate}}),
         (o)-[:DURING]->(so),
         (o)-[:DONE]->(st)
-    `
-	args["price"] = o.Price
-	args["soid"] = o.Soiree.ID
-	args["stname"] = "Issued"
-	args["stdate"] = time.Now().Format(timeForm)
-
-	/* Users */
-	for _, user := range o.Users {
-		nodeName := `u` + strconv.Itoa(i)
-		relName := nodeName + `r`
-		reqMatch += `, (` + nodeName + `:USER)`
-		reqWhere += ` AND ID(` + nodeName + `) = {` + nodeName + `id}`
-		reqCreate += `, (o)-[` + relName + `:TO` +
-			`{Price: {` + relName + `price}, Reference: {` + relName + `reference}, Approved: ""}]` +
-			`->(`  + nodeName + `)`
-		args[nodeName + `id`] = user.User.ID
-		args[relName + `price`] = user.Price
-		args[relName + `reference`] = user.Reference
-		sumPrice += user.Price
-		i++
-	}
-
-	/* Consos */
-	for _, conso := range o.Consos {
-		nodeName := `c` + strconv.Itoa(i)
-		relName := nodeName + `r`
-		reqMatch += `, (` + nodeName + `:CONSO)`
-		reqWhere += ` AND ID(` + nodeName + `) = {` + nodeName + `id}`
-		reqCreate += `, (o)-[` + relName + `:FOR` +
-			`{Amount: {` + relName + `amount}}]` +
-			`->(` + nodeName + `)`
-		args[nodeName + `id`] = conso.Conso.ID
-		args[relName + `amount`] = conso.Amount
-		i++
-	}
-
-	if sumPrice != o.Price {
-		return order, errors.New("CreateOrder (Price Check) : sum of price in users != order.Price")
-	}
+        RETURN ID(o)
+    `)
+	argss = append(argss, map[string]interface{}{
+		"price":  o.Price,
+		"soid":   o.Soiree.ID,
+		"stname": "Issued",
+		"stdate": formatTime(time.Now()),
+	})
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		rows, err := ExecBatch(conn, reqs, argss)
+		if err != nil {
+			fmt.Println("CreateOrder (ExecBatch 1) : " + err.Error())
+			return err
+		}
+		orderID := rows[0][0].(int64)
+		reqs, argss = nil, nil
+
+		/* Users */
+		for _, user := range o.Users {
+			reqs = append(reqs, `
+                MATCH (o:ORDER), (u:USER) WHERE ID(o) = {oid} AND ID(u) = {uid}
+                CREATE (o)-[:TO {Price: {price}, Reference: {reference}, Approved: ""}]->(u)
+                RETURN ID(o)
+            `)
+			argss = append(argss, map[string]interface{}{
+				"oid":       orderID,
+				"uid":       user.User.ID,
+				"price":     user.Price,
+				"reference": user.Reference,
+			})
+		}
 
-	/* Final */
-	req = reqMatch + ` ` + reqWhere + ` ` + reqCreate + ` RETURN ID(o)`
-	stmt, err := conn.PrepareNeo(req)
-	if err != nil {
-		fmt.Println("CreateOrder (PrepareNeo) : " + err.Error())
-		return order, err
-	}
-	defer stmt.Close()
+		/* Consos, snapshotted so that later edits of the menu leave the order as placed */
+		for _, conso := range o.Consos {
+			reqs = append(reqs, `
+                MATCH (o:ORDER), (c:CONSO) WHERE ID(o) = {oid} AND ID(c) = {cid}
+                CREATE (o)-[:FOR {Amount: {amount}, Name: c.Name, Price: c.Price, Currency: {currency}}]->(c)
+                RETURN ID(o)
+            `)
+			argss = append(argss, map[string]interface{}{
+				"oid":      orderID,
+				"cid":      conso.Conso.ID,
+				"amount":   conso.Amount,
+				"currency": DefaultCurrency,
+			})
+		}
 
-	rows, err := stmt.QueryNeo(args)
+		if _, err = ExecBatch(conn, reqs, argss); err != nil {
+			fmt.Println("CreateOrder (ExecBatch 2) : " + err.Error())
+			return err
+		}
+		order.ID = orderID
+		return nil
+	})
 	if err != nil {
-		fmt.Println("CreateOrder (QueryNeo) : " + err.Error())
 		return order, err
 	}
 
-	data, _, err := rows.NextNeo()
-	if err != nil {
-		fmt.Println("CreateOrder (NextNeo) : " + err.Error())
-		return order, err
-	}
-	orderID := data[0].(int64)
-	
-	order, err = s.GetOrder(ctx, orderID)
+	order, err = s.GetOrder(ctx, order.ID)
 	if err != nil {
 		fmt.Println("CreateOrder (GetOrder) : " + err.Error())
 		return order, err
@@ -10,8 +10,6 @@ import (
 
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
@@ -27,7 +25,7 @@ import (
 func (s Service) CreatePro(c context.Context, u Pro) (Pro, error) {
 	var pro Pro
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreatePro (WaitConnection) : " + err.Error())
 		return pro, err
@@ -35,15 +33,15 @@ func (s Service) CreatePro(c context.Context, u Pro) (Pro, error) {
 	defer CloseConnection(conn)
 
 	_, err = s.GetPro(c, u)
-	if err == nil {
+	if err == nil || err == InvalidPasswordErr {
 		err = errors.New("Pro already exists")
 		return pro, err
 	}
 	err = nil
 
-	_, err = encryptPassword(u.Password)
+	password, err := hashPassword(u.Password)
 	if err != nil {
-		fmt.Println("CreatePro (encryptPassword) : " + err.Error())
+		fmt.Println("CreatePro (hashPassword) : " + err.Error())
 		return pro, err
 	}
 
@@ -69,8 +67,7 @@ func (s Service) CreatePro(c context.Context, u Pro) (Pro, error) {
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"Email":  u.Email,
 		"Pseudo": u.Pseudo,
-		// "Password":   encryptPassword(u.Password),
-		"Password":   u.Password,
+		"Password":   password,
 		"Firstname":  u.Firstname,
 		"Surname":    u.Surname,
 		"Number":     u.Number,
@@ -95,14 +92,6 @@ func (s Service) CreatePro(c context.Context, u Pro) (Pro, error) {
 	return pro, err
 }
 
-func encryptPassword(proPassword string) ([]byte, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(proPassword), bcrypt.DefaultCost)
-	if err != nil {
-		return *new([]byte), err // Dirty
-	}
-	return hash, nil
-}
-
 /*************** Endpoint ***************/
 type createProRequest struct {
 	Pro Pro `json:"pro"`
@@ -0,0 +1,176 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// CreateRefreshToken stores the refresh token of a new session. The refresh
+// tokens and revocations that expired meanwhile are purged.
+func (s Service) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("CreateRefreshToken (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	now := formatTime(time.Now())
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		_, err := conn.ExecNeo(`
+			MATCH (t:REFRESH_TOKEN) WHERE t.Expires < {now} DELETE t`, map[string]interface{}{
+			"now": now,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = conn.ExecNeo(`
+			MATCH (r:REVOCATION) WHERE r.Until < {now} DELETE r`, map[string]interface{}{
+			"now": now,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = conn.ExecNeo(`
+			CREATE (:REFRESH_TOKEN {Hash: {hash}, Subject: {subject}, Expires: {expires}, Used: false})`, map[string]interface{}{
+			"hash":    token.Hash,
+			"subject": token.Subject,
+			"expires": formatTime(token.Expires),
+		})
+		return err
+	})
+	if err != nil {
+		fmt.Println("CreateRefreshToken (Transaction) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type createRefreshTokenRequest struct {
+	Token RefreshToken `json:"token"`
+}
+
+type createRefreshTokenResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func CreateRefreshTokenEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRefreshTokenRequest)
+		err := svc.CreateRefreshToken(ctx, req.Token)
+		if err != nil {
+			fmt.Println("Error CreateRefreshTokenEndpoint : ", err.Error())
+			return createRefreshTokenResponse{Err: err.Error()}, nil
+		}
+		return createRefreshTokenResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPCreateRefreshTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request createRefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPCreateRefreshTokenRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPCreateRefreshTokenResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response createRefreshTokenResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPCreateRefreshTokenResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func CreateRefreshTokenHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/tokens/refresh").Handler(httptransport.NewServer(
+		endpoints.CreateRefreshTokenEndpoint,
+		DecodeHTTPCreateRefreshTokenRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateRefreshToken", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "createRefreshToken",
+			"subject", token.Subject,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.CreateRefreshToken(ctx, token)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	err := mw.next.CreateRefreshToken(ctx, token)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildCreateRefreshTokenEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "CreateRefreshToken")
+		csLogger := log.With(logger, "method", "CreateRefreshToken")
+
+		csEndpoint = CreateRefreshTokenEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "CreateRefreshToken")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	response, err := e.CreateRefreshTokenEndpoint(ctx, createRefreshTokenRequest{Token: token})
+	if err != nil {
+		fmt.Println("Error CreateRefreshToken : ", err.Error())
+		return err
+	}
+	return str2err(response.(createRefreshTokenResponse).Err)
+}
+
+func ClientCreateRefreshToken(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/tokens/refresh"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPCreateRefreshTokenResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "CreateRefreshToken")(ceEndpoint)
+	return ceEndpoint, nil
+}
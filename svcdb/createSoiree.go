@@ -24,7 +24,7 @@ import (
 func (s Service) CreateSoiree(c context.Context, menuID, establishmentID int64, u Soiree) (Soiree, error) {
 	var soiree Soiree
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreateSoiree (WaitConnection) : " + err.Error())
 		return soiree, err
@@ -53,8 +53,8 @@ func (s Service) CreateSoiree(c context.Context, menuID, establishmentID int64,
 		"mid":   menuID,
 		"eid":   establishmentID,
 		"Desc":  u.Desc,
-		"Begin": u.Begin.Format(timeForm),
-		"End":   u.End.Format(timeForm),
+		"Begin": formatTime(u.Begin),
+		"End":   formatTime(u.End),
 	})
 
 	if err != nil {
@@ -25,7 +25,7 @@ import (
 func (s Service) CreateUser(c context.Context, u User) (User, error) {
 	var user User
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("CreateUser (WaitConnection) : " + err.Error())
 		return user, err
@@ -33,12 +33,18 @@ func (s Service) CreateUser(c context.Context, u User) (User, error) {
 	defer CloseConnection(conn)
 
 	_, err = s.GetUser(c, u)
-	if err == nil {
+	if err == nil || err == InvalidPasswordErr {
 		err = errors.New("User already exists")
 		return user, err
 	}
 	err = nil
 
+	password, err := hashPassword(u.Password)
+	if err != nil {
+		fmt.Println("CreateUser (hashPassword) : " + err.Error())
+		return user, err
+	}
+
 	stmt, err := conn.PrepareNeo(`CREATE (u:USER {
 		Email: {Email},
 		Pseudo: {Pseudo},
@@ -52,10 +58,9 @@ func (s Service) CreateUser(c context.Context, u User) (User, error) {
 	}
 
 	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"Email":  u.Email,
-		"Pseudo": u.Pseudo,
-		// "Password":   encryptPassword(u.Password),
-		"Password": u.Password,
+		"Email":    u.Email,
+		"Pseudo":   u.Pseudo,
+		"Password": password,
 	})
 
 	if err != nil {
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	lightstep "github.com/lightstep/lightstep-tracer-go"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -26,6 +29,7 @@ import (
 )
 
 func main() {
+	defaultDB := svcdb.DefaultDriverConfig()
 	var (
 		debugAddr       = flag.String("debug.addr", ":8034", "Debug and metrics listen address")
 		httpAddr        = flag.String("http.addr", ":8044", "HTTP listen address")
@@ -33,13 +37,18 @@ func main() {
 		zipkinKafkaAddr = flag.String("zipkin.kafka.addr", "", "Enable Zipkin tracing via a Kafka server host:port")
 		appdashAddr     = flag.String("appdash.addr", "", "Enable Appdash tracing via an Appdash server host:port")
 		lightstepToken  = flag.String("lightstep.token", "", "Enable LightStep tracing via a LightStep access token")
+		dbAddr          = flag.String("db.addr", envString("NEO4J_ADDR", defaultDB.Addr), "Neo4j bolt host:port")
+		dbUser          = flag.String("db.user", envString("NEO4J_USER", defaultDB.Username), "Neo4j username")
+		dbPassword      = flag.String("db.password", envString("NEO4J_PASSWORD", defaultDB.Password), "Neo4j password")
+		dbPoolSize      = flag.Int("db.pool.size", envInt("NEO4J_POOL_SIZE", defaultDB.PoolSize), "Max number of Neo4j connections")
+		dbTimeout       = flag.Duration("db.timeout", envDuration("NEO4J_TIMEOUT", defaultDB.Timeout), "Neo4j read/write timeout")
+		dbClaimTimeout  = flag.Duration("db.claim.timeout", envDuration("NEO4J_CLAIM_TIMEOUT", defaultDB.ClaimTimeout), "Max wait for a free connection in the pool")
+		dbRetryDelay    = flag.Duration("db.retry.delay", envDuration("NEO4J_RETRY_DELAY", defaultDB.RetryDelay), "First backoff delay when claiming a connection")
+		memory          = flag.Bool("db.memory", false, "Keep the graph in memory instead of connecting to Neo4j")
+		memoryTypes     = flag.String("db.memory.types", "Bar,Club,Pub", "Establishment types seeded in the in memory graph")
 	)
 	flag.Parse()
 
-	/* Database */
-	svcdb.StartDriver()
-	defer svcdb.CloseDriver()
-
 	/* Logger */
 	var logger log.Logger
 	{
@@ -50,6 +59,29 @@ func main() {
 	logger.Log("msg", "[SVCDB BEGIN]")
 	defer logger.Log("msg", "[SVCDB END]")
 
+	/* Database */
+	if !*memory {
+		err := svcdb.StartDriver(svcdb.DriverConfig{
+			Addr:         *dbAddr,
+			Username:     *dbUser,
+			Password:     *dbPassword,
+			PoolSize:     *dbPoolSize,
+			Timeout:      *dbTimeout,
+			ClaimTimeout: *dbClaimTimeout,
+			RetryDelay:   *dbRetryDelay,
+		})
+		if err != nil {
+			logger.Log("err", err)
+			os.Exit(1)
+		}
+		defer svcdb.CloseDriver()
+
+		if err = svcdb.CreateConstraints(context.Background()); err != nil {
+			logger.Log("err", err)
+			os.Exit(1)
+		}
+	}
+
 	/* Metrics */
 	var ints metrics.Counter
 	{
@@ -135,7 +167,18 @@ func main() {
 	/* Business domain */
 	var service svcdb.IService
 	{
-		service = svcdb.NewService()
+		if *memory {
+			memoryService := svcdb.NewMemoryService()
+			for _, name := range strings.Split(*memoryTypes, ",") {
+				memoryService.SeedEstablishmentType(name)
+			}
+			memoryService.SeedSuccess("Profile complete", "profile_complete")
+			memoryService.SeedSuccess("First friend", "first_friend")
+			memoryService.SeedSuccess("Group created", "group_created")
+			service = memoryService
+		} else {
+			service = svcdb.NewService()
+		}
 		service = svcdb.ServiceLoggingMiddleware(logger)(service)
 		service = svcdb.ServiceInstrumentingMiddleware(ints)(service)
 	}
@@ -148,6 +191,7 @@ func main() {
 	getProBySoireeEndpoint := svcdb.BuildGetProBySoireeEndpoint(service, logger, tracer, duration)
 	createProEndpoint := svcdb.BuildCreateProEndpoint(service, logger, tracer, duration)
 	updateProEndpoint := svcdb.BuildUpdateProEndpoint(service, logger, tracer, duration)
+	updateProStripeStatusEndpoint := svcdb.BuildUpdateProStripeStatusEndpoint(service, logger, tracer, duration)
 	getProEstablishmentsEndpoint := svcdb.BuildGetProEstablishmentsEndpoint(service, logger, tracer, duration)
 
 	/* User */
@@ -166,6 +210,7 @@ func main() {
 	getUserFriendsEndpoint := svcdb.BuildGetUserFriendsEndpoint(service, logger, tracer, duration)
 	inviteFriendEndpoint := svcdb.BuildInviteFriendEndpoint(service, logger, tracer, duration)
 	getUsersInvitationsEndpoint := svcdb.BuildGetUsersInvitationsEndpoint(service, logger, tracer, duration)
+	getInvitationEndpoint := svcdb.BuildGetInvitationEndpoint(service, logger, tracer, duration)
 	invitationAcceptEndpoint := svcdb.BuildInvitationAcceptEndpoint(service, logger, tracer, duration)
 	invitationDeclineEndpoint := svcdb.BuildInvitationDeclineEndpoint(service, logger, tracer, duration)
 	usersConnectedEndpoint := svcdb.BuildUsersConnectedEndpoint(service, logger, tracer, duration)
@@ -179,6 +224,7 @@ func main() {
 	getMenuFromEstablishmentEndpoint := svcdb.BuildGetMenuFromEstablishmentEndpoint(service, logger, tracer, duration)
 	getEstablishmentSoireeEndpoint := svcdb.BuildGetEstablishmentSoireeEndpoint(service, logger, tracer, duration)
 	searchEstablishmentsEndpoint := svcdb.BuildSearchEstablishmentsEndpoint(service, logger, tracer, duration)
+	searchNearbyEndpoint := svcdb.BuildSearchNearbyEndpoint(service, logger, tracer, duration)
 	updateEstablishmentEndpoint := svcdb.BuildUpdateEstablishmentEndpoint(service, logger, tracer, duration)
 	getEstablishmentTypesEndpoint := svcdb.BuildGetEstablishmentTypesEndpoint(service, logger, tracer, duration)
 	getEstablishmentTypeEndpoint := svcdb.BuildGetEstablishmentTypeEndpoint(service, logger, tracer, duration)
@@ -201,11 +247,17 @@ func main() {
 	createOrderEndpoint := svcdb.BuildCreateOrderEndpoint(service, logger, tracer, duration)
 	putOrderEndpoint := svcdb.BuildPutOrderEndpoint(service, logger, tracer, duration)
 	updateOrderReferenceEndpoint := svcdb.BuildUpdateOrderReferenceEndpoint(service, logger, tracer, duration)
+	getOrderByReferenceEndpoint := svcdb.BuildGetOrderByReferenceEndpoint(service, logger, tracer, duration)
+	getChargedOrdersEndpoint := svcdb.BuildGetChargedOrdersEndpoint(service, logger, tracer, duration)
+	flagOrderEndpoint := svcdb.BuildFlagOrderEndpoint(service, logger, tracer, duration)
 
 	userOrderEndpoint := svcdb.BuildUserOrderEndpoint(service, logger, tracer, duration)
 	getConsoByOrderIDEndpoint := svcdb.BuildGetConsoByOrderIDEndpoint(service, logger, tracer, duration)
 	answerOrderEndpoint := svcdb.BuildAnswerOrderEndpoint(service, logger, tracer, duration)
 	failOrderEndpoint := svcdb.BuildFailOrderEndpoint(service, logger, tracer, duration)
+	cancelOrderEndpoint := svcdb.BuildCancelOrderEndpoint(service, logger, tracer, duration)
+	requestCancelEndpoint := svcdb.BuildRequestCancelEndpoint(service, logger, tracer, duration)
+	getPendingOrdersEndpoint := svcdb.BuildGetPendingOrdersEndpoint(service, logger, tracer, duration)
 
 	/* Menu */
 	getEstablishmentConsosEndpoint := svcdb.BuildGetEstablishmentConsosEndpoint(service, logger, tracer, duration)
@@ -214,6 +266,7 @@ func main() {
 	createMenuEndpoint := svcdb.BuildCreateMenuEndpoint(service, logger, tracer, duration)
 	createConsoEndpoint := svcdb.BuildCreateConsoEndpoint(service, logger, tracer, duration)
 	getMenuFromSoireeEndpoint := svcdb.BuildGetMenuFromSoireeEndpoint(service, logger, tracer, duration)
+	importMenusEndpoint := svcdb.BuildImportMenusEndpoint(service, logger, tracer, duration)
 
 	/* Groups */
 	getGroupEndpoint := svcdb.BuildGetGroupEndpoint(service, logger, tracer, duration)
@@ -238,6 +291,33 @@ func main() {
 
 	/* Analyse */
 	getAnalysePEndpoint := svcdb.BuildGetAnalysePEndpoint(service, logger, tracer, duration)
+	getAnalyseCEndpoint := svcdb.BuildGetAnalyseCEndpoint(service, logger, tracer, duration)
+	getAnalyseFEndpoint := svcdb.BuildGetAnalyseFEndpoint(service, logger, tracer, duration)
+	getMenuStatEndpoint := svcdb.BuildGetMenuStatEndpoint(service, logger, tracer, duration)
+
+	/* Webhook */
+	claimWebhookEventEndpoint := svcdb.BuildClaimWebhookEventEndpoint(service, logger, tracer, duration)
+	releaseWebhookEventEndpoint := svcdb.BuildReleaseWebhookEventEndpoint(service, logger, tracer, duration)
+
+	/* Idempotency */
+	claimIdempotencyKeyEndpoint := svcdb.BuildClaimIdempotencyKeyEndpoint(service, logger, tracer, duration)
+	storeIdempotencyKeyEndpoint := svcdb.BuildStoreIdempotencyKeyEndpoint(service, logger, tracer, duration)
+
+	/* Ledger */
+	addLedgerEntryEndpoint := svcdb.BuildAddLedgerEntryEndpoint(service, logger, tracer, duration)
+	getLedgerEndpoint := svcdb.BuildGetLedgerEndpoint(service, logger, tracer, duration)
+
+	/* Tokens */
+	createRefreshTokenEndpoint := svcdb.BuildCreateRefreshTokenEndpoint(service, logger, tracer, duration)
+	useRefreshTokenEndpoint := svcdb.BuildUseRefreshTokenEndpoint(service, logger, tracer, duration)
+	revokeTokensEndpoint := svcdb.BuildRevokeTokensEndpoint(service, logger, tracer, duration)
+	isTokenRevokedEndpoint := svcdb.BuildIsTokenRevokedEndpoint(service, logger, tracer, duration)
+	createAccountTokenEndpoint := svcdb.BuildCreateAccountTokenEndpoint(service, logger, tracer, duration)
+
+	/* Accounts */
+	getAccountByEmailEndpoint := svcdb.BuildGetAccountByEmailEndpoint(service, logger, tracer, duration)
+	resetPasswordEndpoint := svcdb.BuildResetPasswordEndpoint(service, logger, tracer, duration)
+	verifyEmailEndpoint := svcdb.BuildVerifyEmailEndpoint(service, logger, tracer, duration)
 
 	endpoints := svcdb.Endpoints{
 		/* Pro */
@@ -247,6 +327,7 @@ func main() {
 		GetProByIDEndpoint:           getProByIDEndpoint,
 		GetProBySoireeEndpoint:       getProBySoireeEndpoint,
 		UpdateProEndpoint:            updateProEndpoint,
+		UpdateProStripeStatusEndpoint: updateProStripeStatusEndpoint,
 		GetProEstablishmentsEndpoint: getProEstablishmentsEndpoint,
 
 		/* User */
@@ -265,6 +346,7 @@ func main() {
 		GetUserFriendsEndpoint:      getUserFriendsEndpoint,
 		InviteFriendEndpoint:        inviteFriendEndpoint,
 		GetUsersInvitationsEndpoint: getUsersInvitationsEndpoint,
+		GetInvitationEndpoint:       getInvitationEndpoint,
 		InvitationAcceptEndpoint:    invitationAcceptEndpoint,
 		InvitationDeclineEndpoint:   invitationDeclineEndpoint,
 		UsersConnectedEndpoint:      usersConnectedEndpoint,
@@ -274,6 +356,7 @@ func main() {
 		CreateEstablishmentEndpoint:      createEstablishmentEndpoint,
 		GetEstablishmentsEndpoint:        getEstablishmentsEndpoint,
 		SearchEstablishmentsEndpoint:     searchEstablishmentsEndpoint,
+		SearchNearbyEndpoint:             searchNearbyEndpoint,
 		GetEstablishmentEndpoint:         getEstablishmentEndpoint,
 		GetEstablishmentFromMenuEndpoint: getEstablishmentFromMenuEndpoint,
 		GetMenuFromEstablishmentEndpoint: getMenuFromEstablishmentEndpoint,
@@ -301,6 +384,7 @@ func main() {
 		CreateMenuEndpoint:             createMenuEndpoint,
 		CreateConsoEndpoint:            createConsoEndpoint,
 		GetMenuFromSoireeEndpoint:      getMenuFromSoireeEndpoint,
+		ImportMenusEndpoint:            importMenusEndpoint,
 
 		/* Order */
 		GetOrderEndpoint:             getOrderEndpoint,
@@ -309,7 +393,13 @@ func main() {
 		PutOrderEndpoint:             putOrderEndpoint,
 		AnswerOrderEndpoint:          answerOrderEndpoint,
 		FailOrderEndpoint:            failOrderEndpoint,
+		CancelOrderEndpoint:          cancelOrderEndpoint,
+		RequestCancelEndpoint:        requestCancelEndpoint,
+		GetPendingOrdersEndpoint:     getPendingOrdersEndpoint,
 		UpdateOrderReferenceEndpoint: updateOrderReferenceEndpoint,
+		GetOrderByReferenceEndpoint:  getOrderByReferenceEndpoint,
+		GetChargedOrdersEndpoint:     getChargedOrdersEndpoint,
+		FlagOrderEndpoint:            flagOrderEndpoint,
 
 		GetConsoByOrderIDEndpoint: getConsoByOrderIDEndpoint,
 		UserOrderEndpoint:         userOrderEndpoint,
@@ -337,6 +427,33 @@ func main() {
 
 		/* Analyse */
 		GetAnalysePEndpoint:	getAnalysePEndpoint,
+		GetAnalyseCEndpoint:	getAnalyseCEndpoint,
+		GetAnalyseFEndpoint:	getAnalyseFEndpoint,
+		GetMenuStatEndpoint:	getMenuStatEndpoint,
+
+		/* Webhook */
+		ClaimWebhookEventEndpoint:   claimWebhookEventEndpoint,
+		ReleaseWebhookEventEndpoint: releaseWebhookEventEndpoint,
+
+		/* Idempotency */
+		ClaimIdempotencyKeyEndpoint: claimIdempotencyKeyEndpoint,
+		StoreIdempotencyKeyEndpoint: storeIdempotencyKeyEndpoint,
+
+		/* Ledger */
+		AddLedgerEntryEndpoint: addLedgerEntryEndpoint,
+		GetLedgerEndpoint:      getLedgerEndpoint,
+
+		/* Tokens */
+		CreateRefreshTokenEndpoint: createRefreshTokenEndpoint,
+		UseRefreshTokenEndpoint:    useRefreshTokenEndpoint,
+		RevokeTokensEndpoint:       revokeTokensEndpoint,
+		IsTokenRevokedEndpoint:     isTokenRevokedEndpoint,
+		CreateAccountTokenEndpoint: createAccountTokenEndpoint,
+
+		/* Accounts */
+		GetAccountByEmailEndpoint: getAccountByEmailEndpoint,
+		ResetPasswordEndpoint:     resetPasswordEndpoint,
+		VerifyEmailEndpoint:       verifyEmailEndpoint,
 	}
 
 	/* Mechanical domain */
@@ -376,3 +493,24 @@ func main() {
 	/* Run */
 	logger.Log("exit", <-errc)
 }
+
+func envString(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return def
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return def
+}
@@ -20,8 +20,8 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) DeleteEstab(_ context.Context, groupID int64) error {
-	conn, err := WaitConnection(5)
+func (s Service) DeleteEstab(ctx context.Context, groupID int64) error {
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("DeleteEstab (WaitConnection) : " + err.Error())
 		return err
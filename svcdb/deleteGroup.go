@@ -20,8 +20,8 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) DeleteGroup(_ context.Context, groupID int64) error {
-	conn, err := WaitConnection(5)
+func (s Service) DeleteGroup(ctx context.Context, groupID int64) error {
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("DeleteGroup (WaitConnection) : " + err.Error())
 		return err
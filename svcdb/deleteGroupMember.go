@@ -21,8 +21,8 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) DeleteGroupMember(_ context.Context, groupID, userID int64) error {
-	conn, err := WaitConnection(5)
+func (s Service) DeleteGroupMember(ctx context.Context, groupID, userID int64) error {
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("DeleteGroupMember (WaitConnection) : " + err.Error())
 		return err
@@ -20,8 +20,8 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) DeleteSoiree(_ context.Context, soireeID int64) error {
-	conn, err := WaitConnection(5)
+func (s Service) DeleteSoiree(ctx context.Context, soireeID int64) error {
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("DeleteSoiree (WaitConnection) : " + err.Error())
 		return err
@@ -17,6 +17,7 @@ type Endpoints struct {
 	GetProEndpoint               endpoint.Endpoint
 	GetProByIDStripeEndpoint     endpoint.Endpoint
 	UpdateProEndpoint            endpoint.Endpoint
+	UpdateProStripeStatusEndpoint endpoint.Endpoint
 	GetProByIDEndpoint           endpoint.Endpoint
 	GetProBySoireeEndpoint       endpoint.Endpoint
 	GetProEstablishmentsEndpoint endpoint.Endpoint
@@ -37,6 +38,7 @@ type Endpoints struct {
 	GetUserFriendsEndpoint      endpoint.Endpoint
 	InviteFriendEndpoint        endpoint.Endpoint
 	GetUsersInvitationsEndpoint endpoint.Endpoint
+	GetInvitationEndpoint       endpoint.Endpoint
 	InvitationAcceptEndpoint    endpoint.Endpoint
 	InvitationDeclineEndpoint   endpoint.Endpoint
 	UsersConnectedEndpoint      endpoint.Endpoint
@@ -45,6 +47,7 @@ type Endpoints struct {
 	/* Establishment */
 	CreateEstablishmentEndpoint      endpoint.Endpoint
 	SearchEstablishmentsEndpoint     endpoint.Endpoint
+	SearchNearbyEndpoint             endpoint.Endpoint
 	GetEstablishmentsEndpoint        endpoint.Endpoint
 	GetEstablishmentEndpoint         endpoint.Endpoint
 	GetEstablishmentFromMenuEndpoint endpoint.Endpoint
@@ -73,6 +76,7 @@ type Endpoints struct {
 	CreateMenuEndpoint             endpoint.Endpoint
 	CreateConsoEndpoint            endpoint.Endpoint
 	GetMenuFromSoireeEndpoint      endpoint.Endpoint
+	ImportMenusEndpoint            endpoint.Endpoint
 
 	/* Groups */
 	GetGroupEndpoint               endpoint.Endpoint
@@ -93,7 +97,13 @@ type Endpoints struct {
 	PutOrderEndpoint     endpoint.Endpoint
 	AnswerOrderEndpoint  endpoint.Endpoint
 	FailOrderEndpoint    endpoint.Endpoint
+	CancelOrderEndpoint  endpoint.Endpoint
+	RequestCancelEndpoint        endpoint.Endpoint
+	GetPendingOrdersEndpoint endpoint.Endpoint
 	UpdateOrderReferenceEndpoint endpoint.Endpoint
+	GetOrderByReferenceEndpoint  endpoint.Endpoint
+	GetChargedOrdersEndpoint     endpoint.Endpoint
+	FlagOrderEndpoint            endpoint.Endpoint
 
 	UserOrderEndpoint         endpoint.Endpoint
 	GetConsoByOrderIDEndpoint endpoint.Endpoint
@@ -111,6 +121,33 @@ type Endpoints struct {
 
 	/* Analyse */
 	GetAnalysePEndpoint	endpoint.Endpoint
+	GetAnalyseCEndpoint	endpoint.Endpoint
+	GetAnalyseFEndpoint	endpoint.Endpoint
+	GetMenuStatEndpoint	endpoint.Endpoint
+
+	/* Webhook */
+	ClaimWebhookEventEndpoint   endpoint.Endpoint
+	ReleaseWebhookEventEndpoint endpoint.Endpoint
+
+	/* Idempotency */
+	ClaimIdempotencyKeyEndpoint endpoint.Endpoint
+	StoreIdempotencyKeyEndpoint endpoint.Endpoint
+
+	/* Ledger */
+	AddLedgerEntryEndpoint endpoint.Endpoint
+	GetLedgerEndpoint      endpoint.Endpoint
+
+	/* Tokens */
+	CreateRefreshTokenEndpoint endpoint.Endpoint
+	UseRefreshTokenEndpoint    endpoint.Endpoint
+	RevokeTokensEndpoint       endpoint.Endpoint
+	IsTokenRevokedEndpoint     endpoint.Endpoint
+	CreateAccountTokenEndpoint endpoint.Endpoint
+
+	/* Accounts */
+	GetAccountByEmailEndpoint endpoint.Endpoint
+	ResetPasswordEndpoint     endpoint.Endpoint
+	VerifyEmailEndpoint       endpoint.Endpoint
 }
 
 /* Logging Middleware */
@@ -23,7 +23,7 @@ import (
 func (s Service) FailOrder(ctx context.Context, orderID int64) (Order, error) {
     var order Order
 
-    conn, err := WaitConnection(5)
+    conn, err := WaitConnection(ctx, 5)
     if err != nil {
 		fmt.Println("FailOrder (WaitConnection) : " + err.Error())
 		return order, err
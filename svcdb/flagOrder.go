@@ -0,0 +1,161 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// FlagOrder sets the reconciliation issues of the order, an empty issue
+// clearing the flag once the ledger matches again
+func (s Service) FlagOrder(ctx context.Context, orderID int64, issue string) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("FlagOrder (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	req := `MATCH (o:ORDER) WHERE ID(o) = {id} SET o.Reconciliation = {issue}`
+	if len(issue) == 0 {
+		req = `MATCH (o:ORDER) WHERE ID(o) = {id} REMOVE o.Reconciliation`
+	}
+	_, err = conn.ExecNeo(req, map[string]interface{}{
+		"id":    orderID,
+		"issue": issue,
+	})
+	if err != nil {
+		fmt.Println("FlagOrder (ExecNeo) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type flagOrderRequest struct {
+	OrderID int64  `json:"order"`
+	Issue   string `json:"issue"`
+}
+
+type flagOrderResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func FlagOrderEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(flagOrderRequest)
+		err := svc.FlagOrder(ctx, req.OrderID, req.Issue)
+		if err != nil {
+			fmt.Println("Error FlagOrderEndpoint : ", err.Error())
+			return flagOrderResponse{Err: err.Error()}, nil
+		}
+		return flagOrderResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPFlagOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request flagOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPFlagOrderRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPFlagOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response flagOrderResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPFlagOrderResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func FlagOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/flag").Handler(httptransport.NewServer(
+		endpoints.FlagOrderEndpoint,
+		DecodeHTTPFlagOrderRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "FlagOrder", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) FlagOrder(ctx context.Context, orderID int64, issue string) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "flagOrder",
+			"orderID", orderID,
+			"issue", issue,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.FlagOrder(ctx, orderID, issue)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) FlagOrder(ctx context.Context, orderID int64, issue string) error {
+	err := mw.next.FlagOrder(ctx, orderID, issue)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildFlagOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "FlagOrder")
+		csLogger := log.With(logger, "method", "FlagOrder")
+
+		csEndpoint = FlagOrderEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "FlagOrder")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) FlagOrder(ctx context.Context, orderID int64, issue string) error {
+	request := flagOrderRequest{OrderID: orderID, Issue: issue}
+	response, err := e.FlagOrderEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error FlagOrder : ", err.Error())
+		return err
+	}
+	return str2err(response.(flagOrderResponse).Err)
+}
+
+func ClientFlagOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/flag"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPFlagOrderResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "FlagOrder")(ceEndpoint)
+	return ceEndpoint, nil
+}
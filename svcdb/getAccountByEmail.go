@@ -0,0 +1,167 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetAccountByEmail returns the ID of the account of kind (a user or a pro)
+// registered with email, io.EOF when there is none
+func (s Service) GetAccountByEmail(ctx context.Context, kind string, email string) (int64, error) {
+	label, err := subjectLabel(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetAccountByEmail (WaitConnection) : " + err.Error())
+		return 0, err
+	}
+	defer CloseConnection(conn)
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (n:`+label+` {Email: {email}}) RETURN ID(n) LIMIT 1`, map[string]interface{}{
+		"email": email,
+	})
+	if err != nil {
+		fmt.Println("GetAccountByEmail (QueryNeoAll) : " + err.Error())
+		return 0, err
+	} else if len(data) == 0 {
+		return 0, io.EOF
+	}
+	id, _ := data[0][0].(int64)
+	return id, nil
+}
+
+/*************** Endpoint ***************/
+type getAccountByEmailRequest struct {
+	Kind  string `json:"kind"`
+	Email string `json:"email"`
+}
+
+type getAccountByEmailResponse struct {
+	ID  int64  `json:"id"`
+	Err string `json:"err,omitempty"`
+}
+
+func GetAccountByEmailEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getAccountByEmailRequest)
+		id, err := svc.GetAccountByEmail(ctx, req.Kind, req.Email)
+		if err != nil {
+			fmt.Println("Error GetAccountByEmailEndpoint : ", err.Error())
+			return getAccountByEmailResponse{ID: id, Err: err.Error()}, nil
+		}
+		return getAccountByEmailResponse{ID: id, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetAccountByEmailRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getAccountByEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPGetAccountByEmailRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPGetAccountByEmailResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getAccountByEmailResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetAccountByEmailResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetAccountByEmailHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/accounts/email").Handler(httptransport.NewServer(
+		endpoints.GetAccountByEmailEndpoint,
+		DecodeHTTPGetAccountByEmailRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetAccountByEmail", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetAccountByEmail(ctx context.Context, kind string, email string) (int64, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getAccountByEmail",
+			"kind", kind,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetAccountByEmail(ctx, kind, email)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetAccountByEmail(ctx context.Context, kind string, email string) (int64, error) {
+	v, err := mw.next.GetAccountByEmail(ctx, kind, email)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetAccountByEmailEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetAccountByEmail")
+		csLogger := log.With(logger, "method", "GetAccountByEmail")
+
+		csEndpoint = GetAccountByEmailEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetAccountByEmail")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) GetAccountByEmail(ctx context.Context, kind string, email string) (int64, error) {
+	request := getAccountByEmailRequest{Kind: kind, Email: email}
+	response, err := e.GetAccountByEmailEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error GetAccountByEmail : ", err.Error())
+		return 0, err
+	}
+	r := response.(getAccountByEmailResponse)
+	return r.ID, str2err(r.Err)
+}
+
+func ClientGetAccountByEmail(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/accounts/email"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPGetAccountByEmailResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetAccountByEmail")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,221 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+func (s Service) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]AnalyseC, error) {
+	var req string
+	analyses := newAnalysesC()
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetAnalyseC (WaitConnection) : " + err.Error())
+		return nil, err
+	}
+	defer CloseConnection(conn)
+
+	req = `MATCH (e:ESTABLISHMENT)-[:SPAWNED]->(s:SOIREE)<-[:DURING]-(o:ORDER { Done: "true" })-[f:FOR]->(c:CONSO),
+		(o)-[:DONE]->(st:STEP { Name: "Issued" })
+		WHERE ID(e) = {estabID}`
+	if soireeID > 0 {
+		req += ` AND ID(s) = {soireeID}`
+	}
+	req += ` RETURN ID(s), o, c, f, st.Date ORDER BY ID(s)`
+
+	stmt, err := conn.PrepareNeo(req)
+	if err != nil {
+		fmt.Println("GetAnalyseC (PrepareNeo) : " + err.Error())
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"estabID":  estabID,
+		"soireeID": soireeID,
+	})
+	if err != nil {
+		fmt.Println("GetAnalyseC (QueryNeo) : " + err.Error())
+		return nil, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		var tmpOrder Order
+
+		// the conso as ordered, not as it is on the menu now
+		(&tmpOrder).RelationAddConso(row[1].(graph.Node), row[3].(graph.Relationship), row[2].(graph.Node))
+		issued := storedTime("GetAnalyseC", "Date", row[4])
+		analyses.add(row[0].(int64), tmpOrder.Consos[0].Conso, issued, tmpOrder.Consos[0].Amount)
+
+		row, _, err = rows.NextNeo()
+	}
+	if err != nil && err != io.EOF {
+		fmt.Println("GetAnalyseC (NextNeo) : " + err.Error())
+		return nil, err
+	}
+
+	return analyses.result(), nil
+}
+
+/*************** Endpoint ***************/
+type getAnalyseCRequest struct {
+	EstabID  int64 `json:"estabID"`
+	SoireeID int64 `json:"soireeID"`
+}
+
+type getAnalyseCResponse struct {
+	Analyses []AnalyseC `json:"analyses"`
+	Err      string     `json:"err,omitempty"`
+}
+
+func GetAnalyseCEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getAnalyseCRequest)
+		analyses, err := svc.GetAnalyseC(ctx, req.EstabID, req.SoireeID)
+		if err != nil {
+			fmt.Println("Error GetAnalyseCEndpoint : ", err.Error())
+			return getAnalyseCResponse{analyses, err.Error()}, nil
+		}
+		return getAnalyseCResponse{analyses, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetAnalyseCRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getAnalyseCRequest
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["estabID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).EstabID = estabID
+
+	soireeID, err := strconv.ParseInt(mux.Vars(r)["soireeID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).SoireeID = soireeID
+
+	return request, nil
+
+}
+
+func DecodeHTTPGetAnalyseCResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getAnalyseCResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetAnalyseCResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetAnalyseCHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/analyses/Consommation/{estabID:[0-9]+}/{soireeID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetAnalyseCEndpoint,
+		DecodeHTTPGetAnalyseCRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetAnalyseC", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]AnalyseC, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getAnalyseC",
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetAnalyseC(ctx, estabID, soireeID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]AnalyseC, error) {
+	v, err := mw.next.GetAnalyseC(ctx, estabID, soireeID)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetAnalyseCEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetAnalyseC")
+		csLogger := log.With(logger, "method", "GetAnalyseC")
+
+		csEndpoint = GetAnalyseCEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetAnalyseC")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forsearch limiter & circuitbreaker for now kthx
+func (e Endpoints) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]AnalyseC, error) {
+	var analysesC []AnalyseC
+
+	request := getAnalyseCRequest{EstabID: estabID, SoireeID: soireeID}
+	response, err := e.GetAnalyseCEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error Client GetAnalyseC : ", err.Error())
+		return analysesC, err
+	}
+	analysesC = response.(getAnalyseCResponse).Analyses
+	return analysesC, str2err(response.(getAnalyseCResponse).Err)
+}
+
+func EncodeHTTPGetAnalyseCRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	estabID := fmt.Sprintf("%v", request.(getAnalyseCRequest).EstabID)
+	soireeID := fmt.Sprintf("%v", request.(getAnalyseCRequest).SoireeID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("estabID", estabID, "soireeID", soireeID)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	return nil
+}
+
+func ClientGetAnalyseC(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/analyses/Consommation/{estabID:[0-9]+}/{soireeID:[0-9]+}"),
+		EncodeHTTPGetAnalyseCRequest,
+		DecodeHTTPGetAnalyseCResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetAnalyseC")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,247 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+func (s Service) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error) {
+	var req string
+	var analyses []AnalyseF
+	var events []presenceEvent
+	soirees := make(map[int64]Soiree)
+
+	if !validRange(from, to) {
+		return analyses, InvalidRangeErr
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetAnalyseF (WaitConnection) : " + err.Error())
+		return analyses, err
+	}
+	defer CloseConnection(conn)
+
+	// A LEAVE after the range still closes a visit started in it, the range
+	// is applied once visits are paired
+	req = `MATCH (e:ESTABLISHMENT)-[:SPAWNED]->(s:SOIREE)<-[r:JOIN|LEAVE]-(u:USER) WHERE ID(e) = {estabID}`
+	if soireeID > 0 {
+		req += ` AND ID(s) = {soireeID}`
+	}
+	req += ` RETURN ID(u), s, TYPE(r), r.When`
+
+	stmt, err := conn.PrepareNeo(req)
+	if err != nil {
+		fmt.Println("GetAnalyseF (PrepareNeo) : " + err.Error())
+		return analyses, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"estabID":  estabID,
+		"soireeID": soireeID,
+	})
+	if err != nil {
+		fmt.Println("GetAnalyseF (QueryNeo) : " + err.Error())
+		return analyses, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		var tmpSoiree Soiree
+		var tmpEvent presenceEvent
+
+		(&tmpSoiree).NodeToSoiree(row[1].(graph.Node))
+		soirees[tmpSoiree.ID] = tmpSoiree
+
+		tmpEvent.UserID = row[0].(int64)
+		tmpEvent.SoireeID = tmpSoiree.ID
+		tmpEvent.Join = row[2].(string) == "JOIN"
+		tmpEvent.When = storedTime("GetAnalyseF", "When", row[3])
+		if !tmpEvent.When.IsZero() {
+			events = append(events, tmpEvent)
+		}
+		row, _, err = rows.NextNeo()
+	}
+	if err != nil && err != io.EOF {
+		fmt.Println("GetAnalyseF (NextNeo) : " + err.Error())
+		return analyses, err
+	}
+
+	tmpAnalyse := frequentation(visits(events, soirees, time.Now()), from, to)
+	tmpAnalyse.Soiree = soireeID
+	analyses = append(analyses, tmpAnalyse)
+
+	return analyses, nil
+}
+
+/*************** Endpoint ***************/
+type getAnalyseFRequest struct {
+	EstabID  int64     `json:"estabID"`
+	SoireeID int64     `json:"soireeID"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+type getAnalyseFResponse struct {
+	Analyses []AnalyseF `json:"analyses"`
+	Err      string     `json:"err,omitempty"`
+}
+
+func GetAnalyseFEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getAnalyseFRequest)
+		analyses, err := svc.GetAnalyseF(ctx, req.EstabID, req.SoireeID, req.From, req.To)
+		if err != nil {
+			fmt.Println("Error GetAnalyseFEndpoint : ", err.Error())
+			return getAnalyseFResponse{analyses, err.Error()}, nil
+		}
+		return getAnalyseFResponse{analyses, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetAnalyseFRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getAnalyseFRequest
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["estabID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).EstabID = estabID
+
+	soireeID, err := strconv.ParseInt(mux.Vars(r)["soireeID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).SoireeID = soireeID
+
+	from, to, err := RangeFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	(&request).From, (&request).To = from, to
+
+	return request, nil
+
+}
+
+func DecodeHTTPGetAnalyseFResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getAnalyseFResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetAnalyseFResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetAnalyseFHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/analyses/Frequentation/{estabID:[0-9]+}/{soireeID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetAnalyseFEndpoint,
+		DecodeHTTPGetAnalyseFRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetAnalyseF", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getAnalyseF",
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetAnalyseF(ctx, estabID, soireeID, from, to)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error) {
+	v, err := mw.next.GetAnalyseF(ctx, estabID, soireeID, from, to)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetAnalyseFEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetAnalyseF")
+		csLogger := log.With(logger, "method", "GetAnalyseF")
+
+		csEndpoint = GetAnalyseFEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetAnalyseF")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forsearch limiter & circuitbreaker for now kthx
+func (e Endpoints) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error) {
+	var analysesF []AnalyseF
+
+	request := getAnalyseFRequest{EstabID: estabID, SoireeID: soireeID, From: from, To: to}
+	response, err := e.GetAnalyseFEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error Client GetAnalyseF : ", err.Error())
+		return analysesF, err
+	}
+	analysesF = response.(getAnalyseFResponse).Analyses
+	return analysesF, str2err(response.(getAnalyseFResponse).Err)
+}
+
+func EncodeHTTPGetAnalyseFRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(getAnalyseFRequest)
+	estabID := fmt.Sprintf("%v", req.EstabID)
+	soireeID := fmt.Sprintf("%v", req.SoireeID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("estabID", estabID, "soireeID", soireeID)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	EncodeRangeToRequest(r, req.From, req.To)
+	return nil
+}
+
+func ClientGetAnalyseF(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/analyses/Frequentation/{estabID:[0-9]+}/{soireeID:[0-9]+}"),
+		EncodeHTTPGetAnalyseFRequest,
+		DecodeHTTPGetAnalyseFResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetAnalyseF")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -21,13 +21,13 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetAnalyseP(_ context.Context, estabID int64, soireeID int64) ([]AnalyseP, error) {
+func (s Service) GetAnalyseP(ctx context.Context, estabID int64, soireeID int64) ([]AnalyseP, error) {
 	var req string
 	var analyses	[]AnalyseP
 	// var orders Orders
 	// mapConsoIdx := make(map[int64]int64)
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetAnalyseP (WaitConnection) : " + err.Error())
 		return analyses, err
@@ -0,0 +1,187 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetChargedOrders returns the orders issued since from holding at least one
+// charge reference, the ones svcpayment reconciles with the ledger
+func (s Service) GetChargedOrders(ctx context.Context, from time.Time) ([]Order, error) {
+	var orders []Order
+	var orderIDs []int64
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetChargedOrders (WaitConnection) : " + err.Error())
+		return nil, err
+	}
+	defer CloseConnection(conn)
+
+	stmt, err := conn.PrepareNeo(`
+		MATCH (o:ORDER)-[:DONE]->(st:STEP {Name: {issued}}), (o)-[r:TO]->(:USER)
+		WHERE st.Date >= {from} AND r.Reference <> ""
+		RETURN DISTINCT ID(o) ORDER BY ID(o)`)
+	if err != nil {
+		fmt.Println("GetChargedOrders (PrepareNeo) : " + err.Error())
+		return nil, err
+	}
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"issued": OrderWorkflow[0].Name,
+		"from":   formatTime(from),
+	})
+	if err != nil {
+		stmt.Close()
+		fmt.Println("GetChargedOrders (QueryNeo) : " + err.Error())
+		return nil, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		orderIDs = append(orderIDs, row[0].(int64))
+		row, _, err = rows.NextNeo()
+	}
+	stmt.Close()
+	if err != nil && err != io.EOF {
+		fmt.Println("GetChargedOrders (NextNeo) : " + err.Error())
+		return nil, err
+	}
+
+	for _, orderID := range orderIDs {
+		order, err := s.GetOrder(ctx, orderID)
+		if err != nil {
+			fmt.Println("GetChargedOrders (GetOrder) : " + err.Error())
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+/*************** Endpoint ***************/
+type getChargedOrdersRequest struct {
+	From time.Time `json:"from"`
+}
+
+type getChargedOrdersResponse struct {
+	Orders []Order `json:"orders"`
+	Err    string  `json:"err,omitempty"`
+}
+
+func GetChargedOrdersEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getChargedOrdersRequest)
+		orders, err := svc.GetChargedOrders(ctx, req.From)
+		if err != nil {
+			fmt.Println("Error GetChargedOrdersEndpoint : ", err.Error())
+			return getChargedOrdersResponse{orders, err.Error()}, nil
+		}
+		return getChargedOrdersResponse{orders, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetChargedOrdersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getChargedOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPGetChargedOrdersRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPGetChargedOrdersResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getChargedOrdersResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetChargedOrdersResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetChargedOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/orders/charged").Handler(httptransport.NewServer(
+		endpoints.GetChargedOrdersEndpoint,
+		DecodeHTTPGetChargedOrdersRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetChargedOrders", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetChargedOrders(ctx context.Context, from time.Time) ([]Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getChargedOrders",
+			"from", from,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetChargedOrders(ctx, from)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetChargedOrders(ctx context.Context, from time.Time) ([]Order, error) {
+	v, err := mw.next.GetChargedOrders(ctx, from)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetChargedOrdersEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetChargedOrders")
+		csLogger := log.With(logger, "method", "GetChargedOrders")
+
+		csEndpoint = GetChargedOrdersEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetChargedOrders")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) GetChargedOrders(ctx context.Context, from time.Time) ([]Order, error) {
+	response, err := e.GetChargedOrdersEndpoint(ctx, getChargedOrdersRequest{From: from})
+	if err != nil {
+		return nil, err
+	}
+	return response.(getChargedOrdersResponse).Orders, str2err(response.(getChargedOrdersResponse).Err)
+}
+
+func ClientGetChargedOrders(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/orders/charged"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPGetChargedOrdersResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetChargedOrders")(ceEndpoint)
+	return ceEndpoint, nil
+}
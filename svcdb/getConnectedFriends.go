@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetConnectedFriends(_ context.Context, soireeID int64) ([]Profile, error) {
+func (s Service) GetConnectedFriends(ctx context.Context, soireeID int64) ([]Profile, error) {
 	var friends []Profile
 	var tmpProfile Profile
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetConnectedFriends (WaitConnection) : " + err.Error())
 		return nil, err
@@ -36,9 +36,10 @@ func (s Service) GetConnectedFriends(_ context.Context, soireeID int64) ([]Profi
 
 	stmt, err := conn.PrepareNeo(`
 		MATCH (u:USER)-[j:JOIN]->(s:SOIREE)
-		OPTIONAL MATCH (u)-[l:LEAVE]->(s)
 		WHERE ID (s) = {id}
-		RETURN u, count(j), count(l)
+		WITH u, s, count(j) AS joins
+		OPTIONAL MATCH (u)-[l:LEAVE]->(s)
+		RETURN u, joins, count(l)
 	`)
 	defer stmt.Close()
 
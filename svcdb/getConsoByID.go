@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetConsoByID(_ context.Context, consoID int64) (Conso, error) {
+func (s Service) GetConsoByID(ctx context.Context, consoID int64) (Conso, error) {
 	var conso Conso
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetConsoByID (WaitConnection) : " + err.Error())
 		return conso, err
@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetConsoByOrderID(_ context.Context, orderID int64) (Conso, error) {
+func (s Service) GetConsoByOrderID(ctx context.Context, orderID int64) (Conso, error) {
 	var conso Conso
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetConsoByOrderID (WaitConnection) : " + err.Error())
 		return conso, err
@@ -24,7 +24,7 @@ import (
 func (s Service) GetConversationByID(ctx context.Context, convID int64) (Conversation, error) {
 	var conv Conversation
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetConversationByID (WaitConnection) : " + err.Error())
 		return conv, err
@@ -21,10 +21,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishment(_ context.Context, estabID int64) (Establishment, error) {
+func (s Service) GetEstablishment(ctx context.Context, estabID int64) (Establishment, error) {
 	var establishment Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishment (WaitConnection) : " + err.Error())
 		return establishment, err
@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentConsos(_ context.Context, estabID int64) ([]Conso, error) {
+func (s Service) GetEstablishmentConsos(ctx context.Context, estabID int64) ([]Conso, error) {
 	var consos []Conso
 	var tmpConso Conso
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("getEstablishmentConsos (WaitConnection) : " + err.Error())
 		return nil, err
@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentFromMenu(_ context.Context, menuID int64) (Establishment, error) {
+func (s Service) GetEstablishmentFromMenu(ctx context.Context, menuID int64) (Establishment, error) {
 	var establishment Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishmentFromMenu (WaitConnection) : " + err.Error())
 		return establishment, err
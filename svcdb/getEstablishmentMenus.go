@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentMenus(_ context.Context, estabID int64) ([]Menu, error) {
+func (s Service) GetEstablishmentMenus(ctx context.Context, estabID int64) ([]Menu, error) {
 	var menus []Menu
 	var tmpMenu Menu
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishmentMenus (WaitConnection) : " + err.Error())
 		return nil, err
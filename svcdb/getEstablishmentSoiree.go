@@ -24,12 +24,12 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentSoiree(_ context.Context, estabID int64) (Soiree, error) {
+func (s Service) GetEstablishmentSoiree(ctx context.Context, estabID int64) (Soiree, error) {
 	var soiree Soiree
 	var soirees []Soiree
 	timeNow := time.Now()
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishmentSoiree (WaitConnection) : " + err.Error())
 		return soiree, err
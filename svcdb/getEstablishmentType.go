@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentType(_ context.Context, estabID int64) (string, error) {
+func (s Service) GetEstablishmentType(ctx context.Context, estabID int64) (string, error) {
 	var tmp EstablishmentType
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishmentType (WaitConnection) : " + err.Error())
 		return "", err
@@ -21,10 +21,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishmentTypes(_ context.Context) ([]string, error) {
+func (s Service) GetEstablishmentTypes(ctx context.Context) ([]string, error) {
 	var establishments []string
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishmentTypes (WaitConnection) : " + err.Error())
 		return nil, err
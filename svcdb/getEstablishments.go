@@ -21,29 +21,35 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetEstablishments(_ context.Context) ([]Establishment, error) {
+func (s Service) GetEstablishments(ctx context.Context, page Page) ([]Establishment, string, error) {
 	var establishments []Establishment
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := make(map[string]interface{})
+	where, tail, err := page.keyset("ID(e)", false, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetEstablishments (WaitConnection) : " + err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	defer CloseConnection(conn)
 
 	stmt, err := conn.PrepareNeo(`
-		MATCH (e:ESTABLISHMENT)
+		MATCH (e:ESTABLISHMENT) WHERE ` + where + `
 		OPTIONAL MATCH (e)<-[:OWN]-(p:PRO)
 		OPTIONAL MATCH (e)<-[r:RATE]-(u:USER)
-		RETURN e, ID(p) as owner, AVG(r.value)
-	`)
+		RETURN e, ID(p) as owner, AVG(r.value)` + tail)
 	if err != nil {
 		fmt.Println("GetAllEstablishments (PrepareNeo)")
 		panic(err)
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryNeo(nil)
+	rows, err := stmt.QueryNeo(args)
 	if err != nil {
 		fmt.Println("GetAllEstablishments (QueryNeo)")
 		panic(err)
@@ -69,46 +75,60 @@ func (s Service) GetEstablishments(_ context.Context) ([]Establishment, error) {
 				(&tmpEstablishment).Rate = rate
 			}
 			establishments = append(establishments, tmpEstablishment)
+			keys = append(keys, tmpEstablishment.ID)
 			tmpEstablishment = Establishment{}
 		}
 		row, _, err = rows.NextNeo()
 	}
-	fmt.Println(establishments)
-	return establishments, nil
+
+	count, next := page.trim(keys)
+	return establishments[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type getEstablishmentsRequest struct {
+	Page Page `json:"page"`
 }
 
 type getEstablishmentsResponse struct {
 	Establishments []Establishment `json:"establishments"`
+	NextCursor     string          `json:"next_cursor,omitempty"`
 	Err            string          `json:"err,omitempty"`
 }
 
 func GetEstablishmentsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		establishments, err := svc.GetEstablishments(ctx)
+		req := request.(getEstablishmentsRequest)
+		establishments, next, err := svc.GetEstablishments(ctx, req.Page)
 		if err != nil {
 			fmt.Println("Error GetEstablishmentsEndpoint 1 : ", err.Error())
-			return getEstablishmentsResponse{establishments, err.Error()}, nil
+			return getEstablishmentsResponse{establishments, next, err.Error()}, nil
 		}
 
 		for i := 0; i < len(establishments); i++ {
 			establishments[i].Type, err = svc.GetEstablishmentType(ctx, establishments[i].ID)
 			if err != nil {
 				fmt.Println("Error GetEstablishmentsEndpoint 2 : ", err.Error())
-				return getEstablishmentsResponse{establishments, err.Error()}, nil
+				return getEstablishmentsResponse{establishments, next, err.Error()}, nil
 			}
 		}
 
-		return getEstablishmentsResponse{establishments, ""}, nil
+		return getEstablishmentsResponse{establishments, next, ""}, nil
 	}
 }
 
 /*************** Transport ***************/
 func DecodeHTTPGetEstablishmentsRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	return nil, nil
+	var request getEstablishmentsRequest
+
+	page, err := PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetEstablishmentsRequest : ", err.Error())
+		return nil, err
+	}
+	(&request).Page = page
+
+	return request, nil
 }
 
 func DecodeHTTPGetEstablishmentsResponse(_ context.Context, r *http.Response) (interface{}, error) {
@@ -131,22 +151,22 @@ func GetEstablishmentsHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tra
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetEstablishments(ctx context.Context) ([]Establishment, error) {
+func (mw serviceLoggingMiddleware) GetEstablishments(ctx context.Context, page Page) ([]Establishment, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getEstablishments",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetEstablishments(ctx)
+	return mw.next.GetEstablishments(ctx, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetEstablishments(ctx context.Context) ([]Establishment, error) {
-	v, err := mw.next.GetEstablishments(ctx)
+func (mw serviceInstrumentingMiddleware) GetEstablishments(ctx context.Context, page Page) ([]Establishment, string, error) {
+	v, next, err := mw.next.GetEstablishments(ctx, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -168,15 +188,21 @@ func BuildGetEstablishmentsEndpoint(svc IService, logger log.Logger, tracer stdo
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetEstablishments(ctx context.Context) ([]Establishment, error) {
+func (e Endpoints) GetEstablishments(ctx context.Context, page Page) ([]Establishment, string, error) {
 	var et []Establishment
 
-	response, err := e.GetEstablishmentsEndpoint(ctx, nil)
+	request := getEstablishmentsRequest{Page: page}
+	response, err := e.GetEstablishmentsEndpoint(ctx, request)
 	if err != nil {
-		return et, err
+		return et, "", err
 	}
 	et = response.(getEstablishmentsResponse).Establishments
-	return et, str2err(response.(getEstablishmentsResponse).Err)
+	return et, response.(getEstablishmentsResponse).NextCursor, str2err(response.(getEstablishmentsResponse).Err)
+}
+
+func EncodeHTTPGetEstablishmentsRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	request.(getEstablishmentsRequest).Page.EncodeToRequest(r)
+	return nil
 }
 
 func ClientGetEstablishments(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
@@ -185,7 +211,7 @@ func ClientGetEstablishments(u *url.URL, logger log.Logger, tracer stdopentracin
 	ceEndpoint = httptransport.NewClient(
 		"GET",
 		copyURL(u, "/establishments"),
-		EncodeHTTPGenericRequest,
+		EncodeHTTPGetEstablishmentsRequest,
 		DecodeHTTPGetEstablishmentsResponse,
 		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
 	).Endpoint()
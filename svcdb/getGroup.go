@@ -21,11 +21,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetGroup(_ context.Context, groupID int64) (Group, error) {
+func (s Service) GetGroup(ctx context.Context, groupID int64) (Group, error) {
 	var group Group
 	var tmpMember Profile
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetGroup (WaitConnection) : " + err.Error())
 		return group, err
@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetGroupsInvitations(_ context.Context, groupID int64) ([]GroupInvitation, error) {
+func (s Service) GetGroupsInvitations(ctx context.Context, groupID int64) ([]GroupInvitation, error) {
 	var invitations []GroupInvitation
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetGroupsInvitations (WaitConnection) : " + err.Error())
 		return nil, err
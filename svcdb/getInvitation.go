@@ -0,0 +1,199 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetInvitation returns the friend invitation invitationID, From the user who
+// sent it To the invited one
+func (s Service) GetInvitation(ctx context.Context, invitationID int64) (Invitation, error) {
+	var invitation Invitation
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetInvitation (WaitConnection) : " + err.Error())
+		return invitation, err
+	}
+	defer CloseConnection(conn)
+
+	stmt, err := conn.PrepareNeo(`
+		MATCH (u:USER)<-[i:INVITE]-(f:USER) WHERE ID(i) = {id}
+		RETURN f, i, u
+	`)
+	defer stmt.Close()
+
+	if err != nil {
+		fmt.Println("GetInvitation (PrepareNeo) : " + err.Error())
+		return invitation, err
+	}
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"id": invitationID,
+	})
+	if err != nil {
+		fmt.Println("GetInvitation (QueryNeo) : " + err.Error())
+		return invitation, err
+	}
+
+	row, _, err := rows.NextNeo()
+	if err != nil {
+		fmt.Println("GetInvitation (NextNeo) : " + err.Error())
+		return invitation, err
+	}
+
+	(&invitation).RelationToInvitation(
+		row[0].(graph.Node),
+		row[1].(graph.Relationship),
+		row[2].(graph.Node))
+	return invitation, nil
+}
+
+/*************** Endpoint ***************/
+type getInvitationRequest struct {
+	ID int64 `json:"id"`
+}
+
+type getInvitationResponse struct {
+	Invitation Invitation `json:"invitation"`
+	Err        string     `json:"err,omitempty"`
+}
+
+func GetInvitationEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getInvitationRequest)
+		invitation, err := svc.GetInvitation(ctx, req.ID)
+		if err != nil {
+			return getInvitationResponse{invitation, err.Error()}, nil
+		}
+		return getInvitationResponse{invitation, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetInvitationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getInvitationRequest
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	invitationID, err := strconv.ParseInt(mux.Vars(r)["InvitationID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).ID = invitationID
+
+	return request, nil
+}
+
+func DecodeHTTPGetInvitationResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getInvitationResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetInvitationHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/invitations/users/{InvitationID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetInvitationEndpoint,
+		DecodeHTTPGetInvitationRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetInvitation", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetInvitation(ctx context.Context, invitationID int64) (Invitation, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getInvitation",
+			"invitationID", invitationID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetInvitation(ctx, invitationID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetInvitation(ctx context.Context, invitationID int64) (Invitation, error) {
+	v, err := mw.next.GetInvitation(ctx, invitationID)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetInvitationEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetInvitation")
+		csLogger := log.With(logger, "method", "GetInvitation")
+
+		csEndpoint = GetInvitationEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetInvitation")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) GetInvitation(ctx context.Context, invitationID int64) (Invitation, error) {
+	var invitation Invitation
+
+	request := getInvitationRequest{ID: invitationID}
+	response, err := e.GetInvitationEndpoint(ctx, request)
+	if err != nil {
+		return invitation, err
+	}
+	invitation = response.(getInvitationResponse).Invitation
+	return invitation, str2err(response.(getInvitationResponse).Err)
+}
+
+func EncodeHTTPGetInvitationRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	id := fmt.Sprintf("%v", request.(getInvitationRequest).ID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("InvitationID", id)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	return nil
+}
+
+func ClientGetInvitation(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var gefmEndpoint endpoint.Endpoint
+
+	gefmEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/invitations/users/{InvitationID:[0-9]+}"),
+		EncodeHTTPGetInvitationRequest,
+		DecodeHTTPGetInvitationResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	gefmEndpoint = opentracing.TraceClient(tracer, "GetInvitation")(gefmEndpoint)
+	return gefmEndpoint, nil
+}
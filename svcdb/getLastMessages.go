@@ -23,14 +23,25 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetLastMessages(_ context.Context, recipient, initiator int64) ([]Message, error) {
+// GetLastMessages returns the newest messages first
+func (s Service) GetLastMessages(ctx context.Context, recipient, initiator int64, page Page) ([]Message, string, error) {
 	var messages []Message
 	var tmpMessage Message
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"recipient": recipient,
+		"initiator": initiator,
+	}
+	where, tail, err := page.keyset("ID(m)", true, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetLastMessages (WaitConnection) : " + err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	defer CloseConnection(conn)
 
@@ -38,23 +49,20 @@ func (s Service) GetLastMessages(_ context.Context, recipient, initiator int64)
 		MATCH (user)-[a]-(m:MESSAGE)-[b]-(recipient)
 		WHERE
 			ID(user) = {initiator} AND
-			ID(recipient) = {recipient}
-		RETURN user, a, m, b, recipient
-	`)
+			ID(recipient) = {recipient} AND
+			` + where + `
+		RETURN user, a, m, b, recipient` + tail)
 	defer stmt.Close()
 	if err != nil {
 		fmt.Println("GetLastMessages (PrepareNeo) : " + err.Error())
-		return messages, err
+		return messages, "", err
 	}
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"recipient": recipient,
-		"initiator": initiator,
-	})
+	rows, err := stmt.QueryNeo(args)
 
 	if err != nil {
 		fmt.Println("GetLastMessages (QueryNeo) : " + err.Error())
-		return messages, err
+		return messages, "", err
 	}
 
 	row, _, err := rows.NextNeo()
@@ -74,31 +82,36 @@ func (s Service) GetLastMessages(_ context.Context, recipient, initiator int64)
 			}
 
 			messages = append(messages, tmpMessage)
+			keys = append(keys, tmpMessage.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
-	return messages, nil
+
+	count, next := page.trim(keys)
+	return messages[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type getLastMessagesRequest struct {
 	recipient int64 `json:"recipient"`
 	initiator int64 `json:"initiator"`
+	Page      Page  `json:"page"`
 }
 
 type getLastMessagesResponse struct {
-	Messages []Message `json:"messages"`
-	Err      string    `json:"err,omitempty"`
+	Messages   []Message `json:"messages"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Err        string    `json:"err,omitempty"`
 }
 
 func GetLastMessagesEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getLastMessagesRequest)
-		messages, err := svc.GetLastMessages(ctx, req.recipient, req.initiator)
+		messages, next, err := svc.GetLastMessages(ctx, req.recipient, req.initiator, req.Page)
 		if err != nil {
-			return getLastMessagesResponse{Messages: messages, Err: err.Error()}, nil
+			return getLastMessagesResponse{Messages: messages, NextCursor: next, Err: err.Error()}, nil
 		}
-		return getLastMessagesResponse{Messages: messages, Err: ""}, nil
+		return getLastMessagesResponse{Messages: messages, NextCursor: next, Err: ""}, nil
 	}
 }
 
@@ -122,6 +135,12 @@ func DecodeHTTPGetLastMessagesRequest(_ context.Context, r *http.Request) (inter
 	}
 	(&request).recipient = to
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -145,7 +164,7 @@ func GetLastMessagesHTTPHandler(endpoints Endpoints, tracer stdopentracing.Trace
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetLastMessages(ctx context.Context, recipient, initiator int64) ([]Message, error) {
+func (mw serviceLoggingMiddleware) GetLastMessages(ctx context.Context, recipient, initiator int64, page Page) ([]Message, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getLastMessages",
@@ -154,15 +173,15 @@ func (mw serviceLoggingMiddleware) GetLastMessages(ctx context.Context, recipien
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetLastMessages(ctx, recipient, initiator)
+	return mw.next.GetLastMessages(ctx, recipient, initiator, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetLastMessages(ctx context.Context, recipient, initiator int64) ([]Message, error) {
-	v, err := mw.next.GetLastMessages(ctx, recipient, initiator)
+func (mw serviceInstrumentingMiddleware) GetLastMessages(ctx context.Context, recipient, initiator int64, page Page) ([]Message, string, error) {
+	v, next, err := mw.next.GetLastMessages(ctx, recipient, initiator, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -184,16 +203,16 @@ func BuildGetLastMessagesEndpoint(svc IService, logger log.Logger, tracer stdope
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetLastMessages(ctx context.Context, recipient, initiator int64) ([]Message, error) {
+func (e Endpoints) GetLastMessages(ctx context.Context, recipient, initiator int64, page Page) ([]Message, string, error) {
 	var s []Message
 
-	request := getLastMessagesRequest{recipient, initiator}
+	request := getLastMessagesRequest{recipient, initiator, page}
 	response, err := e.GetLastMessagesEndpoint(ctx, request)
 	if err != nil {
-		return s, err
+		return s, "", err
 	}
 	s = response.(getLastMessagesResponse).Messages
-	return s, str2err(response.(getLastMessagesResponse).Err)
+	return s, response.(getLastMessagesResponse).NextCursor, str2err(response.(getLastMessagesResponse).Err)
 }
 
 func EncodeHTTPGetLastMessagesRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -207,6 +226,7 @@ func EncodeHTTPGetLastMessagesRequest(ctx context.Context, r *http.Request, requ
 		return err
 	}
 	r.URL.Path = encodedUrl.Path
+	request.(getLastMessagesRequest).Page.EncodeToRequest(r)
 
 	return nil
 }
@@ -0,0 +1,211 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetLedger returns the entries selected by filter, oldest first. A filter
+// without order nor pro is rejected, the whole ledger is never read at once.
+func (s Service) GetLedger(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	var entries []LedgerEntry
+	var where []string
+
+	if filter.OrderID == 0 && filter.ProID == 0 {
+		return entries, RequestError
+	}
+	if filter.OrderID != 0 {
+		where = append(where, `l.Order = {order}`)
+	}
+	if filter.ProID != 0 {
+		where = append(where, `l.Pro = {pro}`)
+	}
+	if !filter.From.IsZero() {
+		where = append(where, `l.Date >= {from}`)
+	}
+	if !filter.To.IsZero() {
+		where = append(where, `l.Date < {to}`)
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetLedger (WaitConnection) : " + err.Error())
+		return entries, err
+	}
+	defer CloseConnection(conn)
+
+	req := `MATCH (l:LEDGER) WHERE `
+	for i, cond := range where {
+		if i > 0 {
+			req += ` AND `
+		}
+		req += cond
+	}
+	req += ` RETURN l ORDER BY l.Date, ID(l)`
+
+	stmt, err := conn.PrepareNeo(req)
+	if err != nil {
+		fmt.Println("GetLedger (PrepareNeo) : " + err.Error())
+		return entries, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"order": filter.OrderID,
+		"pro":   filter.ProID,
+		"from":  formatTime(filter.From),
+		"to":    formatTime(filter.To),
+	})
+	if err != nil {
+		fmt.Println("GetLedger (QueryNeo) : " + err.Error())
+		return entries, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		var entry LedgerEntry
+
+		(&entry).NodeToLedgerEntry(row[0].(graph.Node))
+		entries = append(entries, entry)
+		row, _, err = rows.NextNeo()
+	}
+	if err != nil && err != io.EOF {
+		fmt.Println("GetLedger (NextNeo) : " + err.Error())
+		return entries, err
+	}
+	return entries, nil
+}
+
+/*************** Endpoint ***************/
+type getLedgerRequest struct {
+	Filter LedgerFilter `json:"filter"`
+}
+
+type getLedgerResponse struct {
+	Entries []LedgerEntry `json:"entries"`
+	Err     string        `json:"err,omitempty"`
+}
+
+func GetLedgerEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getLedgerRequest)
+		entries, err := svc.GetLedger(ctx, req.Filter)
+		if err != nil {
+			fmt.Println("Error GetLedgerEndpoint : ", err.Error())
+			return getLedgerResponse{Entries: entries, Err: err.Error()}, nil
+		}
+		return getLedgerResponse{Entries: entries, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetLedgerRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getLedgerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPGetLedgerRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPGetLedgerResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getLedgerResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetLedgerResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetLedgerHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/ledger/search").Handler(httptransport.NewServer(
+		endpoints.GetLedgerEndpoint,
+		DecodeHTTPGetLedgerRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetLedger", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetLedger(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getLedger",
+			"order", filter.OrderID,
+			"pro", filter.ProID,
+			"from", filter.From,
+			"to", filter.To,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetLedger(ctx, filter)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetLedger(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	v, err := mw.next.GetLedger(ctx, filter)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetLedgerEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetLedger")
+		csLogger := log.With(logger, "method", "GetLedger")
+
+		csEndpoint = GetLedgerEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetLedger")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) GetLedger(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	request := getLedgerRequest{Filter: filter}
+	response, err := e.GetLedgerEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error GetLedger : ", err.Error())
+		return nil, err
+	}
+	return response.(getLedgerResponse).Entries, str2err(response.(getLedgerResponse).Err)
+}
+
+func ClientGetLedger(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/ledger/search"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPGetLedgerResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetLedger")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetMenuConsos(_ context.Context, menuID int64) ([]Conso, error) {
+func (s Service) GetMenuConsos(ctx context.Context, menuID int64) ([]Conso, error) {
 	var consos []Conso
 	var tmpConso Conso
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("getMenuConsos (WaitConnection) : " + err.Error())
 		return nil, err
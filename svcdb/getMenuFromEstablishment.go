@@ -22,11 +22,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetMenuFromEstablishment(_ context.Context, estabID int64) ([]Menu, error) {
+func (s Service) GetMenuFromEstablishment(ctx context.Context, estabID int64) ([]Menu, error) {
 	var menus []Menu
 	var tmpMenu Menu
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetMenuFromEstablishment (WaitConnection) : " + err.Error())
 		return nil, err
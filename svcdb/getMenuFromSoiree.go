@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetMenuFromSoiree(_ context.Context, soireeID int64) (Menu, error) {
+func (s Service) GetMenuFromSoiree(ctx context.Context, soireeID int64) (Menu, error) {
 	var menu Menu
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetMenuFromSoiree (WaitConnection) : " + err.Error())
 		return menu, err
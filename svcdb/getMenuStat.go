@@ -0,0 +1,222 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetMenuStat sums up the orders of the soirees using the menu, issued in
+// [from, to). Stored dates sort as strings, see formatTime.
+func (s Service) GetMenuStat(ctx context.Context, menuID int64, from, to time.Time) (MenuStat, error) {
+	stat := MenuStat{Menu: menuID, From: from, To: to}
+
+	if !validRange(from, to) {
+		return stat, InvalidRangeErr
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetMenuStat (WaitConnection) : " + err.Error())
+		return stat, err
+	}
+	defer CloseConnection(conn)
+
+	stmt, err := conn.PrepareNeo(`
+		MATCH (m:MENU)<-[:USE]-(:SOIREE)<-[:DURING]-(o:ORDER)-[:DONE]->(st:STEP { Name: "Issued" })
+		WHERE ID(m) = {menuID} AND st.Date >= {from} AND st.Date < {to}
+		OPTIONAL MATCH (o)-[:DONE]->(r:STEP { Name: "Ready" })
+		OPTIONAL MATCH (o)-[:DONE]->(c:STEP { Name: "Completed" })
+		RETURN o.Price, o.Done, r.Date, c.Date`)
+	if err != nil {
+		fmt.Println("GetMenuStat (PrepareNeo) : " + err.Error())
+		return stat, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"menuID": menuID,
+		"from":   formatTime(from),
+		"to":     formatTime(to),
+	})
+	if err != nil {
+		fmt.Println("GetMenuStat (QueryNeo) : " + err.Error())
+		return stat, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		var tmpOrder Order
+		var ready, completed time.Time
+
+		tmpOrder.Price = storedMoney(row[0])
+		tmpOrder.Done, _ = row[1].(string)
+		if row[2] != nil {
+			ready = storedTime("GetMenuStat", "Date", row[2])
+		}
+		if row[3] != nil {
+			completed = storedTime("GetMenuStat", "Date", row[3])
+		}
+		(&stat).add(tmpOrder, ready, completed)
+
+		row, _, err = rows.NextNeo()
+	}
+	if err != nil && err != io.EOF {
+		fmt.Println("GetMenuStat (NextNeo) : " + err.Error())
+		return stat, err
+	}
+
+	return stat, nil
+}
+
+/*************** Endpoint ***************/
+type getMenuStatRequest struct {
+	MenuID int64     `json:"menuID"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+type getMenuStatResponse struct {
+	Stat MenuStat `json:"stat"`
+	Err  string   `json:"err,omitempty"`
+}
+
+func GetMenuStatEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getMenuStatRequest)
+		stat, err := svc.GetMenuStat(ctx, req.MenuID, req.From, req.To)
+		if err != nil {
+			fmt.Println("Error GetMenuStatEndpoint : ", err.Error())
+			return getMenuStatResponse{stat, err.Error()}, nil
+		}
+		return getMenuStatResponse{stat, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetMenuStatRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getMenuStatRequest
+
+	menuID, err := strconv.ParseInt(mux.Vars(r)["menuID"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	(&request).MenuID = menuID
+
+	from, to, err := RangeFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	(&request).From, (&request).To = from, to
+
+	return request, nil
+}
+
+func DecodeHTTPGetMenuStatResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getMenuStatResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetMenuStatResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetMenuStatHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/analyses/Menu/{menuID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetMenuStatEndpoint,
+		DecodeHTTPGetMenuStatRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetMenuStat", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetMenuStat(ctx context.Context, menuID int64, from, to time.Time) (MenuStat, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getMenuStat",
+			"menuID", menuID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetMenuStat(ctx, menuID, from, to)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetMenuStat(ctx context.Context, menuID int64, from, to time.Time) (MenuStat, error) {
+	v, err := mw.next.GetMenuStat(ctx, menuID, from, to)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetMenuStatEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetMenuStat")
+		csLogger := log.With(logger, "method", "GetMenuStat")
+
+		csEndpoint = GetMenuStatEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetMenuStat")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) GetMenuStat(ctx context.Context, menuID int64, from, to time.Time) (MenuStat, error) {
+	request := getMenuStatRequest{MenuID: menuID, From: from, To: to}
+	response, err := e.GetMenuStatEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error Client GetMenuStat : ", err.Error())
+		return MenuStat{}, err
+	}
+	return response.(getMenuStatResponse).Stat, str2err(response.(getMenuStatResponse).Err)
+}
+
+func EncodeHTTPGetMenuStatRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(getMenuStatRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("menuID", fmt.Sprintf("%v", req.MenuID))
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	EncodeRangeToRequest(r, req.From, req.To)
+	return nil
+}
+
+func ClientGetMenuStat(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/analyses/Menu/{menuID:[0-9]+}"),
+		EncodeHTTPGetMenuStatRequest,
+		DecodeHTTPGetMenuStatResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetMenuStat")(ceEndpoint)
+	return ceEndpoint, nil
+}
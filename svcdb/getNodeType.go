@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetNodeType(_ context.Context, nodeID int64) (string, error) {
+func (s Service) GetNodeType(ctx context.Context, nodeID int64) (string, error) {
 	var nodeType string
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("getNodeType (WaitConnection) : " + err.Error())
 		return "", err
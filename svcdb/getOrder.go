@@ -23,7 +23,7 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetOrder(_ context.Context, orderID int64) (Order, error) {
+func (s Service) GetOrder(ctx context.Context, orderID int64) (Order, error) {
 	var elementsNode map[int64]graph.Node
 	var elementsRel []graph.Relationship
 	var order Order
@@ -31,7 +31,7 @@ func (s Service) GetOrder(_ context.Context, orderID int64) (Order, error) {
 	elementsNode = make(map[int64]graph.Node)
 	elementsDone := make(map[int64]bool)
 	
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetOrder (WaitConnection) : " + err.Error())
 		return order, err
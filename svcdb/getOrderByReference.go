@@ -0,0 +1,171 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetOrderByReference returns the order one of whose users paid with the
+// charge reference, an order with ID 0 when there is none
+func (s Service) GetOrderByReference(ctx context.Context, reference string) (Order, error) {
+	var order Order
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetOrderByReference (WaitConnection) : " + err.Error())
+		return order, err
+	}
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (o:ORDER)-[r:TO]->(:USER)
+		WHERE r.Reference = {reference}
+		RETURN ID(o) LIMIT 1`, map[string]interface{}{
+		"reference": reference,
+	})
+	CloseConnection(conn)
+	if err != nil {
+		fmt.Println("GetOrderByReference (QueryNeoAll) : " + err.Error())
+		return order, err
+	}
+	if len(data) == 0 {
+		return order, nil
+	}
+
+	order, err = s.GetOrder(ctx, data[0][0].(int64))
+	if err != nil {
+		fmt.Println("GetOrderByReference (GetOrder) : " + err.Error())
+		return order, err
+	}
+	return order, nil
+}
+
+/*************** Endpoint ***************/
+type getOrderByReferenceRequest struct {
+	Reference string `json:"reference"`
+}
+
+type getOrderByReferenceResponse struct {
+	Order Order  `json:"order"`
+	Err   string `json:"err,omitempty"`
+}
+
+func GetOrderByReferenceEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getOrderByReferenceRequest)
+		order, err := svc.GetOrderByReference(ctx, req.Reference)
+		if err != nil {
+			fmt.Println("Error GetOrderByReferenceEndpoint : ", err.Error())
+			return getOrderByReferenceResponse{Order: order, Err: err.Error()}, nil
+		}
+		return getOrderByReferenceResponse{Order: order, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetOrderByReferenceRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request getOrderByReferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPGetOrderByReferenceRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPGetOrderByReferenceResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getOrderByReferenceResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetOrderByReferenceResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetOrderByReferenceHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/by_reference").Handler(httptransport.NewServer(
+		endpoints.GetOrderByReferenceEndpoint,
+		DecodeHTTPGetOrderByReferenceRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetOrderByReference", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetOrderByReference(ctx context.Context, reference string) (Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getOrderByReference",
+			"reference", reference,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetOrderByReference(ctx, reference)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetOrderByReference(ctx context.Context, reference string) (Order, error) {
+	v, err := mw.next.GetOrderByReference(ctx, reference)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetOrderByReferenceEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetOrderByReference")
+		csLogger := log.With(logger, "method", "GetOrderByReference")
+
+		csEndpoint = GetOrderByReferenceEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetOrderByReference")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) GetOrderByReference(ctx context.Context, reference string) (Order, error) {
+	var order Order
+
+	request := getOrderByReferenceRequest{Reference: reference}
+	response, err := e.GetOrderByReferenceEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error GetOrderByReference : ", err.Error())
+		return order, err
+	}
+	return response.(getOrderByReferenceResponse).Order, str2err(response.(getOrderByReferenceResponse).Err)
+}
+
+func ClientGetOrderByReference(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/by_reference"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPGetOrderByReferenceResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetOrderByReference")(ceEndpoint)
+	return ceEndpoint, nil
+}
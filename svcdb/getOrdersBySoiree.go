@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetOrdersBySoiree(_ context.Context, soireeID int64) ([]Order, error) {
+func (s Service) GetOrdersBySoiree(ctx context.Context, soireeID int64) ([]Order, error) {
 	var orders []Order
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetOrdersBySoiree (WaitConnection) : " + err.Error())
 		return nil, err
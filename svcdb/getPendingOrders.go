@@ -0,0 +1,176 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// GetPendingOrders returns the orders not done yet with their steps, the ones
+// svcpayment checks against the timeouts of OrderWorkflow
+func (s Service) GetPendingOrders(ctx context.Context) ([]Order, error) {
+	var orders []Order
+	var orderIDs []int64
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("GetPendingOrders (WaitConnection) : " + err.Error())
+		return nil, err
+	}
+	defer CloseConnection(conn)
+
+	stmt, err := conn.PrepareNeo(`
+		MATCH (o:ORDER)
+		WHERE NOT EXISTS(o.Done) OR o.Done = ""
+		RETURN ID(o) ORDER BY ID(o)`)
+	if err != nil {
+		fmt.Println("GetPendingOrders (PrepareNeo) : " + err.Error())
+		return nil, err
+	}
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{})
+	if err != nil {
+		stmt.Close()
+		fmt.Println("GetPendingOrders (QueryNeo) : " + err.Error())
+		return nil, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		orderIDs = append(orderIDs, row[0].(int64))
+		row, _, err = rows.NextNeo()
+	}
+	stmt.Close()
+	if err != nil && err != io.EOF {
+		fmt.Println("GetPendingOrders (NextNeo) : " + err.Error())
+		return nil, err
+	}
+
+	for _, orderID := range orderIDs {
+		order, err := s.GetOrder(ctx, orderID)
+		if err != nil {
+			fmt.Println("GetPendingOrders (GetOrder) : " + err.Error())
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+/*************** Endpoint ***************/
+type getPendingOrdersRequest struct{}
+
+type getPendingOrdersResponse struct {
+	Orders []Order `json:"orders"`
+	Err    string  `json:"err,omitempty"`
+}
+
+func GetPendingOrdersEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		orders, err := svc.GetPendingOrders(ctx)
+		if err != nil {
+			fmt.Println("Error GetPendingOrdersEndpoint : ", err.Error())
+			return getPendingOrdersResponse{orders, err.Error()}, nil
+		}
+		return getPendingOrdersResponse{orders, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetPendingOrdersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return getPendingOrdersRequest{}, nil
+}
+
+func DecodeHTTPGetPendingOrdersResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response getPendingOrdersResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPGetPendingOrdersResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func GetPendingOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/orders/pending").Handler(httptransport.NewServer(
+		endpoints.GetPendingOrdersEndpoint,
+		DecodeHTTPGetPendingOrdersRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetPendingOrders", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetPendingOrders(ctx context.Context) ([]Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "getPendingOrders",
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetPendingOrders(ctx)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetPendingOrders(ctx context.Context) ([]Order, error) {
+	v, err := mw.next.GetPendingOrders(ctx)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetPendingOrdersEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetPendingOrders")
+		csLogger := log.With(logger, "method", "GetPendingOrders")
+
+		csEndpoint = GetPendingOrdersEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetPendingOrders")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) GetPendingOrders(ctx context.Context) ([]Order, error) {
+	response, err := e.GetPendingOrdersEndpoint(ctx, getPendingOrdersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return response.(getPendingOrdersResponse).Orders, str2err(response.(getPendingOrdersResponse).Err)
+}
+
+func ClientGetPendingOrders(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/orders/pending"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPGetPendingOrdersResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetPendingOrders")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -3,7 +3,6 @@ package svcdb
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetPro(_ context.Context, u Pro) (Pro, error) {
+func (s Service) GetPro(ctx context.Context, u Pro) (Pro, error) {
 	var pro Pro
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetPro (WaitConnection) : " + err.Error())
 		return pro, err
@@ -54,11 +53,19 @@ func (s Service) GetPro(_ context.Context, u Pro) (Pro, error) {
 	}
 
 	row, _, err := rows.NextNeo()
-	if err != nil {
+	if err == io.EOF {
+		checkUnknownPassword(u.Password)
+		return pro, err
+	} else if err != nil {
 		fmt.Println("GetPro (NextNeo) : " + err.Error())
 		return pro, err
 	}
-	(&pro).NodeToPro(row[0].(graph.Node))
+	node := row[0].(graph.Node)
+	ok, upgrade := checkPassword(storedPassword(node), u.Password)
+	if !ok {
+		return pro, InvalidPasswordErr
+	}
+	(&pro).NodeToPro(node)
 
 	for row != nil && err == nil {
 		if err != nil && err != io.EOF {
@@ -72,11 +79,9 @@ func (s Service) GetPro(_ context.Context, u Pro) (Pro, error) {
 		row, _, err = rows.NextNeo()
 	}
 
-	if pro.Password != u.Password {
-		err = errors.New("Invalid password")
-		return pro, err
+	if upgrade {
+		upgradePassword(ctx, "PRO", pro.ID, u.Password)
 	}
-
 	return pro, nil
 }
 
@@ -137,7 +142,7 @@ func (mw serviceLoggingMiddleware) GetPro(ctx context.Context, u Pro) (Pro, erro
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getPro",
-			"pro", u,
+			"pro", u.Redacted(),
 			"took", time.Since(begin),
 		)
 	}(time.Now())
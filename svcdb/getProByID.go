@@ -23,10 +23,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetProByID(_ context.Context, proID int64) (Pro, error) {
+func (s Service) GetProByID(ctx context.Context, proID int64) (Pro, error) {
 	var pro Pro
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetProByID (WaitConnection) : " + err.Error())
 		return pro, err
@@ -24,7 +24,7 @@ import (
 func (s Service) GetProBySoiree(ctx context.Context, soireeID int64) (Pro, error) {
 	var pro Pro
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetProBySoiree (WaitConnection) : " + err.Error())
 		return pro, err
@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetProEstablishments(_ context.Context, soireeID int64) ([]Establishment, error) {
+func (s Service) GetProEstablishments(ctx context.Context, soireeID int64) ([]Establishment, error) {
 	var establishments []Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetProEstablishments (WaitConnection) : " + err.Error())
 		return nil, err
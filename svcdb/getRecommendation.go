@@ -7,7 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"io"
-
+	"strconv"
 	"time"
 
 	"github.com/go-kit/kit/tracing/opentracing"
@@ -22,81 +22,115 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetRecommendation(_ context.Context, userID int64) ([]Establishment, error) {
-	var establishments []Establishment
+func (s Service) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]Recommendation, error) {
+	var signals []recommendationSignals
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetRecommendation (WaitConnection) : " + err.Error())
-		return establishments, err
+		return nil, err
 	}
 	defer CloseConnection(conn)
 
+	// Each OPTIONAL MATCH is aggregated before the next one, so that the
+	// counts do not multiply each other
 	stmt, err := conn.PrepareNeo(`
-		MATCH (e:ESTABLISHMENT)<-[r:RATE]-(USER) WITH e, AVG(r.value) AS rav
-		ORDER BY rav DESC LIMIT 3 RETURN e
+		MATCH (u:USER), (e:ESTABLISHMENT) WHERE ID(u) = {id}
+		OPTIONAL MATCH (e)-[:IS]->(t:ESTABLISHMENT_TYPE)
+		OPTIONAL MATCH (u)-[p:PREFER]->(t)
+		WITH u, e, t.Name AS type, COUNT(p) > 0 AS preferred
+		OPTIONAL MATCH (u)-[:KNOW]-(f:USER)-[:JOIN]->(:SOIREE)<-[:SPAWNED]-(e)
+		WITH u, e, type, preferred, COUNT(DISTINCT f) AS friends
+		OPTIONAL MATCH (u)-[mr:RATE]->(e)
+		WITH u, e, type, preferred, friends, MAX(mr.value) AS ownRate
+		OPTIONAL MATCH (u)<-[:TO]-(o:ORDER)-[:DURING]->(:SOIREE)<-[:SPAWNED]-(e)
+		WITH e, type, preferred, friends, ownRate, COUNT(DISTINCT o) AS orders
+		OPTIONAL MATCH (e)<-[r:RATE]-(:USER)
+		RETURN e, type, preferred, friends, ownRate, orders, AVG(r.value)
 	`)
-	defer stmt.Close()
-
 	if err != nil {
 		fmt.Println("GetRecommendation (PrepareNeo) : " + err.Error())
-		return establishments, err
+		return nil, err
 	}
-	
+	defer stmt.Close()
+
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"id": userID,
 	})
-
 	if err != nil {
 		fmt.Println("GetRecommendation (QueryNeo) : " + err.Error())
-		return establishments, err
+		return nil, err
 	}
 
-	var tmpEstab Establishment
-
 	row, _, err := rows.NextNeo()
 	for row != nil && err == nil {
-		if err != nil && err != io.EOF {
-			fmt.Println("GetRecommendation (---)")
-			panic(err)
-		} else if err != io.EOF {
-			(&tmpEstab).NodeToEstablishment(row[0].(graph.Node))
+		var tmpSignals recommendationSignals
 
-			establishments = append(establishments, tmpEstab)
+		(&tmpSignals.Establishment).NodeToEstablishment(row[0].(graph.Node))
+		if estabType, ok := row[1].(string); ok {
+			tmpSignals.Establishment.Type = estabType
 		}
+		tmpSignals.Preferred, _ = row[2].(bool)
+		tmpSignals.Friends, _ = row[3].(int64)
+		tmpSignals.OwnRate, _ = row[4].(int64)
+		tmpSignals.Orders, _ = row[5].(int64)
+		if rate, ok := row[6].(float64); ok {
+			tmpSignals.Establishment.Rate = rate
+		}
+
+		signals = append(signals, tmpSignals)
 		row, _, err = rows.NextNeo()
 	}
-	return establishments, nil
+	if err != nil && err != io.EOF {
+		fmt.Println("GetRecommendation (NextNeo) : " + err.Error())
+		return nil, err
+	}
+
+	return rankRecommendations(signals, lat, long), nil
 }
 
 /*************** Endpoint ***************/
 type getRecommendationRequest struct {
-	UserID int64 `json:"id"`
+	UserID int64   `json:"id"`
+	Lat    float64 `json:"lat,omitempty"`
+	Long   float64 `json:"long,omitempty"`
 }
 
 type getRecommendationResponse struct {
-	Establishments	[]Establishment `json:"establishments"`
-	Err				string        `json:"err,omitempty"`
+	Recommendations []Recommendation `json:"recommendations"`
+	Err             string           `json:"err,omitempty"`
 }
 
 func GetRecommendationEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getRecommendationRequest)
-		establishments, err := svc.GetRecommendation(ctx, req.UserID)
+		recos, err := svc.GetRecommendation(ctx, req.UserID, req.Lat, req.Long)
 		if err != nil {
 			fmt.Println("Error GetRecommendationEndpoint 1 : ", err.Error())
-			return getRecommendationResponse{establishments, err.Error()}, nil
+			return getRecommendationResponse{recos, err.Error()}, nil
 		}
-		return getRecommendationResponse{establishments, ""}, nil
+		return getRecommendationResponse{recos, ""}, nil
 	}
 }
 
 /*************** Transport ***************/
 func DecodeHTTPGetRecommendationRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var request getRecommendationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetRecommendationRequest 1 : ", err.Error())
 		return nil, err
 	}
+	(&request).UserID = userID
+
+	lat, long, err := LocationFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetRecommendationRequest 2 : ", err.Error())
+		return nil, err
+	}
+	(&request).Lat, (&request).Long = lat, long
+
 	return request, nil
 }
 
@@ -120,7 +154,7 @@ func GetRecommendationHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tra
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetRecommendation(ctx context.Context, userID int64) ([]Establishment, error) {
+func (mw serviceLoggingMiddleware) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]Recommendation, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getRecommendation",
@@ -128,13 +162,13 @@ func (mw serviceLoggingMiddleware) GetRecommendation(ctx context.Context, userID
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetRecommendation(ctx, userID)
+	return mw.next.GetRecommendation(ctx, userID, lat, long)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetRecommendation(ctx context.Context, userID int64) ([]Establishment, error) {
-	v, err := mw.next.GetRecommendation(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]Recommendation, error) {
+	v, err := mw.next.GetRecommendation(ctx, userID, lat, long)
 	mw.ints.Add(1)
 	return v, err
 }
@@ -158,16 +192,29 @@ func BuildGetRecommendationEndpoint(svc IService, logger log.Logger, tracer stdo
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetRecommendation(ctx context.Context, userID int64) ([]Establishment, error) {
-	var ets []Establishment
+func (e Endpoints) GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]Recommendation, error) {
+	var recos []Recommendation
 
-	request := getRecommendationRequest{UserID: userID}
+	request := getRecommendationRequest{UserID: userID, Lat: lat, Long: long}
 	response, err := e.GetRecommendationEndpoint(ctx, request)
 	if err != nil {
-		return ets, err
+		return recos, err
+	}
+	recos = response.(getRecommendationResponse).Recommendations
+	return recos, str2err(response.(getRecommendationResponse).Err)
+}
+
+func EncodeHTTPGetRecommendationRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(getRecommendationRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("id", fmt.Sprintf("%v", req.UserID))
+	if err != nil {
+		fmt.Println("Error EncodeHTTPGetRecommendationRequest : ", err.Error())
+		return err
 	}
-	ets = response.(getRecommendationResponse).Establishments
-	return ets, str2err(response.(getRecommendationResponse).Err)
+	r.URL.Path = encodedUrl.Path
+	EncodeLocationToRequest(r, req.Lat, req.Long)
+	return nil
 }
 
 func ClientGetRecommendation(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
@@ -176,7 +223,7 @@ func ClientGetRecommendation(u *url.URL, logger log.Logger, tracer stdopentracin
 	ceEndpoint = httptransport.NewClient(
 		"GET",
 		copyURL(u, "/recommendation/{id}"),
-		EncodeHTTPGenericRequest,
+		EncodeHTTPGetRecommendationRequest,
 		DecodeHTTPGetRecommendationResponse,
 		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
 	).Endpoint()
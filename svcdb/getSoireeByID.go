@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetSoireeByID(_ context.Context, soireeID int64) (Soiree, error) {
+func (s Service) GetSoireeByID(ctx context.Context, soireeID int64) (Soiree, error) {
 	var soiree Soiree
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetSoireeByID (WaitConnection) : " + err.Error())
 		return soiree, err
@@ -23,31 +23,38 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetSoireeOrders(_ context.Context, soireeID int64) ([]Order, error) {
+func (s Service) GetSoireeOrders(ctx context.Context, soireeID int64, page Page) ([]Order, string, error) {
 	var orders []Order
 	var tmpOrder Order
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"id": soireeID,
+	}
+	where, tail, err := page.keyset("ID(o)", false, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetSoireeOrders (WaitConnection) : " + err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	defer CloseConnection(conn)
 
-	stmt, err := conn.PrepareNeo(`MATCH (o:ORDER)-[:DURING]->(s:SOIREE) WHERE ID(s) = {id} RETURN o`)
+	stmt, err := conn.PrepareNeo(`MATCH (o:ORDER)-[:DURING]->(s:SOIREE) WHERE ID(s) = {id} AND ` + where + ` RETURN o` + tail)
 	defer stmt.Close()
 	if err != nil {
 		fmt.Println("GetSoireeOrders (PrepareNeo) : " + err.Error())
-		return orders, err
+		return orders, "", err
 	}
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"id": soireeID,
-	})
+	rows, err := stmt.QueryNeo(args)
 
 	if err != nil {
 		fmt.Println("GetSoireeOrders (QueryNeo) : " + err.Error())
-		return orders, err
+		return orders, "", err
 	}
 
 	row, _, err := rows.NextNeo()
@@ -58,31 +65,35 @@ func (s Service) GetSoireeOrders(_ context.Context, soireeID int64) ([]Order, er
 		} else if err != io.EOF {
 			(&tmpOrder).NodeToOrder(row[0].(graph.Node))
 			orders = append(orders, tmpOrder)
+			keys = append(keys, tmpOrder.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
 
-	return orders, nil
+	count, next := page.trim(keys)
+	return orders[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type getSoireeOrdersRequest struct {
 	SoireeID int64 `json:"id"`
+	Page     Page  `json:"page"`
 }
 
 type getSoireeOrdersResponse struct {
-	Orders []Order `json:"orders"`
-	Err    string  `json:"err,omitempty"`
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	Err        string  `json:"err,omitempty"`
 }
 
 func GetSoireeOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getSoireeOrdersRequest)
-		orders, err := svc.GetSoireeOrders(ctx, req.SoireeID)
+		orders, next, err := svc.GetSoireeOrders(ctx, req.SoireeID, req.Page)
 		if err != nil {
-			return getSoireeOrdersResponse{Orders: orders, Err: err.Error()}, nil
+			return getSoireeOrdersResponse{Orders: orders, NextCursor: next, Err: err.Error()}, nil
 		}
-		return getSoireeOrdersResponse{Orders: orders, Err: ""}, nil
+		return getSoireeOrdersResponse{Orders: orders, NextCursor: next, Err: ""}, nil
 	}
 }
 
@@ -100,6 +111,12 @@ func DecodeHTTPGetSoireeOrdersRequest(_ context.Context, r *http.Request) (inter
 	}
 	(&request).SoireeID = soireeID
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -123,7 +140,7 @@ func GetSoireeOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Trace
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, soireeID int64) ([]Order, error) {
+func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, soireeID int64, page Page) ([]Order, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getSoireeOrders",
@@ -131,15 +148,15 @@ func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, soireeID
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetSoireeOrders(ctx, soireeID)
+	return mw.next.GetSoireeOrders(ctx, soireeID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetSoireeOrders(ctx context.Context, soireeID int64) ([]Order, error) {
-	v, err := mw.next.GetSoireeOrders(ctx, soireeID)
+func (mw serviceInstrumentingMiddleware) GetSoireeOrders(ctx context.Context, soireeID int64, page Page) ([]Order, string, error) {
+	v, next, err := mw.next.GetSoireeOrders(ctx, soireeID, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -161,16 +178,16 @@ func BuildGetSoireeOrdersEndpoint(svc IService, logger log.Logger, tracer stdope
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetSoireeOrders(ctx context.Context, soireeID int64) ([]Order, error) {
+func (e Endpoints) GetSoireeOrders(ctx context.Context, soireeID int64, page Page) ([]Order, string, error) {
 	var s []Order
 
-	request := getSoireeOrdersRequest{SoireeID: soireeID}
+	request := getSoireeOrdersRequest{SoireeID: soireeID, Page: page}
 	response, err := e.GetSoireeOrdersEndpoint(ctx, request)
 	if err != nil {
-		return s, err
+		return s, "", err
 	}
 	s = response.(getSoireeOrdersResponse).Orders
-	return s, str2err(response.(getSoireeOrdersResponse).Err)
+	return s, response.(getSoireeOrdersResponse).NextCursor, str2err(response.(getSoireeOrdersResponse).Err)
 }
 
 func EncodeHTTPGetSoireeOrdersRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -181,6 +198,7 @@ func EncodeHTTPGetSoireeOrdersRequest(ctx context.Context, r *http.Request, requ
 		return err
 	}
 	r.URL.Path = encodedUrl.Path
+	request.(getSoireeOrdersRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
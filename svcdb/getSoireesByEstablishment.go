@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetSoireesByEstablishment(_ context.Context, estabID int64) ([]Soiree, error) {
+func (s Service) GetSoireesByEstablishment(ctx context.Context, estabID int64) ([]Soiree, error) {
 	var soirees []Soiree
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetSoireesByEstablishment (WaitConnection) : " + err.Error())
 		return nil, err
@@ -21,10 +21,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetSuccessByValue(_ context.Context, value string) (Success, error) {
+func (s Service) GetSuccessByValue(ctx context.Context, value string) (Success, error) {
 	var success Success
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetSuccessByValue (WaitConnection) : " + err.Error())
 		return success, err
@@ -3,8 +3,8 @@ package svcdb
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -21,10 +21,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUser(_ context.Context, u User) (User, error) {
+func (s Service) GetUser(ctx context.Context, u User) (User, error) {
 	var user User
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUser (WaitConnection) : " + err.Error())
 		return user, err
@@ -51,18 +51,24 @@ func (s Service) GetUser(_ context.Context, u User) (User, error) {
 	}
 
 	row, _, err := rows.NextNeo()
-	if err != nil {
+	if err == io.EOF {
+		checkUnknownPassword(u.Password)
+		return user, err
+	} else if err != nil {
 		fmt.Println("GetUser (NextNeo) : " + err.Error())
 		return user, err
 	}
 
-	(&user).NodeToUser(row[0].(graph.Node))
-
-	if user.Password != u.Password {
-		err = errors.New("Invalid password")
-		return user, err
+	node := row[0].(graph.Node)
+	ok, upgrade := checkPassword(storedPassword(node), u.Password)
+	if !ok {
+		return user, InvalidPasswordErr
 	}
+	(&user).NodeToUser(node)
 
+	if upgrade {
+		upgradePassword(ctx, "USER", user.ID, u.Password)
+	}
 	return user, nil
 }
 
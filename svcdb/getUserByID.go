@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserByID(_ context.Context, userID int64) (User, error) {
+func (s Service) GetUserByID(ctx context.Context, userID int64) (User, error) {
 	var user User
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUserByID (WaitConnection) : " + err.Error())
 		return user, err
@@ -22,31 +22,38 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserFriends(_ context.Context, userID int64) ([]Profile, error) {
+func (s Service) GetUserFriends(ctx context.Context, userID int64, page Page) ([]Profile, string, error) {
 	var menus []Profile
 	var tmpProfile Profile
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"id": userID,
+	}
+	where, tail, err := page.keyset("ID(friends)", false, args)
+	if err != nil {
+		return menus, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUserFriends (WaitConnection) : " + err.Error())
-		return menus, err
+		return menus, "", err
 	}
 	defer CloseConnection(conn)
 
-	stmt, err := conn.PrepareNeo(`MATCH (u:USER)-[:KNOW]-(friends:USER) WHERE ID(u) = {id} RETURN friends`)
+	stmt, err := conn.PrepareNeo(`MATCH (u:USER)-[:KNOW]-(friends:USER) WHERE ID(u) = {id} AND ` + where + ` RETURN friends` + tail)
 	defer stmt.Close()
 	if err != nil {
 		fmt.Println("Error GetUserFriends (PrepareNeo) : " + err.Error())
-		return menus, err
+		return menus, "", err
 	}
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"id": userID,
-	})
+	rows, err := stmt.QueryNeo(args)
 
 	if err != nil {
 		fmt.Println("Error GetUserFriends (QueryNeo) : " + err.Error())
-		return menus, err
+		return menus, "", err
 	}
 
 	row, _, err := rows.NextNeo()
@@ -57,32 +64,36 @@ func (s Service) GetUserFriends(_ context.Context, userID int64) ([]Profile, err
 		} else if err != io.EOF {
 			(&tmpProfile).NodeToProfile(row[0].(graph.Node))
 			menus = append(menus, tmpProfile)
+			keys = append(keys, tmpProfile.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
 
-	return menus, nil
+	count, next := page.trim(keys)
+	return menus[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type getUserFriendsRequest struct {
 	UserID int64 `json:"id"`
+	Page   Page  `json:"page"`
 }
 
 type getUserFriendsResponse struct {
-	Profile []Profile `json:"friends"`
-	Err     string    `json:"err,omitempty"`
+	Profile    []Profile `json:"friends"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Err        string    `json:"err,omitempty"`
 }
 
 func GetUserFriendsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getUserFriendsRequest)
-		menu, err := svc.GetUserFriends(ctx, req.UserID)
+		menu, next, err := svc.GetUserFriends(ctx, req.UserID, req.Page)
 		if err != nil {
 			fmt.Println("Error GetUserFriendsEndpoint : ", err.Error())
-			return getUserFriendsResponse{Profile: menu, Err: err.Error()}, nil
+			return getUserFriendsResponse{Profile: menu, NextCursor: next, Err: err.Error()}, nil
 		}
-		return getUserFriendsResponse{Profile: menu, Err: ""}, nil
+		return getUserFriendsResponse{Profile: menu, NextCursor: next, Err: ""}, nil
 	}
 }
 
@@ -102,6 +113,13 @@ func DecodeHTTPGetUserFriendsRequest(_ context.Context, r *http.Request) (interf
 	}
 	(&request).UserID = userID
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetUserFriendsRequest 3 : ", err.Error())
+		return nil, err
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -126,7 +144,7 @@ func GetUserFriendsHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID int64) ([]Profile, error) {
+func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID int64, page Page) ([]Profile, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getUserFriends",
@@ -134,15 +152,15 @@ func (mw serviceLoggingMiddleware) GetUserFriends(ctx context.Context, userID in
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetUserFriends(ctx, userID)
+	return mw.next.GetUserFriends(ctx, userID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetUserFriends(ctx context.Context, userID int64) ([]Profile, error) {
-	v, err := mw.next.GetUserFriends(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetUserFriends(ctx context.Context, userID int64, page Page) ([]Profile, string, error) {
+	v, next, err := mw.next.GetUserFriends(ctx, userID, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -164,17 +182,17 @@ func BuildGetUserFriendsEndpoint(svc IService, logger log.Logger, tracer stdopen
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetUserFriends(ctx context.Context, userID int64) ([]Profile, error) {
+func (e Endpoints) GetUserFriends(ctx context.Context, userID int64, page Page) ([]Profile, string, error) {
 	var menu []Profile
 
-	request := getUserFriendsRequest{UserID: userID}
+	request := getUserFriendsRequest{UserID: userID, Page: page}
 	response, err := e.GetUserFriendsEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error GetUserFriends : ", err.Error())
-		return menu, nil
+		return menu, "", nil
 	}
 	menu = response.(getUserFriendsResponse).Profile
-	return menu, str2err(response.(getUserFriendsResponse).Err)
+	return menu, response.(getUserFriendsResponse).NextCursor, str2err(response.(getUserFriendsResponse).Err)
 }
 
 func EncodeHTTPGetUserFriendsRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -188,6 +206,7 @@ func EncodeHTTPGetUserFriendsRequest(ctx context.Context, r *http.Request, reque
 	}
 
 	r.URL.Path = encodedUrl.Path
+	request.(getUserFriendsRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
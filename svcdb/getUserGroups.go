@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserGroups(_ context.Context, userID int64) ([]GroupArrayElement, error) {
+func (s Service) GetUserGroups(ctx context.Context, userID int64) ([]GroupArrayElement, error) {
 	var groups []GroupArrayElement
 	var tmpGroups GroupArrayElement
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "GetUserGroups (WaitConnection) : "+err.Error())
 		return nil, err
@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserPreferences(_ context.Context, userID int64) ([]Preference, error) {
+func (s Service) GetUserPreferences(ctx context.Context, userID int64) ([]Preference, error) {
 	var preferences []Preference
 	var tmpPreferences Preference
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUserPreferences (WaitConnection) : " + err.Error())
 		return nil, err
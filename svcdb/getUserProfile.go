@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserProfile(_ context.Context, userID int64) (Profile, error) {
+func (s Service) GetUserProfile(ctx context.Context, userID int64) (Profile, error) {
 	var user Profile
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUserProfile (WaitConnection) : " + err.Error())
 		return user, err
@@ -23,11 +23,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUserSuccess(_ context.Context, userID int64) ([]Success, error) {
+func (s Service) GetUserSuccess(ctx context.Context, userID int64) ([]Success, error) {
 	var success []Success
 	var tmpSuccess Success
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUserSuccess (WaitConnection) : " + err.Error())
 		return nil, err
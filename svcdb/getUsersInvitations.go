@@ -22,26 +22,33 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetUsersInvitations(_ context.Context, userID int64) ([]Invitation, error) {
+func (s Service) GetUsersInvitations(ctx context.Context, userID int64, page Page) ([]Invitation, string, error) {
 	var invitations []Invitation
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"id": userID,
+	}
+	where, tail, err := page.keyset("ID(i)", false, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("GetUsersInvitations (WaitConnection) : " + err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	defer CloseConnection(conn)
 
-	stmt, err := conn.PrepareNeo("MATCH (u:USER)<-[i:INVITE]-(f:USER) WHERE ID(u) = {id} RETURN f, i, u")
+	stmt, err := conn.PrepareNeo("MATCH (u:USER)<-[i:INVITE]-(f:USER) WHERE ID(u) = {id} AND " + where + " RETURN f, i, u" + tail)
 	if err != nil {
 		fmt.Println("GetUsersInvitations (PrepareNeo)")
 		panic(err)
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"id": userID,
-	})
+	rows, err := stmt.QueryNeo(args)
 	if err != nil {
 		fmt.Println("GetUsersInvitations (QueryNeo)")
 		panic(err)
@@ -64,31 +71,36 @@ func (s Service) GetUsersInvitations(_ context.Context, userID int64) ([]Invitat
 				row[2].(graph.Node))
 
 			invitations = append(invitations, tmpInvitation)
+			keys = append(keys, tmpInvitation.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
-	return invitations, nil
+
+	count, next := page.trim(keys)
+	return invitations[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type getUsersInvitationsByEstablishmentRequest struct {
 	UserID int64 `json:"id"`
+	Page   Page  `json:"page"`
 }
 
 type getUsersInvitationsByEstablishmentResponse struct {
 	Invitations []Invitation `json:"invitations"`
+	NextCursor  string       `json:"next_cursor,omitempty"`
 	Err         string       `json:"err,omitempty"`
 }
 
 func GetUsersInvitationsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(getUsersInvitationsByEstablishmentRequest)
-		invitations, err := svc.GetUsersInvitations(ctx, req.UserID)
+		invitations, next, err := svc.GetUsersInvitations(ctx, req.UserID, req.Page)
 		if err != nil {
 			fmt.Println("Error GetUsersInvitationsEndpoint : ", err.Error())
-			return getUsersInvitationsByEstablishmentResponse{invitations, err.Error()}, nil
+			return getUsersInvitationsByEstablishmentResponse{invitations, next, err.Error()}, nil
 		}
-		return getUsersInvitationsByEstablishmentResponse{invitations, ""}, nil
+		return getUsersInvitationsByEstablishmentResponse{invitations, next, ""}, nil
 	}
 }
 
@@ -108,6 +120,13 @@ func DecodeHTTPGetUsersInvitationsRequest(_ context.Context, r *http.Request) (i
 	}
 	(&request).UserID = userID
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetUsersInvitationsRequest 3 : ", err.Error())
+		return nil, err
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -134,22 +153,22 @@ func GetUsersInvitationsHTTPHandler(endpoints Endpoints, tracer stdopentracing.T
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetUsersInvitations(ctx context.Context, userID int64) ([]Invitation, error) {
+func (mw serviceLoggingMiddleware) GetUsersInvitations(ctx context.Context, userID int64, page Page) ([]Invitation, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "getUsersInvitationsByEstablishment",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetUsersInvitations(ctx, userID)
+	return mw.next.GetUsersInvitations(ctx, userID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetUsersInvitations(ctx context.Context, userID int64) ([]Invitation, error) {
-	v, err := mw.next.GetUsersInvitations(ctx, userID)
+func (mw serviceInstrumentingMiddleware) GetUsersInvitations(ctx context.Context, userID int64, page Page) ([]Invitation, string, error) {
+	v, next, err := mw.next.GetUsersInvitations(ctx, userID, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -171,17 +190,17 @@ func BuildGetUsersInvitationsEndpoint(svc IService, logger log.Logger, tracer st
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetUsersInvitations(ctx context.Context, userID int64) ([]Invitation, error) {
+func (e Endpoints) GetUsersInvitations(ctx context.Context, userID int64, page Page) ([]Invitation, string, error) {
 	var et []Invitation
 
-	request := getUsersInvitationsByEstablishmentRequest{UserID: userID}
+	request := getUsersInvitationsByEstablishmentRequest{UserID: userID, Page: page}
 	response, err := e.GetUsersInvitationsEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error GetUsersInvitations : ", err.Error())
-		return et, err
+		return et, "", err
 	}
 	et = response.(getUsersInvitationsByEstablishmentResponse).Invitations
-	return et, str2err(response.(getUsersInvitationsByEstablishmentResponse).Err)
+	return et, response.(getUsersInvitationsByEstablishmentResponse).NextCursor, str2err(response.(getUsersInvitationsByEstablishmentResponse).Err)
 }
 
 func EncodeHTTPGetUsersInvitationsRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -193,6 +212,7 @@ func EncodeHTTPGetUsersInvitationsRequest(ctx context.Context, r *http.Request,
 		return err
 	}
 	r.URL.Path = encodedUrl.Path
+	request.(getUsersInvitationsByEstablishmentRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
@@ -27,5 +27,5 @@ func (i *GroupInvitation) RelationToGroupInvitation(from graph.Node, relation gr
 	i.ID = relation.RelIdentity
 	i.From = fromGroup
 	i.To = toUser
-	i.Date, _ = time.Parse(timeForm, relation.Properties["Date"].(string))
+	i.Date = propertyTime("RelationToGroupInvitation", relation.Properties, "Date")
 }
@@ -21,9 +21,9 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GroupInvitationDecline(_ context.Context, _, invitationID int64) error {
+func (s Service) GroupInvitationDecline(ctx context.Context, _, invitationID int64) error {
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "GroupInvitationDecline (WaitConnection) : "+err.Error())
 		return err
@@ -22,10 +22,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GroupInvite(_ context.Context, groupID, friendID int64) (string, int64, error) {
+func (s Service) GroupInvite(ctx context.Context, groupID, friendID int64) (string, int64, error) {
 	var group Group
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "GroupInvite (WaitConnection) : "+err.Error())
 		return "", 0, err
@@ -47,7 +47,7 @@ func (s Service) GroupInvite(_ context.Context, groupID, friendID int64) (string
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"groupID":  groupID,
 		"friendID": friendID,
-		"date":     time.Now().Format(timeForm),
+		"date":     formatTime(time.Now()),
 	})
 
 	if err != nil {
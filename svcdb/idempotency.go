@@ -0,0 +1,134 @@
+package svcdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+// IdempotencyKeyHeader is the header clients set to retry a request safely
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyTTL is how long a response is replayed, the key can be reused
+// for another request afterwards
+var IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyLease is how long a key stays claimed by a request that never
+// stored its response, e.g. when its service died meanwhile
+var IdempotencyLease = 5 * time.Minute
+
+var (
+	IdempotencyConflictErr   = errors.New("The idempotency key was already used for another request")
+	IdempotencyInProgressErr = errors.New("A request with the same idempotency key is in progress")
+)
+
+// IdempotentResponse is the response stored for an idempotency key. Response
+// stays empty while the first request is handled.
+type IdempotentResponse struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Response    string    `json:"response,omitempty"`
+	Date        time.Time `json:"date"`
+}
+
+func (i *IdempotentResponse) NodeToIdempotentResponse(node graph.Node) {
+	i.Key = node.Properties["Key"].(string)
+	i.Fingerprint = node.Properties["Fingerprint"].(string)
+	if node.Properties["Response"] != nil {
+		i.Response = node.Properties["Response"].(string)
+	}
+	i.Date = propertyTime("NodeToIdempotentResponse", node.Properties, "Date")
+}
+
+type idempotencyKeyContextKey struct{}
+
+// HTTPToIdempotencyKey moves the Idempotency-Key header of the request into
+// the context, for EndpointIdempotencyMiddleware
+func HTTPToIdempotencyKey() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if len(key) == 0 {
+			return ctx
+		}
+		return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+	}
+}
+
+// IdempotencyKeyToHTTP forwards the key of the context to the next service
+func IdempotencyKeyToHTTP() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if key := IdempotencyKeyFromContext(ctx); len(key) > 0 {
+			r.Header.Set(IdempotencyKeyHeader, key)
+		}
+		return ctx
+	}
+}
+
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// Failer is implemented by the responses carrying their error instead of
+// returning it, as the ones of svcpayment
+type Failer interface {
+	Failed() error
+}
+
+// EndpointIdempotencyMiddleware stores the response of the requests carrying
+// an idempotency key and replays it when the request is retried. The keys are
+// prefixed with scope, a key reused for another request is rejected with
+// IdempotencyConflictErr.
+// Failed requests are not stored, their retry is handled again.
+func EndpointIdempotencyMiddleware(db IService, scope string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			key := IdempotencyKeyFromContext(ctx)
+			if len(key) == 0 {
+				return next(ctx, request)
+			}
+			key = scope + ":" + key
+
+			js, err := json.Marshal(request)
+			if err != nil {
+				fmt.Println("EndpointIdempotencyMiddleware (Marshal) : " + err.Error())
+				return nil, err
+			}
+			sum := sha256.Sum256(js)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			stored, claimed, err := db.ClaimIdempotencyKey(ctx, key, fingerprint)
+			if err != nil {
+				fmt.Println("EndpointIdempotencyMiddleware (ClaimIdempotencyKey) : " + err.Error())
+				return nil, err
+			} else if !claimed && stored.Fingerprint != fingerprint {
+				return nil, IdempotencyConflictErr
+			} else if !claimed && len(stored.Response) == 0 {
+				return nil, IdempotencyInProgressErr
+			} else if !claimed {
+				return json.RawMessage(stored.Response), nil
+			}
+
+			/* An empty response releases the key */
+			js = nil
+			response, err := next(ctx, request)
+			failer, ok := response.(Failer)
+			if err == nil && (!ok || failer.Failed() == nil) {
+				js, _ = json.Marshal(response)
+			}
+			if err := db.StoreIdempotencyKey(ctx, key, string(js)); err != nil {
+				fmt.Println("EndpointIdempotencyMiddleware (StoreIdempotencyKey) : " + err.Error())
+			}
+			return response, err
+		}
+	}
+}
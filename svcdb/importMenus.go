@@ -0,0 +1,214 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// ImportMenus creates or updates every menu and conso in a single transaction.
+// Menus are matched by name within the establishment and consos by name within
+// their menu, so that an exported file can be edited and imported back.
+func (s Service) ImportMenus(ctx context.Context, estabID int64, menus []Menu) ([]Menu, error) {
+	var imported []Menu
+	var reqs []string
+	var argss []map[string]interface{}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("ImportMenus (WaitConnection) : " + err.Error())
+		return imported, err
+	}
+	defer CloseConnection(conn)
+
+	for _, menu := range menus {
+		var consos []interface{}
+
+		for _, conso := range menu.Consommations {
+			consos = append(consos, map[string]interface{}{
+				"Name":    conso.Name,
+				"Desc":    conso.Description,
+				"Price":   conso.Price,
+				"Picture": conso.Picture,
+			})
+		}
+
+		reqs = append(reqs, `
+			MATCH (e:ESTABLISHMENT) WHERE ID(e) = {eid}
+			MERGE (e)-[g:GOT]->(m:MENU {Name: {name}})
+			ON CREATE SET g.Display = true
+			SET m.Desc = {desc}
+			FOREACH (conso IN {consos} |
+				MERGE (m)-[:USE]->(c:CONSO {Name: conso.Name})
+				SET c.Desc = conso.Desc, c.Price = conso.Price, c.Currency = {currency}, c.Picture = conso.Picture
+				MERGE (e)-[:GOT]->(c))
+			WITH m
+			OPTIONAL MATCH (m)-[:USE]->(c:CONSO)
+			RETURN m, COLLECT(c)`)
+		argss = append(argss, map[string]interface{}{
+			"eid":      estabID,
+			"name":     menu.Name,
+			"desc":     menu.Desc,
+			"consos":   consos,
+			"currency": DefaultCurrency,
+		})
+	}
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		rows, err := ExecBatch(conn, reqs, argss)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			var tmpMenu Menu
+
+			(&tmpMenu).NodeToMenu(row[0].(graph.Node))
+			consoNodes, _ := row[1].([]interface{})
+			for _, consoNode := range consoNodes {
+				var tmpConso Conso
+
+				(&tmpConso).NodeToConso(consoNode.(graph.Node))
+				tmpMenu.Consommations = append(tmpMenu.Consommations, tmpConso)
+			}
+			imported = append(imported, tmpMenu)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("ImportMenus (Transaction) : " + err.Error())
+		return nil, err
+	}
+
+	return imported, nil
+}
+
+/*************** Endpoint ***************/
+type importMenusRequest struct {
+	EstabID int64  `json:"estabID"`
+	Menus   []Menu `json:"menus"`
+}
+
+type importMenusResponse struct {
+	Menus []Menu `json:"menus"`
+	Err   string `json:"err,omitempty"`
+}
+
+func ImportMenusEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importMenusRequest)
+		menus, err := svc.ImportMenus(ctx, req.EstabID, req.Menus)
+		if err != nil {
+			fmt.Println("Error ImportMenusEndpoint : ", err.Error())
+			return importMenusResponse{menus, err.Error()}, nil
+		}
+		return importMenusResponse{menus, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPImportMenusRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request importMenusRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPImportMenusRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPImportMenusResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response importMenusResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPImportMenusResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ImportMenusHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/menus/import").Handler(httptransport.NewServer(
+		endpoints.ImportMenusEndpoint,
+		DecodeHTTPImportMenusRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ImportMenus", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ImportMenus(ctx context.Context, estabID int64, menus []Menu) ([]Menu, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "importMenus",
+			"estabID", estabID,
+			"menus", len(menus),
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.ImportMenus(ctx, estabID, menus)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ImportMenus(ctx context.Context, estabID int64, menus []Menu) ([]Menu, error) {
+	v, err := mw.next.ImportMenus(ctx, estabID, menus)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildImportMenusEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ImportMenus")
+		csLogger := log.With(logger, "method", "ImportMenus")
+
+		csEndpoint = ImportMenusEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ImportMenus")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) ImportMenus(ctx context.Context, estabID int64, menus []Menu) ([]Menu, error) {
+	request := importMenusRequest{EstabID: estabID, Menus: menus}
+	response, err := e.ImportMenusEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(importMenusResponse).Menus, str2err(response.(importMenusResponse).Err)
+}
+
+func ClientImportMenus(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/menus/import"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPImportMenusResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ImportMenus")(ceEndpoint)
+	return ceEndpoint, nil
+}
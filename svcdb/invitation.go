@@ -27,5 +27,5 @@ func (i *Invitation) RelationToInvitation(from graph.Node, relation graph.Relati
 	i.ID = relation.RelIdentity
 	i.From = fromUser
 	i.To = toUser
-	i.Date, _ = time.Parse(timeForm, relation.Properties["Date"].(string))
+	i.Date = propertyTime("RelationToInvitation", relation.Properties, "Date")
 }
@@ -21,11 +21,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) InvitationAccept(_ context.Context, invitationID int64) (Profile, Profile, error) {
+func (s Service) InvitationAccept(ctx context.Context, invitationID int64) (Profile, Profile, error) {
 	var user Profile
 	var friend Profile
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("InvitationAccept (WaitConnection) : " + err.Error())
 		return user, friend, err
@@ -21,11 +21,11 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) InviteFriend(_ context.Context, userID, friendID int64) (string, int64, int64, error) {
+func (s Service) InviteFriend(ctx context.Context, userID, friendID int64) (string, int64, int64, error) {
 	var p Profile
 	var successID int64
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("InviteFriend (WaitConnection) : " + err.Error())
 		return "", 0, 0, err
@@ -49,7 +49,7 @@ func (s Service) InviteFriend(_ context.Context, userID, friendID int64) (string
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"userID":   userID,
 		"friendID": friendID,
-		"date":     time.Now().Format(timeForm),
+		"date":     formatTime(time.Now()),
 	})
 
 	if err != nil {
@@ -0,0 +1,169 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// IsTokenRevoked checks the access token tokenID of subject, issued at
+// issuedAt, against the revocation list. Times are compared to the second, as
+// the iat of the tokens.
+func (s Service) IsTokenRevoked(ctx context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error) {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("IsTokenRevoked (WaitConnection) : " + err.Error())
+		return false, err
+	}
+	defer CloseConnection(conn)
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (r:REVOCATION {Subject: {subject}})
+		WHERE r.Until >= {now} AND (r.TokenID = {tokenID} OR (r.TokenID = "" AND r.Before > {issued}))
+		RETURN count(r)`, map[string]interface{}{
+		"subject": subject,
+		"tokenID": tokenID,
+		"now":     formatTime(time.Now()),
+		"issued":  formatTime(issuedAt.Truncate(time.Second)),
+	})
+	if err != nil {
+		fmt.Println("IsTokenRevoked (QueryNeoAll) : " + err.Error())
+		return false, err
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	count, _ := data[0][0].(int64)
+	return count > 0, nil
+}
+
+/*************** Endpoint ***************/
+type isTokenRevokedRequest struct {
+	Subject  string    `json:"subject"`
+	TokenID  string    `json:"tokenID"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+type isTokenRevokedResponse struct {
+	Revoked bool   `json:"revoked"`
+	Err     string `json:"err,omitempty"`
+}
+
+func IsTokenRevokedEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(isTokenRevokedRequest)
+		revoked, err := svc.IsTokenRevoked(ctx, req.Subject, req.TokenID, req.IssuedAt)
+		if err != nil {
+			fmt.Println("Error IsTokenRevokedEndpoint : ", err.Error())
+			return isTokenRevokedResponse{Revoked: revoked, Err: err.Error()}, nil
+		}
+		return isTokenRevokedResponse{Revoked: revoked, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPIsTokenRevokedRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request isTokenRevokedRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPIsTokenRevokedRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPIsTokenRevokedResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response isTokenRevokedResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPIsTokenRevokedResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func IsTokenRevokedHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/tokens/revoked").Handler(httptransport.NewServer(
+		endpoints.IsTokenRevokedEndpoint,
+		DecodeHTTPIsTokenRevokedRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "IsTokenRevoked", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) IsTokenRevoked(ctx context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "isTokenRevoked",
+			"subject", subject,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.IsTokenRevoked(ctx, subject, tokenID, issuedAt)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) IsTokenRevoked(ctx context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error) {
+	v, err := mw.next.IsTokenRevoked(ctx, subject, tokenID, issuedAt)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildIsTokenRevokedEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "IsTokenRevoked")
+		csLogger := log.With(logger, "method", "IsTokenRevoked")
+
+		csEndpoint = IsTokenRevokedEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "IsTokenRevoked")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) IsTokenRevoked(ctx context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error) {
+	request := isTokenRevokedRequest{Subject: subject, TokenID: tokenID, IssuedAt: issuedAt}
+	response, err := e.IsTokenRevokedEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error IsTokenRevoked : ", err.Error())
+		return false, err
+	}
+	r := response.(isTokenRevokedResponse)
+	return r.Revoked, str2err(r.Err)
+}
+
+func ClientIsTokenRevoked(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/tokens/revoked"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPIsTokenRevokedResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "IsTokenRevoked")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,88 @@
+package svcdb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+// Kinds of the ledger entries, one per money movement of a charge
+const (
+	LedgerAuthorization = "authorization" // funds reserved on the card of the user
+	LedgerCapture       = "capture"       // funds taken from the card
+	LedgerFee           = "fee"           // NightLine fee kept on the capture
+	LedgerTransfer      = "transfer"      // capture minus the fee, sent to the pro
+	LedgerRefund        = "refund"        // charge given back to the user
+)
+
+// LedgerEntry model. An entry is recorded once per kind and charge reference,
+// and once per refund for the refunds of a charge, e.g. partial ones. Refund
+// is empty on the other kinds and on the release of an expired charge.
+// Amounts are in cents of Currency.
+type LedgerEntry struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Reference string    `json:"reference"`
+	Refund    string    `json:"refund,omitempty"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	OrderID   int64     `json:"order"`
+	UserID    int64     `json:"user"`
+	ProID     int64     `json:"pro"`
+	SoireeID  int64     `json:"soiree"`
+	Date      time.Time `json:"date"`
+}
+
+// LedgerFilter selects the entries of an order and/or a pro, recorded between
+// From and To when they are set
+type LedgerFilter struct {
+	OrderID int64     `json:"order,omitempty"`
+	ProID   int64     `json:"pro,omitempty"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+func (l *LedgerEntry) NodeToLedgerEntry(node graph.Node) {
+	l.ID = node.NodeIdentity
+	l.Kind, _ = node.Properties["Kind"].(string)
+	l.Reference, _ = node.Properties["Reference"].(string)
+	l.Refund, _ = node.Properties["Refund"].(string)
+	l.Amount = propertyMoney(node.Properties, "Amount")
+	l.Currency, _ = node.Properties["Currency"].(string)
+	l.OrderID, _ = node.Properties["Order"].(int64)
+	l.UserID, _ = node.Properties["User"].(int64)
+	l.ProID, _ = node.Properties["Pro"].(int64)
+	l.SoireeID, _ = node.Properties["Soiree"].(int64)
+	l.Date = propertyTime("NodeToLedgerEntry", node.Properties, "Date")
+}
+
+func (l *LedgerEntry) MarshalJSON() ([]byte, error) {
+	type Alias LedgerEntry
+	return json.Marshal(&struct {
+		*Alias
+		Date string `json:"date"`
+	}{
+		Alias: (*Alias)(l),
+		Date:  jsonTime(l.Date),
+	})
+}
+
+func (l *LedgerEntry) UnmarshalJSON(data []byte) error {
+	type Alias LedgerEntry
+	aux := &struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Alias: (*Alias)(l),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	date, err := parseJSONTime(aux.Date)
+	if err != nil {
+		return err
+	}
+	l.Date = date
+	return nil
+}
@@ -0,0 +1,357 @@
+package svcdb
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Memory graph ***************/
+// memoryGraph is a minimal property graph holding the same nodes, labels and
+// relationships as the Neo4j database, so the graph.Node/graph.Relationship
+// converters of the models can be reused as is.
+type memoryGraph struct {
+	mtx       sync.RWMutex
+	lastID    int64
+	nodes     map[int64]graph.Node
+	relations map[int64]graph.Relationship
+	nodeIDs   []int64 // creation order
+	relIDs    []int64 // creation order
+}
+
+// Relationship directions, seen from the node the match starts from
+const (
+	memOut = iota + 1
+	memIn
+	memBoth
+)
+
+// memoryMatch is one (relationship, other node) pair returned by related
+type memoryMatch struct {
+	Rel  graph.Relationship
+	Node graph.Node
+}
+
+func newMemoryGraph() *memoryGraph {
+	return &memoryGraph{
+		nodes:     make(map[int64]graph.Node),
+		relations: make(map[int64]graph.Relationship),
+	}
+}
+
+// Like Cypher, null properties are never stored
+func memoryProperties(props map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for key, value := range props {
+		if value != nil {
+			ret[key] = value
+		}
+	}
+	return ret
+}
+
+func hasLabel(node graph.Node, label string) bool {
+	if len(label) == 0 {
+		return true
+	}
+	for _, l := range node.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// IDs start at 1, 0 is used as "not found" all over the services
+func (g *memoryGraph) nextID() int64 {
+	g.lastID++
+	return g.lastID
+}
+
+func (g *memoryGraph) createNode(label string, props map[string]interface{}) graph.Node {
+	node := graph.Node{
+		NodeIdentity: g.nextID(),
+		Labels:       []string{label},
+		Properties:   memoryProperties(props),
+	}
+	g.nodes[node.NodeIdentity] = node
+	g.nodeIDs = append(g.nodeIDs, node.NodeIdentity)
+	return node
+}
+
+func (g *memoryGraph) createRelation(startID, endID int64, relType string, props map[string]interface{}) graph.Relationship {
+	rel := graph.Relationship{
+		RelIdentity:       g.nextID(),
+		StartNodeIdentity: startID,
+		EndNodeIdentity:   endID,
+		Type:              relType,
+		Properties:        memoryProperties(props),
+	}
+	g.relations[rel.RelIdentity] = rel
+	g.relIDs = append(g.relIDs, rel.RelIdentity)
+	return rel
+}
+
+// node returns io.EOF when nothing matches, as an empty Neo4j result does
+func (g *memoryGraph) node(id int64, label string) (graph.Node, error) {
+	node, ok := g.nodes[id]
+	if !ok || !hasLabel(node, label) {
+		return graph.Node{}, io.EOF
+	}
+	return node, nil
+}
+
+func (g *memoryGraph) relation(id int64, relType string) (graph.Relationship, error) {
+	rel, ok := g.relations[id]
+	if !ok || (len(relType) > 0 && rel.Type != relType) {
+		return graph.Relationship{}, io.EOF
+	}
+	return rel, nil
+}
+
+// findNodes returns the nodes with this label accepted by filter (nil keeps all)
+func (g *memoryGraph) findNodes(label string, filter func(graph.Node) bool) []graph.Node {
+	var nodes []graph.Node
+
+	for _, id := range g.nodeIDs {
+		node := g.nodes[id]
+		if hasLabel(node, label) && (filter == nil || filter(node)) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// related is the equivalent of (n)-[:relType]-(:label), an empty relType or
+// label matching anything
+func (g *memoryGraph) related(nodeID int64, direction int, relType, label string) []memoryMatch {
+	var matches []memoryMatch
+
+	for _, id := range g.relIDs {
+		rel := g.relations[id]
+		if len(relType) > 0 && rel.Type != relType {
+			continue
+		}
+		var otherID int64
+		if direction != memIn && rel.StartNodeIdentity == nodeID {
+			otherID = rel.EndNodeIdentity
+		} else if direction != memOut && rel.EndNodeIdentity == nodeID {
+			otherID = rel.StartNodeIdentity
+		} else {
+			continue
+		}
+		if other, ok := g.nodes[otherID]; ok && hasLabel(other, label) {
+			matches = append(matches, memoryMatch{Rel: rel, Node: other})
+		}
+	}
+	return matches
+}
+
+// relationsBetween returns the relationships linking two nodes, in any direction
+func (g *memoryGraph) relationsBetween(nodeID, otherID int64, relType string) []graph.Relationship {
+	var rels []graph.Relationship
+
+	for _, match := range g.related(nodeID, memBoth, relType, "") {
+		if match.Node.NodeIdentity == otherID {
+			rels = append(rels, match.Rel)
+		}
+	}
+	return rels
+}
+
+// setNode works like SET, a nil value removing the property
+func (g *memoryGraph) setNode(id int64, props map[string]interface{}) graph.Node {
+	node := g.nodes[id]
+	for key, value := range props {
+		if value == nil {
+			delete(node.Properties, key)
+		} else {
+			node.Properties[key] = value
+		}
+	}
+	g.nodes[id] = node
+	return node
+}
+
+func (g *memoryGraph) setRelation(id int64, props map[string]interface{}) graph.Relationship {
+	rel := g.relations[id]
+	for key, value := range props {
+		if value == nil {
+			delete(rel.Properties, key)
+		} else {
+			rel.Properties[key] = value
+		}
+	}
+	g.relations[id] = rel
+	return rel
+}
+
+func (g *memoryGraph) deleteRelation(id int64) {
+	delete(g.relations, id)
+	for i := range g.relIDs {
+		if g.relIDs[i] == id {
+			g.relIDs = append(g.relIDs[:i], g.relIDs[i+1:]...)
+			break
+		}
+	}
+}
+
+// detachDelete works like DETACH DELETE
+func (g *memoryGraph) detachDelete(id int64) {
+	for _, match := range g.related(id, memBoth, "", "") {
+		g.deleteRelation(match.Rel.RelIdentity)
+	}
+	delete(g.nodes, id)
+	for i := range g.nodeIDs {
+		if g.nodeIDs[i] == id {
+			g.nodeIDs = append(g.nodeIDs[:i], g.nodeIDs[i+1:]...)
+			break
+		}
+	}
+}
+
+// pageNodes keeps the nodes of page, using their ID as the cursor key
+func pageNodes(nodes []graph.Node, page Page, desc bool) ([]graph.Node, string, error) {
+	var ret []graph.Node
+
+	keys := make([]int64, len(nodes))
+	for i := range nodes {
+		keys[i] = nodes[i].NodeIdentity
+	}
+	indexes, next, err := page.window(keys, desc)
+	if err != nil {
+		return ret, "", err
+	}
+	for _, i := range indexes {
+		ret = append(ret, nodes[i])
+	}
+	return ret, next, nil
+}
+
+/*************** Memory service ***************/
+// NewMemoryService returns an IService keeping the whole graph in memory,
+// for tests and local development without a Neo4j instance
+func NewMemoryService() MemoryService {
+	return MemoryService{graph: newMemoryGraph()}
+}
+
+// MemoryService implements IService with the same semantics as the Cypher
+// queries of Service
+type MemoryService struct {
+	graph *memoryGraph
+}
+
+// SeedEstablishmentType creates an ESTABLISHMENT_TYPE node, those are never
+// created through the API
+func (s MemoryService) SeedEstablishmentType(name string) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	s.graph.createNode("ESTABLISHMENT_TYPE", map[string]interface{}{
+		"Name": name,
+	})
+}
+
+// SeedSuccess creates a SUCCESS node, those are never created through the API
+func (s MemoryService) SeedSuccess(name, value string) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	s.graph.createNode("SUCCESS", map[string]interface{}{
+		"Name":  name,
+		"Value": value,
+	})
+}
+
+// SeedUser creates a USER node without a password, for the tests that do not
+// log it in
+func (s MemoryService) SeedUser(email string) User {
+	var user User
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node := s.graph.createNode("USER", map[string]interface{}{
+		"Email":  email,
+		"Pseudo": "",
+	})
+	(&user).NodeToUser(node)
+	return user
+}
+
+// SeedPro creates a PRO node without a password, for the tests that do not log
+// it in
+func (s MemoryService) SeedPro(email string) Pro {
+	var pro Pro
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node := s.graph.createNode("PRO", map[string]interface{}{
+		"Email":      email,
+		"Pseudo":     "",
+		"StripeID":   "",
+		"StripeSKey": "",
+		"StripePKey": "",
+	})
+	(&pro).NodeToPro(node)
+	return pro
+}
+
+// SeedEstablishment creates an ESTABLISHMENT node owned by proID which
+// spawned the soirees, without its type
+func (s MemoryService) SeedEstablishment(proID int64, soireeIDs ...int64) Establishment {
+	var establishment Establishment
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node := s.graph.createNode("ESTABLISHMENT", map[string]interface{}{
+		"Name": "Establishment",
+		"Lat":  0.0,
+		"Long": 0.0,
+	})
+	s.graph.createRelation(proID, node.NodeIdentity, "OWN", nil)
+	for _, soireeID := range soireeIDs {
+		s.graph.createRelation(node.NodeIdentity, soireeID, "SPAWNED", nil)
+	}
+	(&establishment).NodeToEstablishment(node)
+	return establishment
+}
+
+// SeedSoiree creates a SOIREE node running for the next hour, out of any
+// establishment or menu
+func (s MemoryService) SeedSoiree() Soiree {
+	var soiree Soiree
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node := s.graph.createNode("SOIREE", map[string]interface{}{
+		"Desc":  "Soiree",
+		"Begin": formatTime(time.Now()),
+		"End":   formatTime(time.Now().Add(time.Hour)),
+	})
+	(&soiree).NodeToSoiree(node)
+	return soiree
+}
+
+// SeedConso creates a CONSO node of price cents, out of any menu
+func (s MemoryService) SeedConso(price int64) Conso {
+	var conso Conso
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node := s.graph.createNode("CONSO", map[string]interface{}{
+		"Name":     "Conso",
+		"Desc":     "",
+		"Price":    price,
+		"Currency": DefaultCurrency,
+		"Picture":  "",
+	})
+	(&conso).NodeToConso(node)
+	return conso
+}
@@ -0,0 +1,636 @@
+package svcdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Establishments ***************/
+// averageRate mirrors OPTIONAL MATCH (e)<-[r:RATE]-(u:USER) RETURN AVG(r.value)
+func (s MemoryService) averageRate(estabID int64) (float64, bool) {
+	var sum, count int64
+
+	for _, match := range s.graph.related(estabID, memIn, "RATE", "USER") {
+		if value, ok := match.Rel.Properties["value"].(int64); ok {
+			sum += value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return float64(sum) / float64(count), true
+}
+
+func (s MemoryService) getEstablishment(estabID int64) (Establishment, error) {
+	var establishment Establishment
+
+	node, err := s.graph.node(estabID, "ESTABLISHMENT")
+	if err != nil {
+		return establishment, err
+	}
+	(&establishment).NodeToEstablishment(node)
+	if owners := s.graph.related(estabID, memIn, "OWN", "PRO"); len(owners) > 0 {
+		establishment.Owner = owners[0].Node.NodeIdentity
+	}
+	if rate, ok := s.averageRate(estabID); ok {
+		establishment.Rate = rate
+	}
+	return establishment, nil
+}
+
+func (s MemoryService) CreateEstablishment(_ context.Context, e Establishment, proID int64) (Establishment, error) {
+	var establishment Establishment
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(proID, "PRO"); err != nil {
+		return establishment, err
+	}
+	types := s.graph.findNodes("ESTABLISHMENT_TYPE", func(node graph.Node) bool {
+		return node.Properties["Name"] == e.Type
+	})
+	if len(types) == 0 {
+		return establishment, io.EOF
+	}
+
+	node := s.graph.createNode("ESTABLISHMENT", map[string]interface{}{
+		"Name":        e.Name,
+		"Address":     e.Address,
+		"Lat":         e.Lat,
+		"Long":        e.Long,
+		"Description": e.Description,
+		"Image":       e.Image,
+	})
+	s.graph.createRelation(proID, node.NodeIdentity, "OWN", nil)
+	for _, t := range types {
+		s.graph.createRelation(node.NodeIdentity, t.NodeIdentity, "IS", nil)
+	}
+
+	(&establishment).NodeToEstablishment(node)
+	return establishment, nil
+}
+
+func (s MemoryService) GetEstablishments(_ context.Context, page Page) ([]Establishment, string, error) {
+	var establishments []Establishment
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes, next, err := pageNodes(s.graph.findNodes("ESTABLISHMENT", nil), page, false)
+	if err != nil {
+		return establishments, "", err
+	}
+	for _, node := range nodes {
+		tmpEstablishment, _ := s.getEstablishment(node.NodeIdentity)
+		establishments = append(establishments, tmpEstablishment)
+	}
+	return establishments, next, nil
+}
+
+func (s MemoryService) SearchEstablishments(_ context.Context, query string, page Page) ([]SearchResponse, string, error) {
+	var response []SearchResponse
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes := s.graph.findNodes("ESTABLISHMENT", func(node graph.Node) bool {
+		name, _ := node.Properties["Name"].(string)
+		return strings.Contains(strings.ToLower(name), strings.ToLower(query))
+	})
+	nodes, next, err := pageNodes(nodes, page, false)
+	if err != nil {
+		return response, "", err
+	}
+	for _, node := range nodes {
+		var tmpEstablishment Establishment
+		var tmpResponse SearchResponse
+
+		(&tmpEstablishment).NodeToEstablishment(node)
+		tmpResponse.FromEstablishment(tmpEstablishment)
+		response = append(response, tmpResponse)
+	}
+	return response, next, nil
+}
+
+func (s MemoryService) SearchNearby(_ context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error) {
+	var results []NearbyResult
+	timeNow := time.Now()
+
+	if !validLocation(lat, long, radius) {
+		return results, InvalidLocationErr
+	}
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, node := range s.graph.findNodes("ESTABLISHMENT", nil) {
+		var tmpResult NearbyResult
+
+		tmpResult.Establishment, _ = s.getEstablishment(node.NodeIdentity)
+		if types := s.graph.related(node.NodeIdentity, memOut, "IS", "ESTABLISHMENT_TYPE"); len(types) > 0 {
+			tmpResult.Establishment.Type, _ = types[0].Node.Properties["Name"].(string)
+		}
+		if !filters.hasType(tmpResult.Establishment.Type) {
+			continue
+		}
+
+		tmpResult.Distance = Distance(lat, long, tmpResult.Establishment.Lat, tmpResult.Establishment.Long)
+		if tmpResult.Distance > radius {
+			continue
+		}
+
+		for _, match := range s.graph.related(node.NodeIdentity, memOut, "SPAWNED", "SOIREE") {
+			var tmpSoiree Soiree
+
+			(&tmpSoiree).NodeToSoiree(match.Node)
+			if tmpSoiree.Running(timeNow) {
+				tmpResult.Soirees = append(tmpResult.Soirees, tmpSoiree)
+			}
+		}
+		if filters.keep(tmpResult) {
+			results = append(results, tmpResult)
+		}
+	}
+	sortNearby(results)
+	return results, nil
+}
+
+func (s MemoryService) GetEstablishment(_ context.Context, estabID int64) (Establishment, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	return s.getEstablishment(estabID)
+}
+
+func (s MemoryService) GetEstablishmentFromMenu(_ context.Context, menuID int64) (Establishment, error) {
+	var establishment Establishment
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(menuID, "MENU"); err != nil {
+		return establishment, err
+	}
+	matches := s.graph.related(menuID, memIn, "GOT", "ESTABLISHMENT")
+	if len(matches) == 0 {
+		return establishment, io.EOF
+	}
+	(&establishment).NodeToEstablishment(matches[0].Node)
+	return establishment, nil
+}
+
+func (s MemoryService) GetMenuFromEstablishment(_ context.Context, estabID int64) ([]Menu, error) {
+	return s.GetEstablishmentMenus(context.Background(), estabID)
+}
+
+func (s MemoryService) UpdateEstablishment(_ context.Context, new Establishment) (Establishment, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	estab, err := s.getEstablishment(new.ID)
+	if err != nil {
+		return estab, err
+	}
+	estab.UpdateEstablishment(new)
+
+	node := s.graph.setNode(estab.ID, map[string]interface{}{
+		"Name":        estab.Name,
+		"Long":        estab.Long,
+		"Lat":         estab.Lat,
+		"Address":     estab.Address,
+		"Description": estab.Description,
+		"Image":       estab.Image,
+		"OpenHours":   estab.OpenHours,
+	})
+	(&estab).NodeToEstablishment(node)
+	return estab, nil
+}
+
+func (s MemoryService) GetEstablishmentSoiree(_ context.Context, estabID int64) (Soiree, error) {
+	var soiree Soiree
+	timeNow := time.Now()
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	matches := s.graph.related(estabID, memOut, "SPAWNED", "SOIREE")
+	if len(matches) == 0 {
+		return soiree, io.EOF
+	}
+
+	/* select best, same rules as Service.GetEstablishmentSoiree */
+	soiree.End = timeNow.Add(-time.Hour)
+	for _, match := range matches {
+		var tmpSoiree Soiree
+
+		(&tmpSoiree).NodeToSoiree(match.Node)
+		if tmpSoiree.End.After(timeNow) &&
+			(tmpSoiree.End.Before(soiree.End) ||
+				soiree.End.Before(timeNow)) {
+			soiree = tmpSoiree
+		}
+	}
+
+	if soiree.End.Before(timeNow) {
+		return soiree, errors.New("No party incoming")
+	}
+	return soiree, nil
+}
+
+func (s MemoryService) GetSoireesByEstablishment(_ context.Context, estabID int64) ([]Soiree, error) {
+	var soirees []Soiree
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return soirees, nil
+	}
+	for _, match := range s.graph.related(estabID, memOut, "", "SOIREE") {
+		var tmpSoiree Soiree
+
+		(&tmpSoiree).NodeToSoiree(match.Node)
+		soirees = append(soirees, tmpSoiree)
+	}
+	return soirees, nil
+}
+
+func (s MemoryService) GetEstablishmentTypes(_ context.Context) ([]string, error) {
+	var establishments []string
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, node := range s.graph.findNodes("ESTABLISHMENT_TYPE", nil) {
+		var tmpEstablishment EstablishmentType
+
+		(&tmpEstablishment).NodeToEstablishmentType(node)
+		establishments = append(establishments, tmpEstablishment.Name)
+	}
+	return establishments, nil
+}
+
+func (s MemoryService) GetEstablishmentType(_ context.Context, estabID int64) (string, error) {
+	var tmp EstablishmentType
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	// No result != error
+	matches := s.graph.related(estabID, memOut, "IS", "ESTABLISHMENT_TYPE")
+	if len(matches) == 0 {
+		return "", nil
+	}
+	(&tmp).NodeToEstablishmentType(matches[0].Node)
+	return tmp.Name, nil
+}
+
+// Arguments are taken in the same order as Service.RateEstablishment
+func (s MemoryService) RateEstablishment(_ context.Context, estabID, userID, rate int64) (Establishment, error) {
+	var estab Establishment
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(userID, "USER"); err != nil {
+		return estab, err
+	}
+	node, err := s.graph.node(estabID, "ESTABLISHMENT")
+	if err != nil {
+		return estab, err
+	}
+	if len(s.graph.relationsBetween(userID, estabID, "RATE")) > 0 {
+		return estab, io.EOF
+	}
+
+	s.graph.createRelation(userID, estabID, "RATE", map[string]interface{}{
+		"value": rate,
+	})
+	(&estab).NodeToEstablishment(node)
+	return estab, nil
+}
+
+func (s MemoryService) DeleteEstab(_ context.Context, estabID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return err
+	}
+	s.graph.detachDelete(estabID)
+	return nil
+}
+
+/*************** Soiree ***************/
+func (s MemoryService) GetConsoByID(_ context.Context, consoID int64) (Conso, error) {
+	var conso Conso
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(consoID, "CONSO")
+	if err != nil {
+		return conso, err
+	}
+	(&conso).NodeToConso(node)
+	return conso, nil
+}
+
+func (s MemoryService) GetSoireeByID(_ context.Context, soireeID int64) (Soiree, error) {
+	var soiree Soiree
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(soireeID, "SOIREE")
+	if err != nil {
+		return soiree, err
+	}
+	(&soiree).NodeToSoiree(node)
+	return soiree, nil
+}
+
+func (s MemoryService) CreateSoiree(_ context.Context, menuID, establishmentID int64, u Soiree) (Soiree, error) {
+	var soiree Soiree
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(menuID, "MENU"); err != nil {
+		return soiree, err
+	}
+	if _, err := s.graph.node(establishmentID, "ESTABLISHMENT"); err != nil {
+		return soiree, err
+	}
+
+	node := s.graph.createNode("SOIREE", map[string]interface{}{
+		"Desc":  u.Desc,
+		"Begin": formatTime(u.Begin),
+		"End":   formatTime(u.End),
+	})
+	s.graph.createRelation(establishmentID, node.NodeIdentity, "SPAWNED", nil)
+	s.graph.createRelation(node.NodeIdentity, menuID, "USE", nil)
+
+	(&soiree).NodeToSoiree(node)
+	return soiree, nil
+}
+
+// userSoireeEvent records a JOIN or LEAVE, stamped with When as in the queries
+func (s MemoryService) userSoireeEvent(userID, soireeID int64, relType string) (Soiree, bool, error) {
+	var soiree Soiree
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(userID, "USER"); err != nil {
+		return soiree, false, err
+	}
+	node, err := s.graph.node(soireeID, "SOIREE")
+	if err != nil {
+		return soiree, false, err
+	}
+
+	s.graph.createRelation(userID, soireeID, relType, map[string]interface{}{
+		"When": formatTime(time.Now()),
+	})
+	(&soiree).NodeToSoiree(node)
+	return soiree, true, nil
+}
+
+func (s MemoryService) UserJoinSoiree(_ context.Context, userID int64, soireeID int64) (Soiree, bool, error) {
+	return s.userSoireeEvent(userID, soireeID, "JOIN")
+}
+
+func (s MemoryService) UserLeaveSoiree(_ context.Context, userID int64, soireeID int64) (Soiree, bool, error) {
+	return s.userSoireeEvent(userID, soireeID, "LEAVE")
+}
+
+// GetConnectedFriends returns the users having joined the soiree more times
+// than they left it
+func (s MemoryService) GetConnectedFriends(_ context.Context, soireeID int64) ([]Profile, error) {
+	var friends []Profile
+	var userIDs []int64
+	joinCount := make(map[int64]int64)
+	leaveCount := make(map[int64]int64)
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, match := range s.graph.related(soireeID, memIn, "", "USER") {
+		userID := match.Node.NodeIdentity
+		switch match.Rel.Type {
+		case "JOIN":
+			if joinCount[userID] == 0 {
+				userIDs = append(userIDs, userID)
+			}
+			joinCount[userID]++
+		case "LEAVE":
+			leaveCount[userID]++
+		}
+	}
+
+	for _, userID := range userIDs {
+		var tmpProfile Profile
+
+		if joinCount[userID] > leaveCount[userID] {
+			(&tmpProfile).NodeToProfile(s.graph.nodes[userID])
+			friends = append(friends, tmpProfile)
+		}
+	}
+	return friends, nil
+}
+
+func (s MemoryService) DeleteSoiree(_ context.Context, soireeID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(soireeID, "SOIREE"); err != nil {
+		return err
+	}
+	s.graph.detachDelete(soireeID)
+	return nil
+}
+
+/*************** Menu ***************/
+func (s MemoryService) relatedConsos(nodeID int64, relType string) []Conso {
+	var consos []Conso
+
+	for _, match := range s.graph.related(nodeID, memOut, relType, "CONSO") {
+		var tmpConso Conso
+
+		(&tmpConso).NodeToConso(match.Node)
+		consos = append(consos, tmpConso)
+	}
+	return consos
+}
+
+func (s MemoryService) GetEstablishmentConsos(_ context.Context, estabID int64) ([]Conso, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return nil, nil
+	}
+	return s.relatedConsos(estabID, "GOT"), nil
+}
+
+func (s MemoryService) GetEstablishmentMenus(_ context.Context, estabID int64) ([]Menu, error) {
+	var menus []Menu
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return menus, nil
+	}
+	for _, match := range s.graph.related(estabID, memOut, "GOT", "MENU") {
+		var tmpMenu Menu
+
+		(&tmpMenu).NodeToMenu(match.Node)
+		menus = append(menus, tmpMenu)
+	}
+	return menus, nil
+}
+
+func (s MemoryService) GetMenuConsos(_ context.Context, menuID int64) ([]Conso, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(menuID, "MENU"); err != nil {
+		return nil, nil
+	}
+	return s.relatedConsos(menuID, "USE"), nil
+}
+
+func (s MemoryService) CreateMenu(_ context.Context, establishmentID int64, u Menu) (Menu, error) {
+	var menu Menu
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(establishmentID, "ESTABLISHMENT"); err != nil {
+		return menu, err
+	}
+
+	node := s.graph.createNode("MENU", map[string]interface{}{
+		"Name": u.Name,
+		"Desc": u.Desc,
+	})
+	s.graph.createRelation(establishmentID, node.NodeIdentity, "GOT", map[string]interface{}{
+		"Display": true,
+	})
+
+	(&menu).NodeToMenu(node)
+	return menu, nil
+}
+
+func (s MemoryService) CreateConso(_ context.Context, establishmentID, menuID int64, c Conso) (Conso, error) {
+	var conso Conso
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(establishmentID, "ESTABLISHMENT"); err != nil {
+		return conso, err
+	}
+	if _, err := s.graph.node(menuID, "MENU"); err != nil {
+		return conso, err
+	}
+	if len(s.graph.relationsBetween(establishmentID, menuID, "GOT")) == 0 {
+		return conso, io.EOF
+	}
+
+	node := s.graph.createNode("CONSO", map[string]interface{}{
+		"Name":    c.Name,
+		"Desc":    c.Description,
+		"Price":    c.Price,
+		"Currency": DefaultCurrency,
+		"Picture":  c.Picture,
+	})
+	s.graph.createRelation(establishmentID, node.NodeIdentity, "GOT", nil)
+	s.graph.createRelation(menuID, node.NodeIdentity, "USE", nil)
+
+	(&conso).NodeToConso(node)
+	return conso, nil
+}
+
+func (s MemoryService) ImportMenus(_ context.Context, estabID int64, menus []Menu) ([]Menu, error) {
+	var imported []Menu
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return nil, io.EOF
+	}
+
+	for _, menu := range menus {
+		var menuNode graph.Node
+		var tmpMenu Menu
+
+		for _, match := range s.graph.related(estabID, memOut, "GOT", "MENU") {
+			if match.Node.Properties["Name"] == menu.Name {
+				menuNode = match.Node
+			}
+		}
+		if menuNode.NodeIdentity == 0 {
+			menuNode = s.graph.createNode("MENU", map[string]interface{}{"Name": menu.Name})
+			s.graph.createRelation(estabID, menuNode.NodeIdentity, "GOT", map[string]interface{}{
+				"Display": true,
+			})
+		}
+		menuNode = s.graph.setNode(menuNode.NodeIdentity, map[string]interface{}{"Desc": menu.Desc})
+
+		for _, conso := range menu.Consommations {
+			var consoNode graph.Node
+
+			for _, match := range s.graph.related(menuNode.NodeIdentity, memOut, "USE", "CONSO") {
+				if match.Node.Properties["Name"] == conso.Name {
+					consoNode = match.Node
+				}
+			}
+			if consoNode.NodeIdentity == 0 {
+				consoNode = s.graph.createNode("CONSO", map[string]interface{}{"Name": conso.Name})
+				s.graph.createRelation(menuNode.NodeIdentity, consoNode.NodeIdentity, "USE", nil)
+			}
+			if len(s.graph.relationsBetween(estabID, consoNode.NodeIdentity, "GOT")) == 0 {
+				s.graph.createRelation(estabID, consoNode.NodeIdentity, "GOT", nil)
+			}
+			s.graph.setNode(consoNode.NodeIdentity, map[string]interface{}{
+				"Desc":    conso.Description,
+				"Price":    conso.Price,
+				"Currency": DefaultCurrency,
+				"Picture":  conso.Picture,
+			})
+		}
+
+		(&tmpMenu).NodeToMenu(menuNode)
+		tmpMenu.Consommations = s.relatedConsos(menuNode.NodeIdentity, "USE")
+		imported = append(imported, tmpMenu)
+	}
+	return imported, nil
+}
+
+func (s MemoryService) GetMenuFromSoiree(_ context.Context, soireeID int64) (Menu, error) {
+	var menu Menu
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(soireeID, "SOIREE"); err != nil {
+		return menu, err
+	}
+	matches := s.graph.related(soireeID, memOut, "USE", "MENU")
+	if len(matches) == 0 {
+		return menu, io.EOF
+	}
+	(&menu).NodeToMenu(matches[0].Node)
+	return menu, nil
+}
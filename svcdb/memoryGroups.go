@@ -0,0 +1,385 @@
+package svcdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Groups ***************/
+func (s MemoryService) getGroup(groupID int64) (Group, error) {
+	var group Group
+
+	node, err := s.graph.node(groupID, "GROUP")
+	if err != nil {
+		return group, err
+	}
+	owners := s.graph.related(groupID, memIn, "CREATE", "")
+	if len(owners) == 0 {
+		return group, io.EOF
+	}
+
+	(&group).NodeToGroup(node)
+	(&group.Owner).NodeToProfile(owners[0].Node)
+	for _, match := range s.graph.related(groupID, memIn, "MEMBER", "") {
+		var tmpMember Profile
+
+		(&tmpMember).NodeToProfile(match.Node)
+		group.Users = append(group.Users, tmpMember)
+	}
+	return group, nil
+}
+
+func (s MemoryService) GetGroup(_ context.Context, groupID int64) (Group, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	return s.getGroup(groupID)
+}
+
+func (s MemoryService) CreateGroup(_ context.Context, g Group, userID int64) (Group, error) {
+	var group Group
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	user, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return group, err
+	}
+
+	node := s.graph.createNode("GROUP", map[string]interface{}{
+		"Name":        g.Name,
+		"Description": g.Description,
+	})
+	s.graph.createRelation(userID, node.NodeIdentity, "CREATE", nil)
+
+	(&group).NodeToGroup(node)
+	(&group.Owner).NodeToProfile(user)
+	return group, nil
+}
+
+func (s MemoryService) UpdateGroup(_ context.Context, new Group) (Group, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	group, err := s.getGroup(new.ID)
+	if err != nil {
+		return group, err
+	}
+	group.UpdateFrom(new)
+
+	node := s.graph.setNode(group.ID, map[string]interface{}{
+		"Name":        group.Name,
+		"Description": group.Description,
+	})
+	(&group).NodeToGroup(node)
+	return group, nil
+}
+
+func (s MemoryService) GroupInvite(_ context.Context, groupID, friendID int64) (string, int64, error) {
+	var group Group
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node, err := s.graph.node(groupID, "GROUP")
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err = s.graph.node(friendID, "USER"); err != nil {
+		return "", 0, err
+	}
+
+	invitation := s.graph.createRelation(groupID, friendID, "INVITE", map[string]interface{}{
+		"Date": formatTime(time.Now()),
+	})
+	(&group).NodeToGroup(node)
+	return group.Name, invitation.RelIdentity, nil
+}
+
+// groupInvitation returns the (u:USER)<-[i:INVITE]-(g:GROUP) relationship
+func (s MemoryService) groupInvitation(invitationID int64) (graph.Relationship, bool) {
+	invitation, err := s.graph.relation(invitationID, "INVITE")
+	if err != nil {
+		return invitation, false
+	}
+	if _, err = s.graph.node(invitation.StartNodeIdentity, "GROUP"); err != nil {
+		return invitation, false
+	}
+	if _, err = s.graph.node(invitation.EndNodeIdentity, "USER"); err != nil {
+		return invitation, false
+	}
+	return invitation, true
+}
+
+func (s MemoryService) GroupInvitationDecline(_ context.Context, _, invitationID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	// No result != error
+	if invitation, ok := s.groupInvitation(invitationID); ok {
+		s.graph.deleteRelation(invitation.RelIdentity)
+	}
+	return nil
+}
+
+func (s MemoryService) GroupInvitationAccept(_ context.Context, _, invitationID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	// No result != error
+	if invitation, ok := s.groupInvitation(invitationID); ok {
+		s.graph.deleteRelation(invitation.RelIdentity)
+		s.graph.createRelation(invitation.EndNodeIdentity, invitation.StartNodeIdentity, "MEMBER", nil)
+	}
+	return nil
+}
+
+func (s MemoryService) GetUserGroups(_ context.Context, userID int64) ([]GroupArrayElement, error) {
+	var groups []GroupArrayElement
+	seen := make(map[int64]bool)
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, match := range s.graph.related(userID, memOut, "", "GROUP") {
+		var tmpGroup GroupArrayElement
+
+		if match.Rel.Type != "MEMBER" && match.Rel.Type != "CREATE" {
+			continue
+		} else if seen[match.Node.NodeIdentity] {
+			continue
+		}
+		seen[match.Node.NodeIdentity] = true
+
+		(&tmpGroup).NodeToGroupArrayElement(match.Node)
+		tmpGroup.UserCount = int64(len(s.graph.related(match.Node.NodeIdentity, memBoth, "", "USER")))
+		groups = append(groups, tmpGroup)
+	}
+	return groups, nil
+}
+
+func (s MemoryService) DeleteGroup(_ context.Context, groupID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(groupID, "GROUP"); err != nil {
+		return err
+	}
+	s.graph.detachDelete(groupID)
+	return nil
+}
+
+func (s MemoryService) DeleteGroupMember(_ context.Context, groupID, userID int64) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(groupID, "GROUP"); err != nil {
+		return err
+	}
+	if _, err := s.graph.node(userID, "USER"); err != nil {
+		return err
+	}
+	members := s.graph.relationsBetween(groupID, userID, "MEMBER")
+	if len(members) == 0 {
+		return io.EOF
+	}
+	for _, member := range members {
+		s.graph.deleteRelation(member.RelIdentity)
+	}
+	return nil
+}
+
+func (s MemoryService) GetGroupsInvitations(_ context.Context, userID int64) ([]GroupInvitation, error) {
+	var invitations []GroupInvitation
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	user, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return invitations, nil
+	}
+	for _, match := range s.graph.related(userID, memIn, "INVITE", "GROUP") {
+		var tmpInvitation GroupInvitation
+
+		(&tmpInvitation).RelationToGroupInvitation(match.Node, match.Rel, user)
+		invitations = append(invitations, tmpInvitation)
+	}
+	return invitations, nil
+}
+
+/*************** Conversation ***************/
+// GetLastMessages returns the messages linked to both nodes, whatever the
+// direction, From being the node holding the FROM relationship. Newest first.
+func (s MemoryService) GetLastMessages(_ context.Context, recipient, initiator int64, page Page) ([]Message, string, error) {
+	var messages []Message
+	var keys []int64
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, message := range s.graph.findNodes("MESSAGE", nil) {
+		for _, a := range s.graph.relationsBetween(message.NodeIdentity, initiator, "") {
+			for _, b := range s.graph.relationsBetween(message.NodeIdentity, recipient, "") {
+				var tmpMessage Message
+
+				if a.RelIdentity == b.RelIdentity {
+					continue
+				}
+				(&tmpMessage).NodeToMessage(message)
+				if a.Type == "FROM" {
+					tmpMessage.From = initiator
+					tmpMessage.To = recipient
+				} else {
+					tmpMessage.From = recipient
+					tmpMessage.To = initiator
+				}
+				messages = append(messages, tmpMessage)
+				keys = append(keys, tmpMessage.ID)
+			}
+		}
+	}
+
+	indexes, next, err := page.window(keys, true)
+	if err != nil {
+		return nil, "", err
+	}
+	ret := make([]Message, 0, len(indexes))
+	for _, i := range indexes {
+		ret = append(ret, messages[i])
+	}
+	return ret, next, nil
+}
+
+// CreateMessage keeps the relationships of the Cypher queries: between users
+// the FROM side is the recipient node, to a group it is the sender
+func (s MemoryService) CreateMessage(_ context.Context, nodeType string, m Message) (Message, error) {
+	var message Message
+	var fromID, toID int64
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if nodeType != "USER" && nodeType != "GROUP" {
+		return message, errors.New("You can't send a message to this ")
+	}
+	if _, err := s.graph.node(m.From, "USER"); err != nil {
+		return message, err
+	}
+	if _, err := s.graph.node(m.To, nodeType); err != nil {
+		return message, err
+	}
+	if nodeType == "USER" {
+		fromID, toID = m.To, m.From
+	} else {
+		fromID, toID = m.From, m.To
+	}
+
+	node := s.graph.createNode("MESSAGE", map[string]interface{}{
+		"Text": m.Text,
+		"Date": formatTime(time.Now()),
+	})
+	s.graph.createRelation(fromID, node.NodeIdentity, "FROM", nil)
+	s.graph.createRelation(node.NodeIdentity, toID, "TO", nil)
+
+	(&message).NodeToMessage(node)
+	message.From = m.From
+	message.To = m.To
+	return message, nil
+}
+
+func (s MemoryService) GetConversationByID(_ context.Context, convID int64) (Conversation, error) {
+	var conv Conversation
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(convID, "CONVERSATION"); err != nil {
+		return conv, err
+	}
+
+	var recipient graph.Node
+	found := false
+	for _, user := range s.graph.related(convID, memIn, "", "USER") {
+		for _, other := range s.graph.related(convID, memIn, "", "") {
+			if other.Rel.RelIdentity != user.Rel.RelIdentity {
+				recipient = other.Node
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		return conv, io.EOF
+	}
+	count := int64(len(s.graph.related(convID, memBoth, "", "MESSAGE")))
+
+	(&conv).NodeToConversation([]interface{}{convID, recipient.NodeIdentity, count})
+	if conv.RecipientID > 0 {
+		conv.RecipientType = recipient.Labels[0]
+	}
+	return conv, nil
+}
+
+func (s MemoryService) GetNodeType(_ context.Context, nodeID int64) (string, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(nodeID, "")
+	if err != nil {
+		return "", err
+	}
+	return node.Labels[0], nil
+}
+
+/*************** Success ***************/
+func (s MemoryService) AddSuccess(_ context.Context, userID int64, successValue string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	successes := s.graph.findNodes("SUCCESS", func(node graph.Node) bool {
+		return node.Properties["Value"] == successValue
+	})
+	if len(successes) == 0 {
+		return io.EOF
+	}
+	user, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return err
+	}
+
+	for _, success := range successes {
+		s.graph.createRelation(userID, success.NodeIdentity, "GOT", nil)
+		// null + 10 stays null in Cypher
+		if points, ok := user.Properties["SuccessPoints"].(int64); ok {
+			user = s.graph.setNode(userID, map[string]interface{}{
+				"SuccessPoints": points + 10,
+			})
+		}
+	}
+	return nil
+}
+
+func (s MemoryService) GetSuccessByValue(_ context.Context, value string) (Success, error) {
+	var success Success
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes := s.graph.findNodes("", func(node graph.Node) bool {
+		return node.Properties["Value"] == value
+	})
+	if len(nodes) == 0 {
+		return success, io.EOF
+	}
+	(&success).NodeToSuccess(nodes[0])
+	return success, nil
+}
@@ -0,0 +1,873 @@
+package svcdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Order ***************/
+// getOrder mirrors Service.GetOrder : an order missing its TO, FOR or DURING
+// relationships is not matched and an empty Order is returned without error
+func (s MemoryService) getOrder(orderID int64) (Order, error) {
+	var order Order
+
+	node, err := s.graph.node(orderID, "ORDER")
+	if err != nil {
+		return order, nil
+	}
+	users := s.graph.related(orderID, memOut, "TO", "USER")
+	consos := s.graph.related(orderID, memOut, "FOR", "CONSO")
+	soirees := s.graph.related(orderID, memOut, "DURING", "SOIREE")
+	if len(users) == 0 || len(consos) == 0 || len(soirees) == 0 {
+		return order, nil
+	}
+
+	(&order).NodeToOrder(node)
+	(&order).RelationToSoiree(node, soirees[0].Rel, soirees[0].Node)
+	for _, match := range users {
+		(&order).RelationAddUser(node, match.Rel, match.Node)
+	}
+	for _, match := range consos {
+		(&order).RelationAddConso(node, match.Rel, match.Node)
+	}
+	for _, match := range s.graph.related(orderID, memOut, "DONE", "STEP") {
+		(&order).RelationAddStep(node, match.Rel, match.Node)
+	}
+	return order, nil
+}
+
+func (s MemoryService) GetOrder(_ context.Context, orderID int64) (Order, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	return s.getOrder(orderID)
+}
+
+// orderMatches applies the filters built by Service.SearchOrders
+func (s MemoryService) orderMatches(node graph.Node, order Order) bool {
+	orderID := node.NodeIdentity
+
+	/* Order Done */
+	if len(order.Done) > 0 {
+		_, set := node.Properties["Done"]
+		if order.Done == "set" && !set {
+			return false
+		} else if order.Done == "unset" && set {
+			return false
+		} else if order.Done != "set" && order.Done != "unset" && node.Properties["Done"] != order.Done {
+			return false
+		}
+	}
+
+	/* Soiree */
+	if order.Soiree.ID > 0 {
+		found := false
+		for _, match := range s.graph.related(orderID, memOut, "", "SOIREE") {
+			found = found || match.Node.NodeIdentity == order.Soiree.ID
+		}
+		if !found {
+			return false
+		}
+	}
+
+	/* Users */
+	for _, user := range order.Users {
+		found := false
+		for _, match := range s.graph.related(orderID, memOut, "TO", "USER") {
+			if match.Node.NodeIdentity == user.User.ID &&
+				(len(user.Approved) == 0 || match.Rel.Properties["Approved"] == user.Approved) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	/* Steps */
+	for _, step := range order.Steps {
+		found := false
+		for _, match := range s.graph.related(orderID, memOut, "DONE", "STEP") {
+			if match.Node.Properties["Name"] == step.Name &&
+				(len(step.Result) == 0 || match.Node.Properties["Result"] == step.Result) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s MemoryService) SearchOrders(_ context.Context, order Order, page Page) (Orders, string, error) {
+	var orders Orders
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes := s.graph.findNodes("ORDER", func(node graph.Node) bool {
+		return s.orderMatches(node, order)
+	})
+	nodes, next, err := pageNodes(nodes, page, false)
+	if err != nil {
+		return orders, "", err
+	}
+	for _, node := range nodes {
+		tmpOrder, _ := s.getOrder(node.NodeIdentity)
+		orders = append(orders, tmpOrder)
+	}
+	return orders, next, nil
+}
+
+func (s MemoryService) CreateOrder(_ context.Context, o Order) (Order, error) {
+	var order Order
+	var sumPrice int64
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	for _, user := range o.Users {
+		sumPrice += user.Price
+	}
+	if sumPrice != o.Price {
+		return order, errors.New("CreateOrder (Price Check) : sum of price in users != order.Price")
+	}
+
+	/* Match */
+	if _, err := s.graph.node(o.Soiree.ID, "SOIREE"); err != nil {
+		return order, err
+	}
+	for _, user := range o.Users {
+		if _, err := s.graph.node(user.User.ID, "USER"); err != nil {
+			return order, err
+		}
+	}
+	consoNodes := make(map[int64]graph.Node)
+	for _, conso := range o.Consos {
+		node, err := s.graph.node(conso.Conso.ID, "CONSO")
+		if err != nil {
+			return order, err
+		}
+		consoNodes[node.NodeIdentity] = node
+	}
+
+	/* Create */
+	node := s.graph.createNode("ORDER", map[string]interface{}{
+		"Price": o.Price,
+	})
+	step := s.graph.createNode("STEP", map[string]interface{}{
+		"Name": "Issued",
+		"Date": formatTime(time.Now()),
+	})
+	s.graph.createRelation(node.NodeIdentity, o.Soiree.ID, "DURING", nil)
+	s.graph.createRelation(node.NodeIdentity, step.NodeIdentity, "DONE", nil)
+	for _, user := range o.Users {
+		s.graph.createRelation(node.NodeIdentity, user.User.ID, "TO", map[string]interface{}{
+			"Price":     user.Price,
+			"Reference": user.Reference,
+			"Approved":  "",
+		})
+	}
+	for _, conso := range o.Consos {
+		s.graph.createRelation(node.NodeIdentity, conso.Conso.ID, "FOR", map[string]interface{}{
+			"Amount":   conso.Amount,
+			"Name":     consoNodes[conso.Conso.ID].Properties["Name"],
+			"Price":    consoMoney(consoNodes[conso.Conso.ID].Properties),
+			"Currency": DefaultCurrency,
+		})
+	}
+
+	return s.getOrder(node.NodeIdentity)
+}
+
+// PutOrder closes a step with the same checks and transitions as
+// Service.PutOrder and its stepHandler
+func (s MemoryService) PutOrder(_ context.Context, orderID int64, step string, flag bool) (Order, error) {
+	var stepNode StepOrder
+
+	step = strings.Title(strings.ToLower(step))
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	/* Get Order */
+	order, err := s.getOrder(orderID)
+	if err != nil {
+		return order, err
+	}
+
+	/* Error handling */
+	if order.ID == 0 {
+		return order, errors.New("Target order not found")
+	} else if order.Done == "true" {
+		return order, errors.New("Order already done")
+	}
+	for i := range order.Steps {
+		if order.Steps[i].Name == step {
+			stepNode = order.Steps[i]
+			break
+		}
+	}
+	if stepNode.ID == 0 {
+		return order, errors.New("Target step have yet to open")
+	} else if len(stepNode.Result) > 0 {
+		return order, errors.New("Target step is closed already")
+	}
+
+	/* Step handling */
+	validated, _, _, err := stepCondition(order, stepNode, flag)
+	if err != nil {
+		return order, err
+	} else if !validated {
+		return order, nil
+	}
+
+	/* Mark step result */
+	s.graph.setNode(stepNode.ID, map[string]interface{}{
+		"Result": strconv.FormatBool(flag),
+	})
+	for i := range order.Steps {
+		if order.Steps[i].Name == stepNode.Name {
+			order.Steps[i].Result = "wip"
+			break
+		}
+	}
+
+	if flag {
+		/* Get next steps */
+		nextStep, err := getNextAllowedStep(order)
+		if err != nil {
+			return order, err
+		}
+
+		/* Open them */
+		if len(nextStep) > 0 {
+			next := s.graph.createNode("STEP", map[string]interface{}{
+				"Name": nextStep,
+				"Date": formatTime(time.Now()),
+			})
+			s.graph.createRelation(order.ID, next.NodeIdentity, "DONE", nil)
+		} else {
+			s.graph.setNode(order.ID, map[string]interface{}{"Done": "true"})
+		}
+	} else {
+		s.graph.setNode(order.ID, map[string]interface{}{"Done": "false"})
+	}
+
+	/* Get updated order */
+	return s.getOrder(order.ID)
+}
+
+func (s MemoryService) AnswerOrder(_ context.Context, orderID int64, userID int64, answer bool) (Order, error) {
+	var order Order
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(orderID, "ORDER"); err != nil {
+		return order, err
+	}
+	rels := s.graph.relationsBetween(orderID, userID, "TO")
+	if len(rels) == 0 {
+		return order, io.EOF
+	}
+	for _, rel := range rels {
+		s.graph.setRelation(rel.RelIdentity, map[string]interface{}{
+			"Approved": strconv.FormatBool(answer),
+		})
+	}
+	return s.getOrder(orderID)
+}
+
+func (s MemoryService) FailOrder(_ context.Context, orderID int64) (Order, error) {
+	var order Order
+	var openSteps []int64
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(orderID, "ORDER"); err != nil {
+		return order, err
+	}
+	for _, match := range s.graph.related(orderID, memBoth, "", "STEP") {
+		if result, _ := match.Node.Properties["Result"].(string); len(result) == 0 {
+			openSteps = append(openSteps, match.Node.NodeIdentity)
+		}
+	}
+	if len(openSteps) == 0 {
+		return order, io.EOF
+	}
+
+	s.graph.setNode(orderID, map[string]interface{}{"Done": "false"})
+	for _, stepID := range openSteps {
+		s.graph.setNode(stepID, map[string]interface{}{"Result": "false"})
+	}
+	return s.getOrder(orderID)
+}
+
+func (s MemoryService) CancelOrder(_ context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+	var stepID int64
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node, err := s.graph.node(orderID, "ORDER")
+	if err != nil {
+		return order, StepNotOpenErr
+	} else if done, _ := node.Properties["Done"].(string); len(done) > 0 {
+		return order, StepNotOpenErr
+	}
+	for _, match := range s.graph.related(orderID, memOut, "DONE", "STEP") {
+		result, _ := match.Node.Properties["Result"].(string)
+		if match.Node.Properties["Name"] == step && len(result) == 0 {
+			stepID = match.Node.NodeIdentity
+		}
+	}
+	if stepID == 0 {
+		return order, StepNotOpenErr
+	}
+
+	s.graph.setNode(orderID, map[string]interface{}{"Done": "false", "CancelRequest": nil})
+	s.graph.setNode(stepID, map[string]interface{}{"Result": "false"})
+	cancelled := s.graph.createNode("STEP", map[string]interface{}{
+		"Name":   CancelledStep,
+		"Date":   formatTime(time.Now()),
+		"Result": "true",
+		"By":     userID,
+	})
+	s.graph.createRelation(orderID, cancelled.NodeIdentity, "DONE", nil)
+	return s.getOrder(orderID)
+}
+
+func (s MemoryService) RequestCancel(_ context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+	var requester interface{}
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node, err := s.graph.node(orderID, "ORDER")
+	if err != nil {
+		return order, StepNotOpenErr
+	} else if done, _ := node.Properties["Done"].(string); len(done) > 0 {
+		return order, StepNotOpenErr
+	}
+	open := false
+	for _, match := range s.graph.related(orderID, memOut, "DONE", "STEP") {
+		result, _ := match.Node.Properties["Result"].(string)
+		open = open || (match.Node.Properties["Name"] == step && len(result) == 0)
+	}
+	if !open {
+		return order, StepNotOpenErr
+	}
+
+	if userID != 0 {
+		requester = userID
+	}
+	s.graph.setNode(orderID, map[string]interface{}{"CancelRequest": requester})
+	return s.getOrder(orderID)
+}
+
+func (s MemoryService) GetPendingOrders(_ context.Context) ([]Order, error) {
+	var orders []Order
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	pending := s.graph.findNodes("ORDER", func(node graph.Node) bool {
+		done, _ := node.Properties["Done"].(string)
+		return len(done) == 0
+	})
+	for _, node := range pending {
+		order, err := s.getOrder(node.NodeIdentity)
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s MemoryService) UpdateOrderReference(_ context.Context, orderID int64, userID int64, reference string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	// No result != error
+	if _, err := s.graph.node(orderID, "ORDER"); err != nil {
+		return nil
+	}
+	for _, rel := range s.graph.relationsBetween(orderID, userID, "TO") {
+		s.graph.setRelation(rel.RelIdentity, map[string]interface{}{
+			"Reference": reference,
+		})
+	}
+	return nil
+}
+
+func (s MemoryService) GetOrderByReference(_ context.Context, reference string) (Order, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, node := range s.graph.findNodes("ORDER", nil) {
+		for _, match := range s.graph.related(node.NodeIdentity, memOut, "TO", "USER") {
+			if match.Rel.Properties["Reference"] == reference {
+				return s.getOrder(node.NodeIdentity)
+			}
+		}
+	}
+	return Order{}, nil
+}
+
+func (s MemoryService) ClaimWebhookEvent(_ context.Context, eventID string, eventType string) (bool, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	claimed := s.graph.findNodes("WEBHOOK", func(node graph.Node) bool {
+		return node.Properties["EventID"] == eventID
+	})
+	if len(claimed) > 0 {
+		return false, nil
+	}
+	s.graph.createNode("WEBHOOK", map[string]interface{}{
+		"EventID": eventID,
+		"Type":    eventType,
+		"Date":    formatTime(time.Now()),
+		"Claimed": true,
+	})
+	return true, nil
+}
+
+func (s MemoryService) ReleaseWebhookEvent(_ context.Context, eventID string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	claimed := s.graph.findNodes("WEBHOOK", func(node graph.Node) bool {
+		return node.Properties["EventID"] == eventID
+	})
+	for _, node := range claimed {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+	return nil
+}
+
+// GetChargedOrders mirrors the request of Service : orders whose Issued step
+// is not older than from, with a charge reference
+func (s MemoryService) GetChargedOrders(_ context.Context, from time.Time) ([]Order, error) {
+	var orders []Order
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	since := formatTime(from)
+	for _, node := range s.graph.findNodes("ORDER", nil) {
+		issued := false
+		for _, match := range s.graph.related(node.NodeIdentity, memOut, "DONE", "STEP") {
+			date, _ := match.Node.Properties["Date"].(string)
+			if match.Node.Properties["Name"] == OrderWorkflow[0].Name && date >= since {
+				issued = true
+			}
+		}
+		charged := false
+		for _, match := range s.graph.related(node.NodeIdentity, memOut, "TO", "USER") {
+			if reference, _ := match.Rel.Properties["Reference"].(string); len(reference) > 0 {
+				charged = true
+			}
+		}
+		if !issued || !charged {
+			continue
+		}
+		order, err := s.getOrder(node.NodeIdentity)
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s MemoryService) FlagOrder(_ context.Context, orderID int64, issue string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	// No result != error
+	if _, err := s.graph.node(orderID, "ORDER"); err != nil {
+		return nil
+	}
+	var value interface{}
+	if len(issue) > 0 {
+		value = issue
+	}
+	s.graph.setNode(orderID, map[string]interface{}{"Reconciliation": value})
+	return nil
+}
+
+// AddLedgerEntry mirrors the MERGE of Service on the kind, reference and refund
+func (s MemoryService) AddLedgerEntry(_ context.Context, entry LedgerEntry) (LedgerEntry, error) {
+	var stored LedgerEntry
+
+	if len(entry.Kind) == 0 || len(entry.Reference) == 0 {
+		return stored, RequestError
+	}
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	if len(entry.Currency) == 0 {
+		entry.Currency = DefaultCurrency
+	}
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	recorded := s.graph.findNodes("LEDGER", func(node graph.Node) bool {
+		refund, _ := node.Properties["Refund"].(string)
+		return node.Properties["Kind"] == entry.Kind && node.Properties["Reference"] == entry.Reference &&
+			(len(entry.Refund) == 0 || refund == entry.Refund)
+	})
+	if len(recorded) > 0 {
+		(&stored).NodeToLedgerEntry(recorded[0])
+		return stored, nil
+	}
+	node := s.graph.createNode("LEDGER", map[string]interface{}{
+		"Kind":      entry.Kind,
+		"Reference": entry.Reference,
+		"Refund":    entry.Refund,
+		"Amount":    entry.Amount,
+		"Currency":  entry.Currency,
+		"Order":     entry.OrderID,
+		"User":      entry.UserID,
+		"Pro":       entry.ProID,
+		"Soiree":    entry.SoireeID,
+		"Date":      formatTime(entry.Date),
+	})
+	(&stored).NodeToLedgerEntry(node)
+	return stored, nil
+}
+
+func (s MemoryService) GetLedger(_ context.Context, filter LedgerFilter) ([]LedgerEntry, error) {
+	var entries []LedgerEntry
+
+	if filter.OrderID == 0 && filter.ProID == 0 {
+		return entries, RequestError
+	}
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	from, to := formatTime(filter.From), formatTime(filter.To)
+	nodes := s.graph.findNodes("LEDGER", func(node graph.Node) bool {
+		date, _ := node.Properties["Date"].(string)
+		return (filter.OrderID == 0 || node.Properties["Order"] == filter.OrderID) &&
+			(filter.ProID == 0 || node.Properties["Pro"] == filter.ProID) &&
+			(filter.From.IsZero() || date >= from) &&
+			(filter.To.IsZero() || date < to)
+	})
+	for _, node := range nodes {
+		var entry LedgerEntry
+
+		(&entry).NodeToLedgerEntry(node)
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// ClaimIdempotencyKey mirrors the MERGE of Service, the expired keys and the
+// keys abandoned without response being claimed again
+func (s MemoryService) ClaimIdempotencyKey(_ context.Context, key string, fingerprint string) (IdempotentResponse, bool, error) {
+	var stored IdempotentResponse
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	now := time.Now()
+	expired := formatTime(now.Add(-IdempotencyTTL))
+	abandoned := formatTime(now.Add(-IdempotencyLease))
+	keys := s.graph.findNodes("IDEMPOTENCY", func(node graph.Node) bool {
+		return node.Properties["Key"] == key
+	})
+	for _, node := range keys {
+		date, _ := node.Properties["Date"].(string)
+		response, _ := node.Properties["Response"].(string)
+		if date < expired || (len(response) == 0 && date < abandoned) {
+			s.graph.detachDelete(node.NodeIdentity)
+			continue
+		}
+		(&stored).NodeToIdempotentResponse(node)
+		return stored, false, nil
+	}
+
+	node := s.graph.createNode("IDEMPOTENCY", map[string]interface{}{
+		"Key":         key,
+		"Fingerprint": fingerprint,
+		"Date":        formatTime(now),
+		"Response":    "",
+	})
+	(&stored).NodeToIdempotentResponse(node)
+	return stored, true, nil
+}
+
+func (s MemoryService) StoreIdempotencyKey(_ context.Context, key string, response string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	keys := s.graph.findNodes("IDEMPOTENCY", func(node graph.Node) bool {
+		return node.Properties["Key"] == key
+	})
+	for _, node := range keys {
+		if len(response) == 0 {
+			s.graph.detachDelete(node.NodeIdentity)
+		} else {
+			s.graph.setNode(node.NodeIdentity, map[string]interface{}{"Response": response})
+		}
+	}
+	return nil
+}
+
+// UserOrder is the legacy single conso order
+func (s MemoryService) UserOrder(_ context.Context, user User, soiree Soiree, conso Conso) (int64, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(user.ID, "USER"); err != nil {
+		return 0, err
+	}
+	if _, err := s.graph.node(soiree.ID, "SOIREE"); err != nil {
+		return 0, err
+	}
+	if _, err := s.graph.node(conso.ID, "CONSO"); err != nil {
+		return 0, err
+	}
+
+	node := s.graph.createNode("ORDER", map[string]interface{}{
+		"Price":     conso.Price,
+		"Created":   formatTime(time.Now()),
+		"Reference": "",
+	})
+	s.graph.createRelation(user.ID, node.NodeIdentity, "ORDERED", nil)
+	s.graph.createRelation(node.NodeIdentity, soiree.ID, "DURING", nil)
+	s.graph.createRelation(node.NodeIdentity, conso.ID, "PURCHASED", nil)
+	return node.NodeIdentity, nil
+}
+
+func (s MemoryService) soireeOrders(soireeID int64, relType string, page Page) ([]Order, string, error) {
+	var orders []Order
+	var nodes []graph.Node
+
+	if _, err := s.graph.node(soireeID, "SOIREE"); err != nil {
+		return orders, "", nil
+	}
+	for _, match := range s.graph.related(soireeID, memIn, relType, "ORDER") {
+		nodes = append(nodes, match.Node)
+	}
+	nodes, next, err := pageNodes(nodes, page, false)
+	if err != nil {
+		return orders, "", err
+	}
+	for _, node := range nodes {
+		var tmpOrder Order
+
+		(&tmpOrder).NodeToOrder(node)
+		orders = append(orders, tmpOrder)
+	}
+	return orders, next, nil
+}
+
+func (s MemoryService) GetOrdersBySoiree(_ context.Context, soireeID int64) ([]Order, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	orders, _, err := s.soireeOrders(soireeID, "", Page{})
+	return orders, err
+}
+
+func (s MemoryService) GetSoireeOrders(_ context.Context, soireeID int64, page Page) ([]Order, string, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	return s.soireeOrders(soireeID, "DURING", page)
+}
+
+func (s MemoryService) GetConsoByOrderID(_ context.Context, orderID int64) (Conso, error) {
+	var conso Conso
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(orderID, "ORDER"); err != nil {
+		return conso, err
+	}
+	matches := s.graph.related(orderID, memOut, "FOR", "CONSO")
+	if len(matches) == 0 {
+		return conso, io.EOF
+	}
+	(&conso).NodeToConso(matches[0].Node)
+	return conso, nil
+}
+
+/*************** Analyse ***************/
+func (s MemoryService) GetAnalyseP(_ context.Context, estabID int64, soireeID int64) ([]AnalyseP, error) {
+	var analyses []AnalyseP
+	var userIDs []int64
+	soireeCount := make(map[int64]map[int64]bool)
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err == nil {
+		for _, soiree := range s.graph.related(estabID, memOut, "", "SOIREE") {
+			if soireeID > 0 && soiree.Node.NodeIdentity != soireeID {
+				continue
+			}
+			for _, user := range s.graph.related(soiree.Node.NodeIdentity, memIn, "", "USER") {
+				userID := user.Node.NodeIdentity
+				if soireeCount[userID] == nil {
+					soireeCount[userID] = make(map[int64]bool)
+					userIDs = append(userIDs, userID)
+				}
+				soireeCount[userID][soiree.Node.NodeIdentity] = true
+			}
+		}
+	}
+
+	var tmpAnalyse AnalyseP
+	tmpAnalyse.Type = "Population"
+	tmpAnalyse.Values = make(map[string]int64)
+	tmpAnalyse.Values["new"] = 0
+	tmpAnalyse.Values["initied"] = 0
+	tmpAnalyse.Values["regular"] = 0
+	tmpAnalyse.Values["habitual"] = 0
+	for _, userID := range userIDs {
+		count := int64(len(soireeCount[userID]))
+		if count > 10 {
+			tmpAnalyse.Values["habitual"] += 1
+		} else if count > 5 {
+			tmpAnalyse.Values["regular"] += 1
+		} else if count > 2 {
+			tmpAnalyse.Values["initied"] += 1
+		} else {
+			tmpAnalyse.Values["new"] += 1
+		}
+	}
+	analyses = append(analyses, tmpAnalyse)
+	return analyses, nil
+}
+
+func (s MemoryService) GetAnalyseC(_ context.Context, estabID int64, soireeID int64) ([]AnalyseC, error) {
+	analyses := newAnalysesC()
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err != nil {
+		return nil, nil
+	}
+	for _, soiree := range s.graph.related(estabID, memOut, "SPAWNED", "SOIREE") {
+		if soireeID > 0 && soiree.Node.NodeIdentity != soireeID {
+			continue
+		}
+		for _, match := range s.graph.related(soiree.Node.NodeIdentity, memIn, "DURING", "ORDER") {
+			order, _ := s.getOrder(match.Node.NodeIdentity)
+			if order.ID == 0 || order.Done != "true" {
+				continue
+			}
+
+			var issued time.Time
+			for _, step := range order.Steps {
+				if step.Name == "Issued" {
+					issued = step.Date
+				}
+			}
+			for _, conso := range order.Consos {
+				analyses.add(soiree.Node.NodeIdentity, conso.Conso, issued, conso.Amount)
+			}
+		}
+	}
+	return analyses.result(), nil
+}
+
+func (s MemoryService) GetAnalyseF(_ context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error) {
+	var events []presenceEvent
+	soirees := make(map[int64]Soiree)
+
+	if !validRange(from, to) {
+		return nil, InvalidRangeErr
+	}
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(estabID, "ESTABLISHMENT"); err == nil {
+		for _, soiree := range s.graph.related(estabID, memOut, "SPAWNED", "SOIREE") {
+			if soireeID > 0 && soiree.Node.NodeIdentity != soireeID {
+				continue
+			}
+			var tmpSoiree Soiree
+
+			(&tmpSoiree).NodeToSoiree(soiree.Node)
+			soirees[tmpSoiree.ID] = tmpSoiree
+			for _, match := range s.graph.related(tmpSoiree.ID, memIn, "", "USER") {
+				if match.Rel.Type != "JOIN" && match.Rel.Type != "LEAVE" {
+					continue
+				}
+				tmpEvent := presenceEvent{
+					UserID:   match.Node.NodeIdentity,
+					SoireeID: tmpSoiree.ID,
+					Join:     match.Rel.Type == "JOIN",
+					When:     propertyTime("GetAnalyseF", match.Rel.Properties, "When"),
+				}
+				if !tmpEvent.When.IsZero() {
+					events = append(events, tmpEvent)
+				}
+			}
+		}
+	}
+
+	tmpAnalyse := frequentation(visits(events, soirees, time.Now()), from, to)
+	tmpAnalyse.Soiree = soireeID
+	return []AnalyseF{tmpAnalyse}, nil
+}
+
+func (s MemoryService) GetMenuStat(_ context.Context, menuID int64, from, to time.Time) (MenuStat, error) {
+	stat := MenuStat{Menu: menuID, From: from, To: to}
+
+	if !validRange(from, to) {
+		return stat, InvalidRangeErr
+	}
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, soiree := range s.graph.related(menuID, memIn, "USE", "SOIREE") {
+		for _, match := range s.graph.related(soiree.Node.NodeIdentity, memIn, "DURING", "ORDER") {
+			var issued, ready, completed time.Time
+
+			order, _ := s.getOrder(match.Node.NodeIdentity)
+			for _, step := range order.Steps {
+				switch step.Name {
+				case "Issued":
+					issued = step.Date
+				case "Ready":
+					ready = step.Date
+				case "Completed":
+					completed = step.Date
+				}
+			}
+			if order.ID == 0 || issued.Before(from) || !issued.Before(to) {
+				continue
+			}
+			(&stat).add(order, ready, completed)
+		}
+	}
+	return stat, nil
+}
@@ -0,0 +1,37 @@
+package svcdb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryPutOrderFollowsTheWorkflow(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryService()
+	user := db.SeedUser("user@nightline.fr")
+
+	order, err := db.CreateOrder(ctx, Order{
+		Price:  500,
+		Soiree: db.SeedSoiree(),
+		Users:  UserOrders{{User: user, Price: 500}},
+		Consos: ConsoOrders{{Conso: db.SeedConso(500), Amount: 1}},
+	})
+	if err != nil {
+		t.Fatal("CreateOrder : " + err.Error())
+	}
+
+	if _, err = db.PutOrder(ctx, order.ID, "Ready", true); err == nil {
+		t.Error("PutOrder Ready before Issued : got no error")
+	}
+	for _, step := range OrderWorkflow {
+		if order, err = db.PutOrder(ctx, order.ID, step.Name, true); err != nil {
+			t.Fatal("PutOrder " + step.Name + " : " + err.Error())
+		}
+	}
+	if order.Done != "true" || len(order.Steps) != len(OrderWorkflow) {
+		t.Errorf("PutOrder : got done %q with %d steps, want a completed order of %d steps", order.Done, len(order.Steps), len(OrderWorkflow))
+	}
+	if _, err = db.PutOrder(ctx, order.ID, "Completed", true); err == nil {
+		t.Error("PutOrder on a completed order : got no error")
+	}
+}
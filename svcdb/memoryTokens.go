@@ -0,0 +1,217 @@
+package svcdb
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Tokens ***************/
+func (s MemoryService) CreateRefreshToken(_ context.Context, token RefreshToken) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	now := formatTime(time.Now())
+	for _, node := range s.graph.findNodes("REFRESH_TOKEN", func(node graph.Node) bool {
+		expires, _ := node.Properties["Expires"].(string)
+		return expires < now
+	}) {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+	for _, node := range s.graph.findNodes("REVOCATION", func(node graph.Node) bool {
+		until, _ := node.Properties["Until"].(string)
+		return until < now
+	}) {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+
+	s.createRefreshToken(token.Hash, token.Subject, token.Expires)
+	return nil
+}
+
+func (s MemoryService) createRefreshToken(hash, subject string, expires time.Time) {
+	s.graph.createNode("REFRESH_TOKEN", map[string]interface{}{
+		"Hash":    hash,
+		"Subject": subject,
+		"Expires": formatTime(expires),
+		"Used":    false,
+	})
+}
+
+func (s MemoryService) UseRefreshToken(_ context.Context, hash string, next RefreshToken, until time.Time) (RefreshToken, error) {
+	var used RefreshToken
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	now := time.Now()
+	nodes := s.graph.findNodes("REFRESH_TOKEN", func(node graph.Node) bool {
+		expires, _ := node.Properties["Expires"].(string)
+		return node.Properties["Hash"] == hash && expires >= formatTime(now)
+	})
+	if len(nodes) == 0 {
+		return used, RefreshTokenErr
+	}
+	(&used).NodeToRefreshToken(nodes[0])
+	s.graph.setNode(nodes[0].NodeIdentity, map[string]interface{}{"Used": true})
+
+	if used.Used {
+		s.revokeSubject(Revocation{Subject: used.Subject, Before: now, Until: until})
+		return used, RefreshTokenErr
+	}
+	s.createRefreshToken(next.Hash, used.Subject, next.Expires)
+	return used, nil
+}
+
+func (s MemoryService) RevokeTokens(_ context.Context, r Revocation, refreshHash string) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if len(r.TokenID) == 0 {
+		s.revokeSubject(r)
+		return nil
+	}
+	s.graph.createNode("REVOCATION", map[string]interface{}{
+		"Subject": r.Subject,
+		"TokenID": r.TokenID,
+		"Before":  "",
+		"Until":   formatTime(r.Until),
+	})
+	for _, node := range s.graph.findNodes("REFRESH_TOKEN", func(node graph.Node) bool {
+		return len(refreshHash) > 0 && node.Properties["Hash"] == refreshHash && node.Properties["Subject"] == r.Subject
+	}) {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+	return nil
+}
+
+// revokeSubject mirrors the one of Service
+func (s MemoryService) revokeSubject(r Revocation) {
+	s.graph.createNode("REVOCATION", map[string]interface{}{
+		"Subject": r.Subject,
+		"TokenID": "",
+		"Before":  formatTime(r.Before.Truncate(time.Second)),
+		"Until":   formatTime(r.Until),
+	})
+	for _, node := range s.graph.findNodes("REFRESH_TOKEN", func(node graph.Node) bool {
+		return node.Properties["Subject"] == r.Subject
+	}) {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+}
+
+func (s MemoryService) IsTokenRevoked(_ context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	now := formatTime(time.Now())
+	issued := formatTime(issuedAt.Truncate(time.Second))
+	revocations := s.graph.findNodes("REVOCATION", func(node graph.Node) bool {
+		until, _ := node.Properties["Until"].(string)
+		id, _ := node.Properties["TokenID"].(string)
+		before, _ := node.Properties["Before"].(string)
+		return node.Properties["Subject"] == subject && until >= now &&
+			(id == tokenID || (len(id) == 0 && before > issued))
+	})
+	return len(revocations) > 0, nil
+}
+
+func (s MemoryService) CreateAccountToken(_ context.Context, token AccountToken) error {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	now := formatTime(time.Now())
+	for _, node := range s.graph.findNodes("ACCOUNT_TOKEN", func(node graph.Node) bool {
+		expires, _ := node.Properties["Expires"].(string)
+		return expires < now ||
+			(node.Properties["Subject"] == token.Subject && node.Properties["Purpose"] == token.Purpose)
+	}) {
+		s.graph.detachDelete(node.NodeIdentity)
+	}
+
+	s.graph.createNode("ACCOUNT_TOKEN", map[string]interface{}{
+		"Hash":    token.Hash,
+		"Subject": token.Subject,
+		"Purpose": token.Purpose,
+		"Email":   token.Email,
+		"Expires": formatTime(token.Expires),
+	})
+	return nil
+}
+
+// useAccountToken mirrors the one of Service, s.graph.mtx must be held
+func (s MemoryService) useAccountToken(hash string, purpose string) (token AccountToken, label string, id int64, err error) {
+	now := formatTime(time.Now())
+	nodes := s.graph.findNodes("ACCOUNT_TOKEN", func(node graph.Node) bool {
+		expires, _ := node.Properties["Expires"].(string)
+		return node.Properties["Hash"] == hash && node.Properties["Purpose"] == purpose && expires >= now
+	})
+	if len(nodes) == 0 {
+		return token, "", 0, AccountTokenErr
+	}
+	(&token).NodeToAccountToken(nodes[0])
+	s.graph.detachDelete(nodes[0].NodeIdentity)
+
+	kind, id, err := ParseTokenSubject(token.Subject)
+	if err != nil {
+		return token, "", 0, err
+	}
+	label, err = subjectLabel(kind)
+	return token, label, id, err
+}
+
+func (s MemoryService) ResetPassword(_ context.Context, hash string, password string) (AccountToken, error) {
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return AccountToken{}, err
+	}
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	token, label, id, err := s.useAccountToken(hash, PurposeResetPassword)
+	if err != nil {
+		return token, err
+	}
+	if _, err := s.graph.node(id, label); err != nil {
+		return token, AccountTokenErr
+	}
+	s.graph.setNode(id, map[string]interface{}{"Password": hashed})
+	return token, nil
+}
+
+func (s MemoryService) GetAccountByEmail(_ context.Context, kind string, email string) (int64, error) {
+	label, err := subjectLabel(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes := s.graph.findNodes(label, func(node graph.Node) bool {
+		return node.Properties["Email"] == email
+	})
+	if len(nodes) == 0 {
+		return 0, io.EOF
+	}
+	return nodes[0].NodeIdentity, nil
+}
+
+func (s MemoryService) VerifyEmail(_ context.Context, hash string) (AccountToken, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	token, label, id, err := s.useAccountToken(hash, PurposeVerifyEmail)
+	if err != nil {
+		return token, err
+	}
+	node, err := s.graph.node(id, label)
+	if err != nil || node.Properties["Email"] != token.Email {
+		return token, AccountTokenErr
+	}
+	s.graph.setNode(id, map[string]interface{}{"Verified": true})
+	return token, nil
+}
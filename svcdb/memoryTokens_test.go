@@ -0,0 +1,32 @@
+package svcdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryIsTokenRevokedToTheSecond(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryService()
+	logout := time.Now().Truncate(time.Second).Add(700 * time.Millisecond)
+
+	err := db.RevokeTokens(ctx, Revocation{Subject: "user:1", Before: logout, Until: logout.Add(time.Hour)}, "")
+	if err != nil {
+		t.Fatal("RevokeTokens : " + err.Error())
+	}
+
+	cases := []struct {
+		name   string
+		issued time.Time
+		want   bool
+	}{
+		{"issued the second before", logout.Truncate(time.Second).Add(-time.Second), true},
+		{"issued by a login right after", logout.Truncate(time.Second), false},
+	}
+	for _, c := range cases {
+		if revoked, err := db.IsTokenRevoked(ctx, "user:1", "token", c.issued); err != nil || revoked != c.want {
+			t.Errorf("IsTokenRevoked %s : got %v, %v, want %v", c.name, revoked, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,641 @@
+package svcdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+/*************** Users ***************/
+func (s MemoryService) findUser(email string) (graph.Node, error) {
+	nodes := s.graph.findNodes("USER", func(node graph.Node) bool {
+		return node.Properties["Email"] == email
+	})
+	if len(nodes) == 0 {
+		return graph.Node{}, io.EOF
+	}
+	return nodes[0], nil
+}
+
+func (s MemoryService) CreateUser(_ context.Context, u User) (User, error) {
+	var user User
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.findUser(u.Email); err == nil {
+		return user, errors.New("User already exists")
+	}
+	password, err := hashPassword(u.Password)
+	if err != nil {
+		return user, err
+	}
+
+	node := s.graph.createNode("USER", map[string]interface{}{
+		"Email":    u.Email,
+		"Pseudo":   u.Pseudo,
+		"Password": password,
+	})
+	(&user).NodeToUser(node)
+	return user, nil
+}
+
+func (s MemoryService) SearchUsers(_ context.Context, query string, page Page) ([]SearchResponse, string, error) {
+	var response []SearchResponse
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	nodes, next, err := pageNodes(s.graph.findNodes("USER", pseudoContains(query)), page, false)
+	if err != nil {
+		return response, "", err
+	}
+	for _, node := range nodes {
+		var tmpUser User
+		var tmpResponse SearchResponse
+
+		(&tmpUser).NodeToUser(node)
+		tmpResponse.FromUser(tmpUser)
+		response = append(response, tmpResponse)
+	}
+	return response, next, nil
+}
+
+func pseudoContains(query string) func(graph.Node) bool {
+	return func(node graph.Node) bool {
+		pseudo, _ := node.Properties["Pseudo"].(string)
+		return strings.Contains(strings.ToLower(pseudo), strings.ToLower(query))
+	}
+}
+
+func (s MemoryService) UpdateUser(_ context.Context, new User) (User, error) {
+	var user User
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node, err := s.graph.node(new.ID, "USER")
+	if err != nil {
+		return user, err
+	}
+	(&user).NodeToUser(node)
+	email := user.Email
+	user.UpdateUser(new)
+
+	properties := map[string]interface{}{
+		"Verified":      user.Verified && user.Email == email,
+		"Email":         user.Email,
+		"Pseudo":        user.Pseudo,
+		"Birthdate":     formatTime(user.Birthdate),
+		"Firstname":     user.Firstname,
+		"Surname":       user.Surname,
+		"Number":        user.Number,
+		"Image":         user.Image,
+		"SuccessPoints": user.SuccessPoints,
+		"StripeID":      user.StripeID,
+	}
+	if len(new.Password) > 0 {
+		if properties["Password"], err = hashPassword(new.Password); err != nil {
+			return user, err
+		}
+	}
+	node = s.graph.setNode(user.ID, properties)
+	(&user).NodeToUser(node)
+	return user, nil
+}
+
+func (s MemoryService) GetUserByID(_ context.Context, userID int64) (User, error) {
+	var user User
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return user, err
+	}
+	(&user).NodeToUser(node)
+	return user, nil
+}
+
+func (s MemoryService) GetUser(_ context.Context, u User) (User, error) {
+	var user User
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	node, err := s.findUser(u.Email)
+	if err != nil {
+		checkUnknownPassword(u.Password)
+		return user, err
+	}
+	ok, upgrade := checkPassword(storedPassword(node), u.Password)
+	if !ok {
+		return user, InvalidPasswordErr
+	}
+	if upgrade {
+		s.upgradePassword(node.NodeIdentity, u.Password)
+	}
+	(&user).NodeToUser(node)
+	return user, nil
+}
+
+// upgradePassword hashes the plaintext password of the node id, see GetUser
+func (s MemoryService) upgradePassword(id int64, password string) {
+	if hash, err := hashPassword(password); err == nil {
+		s.graph.setNode(id, map[string]interface{}{"Password": hash})
+	}
+}
+
+func (s MemoryService) GetUserProfile(_ context.Context, userID int64) (Profile, error) {
+	var user Profile
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return user, err
+	}
+	(&user).NodeToProfile(node)
+	return user, nil
+}
+
+func (s MemoryService) GetUserPreferences(_ context.Context, userID int64) ([]Preference, error) {
+	var preferences []Preference
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, match := range s.graph.related(userID, memOut, "PREFER", "ESTABLISHMENT_TYPE") {
+		var tmpPreference Preference
+
+		(&tmpPreference).NodeToPreference(match.Node)
+		preferences = append(preferences, tmpPreference)
+	}
+	return preferences, nil
+}
+
+func (s MemoryService) GetUserSuccess(_ context.Context, userID int64) ([]Success, error) {
+	var success []Success
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, node := range s.graph.findNodes("SUCCESS", nil) {
+		var tmpSuccess Success
+
+		(&tmpSuccess).NodeToSuccess(node)
+		for _, match := range s.graph.related(node.NodeIdentity, memIn, "GOT", "USER") {
+			if match.Node.NodeIdentity == userID {
+				tmpSuccess.Active = true
+			}
+		}
+		success = append(success, tmpSuccess)
+	}
+	return success, nil
+}
+
+func (s MemoryService) UpdatePreference(_ context.Context, userID int64, preferences []string) ([]string, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	if _, err := s.graph.node(userID, "USER"); err != nil {
+		return preferences, nil
+	}
+
+	types := s.graph.findNodes("ESTABLISHMENT_TYPE", func(node graph.Node) bool {
+		for _, preference := range preferences {
+			if node.Properties["Name"] == preference {
+				return true
+			}
+		}
+		return false
+	})
+	for _, node := range types {
+		s.graph.createRelation(userID, node.NodeIdentity, "PREFER", nil)
+	}
+	return preferences, nil
+}
+
+func (s MemoryService) GetRecommendation(_ context.Context, userID int64, lat, long float64) ([]Recommendation, error) {
+	var signals []recommendationSignals
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(userID, "USER"); err != nil {
+		return nil, nil
+	}
+
+	preferred := make(map[int64]bool)
+	for _, match := range s.graph.related(userID, memOut, "PREFER", "ESTABLISHMENT_TYPE") {
+		preferred[match.Node.NodeIdentity] = true
+	}
+	friends := make(map[int64]bool)
+	for _, match := range s.graph.related(userID, memBoth, "KNOW", "USER") {
+		friends[match.Node.NodeIdentity] = true
+	}
+
+	for _, node := range s.graph.findNodes("ESTABLISHMENT", nil) {
+		var tmpSignals recommendationSignals
+
+		tmpSignals.Establishment, _ = s.getEstablishment(node.NodeIdentity)
+		for _, match := range s.graph.related(node.NodeIdentity, memOut, "IS", "ESTABLISHMENT_TYPE") {
+			tmpSignals.Establishment.Type, _ = match.Node.Properties["Name"].(string)
+			tmpSignals.Preferred = tmpSignals.Preferred || preferred[match.Node.NodeIdentity]
+		}
+		for _, rel := range s.graph.relationsBetween(userID, node.NodeIdentity, "RATE") {
+			if value, ok := rel.Properties["value"].(int64); ok && value > tmpSignals.OwnRate {
+				tmpSignals.OwnRate = value
+			}
+		}
+
+		joined := make(map[int64]bool)
+		for _, soiree := range s.graph.related(node.NodeIdentity, memOut, "SPAWNED", "SOIREE") {
+			for _, match := range s.graph.related(soiree.Node.NodeIdentity, memIn, "JOIN", "USER") {
+				if friends[match.Node.NodeIdentity] {
+					joined[match.Node.NodeIdentity] = true
+				}
+			}
+			for _, order := range s.graph.related(soiree.Node.NodeIdentity, memIn, "DURING", "ORDER") {
+				if len(s.graph.relationsBetween(order.Node.NodeIdentity, userID, "TO")) > 0 {
+					tmpSignals.Orders++
+				}
+			}
+		}
+		tmpSignals.Friends = int64(len(joined))
+
+		signals = append(signals, tmpSignals)
+	}
+	return rankRecommendations(signals, lat, long), nil
+}
+
+/*************** Friends ***************/
+func (s MemoryService) InviteFriend(_ context.Context, userID, friendID int64) (string, int64, int64, error) {
+	var p Profile
+	var successID int64
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	user, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if _, err = s.graph.node(friendID, "USER"); err != nil {
+		return "", 0, 0, err
+	}
+
+	if success := s.graph.related(userID, memBoth, "", "SUCCESS"); len(success) > 0 {
+		successID = success[0].Node.NodeIdentity
+	}
+	invitation := s.graph.createRelation(userID, friendID, "INVITE", map[string]interface{}{
+		"Date": formatTime(time.Now()),
+	})
+
+	(&p).NodeToProfile(user)
+	return p.Pseudo, invitation.RelIdentity, successID, nil
+}
+
+func (s MemoryService) GetUserFriends(_ context.Context, userID int64, page Page) ([]Profile, string, error) {
+	var friends []Profile
+	var nodes []graph.Node
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, match := range s.graph.related(userID, memBoth, "KNOW", "USER") {
+		nodes = append(nodes, match.Node)
+	}
+	nodes, next, err := pageNodes(nodes, page, false)
+	if err != nil {
+		return friends, "", err
+	}
+	for _, node := range nodes {
+		var tmpProfile Profile
+
+		(&tmpProfile).NodeToProfile(node)
+		friends = append(friends, tmpProfile)
+	}
+	return friends, next, nil
+}
+
+func (s MemoryService) GetUsersInvitations(_ context.Context, userID int64, page Page) ([]Invitation, string, error) {
+	var invitations []Invitation
+	var keys []int64
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	user, err := s.graph.node(userID, "USER")
+	if err != nil {
+		return invitations, "", nil
+	}
+
+	matches := s.graph.related(userID, memIn, "INVITE", "USER")
+	for _, match := range matches {
+		keys = append(keys, match.Rel.RelIdentity)
+	}
+	indexes, next, err := page.window(keys, false)
+	if err != nil {
+		return invitations, "", err
+	}
+	for _, i := range indexes {
+		var tmpInvitation Invitation
+
+		(&tmpInvitation).RelationToInvitation(matches[i].Node, matches[i].Rel, user)
+		invitations = append(invitations, tmpInvitation)
+	}
+	return invitations, next, nil
+}
+
+// userInvitation returns the INVITE relationship between two users, with the
+// invited user first as in (u:USER)<-[i:INVITE]-(f:USER)
+func (s MemoryService) userInvitation(invitationID int64) (graph.Node, graph.Node, graph.Relationship, error) {
+	invitation, err := s.graph.relation(invitationID, "INVITE")
+	if err != nil {
+		return graph.Node{}, graph.Node{}, invitation, err
+	}
+	user, err := s.graph.node(invitation.EndNodeIdentity, "USER")
+	if err != nil {
+		return graph.Node{}, graph.Node{}, invitation, err
+	}
+	friend, err := s.graph.node(invitation.StartNodeIdentity, "USER")
+	if err != nil {
+		return graph.Node{}, graph.Node{}, invitation, err
+	}
+	return user, friend, invitation, nil
+}
+
+func (s MemoryService) GetInvitation(_ context.Context, invitationID int64) (Invitation, error) {
+	var invitation Invitation
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	user, friend, relation, err := s.userInvitation(invitationID)
+	if err != nil {
+		return invitation, err
+	}
+	(&invitation).RelationToInvitation(friend, relation, user)
+	return invitation, nil
+}
+
+func (s MemoryService) InvitationAccept(_ context.Context, invitationID int64) (Profile, Profile, error) {
+	var user Profile
+	var friend Profile
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	userNode, friendNode, invitation, err := s.userInvitation(invitationID)
+	if err != nil {
+		return user, friend, err
+	}
+	s.graph.deleteRelation(invitation.RelIdentity)
+	s.graph.createRelation(userNode.NodeIdentity, friendNode.NodeIdentity, "KNOW", nil)
+
+	(&user).NodeToProfile(userNode)
+	(&friend).NodeToProfile(friendNode)
+	return user, friend, nil
+}
+
+func (s MemoryService) InvitationDecline(_ context.Context, invitationID int64) (Profile, Profile, error) {
+	var user Profile
+	var friend Profile
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	userNode, friendNode, invitation, err := s.userInvitation(invitationID)
+	if err != nil {
+		return user, friend, err
+	}
+	s.graph.deleteRelation(invitation.RelIdentity)
+
+	(&user).NodeToProfile(userNode)
+	(&friend).NodeToProfile(friendNode)
+	return user, friend, nil
+}
+
+func (s MemoryService) UsersConnected(_ context.Context, userID, friendID int64) (bool, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	if _, err := s.graph.node(friendID, "USER"); err != nil {
+		return false, nil
+	}
+	for _, rel := range s.graph.relationsBetween(userID, friendID, "") {
+		if rel.Type == "KNOW" || rel.Type == "INVITE" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s MemoryService) SearchFriends(_ context.Context, query string, userID int64) ([]SearchResponse, error) {
+	var response []SearchResponse
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	filter := pseudoContains(query)
+	for _, match := range s.graph.related(userID, memBoth, "KNOW", "USER") {
+		var tmpUser User
+		var tmpResponse SearchResponse
+
+		if !filter(match.Node) {
+			continue
+		}
+		(&tmpUser).NodeToUser(match.Node)
+		tmpResponse.FromUser(tmpUser)
+		response = append(response, tmpResponse)
+	}
+	return response, nil
+}
+
+/*************** Pro ***************/
+// proEstablishments mirrors OPTIONAL MATCH (u)-[:OWN]-(e:ESTABLISHMENT) RETURN ID(e)
+func (s MemoryService) proEstablishments(proID int64) []int64 {
+	var establishments []int64
+
+	for _, match := range s.graph.related(proID, memBoth, "OWN", "ESTABLISHMENT") {
+		establishments = append(establishments, match.Node.NodeIdentity)
+	}
+	return establishments
+}
+
+func (s MemoryService) getProByID(proID int64) (Pro, error) {
+	var pro Pro
+
+	node, err := s.graph.node(proID, "PRO")
+	if err != nil {
+		return pro, err
+	}
+	(&pro).NodeToPro(node)
+	pro.Establishments = s.proEstablishments(proID)
+	return pro, nil
+}
+
+func (s MemoryService) CreatePro(_ context.Context, u Pro) (Pro, error) {
+	var pro Pro
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	existing := s.graph.findNodes("PRO", func(node graph.Node) bool {
+		return node.Properties["Email"] == u.Email
+	})
+	if len(existing) > 0 {
+		return pro, errors.New("Pro already exists")
+	}
+	password, err := hashPassword(u.Password)
+	if err != nil {
+		return pro, err
+	}
+
+	node := s.graph.createNode("PRO", map[string]interface{}{
+		"Email":      u.Email,
+		"Pseudo":     u.Pseudo,
+		"Password":   password,
+		"Firstname":  u.Firstname,
+		"Surname":    u.Surname,
+		"Image":      u.Image,
+		"Number":     u.Number,
+		"StripeID":   u.StripeID,
+		"StripeSKey": u.StripeSKey,
+		"StripePKey": u.StripePKey,
+	})
+	(&pro).NodeToPro(node)
+	return pro, nil
+}
+
+func (s MemoryService) GetPro(_ context.Context, u Pro) (Pro, error) {
+	var pro Pro
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	nodes := s.graph.findNodes("PRO", func(node graph.Node) bool {
+		return node.Properties["Email"] == u.Email
+	})
+	if len(nodes) == 0 {
+		checkUnknownPassword(u.Password)
+		return pro, io.EOF
+	}
+	ok, upgrade := checkPassword(storedPassword(nodes[0]), u.Password)
+	if !ok {
+		return pro, InvalidPasswordErr
+	}
+	if upgrade {
+		s.upgradePassword(nodes[0].NodeIdentity, u.Password)
+	}
+	(&pro).NodeToPro(nodes[0])
+	pro.Establishments = s.proEstablishments(pro.ID)
+	return pro, nil
+}
+
+func (s MemoryService) GetProByIDStripe(_ context.Context, proID int64) (Pro, error) {
+	var pro Pro
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	node, err := s.graph.node(proID, "PRO")
+	if err != nil {
+		return pro, err
+	}
+	(&pro).NodeToProStripe(node)
+	pro.Establishments = s.proEstablishments(proID)
+	return pro, nil
+}
+
+func (s MemoryService) GetProByID(_ context.Context, proID int64) (Pro, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	return s.getProByID(proID)
+}
+
+func (s MemoryService) GetProBySoiree(_ context.Context, soireeID int64) (Pro, error) {
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, estab := range s.graph.related(soireeID, memIn, "SPAWNED", "ESTABLISHMENT") {
+		for _, pro := range s.graph.related(estab.Node.NodeIdentity, memIn, "OWN", "PRO") {
+			return s.getProByID(pro.Node.NodeIdentity)
+		}
+	}
+	return Pro{}, io.EOF
+}
+
+func (s MemoryService) UpdatePro(_ context.Context, new Pro) (Pro, error) {
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	pro, err := s.getProByID(new.ID)
+	if err != nil {
+		return pro, err
+	}
+	email := pro.Email
+	pro.UpdatePro(new)
+
+	properties := map[string]interface{}{
+		"Verified":  pro.Verified && pro.Email == email,
+		"Email":     pro.Email,
+		"Pseudo":    pro.Pseudo,
+		"Firstname": pro.Firstname,
+		"Surname":   pro.Surname,
+		"Number":    pro.Number,
+	}
+	if len(new.Password) > 0 {
+		if properties["Password"], err = hashPassword(new.Password); err != nil {
+			return pro, err
+		}
+	}
+	node := s.graph.setNode(pro.ID, properties)
+	(&pro).NodeToPro(node)
+	return pro, nil
+}
+
+func (s MemoryService) UpdateProStripeStatus(_ context.Context, stripeID string, status string) (Pro, error) {
+	var pro Pro
+
+	s.graph.mtx.Lock()
+	defer s.graph.mtx.Unlock()
+
+	nodes := s.graph.findNodes("PRO", func(node graph.Node) bool {
+		return node.Properties["StripeID"] == stripeID
+	})
+	if len(nodes) == 0 {
+		return pro, nil
+	}
+	node := s.graph.setNode(nodes[0].NodeIdentity, map[string]interface{}{
+		"StripeStatus": status,
+	})
+	(&pro).NodeToPro(node)
+	return pro, nil
+}
+
+func (s MemoryService) GetProEstablishments(_ context.Context, proID int64) ([]Establishment, error) {
+	var establishments []Establishment
+
+	s.graph.mtx.RLock()
+	defer s.graph.mtx.RUnlock()
+
+	for _, match := range s.graph.related(proID, memOut, "OWN", "ESTABLISHMENT") {
+		var tmpEstablishment Establishment
+
+		(&tmpEstablishment).NodeToEstablishment(match.Node)
+		if rate, ok := s.averageRate(match.Node.NodeIdentity); ok {
+			tmpEstablishment.Rate = rate
+		}
+		establishments = append(establishments, tmpEstablishment)
+	}
+	return establishments, nil
+}
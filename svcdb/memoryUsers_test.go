@@ -0,0 +1,29 @@
+package svcdb
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestMemoryGetUserPassword(t *testing.T) {
+	db := NewMemoryService()
+	if _, err := db.CreateUser(context.Background(), User{Email: "user@nightline.fr", Password: "password"}); err != nil {
+		t.Fatal("CreateUser : " + err.Error())
+	}
+
+	cases := []struct {
+		email    string
+		password string
+		want     error
+	}{
+		{"user@nightline.fr", "password", nil},
+		{"user@nightline.fr", "wrong", InvalidPasswordErr},
+		{"nobody@nightline.fr", "password", io.EOF},
+	}
+	for _, c := range cases {
+		if _, err := db.GetUser(context.Background(), User{Email: c.email, Password: c.password}); err != c.want {
+			t.Errorf("GetUser %s with %q : got %v, want %v", c.email, c.password, err, c.want)
+		}
+	}
+}
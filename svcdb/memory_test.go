@@ -0,0 +1,40 @@
+package svcdb
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryConnectedFriendsCountsJoinsAndLeaves(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemoryService()
+	soiree := db.SeedSoiree()
+	back := db.SeedUser("back@nightline.fr")
+	gone := db.SeedUser("gone@nightline.fr")
+
+	events := []struct {
+		user int64
+		join bool
+	}{
+		{back.ID, true}, {gone.ID, true}, {back.ID, false}, {gone.ID, false}, {back.ID, true},
+	}
+	for _, e := range events {
+		var err error
+		if e.join {
+			_, _, err = db.UserJoinSoiree(ctx, e.user, soiree.ID)
+		} else {
+			_, _, err = db.UserLeaveSoiree(ctx, e.user, soiree.ID)
+		}
+		if err != nil {
+			t.Fatal("UserJoinSoiree/UserLeaveSoiree : " + err.Error())
+		}
+	}
+
+	present, err := db.GetConnectedFriends(ctx, soiree.ID)
+	if err != nil {
+		t.Fatal("GetConnectedFriends : " + err.Error())
+	}
+	if len(present) != 1 || present[0].ID != back.ID {
+		t.Errorf("GetConnectedFriends : got %v, want only %d", present, back.ID)
+	}
+}
@@ -21,8 +21,6 @@ type Messages []Message
 func (i *Message) NodeToMessage(node graph.Node) {
 	i.ID = node.NodeIdentity
 
-	if date, ok := node.Properties["Date"].(string); ok {
-		i.Date, _ = time.Parse(timeForm, date)
-	}
+	i.Date = propertyTime("NodeToMessage", node.Properties, "Date")
 	i.Text = node.Properties["Text"].(string)
 }
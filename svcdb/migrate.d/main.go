@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"svcdb"
+)
+
+// One-shot rewrite of the times stored in the legacy display format and of the
+// amounts stored in euros, see svcdb.MigrateTimes and svcdb.MigrateMoney.
+// Run it once svcdb writes the new formats, it can safely be run again.
+// Conso prices are then read as price_cents by the API clients, the price
+// field they held in euros is gone.
+func main() {
+	defaultDB := svcdb.DefaultDriverConfig()
+	var (
+		dbAddr     = flag.String("db.addr", envString("NEO4J_ADDR", defaultDB.Addr), "Neo4j bolt host:port")
+		dbUser     = flag.String("db.user", envString("NEO4J_USER", defaultDB.Username), "Neo4j username")
+		dbPassword = flag.String("db.password", envString("NEO4J_PASSWORD", defaultDB.Password), "Neo4j password")
+		location   = flag.String("location", "Local", "Location the legacy zone abbreviations were written in, e.g. Europe/Paris")
+		dryRun     = flag.Bool("dry-run", false, "Only count the times to migrate")
+	)
+	flag.Parse()
+
+	/* Logger */
+	var logger log.Logger
+	{
+		logger = log.NewJSONLogger(os.Stdout)
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+	}
+
+	loc, err := time.LoadLocation(*location)
+	if err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+
+	config := defaultDB
+	config.Addr, config.Username, config.Password = *dbAddr, *dbUser, *dbPassword
+	config.PoolSize = 1
+	if err = svcdb.StartDriver(config); err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+	defer svcdb.CloseDriver()
+
+	reports, err := svcdb.MigrateTimes(context.Background(), loc, *dryRun)
+	if err == nil {
+		var moneyReports []svcdb.MigrationReport
+
+		moneyReports, err = svcdb.MigrateMoney(context.Background(), *dryRun)
+		reports = append(reports, moneyReports...)
+	}
+	for _, report := range reports {
+		logger.Log(
+			"property", report.Property,
+			"migrated", report.Migrated,
+			"skipped", report.Skipped,
+			"failed", report.Failed,
+			"dryRun", *dryRun,
+		)
+	}
+	if err != nil {
+		logger.Log("err", err)
+		svcdb.CloseDriver()
+		os.Exit(1)
+	}
+}
+
+func envString(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return def
+}
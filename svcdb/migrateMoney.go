@@ -0,0 +1,166 @@
+package svcdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+)
+
+// moneyProperties lists every amount written to the graph
+var moneyProperties = []storedProperty{
+	{Label: "CONSO", Key: "Price"},
+	{Label: "ORDER", Key: "Price"},
+	{Label: "TO", Key: "Price", Relation: true},
+	{Label: "FOR", Key: "Price", Relation: true},
+}
+
+// MigrateMoney rewrites every amount still stored in euros into int64 cents :
+// the float64 ones, and the int64 prices of the consos without Currency. Then
+// it snapshots the consos of the orders placed before FOR relations held their
+// name and price. Those orders get the conso as it is at migration time, the
+// closest there is to what was ordered.
+// Stored amounts are only read when dryRun is set.
+func MigrateMoney(ctx context.Context, dryRun bool) ([]MigrationReport, error) {
+	var reports []MigrationReport
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("MigrateMoney (WaitConnection) : " + err.Error())
+		return reports, err
+	}
+	defer CloseConnection(conn)
+
+	for _, property := range moneyProperties {
+		report, err := migrateMoneyProperty(conn, property, dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			return reports, err
+		}
+	}
+
+	report, err := migrateConsoSnapshots(conn, dryRun)
+	reports = append(reports, report)
+	return reports, err
+}
+
+func migrateMoneyProperty(conn bolt.Conn, property storedProperty, dryRun bool) (MigrationReport, error) {
+	var reqs []string
+	var argss []map[string]interface{}
+
+	report := MigrationReport{Property: property.Label + "." + property.Key}
+
+	// Labels and keys cannot be parameters, they only come from moneyProperties
+	match := `MATCH (n:` + property.Label + `)`
+	if property.Relation {
+		match = `MATCH ()-[n:` + property.Label + `]->()`
+	}
+
+	// Consos are the only amounts ever stored in euros, as float64 or int64 :
+	// the ones in cents are told apart by their Currency
+	priced := `false`
+	if property.Label == "CONSO" {
+		priced = `EXISTS(n.Currency)`
+	}
+	stmt, err := conn.PrepareNeo(match + ` WHERE EXISTS(n.` + property.Key + `) RETURN ID(n), n.` + property.Key + `, ` + priced)
+	if err != nil {
+		fmt.Println("MigrateMoney (PrepareNeo) : " + err.Error())
+		return report, err
+	}
+
+	rows, err := stmt.QueryNeo(nil)
+	if err != nil {
+		stmt.Close()
+		fmt.Println("MigrateMoney (QueryNeo) : " + err.Error())
+		return report, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		id := row[0].(int64)
+		cents, _ := row[2].(bool)
+		euros := property.Label == "CONSO" && !cents
+
+		switch row[1].(type) {
+		case int64:
+			if !euros {
+				report.Skipped++
+				break
+			}
+			reqs = append(reqs, match+` WHERE ID(n) = {id} SET n.`+property.Key+` = {value}, n.Currency = {currency} RETURN ID(n)`)
+			argss = append(argss, map[string]interface{}{
+				"id":       id,
+				"value":    row[1].(int64) * 100,
+				"currency": DefaultCurrency,
+			})
+		case float64:
+			set := `SET n.` + property.Key + ` = {value}`
+			if euros {
+				set += `, n.Currency = {currency}`
+			}
+			reqs = append(reqs, match+` WHERE ID(n) = {id} `+set+` RETURN ID(n)`)
+			argss = append(argss, map[string]interface{}{
+				"id":       id,
+				"value":    storedMoney(row[1]),
+				"currency": DefaultCurrency,
+			})
+		default:
+			fmt.Printf("MigrateMoney (%s) : %d : not an amount, got %v\n", report.Property, id, row[1])
+			report.Failed++
+		}
+		row, _, err = rows.NextNeo()
+	}
+	stmt.Close()
+	if err != nil && err != io.EOF {
+		fmt.Println("MigrateMoney (NextNeo) : " + err.Error())
+		return report, err
+	}
+
+	if !dryRun && len(reqs) > 0 {
+		err = Transaction(conn, func(conn bolt.Conn) error {
+			_, err := ExecBatch(conn, reqs, argss)
+			return err
+		})
+		if err != nil {
+			fmt.Println("MigrateMoney (ExecBatch) : " + err.Error())
+			return report, err
+		}
+	}
+	report.Migrated = len(reqs)
+	return report, nil
+}
+
+// migrateConsoSnapshots copies the current name and price of the conso on the
+// FOR relations missing them, once the prices are in cents
+func migrateConsoSnapshots(conn bolt.Conn, dryRun bool) (MigrationReport, error) {
+	report := MigrationReport{Property: "FOR.Snapshot"}
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (:ORDER)-[f:FOR]->(:CONSO)
+		RETURN SUM(CASE WHEN EXISTS(f.Price) AND EXISTS(f.Name) THEN 1 ELSE 0 END),
+		SUM(CASE WHEN EXISTS(f.Price) AND EXISTS(f.Name) THEN 0 ELSE 1 END)`, nil)
+	if err != nil {
+		fmt.Println("MigrateMoney (QueryNeoAll) : " + err.Error())
+		return report, err
+	}
+	if len(data) > 0 {
+		skipped, _ := data[0][0].(int64)
+		migrated, _ := data[0][1].(int64)
+		report.Skipped, report.Migrated = int(skipped), int(migrated)
+	}
+
+	if !dryRun && report.Migrated > 0 {
+		_, err = conn.ExecNeo(`
+			MATCH (:ORDER)-[f:FOR]->(c:CONSO)
+			WHERE NOT EXISTS(f.Price) OR NOT EXISTS(f.Name)
+			SET f.Price = c.Price, f.Name = c.Name, f.Currency = {currency}`, map[string]interface{}{
+			"currency": DefaultCurrency,
+		})
+		if err != nil {
+			fmt.Println("MigrateMoney (ExecNeo) : " + err.Error())
+			return report, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,138 @@
+package svcdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+)
+
+// storedProperty is a property either on the nodes labelled Label or on the
+// relations of type Label
+type storedProperty struct {
+	Label    string
+	Key      string
+	Relation bool
+}
+
+// timeProperties lists every time written to the graph
+var timeProperties = []storedProperty{
+	{Label: "SOIREE", Key: "Begin"},
+	{Label: "SOIREE", Key: "End"},
+	{Label: "USER", Key: "Birthdate"},
+	{Label: "MESSAGE", Key: "Date"},
+	{Label: "STEP", Key: "Date"},
+	{Label: "ORDER", Key: "Created"},
+	{Label: "JOIN", Key: "When", Relation: true},
+	{Label: "LEAVE", Key: "When", Relation: true},
+	{Label: "INVITE", Key: "Date", Relation: true},
+}
+
+// MigrationReport counts what a migration did with one property
+type MigrationReport struct {
+	Property string `json:"property"`
+	Migrated int    `json:"migrated"`
+	Skipped  int    `json:"skipped"` // already stored in the new form
+	Failed   int    `json:"failed"`  // not readable, left untouched
+}
+
+// MigrateTimes rewrites every time still stored in the legacy display format
+// into storeForm. The legacy format only kept a zone abbreviation, which is
+// resolved in loc ; an abbreviation unknown to loc is read as UTC.
+// Stored times are only read when dryRun is set.
+func MigrateTimes(ctx context.Context, loc *time.Location, dryRun bool) ([]MigrationReport, error) {
+	var reports []MigrationReport
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("MigrateTimes (WaitConnection) : " + err.Error())
+		return reports, err
+	}
+	defer CloseConnection(conn)
+
+	for _, property := range timeProperties {
+		report, err := migrateTimeProperty(conn, property, loc, dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			return reports, err
+		}
+	}
+	return reports, nil
+}
+
+func migrateTimeProperty(conn bolt.Conn, property storedProperty, loc *time.Location, dryRun bool) (MigrationReport, error) {
+	var reqs []string
+	var argss []map[string]interface{}
+
+	report := MigrationReport{Property: property.Label + "." + property.Key}
+
+	// Labels and keys cannot be parameters, they only come from timeProperties
+	match := `MATCH (n:` + property.Label + `)`
+	if property.Relation {
+		match = `MATCH ()-[n:` + property.Label + `]->()`
+	}
+
+	stmt, err := conn.PrepareNeo(match + ` WHERE EXISTS(n.` + property.Key + `) RETURN ID(n), n.` + property.Key)
+	if err != nil {
+		fmt.Println("MigrateTimes (PrepareNeo) : " + err.Error())
+		return report, err
+	}
+
+	rows, err := stmt.QueryNeo(nil)
+	if err != nil {
+		stmt.Close()
+		fmt.Println("MigrateTimes (QueryNeo) : " + err.Error())
+		return report, err
+	}
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		id := row[0].(int64)
+		value, _ := row[1].(string)
+
+		if migrated, ok := migrateTime(value, loc); !ok {
+			fmt.Printf("MigrateTimes (%s) : %d : %s, got %q\n", report.Property, id, TimeErr.Error(), value)
+			report.Failed++
+		} else if migrated == value {
+			report.Skipped++
+		} else {
+			reqs = append(reqs, match+` WHERE ID(n) = {id} SET n.`+property.Key+` = {value} RETURN ID(n)`)
+			argss = append(argss, map[string]interface{}{
+				"id":    id,
+				"value": migrated,
+			})
+		}
+		row, _, err = rows.NextNeo()
+	}
+	stmt.Close()
+	if err != nil && err != io.EOF {
+		fmt.Println("MigrateTimes (NextNeo) : " + err.Error())
+		return report, err
+	}
+
+	if !dryRun && len(reqs) > 0 {
+		err = Transaction(conn, func(conn bolt.Conn) error {
+			_, err := ExecBatch(conn, reqs, argss)
+			return err
+		})
+		if err != nil {
+			fmt.Println("MigrateTimes (ExecBatch) : " + err.Error())
+			return report, err
+		}
+	}
+	report.Migrated = len(reqs)
+	return report, nil
+}
+
+// migrateTime returns the storeForm of a stored time, be it legacy or not
+func migrateTime(value string, loc *time.Location) (string, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return formatTime(t), true
+	}
+	if t, err := time.ParseInLocation(legacyTimeForm, value, loc); err == nil {
+		return formatTime(t), true
+	}
+	return "", false
+}
@@ -0,0 +1,39 @@
+package svcdb
+
+import (
+	"math"
+)
+
+// DefaultCurrency is the currency of every price, as the ISO 4217 code Stripe
+// expects. Prices are stored as int64 minor units of it, i.e. cents.
+const DefaultCurrency = "eur"
+
+// storedMoney reads an amount of minor units returned by a request. Conso
+// prices used to be stored as float64 major units, the values not migrated
+// yet by MigrateMoney are converted on the fly.
+func storedMoney(value interface{}) int64 {
+	switch value.(type) {
+	case int64:
+		return value.(int64)
+	case float64:
+		return int64(math.Round(value.(float64) * 100))
+	}
+	return 0
+}
+
+// consoMoney reads the price of a CONSO node. The consos priced in cents have
+// a Currency, the ones priced before hold euros, as float64 or as int64.
+func consoMoney(properties map[string]interface{}) int64 {
+	if _, ok := properties["Currency"]; !ok {
+		if price, ok := properties["Price"].(int64); ok {
+			return price * 100
+		}
+	}
+	return propertyMoney(properties, "Price")
+}
+
+// propertyMoney reads the amount stored in a node or relation property, a
+// missing property being 0
+func propertyMoney(properties map[string]interface{}, key string) int64 {
+	return storedMoney(properties[key])
+}
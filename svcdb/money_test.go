@@ -0,0 +1,21 @@
+package svcdb
+
+import "testing"
+
+func TestConsoMoney(t *testing.T) {
+	cases := []struct {
+		name       string
+		properties map[string]interface{}
+		want       int64
+	}{
+		{"legacy float64 euros", map[string]interface{}{"Price": 4.5}, 450},
+		{"legacy int64 euros", map[string]interface{}{"Price": int64(5)}, 500},
+		{"cents", map[string]interface{}{"Price": int64(5), "Currency": DefaultCurrency}, 5},
+		{"no price", map[string]interface{}{}, 0},
+	}
+	for _, c := range cases {
+		if got := consoMoney(c.properties); got != c.want {
+			t.Errorf("consoMoney %s : got %d, want %d", c.name, got, c.want)
+		}
+	}
+}
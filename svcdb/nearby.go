@@ -0,0 +1,162 @@
+package svcdb
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	earthRadius     = 6371000 // meters
+	MaxNearbyRadius = 50000   // meters
+)
+
+var InvalidLocationErr = errors.New("Invalid location or radius")
+
+// NearbyFilters model, zero values filter nothing
+type NearbyFilters struct {
+	Types       []string `json:"types,omitempty"`        // establishment types, any of them
+	MinRate     float64  `json:"min_rate,omitempty"`     // minimal average rate
+	RunningOnly bool     `json:"running_only,omitempty"` // only establishments with a running soiree
+}
+
+// NearbyResult model, an establishment, its running soirees and its distance
+// in meters to the searched location
+type NearbyResult struct {
+	Establishment Establishment `json:"establishment"`
+	Soirees       []Soiree      `json:"soirees,omitempty"`
+	Distance      float64       `json:"distance"`
+}
+
+// NearbyResults NearbyResult array
+type NearbyResults []NearbyResult
+
+// Distance returns the great-circle distance in meters between two points
+func Distance(lat1, long1, lat2, long2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (long2 - long1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// boundingBox returns the lat / long ranges holding every point within radius
+// of the center, so that the database only checks a few candidates
+func boundingBox(lat, long, radius float64) (minLat, maxLat, minLong, maxLong float64) {
+	dLat := radius / earthRadius * 180 / math.Pi
+	minLat, maxLat = lat-dLat, lat+dLat
+
+	cos := math.Cos(lat * math.Pi / 180)
+	if maxLat >= 90 || minLat <= -90 || cos < 1e-9 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180
+	}
+	dLong := dLat / cos
+	if long-dLong < -180 || long+dLong > 180 {
+		return minLat, maxLat, -180, 180
+	}
+	return minLat, maxLat, long - dLong, long + dLong
+}
+
+func validLocation(lat, long, radius float64) bool {
+	return lat >= -90 && lat <= 90 && long >= -180 && long <= 180 &&
+		radius > 0 && radius <= MaxNearbyRadius
+}
+
+// Running tells if the soiree is happening at now
+func (s Soiree) Running(now time.Time) bool {
+	return !s.Begin.After(now) && s.End.After(now)
+}
+
+// keep applies the filters that cannot be checked before knowing the rate
+// and the running soirees of an establishment
+func (f NearbyFilters) keep(result NearbyResult) bool {
+	if f.MinRate > 0 && result.Establishment.Rate < f.MinRate {
+		return false
+	}
+	if f.RunningOnly && len(result.Soirees) == 0 {
+		return false
+	}
+	return true
+}
+
+func (f NearbyFilters) hasType(estabType string) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == estabType {
+			return true
+		}
+	}
+	return false
+}
+
+// sortNearby orders results by distance, closest first
+func sortNearby(results []NearbyResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Establishment.ID < results[j].Establishment.ID
+	})
+}
+
+// NearbyFromRequest reads the lat, long, radius, type, min_rate and running
+// query parameters
+func NearbyFromRequest(r *http.Request) (float64, float64, float64, NearbyFilters, error) {
+	var filters NearbyFilters
+	var values [3]float64
+
+	query := r.URL.Query()
+	for i, name := range []string{"lat", "long", "radius"} {
+		value, err := strconv.ParseFloat(query.Get(name), 64)
+		if err != nil {
+			return 0, 0, 0, filters, InvalidLocationErr
+		}
+		values[i] = value
+	}
+	if !validLocation(values[0], values[1], values[2]) {
+		return 0, 0, 0, filters, InvalidLocationErr
+	}
+
+	filters.Types = query["type"]
+	if rate := query.Get("min_rate"); len(rate) > 0 {
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return 0, 0, 0, filters, errors.New("Invalid min_rate")
+		}
+		filters.MinRate = value
+	}
+	if running := query.Get("running"); len(running) > 0 {
+		value, err := strconv.ParseBool(running)
+		if err != nil {
+			return 0, 0, 0, filters, errors.New("Invalid running")
+		}
+		filters.RunningOnly = value
+	}
+	return values[0], values[1], values[2], filters, nil
+}
+
+// EncodeNearbyToRequest sets the query parameters read by NearbyFromRequest
+func EncodeNearbyToRequest(r *http.Request, lat, long, radius float64, filters NearbyFilters) {
+	query := r.URL.Query()
+	query.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	query.Set("long", strconv.FormatFloat(long, 'f', -1, 64))
+	query.Set("radius", strconv.FormatFloat(radius, 'f', -1, 64))
+	for _, t := range filters.Types {
+		query.Add("type", t)
+	}
+	if filters.MinRate > 0 {
+		query.Set("min_rate", strconv.FormatFloat(filters.MinRate, 'f', -1, 64))
+	}
+	if filters.RunningOnly {
+		query.Set("running", "true")
+	}
+	r.URL.RawQuery = query.Encode()
+}
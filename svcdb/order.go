@@ -19,10 +19,12 @@ type UserOrder struct {
 // UserOrder array
 type UserOrders []UserOrder
 
-// ConsoOrder model
+// ConsoOrder model, Conso holding the name and unit price of the conso when
+// the order was placed
 type ConsoOrder struct {
-	Conso  Conso `json:"conso"`
-	Amount int64 `json:"amount"`
+	Conso    Conso  `json:"conso"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
 }
 
 // ConsoOrder array
@@ -34,22 +36,15 @@ type StepOrder struct {
 	Name   string    `json:"name"`
 	Date   time.Time `json:"date"`
 	Result string    `json:"result"`
+	By     int64     `json:"by,omitempty"`
 }
 
 // StepOrder map
 type StepOrders []StepOrder
 
-var nextAllowedSteps = map[string]string{
-	"Issued":      "Confirmed",
-	"Confirmed":   "Verified",
-	"Verified":    "Ready",
-	"Ready":       "Deliverpaid",
-	"Deliverpaid": "Completed",
-	"Completed":   "",
-}
-
+// getNextAllowedStep returns the first step of OrderWorkflow not started yet,
+// once every previous one succeeded
 func getNextAllowedStep(order Order) (string, error) {
-	next := "Issued"
 	stepMap := make(map[string]StepOrder)
 
 	for i := range order.Steps {
@@ -59,23 +54,17 @@ func getNextAllowedStep(order Order) (string, error) {
 		return "", nil // No cont if order is finished
 	}
 
-	for len(next) > 0 {
-		nextnext := nextAllowedSteps[next]
-		if step, ok := stepMap[next]; ok {
-			if len(step.Result) == 0 {
-				return "", nil // No cont if step hasn't ended
-			} else if step.Result == "false" {
-				return "", nil // No cont if order has been stopped
-			}
-			_, ok := stepMap[nextnext]
-			if len(nextnext) > 0 && !ok && len(step.Result) > 0 {
-				return nextnext, nil // Success, found step finished and next one not started
-			}
-			// if this step done and nextstep present, continue
-			next = nextnext
+	for _, next := range OrderWorkflow {
+		step, ok := stepMap[next.Name]
+		if !ok {
+			return next.Name, nil // Success, found the steps before finished and this one not started
+		} else if len(step.Result) == 0 {
+			return "", nil // No cont if step hasn't ended
+		} else if step.Result == "false" {
+			return "", nil // No cont if order has been stopped
 		}
 	}
-	return next, nil
+	return "", nil
 }
 
 func (so *StepOrder) MarshalJSON() ([]byte, error) {
@@ -85,7 +74,7 @@ func (so *StepOrder) MarshalJSON() ([]byte, error) {
 		Date string `json:"date"`
 	}{
 		Alias: (*Alias)(so),
-		Date:  so.Date.Format(timeForm),
+		Date:  jsonTime(so.Date),
 	})
 }
 
@@ -100,16 +89,11 @@ func (so *StepOrder) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	if len(aux.Date) > 0 {
-		date, err := time.Parse(timeForm, aux.Date)
-		if err != nil {
-			date, err = time.Parse(timeFormFucked, aux.Date)
-			if err != nil {
-				return err
-			}
-		}
-		so.Date = date
+	date, err := parseJSONTime(aux.Date)
+	if err != nil {
+		return err
 	}
+	so.Date = date
 	return nil
 }
 
@@ -122,6 +106,11 @@ type Order struct {
 	Users  UserOrders  `json:"users"`
 	Consos ConsoOrders `json:"consos"`
 	Steps  StepOrders  `json:"steps"`
+	// Reconciliation lists what the ledger disagrees on, empty when it matches
+	Reconciliation string `json:"reconciliation,omitempty"`
+	// CancelRequest is the user waiting for the pro to cancel the order, 0 when
+	// none is
+	CancelRequest int64 `json:"cancel_request,omitempty"`
 }
 
 // Orders Order array
@@ -129,11 +118,13 @@ type Orders []Order
 
 func (o *Order) NodeToOrder(node graph.Node) {
 	o.ID = node.NodeIdentity
-	o.Price = node.Properties["Price"].(int64)
+	o.Price = propertyMoney(node.Properties, "Price")
 
 	if node.Properties["Done"] != nil {
 		o.Done = node.Properties["Done"].(string)
 	}
+	o.Reconciliation, _ = node.Properties["Reconciliation"].(string)
+	o.CancelRequest, _ = node.Properties["CancelRequest"].(int64)
 }
 
 func (o *Order) MarshalJSON() ([]byte, error) {
@@ -171,7 +162,7 @@ func (o *Order) RelationAddUser(orderNode graph.Node, relation graph.Relationshi
 	var price int64
 
 	user.NodeToUser(userNode)
-	price = relation.Properties["Price"].(int64)
+	price = propertyMoney(relation.Properties, "Price")
 	reference, _ = relation.Properties["Reference"].(string)
 	approved = relation.Properties["Approved"].(string)
 
@@ -184,15 +175,28 @@ func (o *Order) RelationAddUser(orderNode graph.Node, relation graph.Relationshi
 	})
 }
 
+// RelationAddConso reads the conso as snapshotted on the FOR relation, orders
+// placed before the snapshots falling back on the current conso
 func (o *Order) RelationAddConso(orderNode graph.Node, relation graph.Relationship, consoNode graph.Node) {
 	var conso Conso
 	var amount int64
 
 	conso.NodeToConso(consoNode)
 	amount = relation.Properties["Amount"].(int64)
+	if name, ok := relation.Properties["Name"].(string); ok {
+		conso.Name = name
+	}
+	if _, ok := relation.Properties["Price"]; ok {
+		conso.Price = propertyMoney(relation.Properties, "Price")
+	}
+	currency, _ := relation.Properties["Currency"].(string)
+	if len(currency) == 0 {
+		currency = DefaultCurrency
+	}
 	o.Consos = append(o.Consos, ConsoOrder{
-		Conso:  conso,
-		Amount: amount,
+		Conso:    conso,
+		Amount:   amount,
+		Currency: currency,
 	})
 }
 
@@ -203,13 +207,15 @@ func (o *Order) RelationAddStep(orderNode graph.Node, relation graph.Relationshi
 
 	id = stepNode.NodeIdentity
 	name = stepNode.Properties["Name"].(string)
-	date, _ = time.Parse(timeForm, stepNode.Properties["Date"].(string))
+	date = propertyTime("RelationAddStep", stepNode.Properties, "Date")
 	result, _ = stepNode.Properties["Result"].(string)
+	by, _ := stepNode.Properties["By"].(int64)
 
 	o.Steps = append(o.Steps, StepOrder{
 		ID:     id,
 		Name:   name,
 		Date:   date,
 		Result: result,
+		By:     by,
 	})
 }
@@ -0,0 +1,156 @@
+package svcdb
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// Page model, a zero Limit returns every result
+type Page struct {
+	Limit  int64  `json:"limit,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+const (
+	DefaultPageLimit int64 = 50
+	MaxPageLimit     int64 = 200
+)
+
+var InvalidCursorErr = errors.New("Invalid page cursor")
+
+// EncodeCursor hides the node ID a page stops at behind an opaque string
+func EncodeCursor(key int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(key, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, InvalidCursorErr
+	}
+	key, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, InvalidCursorErr
+	}
+	return key, nil
+}
+
+// PageFromRequest reads the limit and cursor query parameters
+func PageFromRequest(r *http.Request) (Page, error) {
+	var page Page
+
+	query := r.URL.Query()
+	if limit := query.Get("limit"); len(limit) > 0 {
+		value, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || value < 0 {
+			return page, errors.New("Invalid page limit")
+		}
+		page.Limit = value
+	}
+	page.Cursor = query.Get("cursor")
+	return page, nil
+}
+
+// EncodeToRequest sets the limit and cursor query parameters
+func (p Page) EncodeToRequest(r *http.Request) {
+	query := r.URL.Query()
+	if p.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(p.Limit, 10))
+	}
+	if len(p.Cursor) > 0 {
+		query.Set("cursor", p.Cursor)
+	}
+	r.URL.RawQuery = query.Encode()
+}
+
+// Bounded applies the default limit to pages coming from the public API
+func (p Page) Bounded() Page {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	} else if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	return p
+}
+
+// keyset returns the WHERE condition on key and the ORDER BY / LIMIT clause of
+// a Cypher request, one extra row being fetched to know if a next page exists
+func (p Page) keyset(key string, desc bool, args map[string]interface{}) (string, string, error) {
+	var where, tail string
+
+	after := int64(-1)
+	if desc {
+		after = math.MaxInt64
+	}
+	if len(p.Cursor) > 0 {
+		value, err := decodeCursor(p.Cursor)
+		if err != nil {
+			return where, tail, err
+		}
+		after = value
+	}
+	args["pagecursor"] = after
+
+	if desc {
+		where = key + ` < {pagecursor}`
+		tail = ` ORDER BY ` + key + ` DESC`
+	} else {
+		where = key + ` > {pagecursor}`
+		tail = ` ORDER BY ` + key
+	}
+	if p.Limit > 0 {
+		tail += ` LIMIT {pagelimit}`
+		args["pagelimit"] = p.Limit + 1
+	}
+	return where, tail, nil
+}
+
+// trim returns how many of the fetched rows belong to the page and the cursor
+// of the next one, empty on the last page
+func (p Page) trim(keys []int64) (int, string) {
+	if p.Limit <= 0 || int64(len(keys)) <= p.Limit {
+		return len(keys), ""
+	}
+	return int(p.Limit), EncodeCursor(keys[p.Limit-1])
+}
+
+// window is keyset + trim for results already in memory : it returns the
+// indexes of keys belonging to the page, in page order
+func (p Page) window(keys []int64, desc bool) ([]int, string, error) {
+	var indexes []int
+
+	after := int64(-1)
+	if desc {
+		after = math.MaxInt64
+	}
+	if len(p.Cursor) > 0 {
+		value, err := decodeCursor(p.Cursor)
+		if err != nil {
+			return indexes, "", err
+		}
+		after = value
+	}
+
+	for i, key := range keys {
+		if (!desc && key > after) || (desc && key < after) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		if desc {
+			return keys[indexes[i]] > keys[indexes[j]]
+		}
+		return keys[indexes[i]] < keys[indexes[j]]
+	})
+
+	sorted := make([]int64, len(indexes))
+	for i := range indexes {
+		sorted[i] = keys[indexes[i]]
+	}
+	count, next := p.trim(sorted)
+	return indexes[:count], next, nil
+}
@@ -0,0 +1,83 @@
+package svcdb
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// InvalidPasswordErr is returned by GetUser and GetPro when the password
+// doesn't match the stored one
+var InvalidPasswordErr = errors.New("Invalid password")
+
+// hashPassword hashes password with bcrypt, the only form stored in neo4j
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// isHashedPassword tells whether stored is a bcrypt hash. The passwords stored
+// before hashing are still in plaintext until their owner logs in.
+func isHashedPassword(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") ||
+		strings.HasPrefix(stored, "$2y$")
+}
+
+// checkPassword compares password with the stored one in constant time.
+// upgrade is set when a plaintext stored password matched and must be hashed.
+func checkPassword(stored, password string) (ok bool, upgrade bool) {
+	if isHashedPassword(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil, false
+	}
+	ok = len(stored) > 0 && subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	return ok, ok
+}
+
+// dummyPassword is the hash checkUnknownPassword compares against
+var dummyPassword, _ = hashPassword("nightline")
+
+// checkUnknownPassword spends the time of a bcrypt compare when no account has
+// the email asked for, so that the login time doesn't tell which ones exist
+func checkUnknownPassword(password string) {
+	bcrypt.CompareHashAndPassword([]byte(dummyPassword), []byte(password))
+}
+
+// storedPassword reads the password of a USER or PRO node. It never leaves
+// svcdb : NodeToUser and NodeToPro leave the Password field empty.
+func storedPassword(node graph.Node) string {
+	password, _ := node.Properties["Password"].(string)
+	return password
+}
+
+// upgradePassword replaces the plaintext password of the USER or PRO node id
+// by its hash. Failures are only logged, the next login tries again.
+func upgradePassword(ctx context.Context, label string, id int64, password string) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		fmt.Println("upgradePassword (hashPassword) : " + err.Error())
+		return
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("upgradePassword (WaitConnection) : " + err.Error())
+		return
+	}
+	defer CloseConnection(conn)
+
+	_, err = conn.ExecNeo(`MATCH (n:`+label+`) WHERE ID(n) = {id} SET n.Password = {password}`, map[string]interface{}{
+		"id":       id,
+		"password": hash,
+	})
+	if err != nil {
+		fmt.Println("upgradePassword (ExecNeo) : " + err.Error())
+	}
+}
@@ -9,7 +9,7 @@ type Pro struct {
 	ID             int64   `json:"id"`
 	Email          string  `json:"email"`
 	Pseudo         string  `json:"pseudo"`
-	Password       string  `json:"password"`
+	Password       string  `json:"password,omitempty"` // only sent to svcdb, never returned
 	Firstname      string  `json:"firstname,omitempty"`
 	Surname        string  `json:"surname,omitempty"`
 	Number         string  `json:"number,omitempty"`
@@ -17,7 +17,9 @@ type Pro struct {
 	StripeID	   string  `json:"stripeid"`
 	StripeSKey	   string  `json:"stripeskey"`
 	StripePKey	   string  `json:"stripepkey"`
+	StripeStatus   string  `json:"stripestatus,omitempty"`
 	Establishments []int64 `json:"establishments"`
+	Verified       bool    `json:"verified"` // set by svcdb only, once the email is verified
 }
 
 // Pros Pro array
@@ -27,7 +29,8 @@ func (u *Pro) NodeToPro(node graph.Node) {
 	u.ID = node.NodeIdentity
 	u.Email = node.Properties["Email"].(string)
 	u.Pseudo = node.Properties["Pseudo"].(string)
-	u.Password = node.Properties["Password"].(string)
+	u.Password = "" // the stored hash never leaves svcdb
+	u.Verified, _ = node.Properties["Verified"].(bool)
 
 	if node.Properties["Firstname"] != nil {
 		u.Firstname = node.Properties["Firstname"].(string)
@@ -41,6 +44,9 @@ func (u *Pro) NodeToPro(node graph.Node) {
 	if node.Properties["Image"] != nil {
 		u.Image = node.Properties["Image"].(string)
 	}
+	if node.Properties["StripeStatus"] != nil {
+		u.StripeStatus = node.Properties["StripeStatus"].(string)
+	}
 }
 
 /* USE WITH CAUTION, ALWAYS ENCRYPTED OR INTERNALLY, sensitive data inside */
@@ -48,7 +54,8 @@ func (u *Pro) NodeToProStripe(node graph.Node) {
 	u.ID = node.NodeIdentity
 	u.Email = node.Properties["Email"].(string)
 	u.Pseudo = node.Properties["Pseudo"].(string)
-	u.Password = node.Properties["Password"].(string)
+	u.Password = "" // the stored hash never leaves svcdb
+	u.Verified, _ = node.Properties["Verified"].(bool)
 
 	u.StripeID = node.Properties["StripeID"].(string)
 	u.StripeSKey = node.Properties["StripeSKey"].(string)
@@ -66,6 +73,15 @@ func (u *Pro) NodeToProStripe(node graph.Node) {
 	if node.Properties["Image"] != nil {
 		u.Image = node.Properties["Image"].(string)
 	}
+	if node.Properties["StripeStatus"] != nil {
+		u.StripeStatus = node.Properties["StripeStatus"].(string)
+	}
+}
+
+// Redacted returns a copy of u without its password, safe to log
+func (u Pro) Redacted() Pro {
+	u.Password = ""
+	return u
 }
 
 func (u *Pro) UpdatePro(new Pro) {
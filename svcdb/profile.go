@@ -52,9 +52,7 @@ func (u *Profile) NodeToProfile(node graph.Node) {
 	// if node.Properties["ConnectedTo"] != "" {
 	// 	u.ConnectedTo = node.Properties["ConnectedTo"].(string)
 	// }
-	if node.Properties["Birthdate"] != nil {
-		u.Birthdate, _ = time.Parse(timeForm, node.Properties["Birthdate"].(string))
-	}
+	u.Birthdate = propertyTime("NodeToProfile", node.Properties, "Birthdate")
 	if node.Properties["StripeID"] != nil {
 		u.StripeID = node.Properties["StripeID"].(string)
 	}
@@ -68,7 +66,7 @@ func (p *Profile) MarshalJSON() ([]byte, error) {
 		Birthdate string `json:"birthdate"`
 	}{
 		Alias:     (*Alias)(p),
-		Birthdate: p.Birthdate.Format(timeForm),
+		Birthdate: jsonTime(p.Birthdate),
 	})
 }
 
@@ -85,6 +83,10 @@ func (p *Profile) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	p.Birthdate, _ = time.Parse(timeForm, aux.Birthdate)
+	birthdate, err := parseJSONTime(aux.Birthdate)
+	if err != nil {
+		return err
+	}
+	p.Birthdate = birthdate
 	return nil
 }
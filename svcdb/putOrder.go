@@ -12,6 +12,8 @@ import (
 	"errors"
 	"time"
 
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -29,7 +31,9 @@ func stepDone(order Order, step StepOrder, flag bool) (string, map[string]interf
 
 	req := `MATCH (o:ORDER)-[:DONE]->(st:STEP)
             WHERE ID(o) = {oid} AND ID(st) = {stid}
-            SET st.Result = {stresult}`
+            AND (NOT EXISTS(st.Result) OR st.Result = "")
+            SET st.Result = {stresult}
+            RETURN ID(st)`
 	args["oid"] = order.ID
 	args["stid"] = step.ID
 	args["stresult"] = strconv.FormatBool(flag)
@@ -40,60 +44,32 @@ func stepOpenNext(order Order, step string) (string, map[string]interface{}, err
 	args := make(map[string]interface{})
 	req := `MATCH (o:ORDER) WHERE ID(o) = {oid}
                 CREATE (st:STEP {Name: {stname}, Date: {stdate}}),
-                (o)-[:DONE]->(st)`
+                (o)-[:DONE]->(st)
+                RETURN ID(st)`
 	args["oid"] = order.ID
 	args["stname"] = step
-	args["stdate"] = time.Now().Format(timeForm)
+	args["stdate"] = formatTime(time.Now())
 
 	return req, args, nil
 }
 
 /*** Specific ***/
+// Requests returned by a specificStepHandler run in the same transaction as
+// the step transition and must RETURN at least one row, see ExecBatch
 type specificStepHandler func(Order, bool) (bool, []string, []map[string]interface{}, error)
-var specificStepHandlerMap = map[string]map[string]specificStepHandler{
-	"Issued": {  "Condition": stepConditionIssued },
-	"Confirmed": { "Condition": stepConditionConfirmed },
-	"Verified": { "Condition": stepConditionVerified },
-	"Ready": { "Condition": stepConditionReady },
-	"Deliverpaid": { "Condition": stepConditionDeliverpaid },
-	"Completed": { "Condition": stepConditionCompleted },
-}
-
-/*** Condition ***/
-func stepConditionIssued(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
-}
-
-func stepConditionConfirmed(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
-}
-
-func stepConditionVerified(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
-}
-
-func stepConditionReady(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
-}
 
-func stepConditionDeliverpaid(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
-}
+// Conditions of the steps of OrderWorkflow needing more than being open, the
+// others are always validated
+var specificStepHandlerMap = map[string]map[string]specificStepHandler{}
 
-func stepConditionCompleted(order Order, flag bool) (bool, []string, []map[string]interface{}, error) {
-	var reqs []string
-	var argss []map[string]interface{}
-	return true, reqs, argss, nil
+func stepCondition(order Order, step StepOrder, flag bool) (bool, []string, []map[string]interface{}, error) {
+	if _, ok := OrderWorkflow.Step(step.Name); !ok {
+		return false, nil, nil, errors.New("Internal Error : step.Name not in OrderWorkflow")
+	}
+	if specificStepHandlerFn, ok := specificStepHandlerMap[step.Name]; ok {
+		return specificStepHandlerFn["Condition"](order, flag)
+	}
+	return true, nil, nil, nil
 }
 
 /*** Handler ***/
@@ -102,17 +78,13 @@ func stepHandler(order Order, step StepOrder, flag bool) ([]string, []map[string
 	var finalArgss, argss []map[string]interface{}
 
 	/* Handle specific */
-	specificStepHandlerFn, ok := specificStepHandlerMap[step.Name]
-	if !ok {
-		return finalReqs, finalArgss, errors.New("Internal Error : step.Name not in StepHandler")
-	}
-	validated, reqs, argss, err := specificStepHandlerFn["Condition"](order, flag)
+	validated, reqs, argss, err := stepCondition(order, step, flag)
 	if err != nil {
 		return finalReqs, finalArgss, err
 	}
 	if validated == true {
-		copy(finalReqs, reqs)
-		copy(finalArgss, argss)
+		finalReqs = append(finalReqs, reqs...)
+		finalArgss = append(finalArgss, argss...)
 
 		/* Mark step result */
 		req, args, err := stepDone(order, step, flag)
@@ -145,7 +117,7 @@ func stepHandler(order Order, step StepOrder, flag bool) ([]string, []map[string
 				finalArgss = append(finalArgss, args)
 			} else {
 				/* Set node as done */
-				finalReqs = append(finalReqs, `MATCH (o:ORDER) WHERE ID(o) = {oid} SET o.Done = {odone}`)
+				finalReqs = append(finalReqs, `MATCH (o:ORDER) WHERE ID(o) = {oid} SET o.Done = {odone} RETURN ID(o)`)
 				finalArgss = append(finalArgss, map[string]interface{}{
 					"oid": order.ID,
 					"odone": "true",
@@ -153,7 +125,7 @@ func stepHandler(order Order, step StepOrder, flag bool) ([]string, []map[string
 			}
 		} else {
 			/* Set node as done */
-			finalReqs = append(finalReqs, `MATCH (o:ORDER) WHERE ID(o) = {oid} SET o.Done = {odone}`)
+			finalReqs = append(finalReqs, `MATCH (o:ORDER) WHERE ID(o) = {oid} SET o.Done = {odone} RETURN ID(o)`)
 			finalArgss = append(finalArgss, map[string]interface{}{
 				"oid": order.ID,
 				"odone": "false",
@@ -215,26 +187,22 @@ func (s Service) PutOrder(ctx context.Context, orderID int64, step string, flag
 
 	if len(reqs) > 0 && len(argss) > 0 {
 		/* Conn */
-		conn, err := WaitConnection(5)
+		conn, err := WaitConnection(ctx, 5)
 		if err != nil {
 			fmt.Println("PutOrder (WaitConnection) : " + err.Error())
 			return order, err
 		}
 		defer CloseConnection(conn)
 
-		/* Pipeline changes */
-		pipeline, err := conn.PreparePipeline(reqs...)
-		if err != nil {
-			fmt.Println("PutOrder (PreparePipeline) : " + err.Error())
-			return order, err
-		}
-
-		_, err = pipeline.ExecPipeline(argss...)
+		/* Apply changes atomically */
+		err = Transaction(conn, func(conn bolt.Conn) error {
+			_, err := ExecBatch(conn, reqs, argss)
+			return err
+		})
 		if err != nil {
-			fmt.Println("PutOrder (ExecPipeline) : " + err.Error())
+			fmt.Println("PutOrder (Transaction) : " + err.Error())
 			return order, err
 		}
-		pipeline.Close()
 
 		/* Get updated order */
 		order, err = s.GetOrder(ctx, order.ID)
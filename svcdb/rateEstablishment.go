@@ -24,7 +24,7 @@ import (
 func (s Service) RateEstablishment(ctx context.Context, estabID, userID, rate int64) (Establishment, error) {
 	var estab Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("RateEstablishment (WaitConnection) : " + err.Error())
 		return estab, err
@@ -0,0 +1,155 @@
+package svcdb
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const RecommendationLimit = 10
+
+/* Weights of each signal in a recommendation score */
+const (
+	preferenceWeight = 3.0 // establishment type among the user PREFER
+	friendWeight     = 1.5 // per friend who joined one of its soirees
+	ownRateWeight    = 1.0 // per point above (or below) an average rate the user gave
+	orderWeight      = 1.0 // per order of the user in one of its soirees
+	rateWeight       = 2.0 // average rate, out of 5
+	distanceWeight   = 3.0 // full when next door, none at MaxNearbyRadius
+
+	maxFriends = 5 // friends / orders above this count do not weigh more
+	maxOrders  = 5
+)
+
+// Recommendation model, the score is only meaningful to sort recommendations
+type Recommendation struct {
+	Establishment Establishment `json:"establishment"`
+	Score         float64       `json:"score"`
+	Reason        string        `json:"reason"`
+	Distance      float64       `json:"distance,omitempty"` // meters, when a location was given
+}
+
+// Recommendations Recommendation array
+type Recommendations []Recommendation
+
+// recommendationSignals is what is known about an establishment for a user
+type recommendationSignals struct {
+	Establishment Establishment
+	Preferred     bool
+	Friends       int64
+	OwnRate       int64 // 0 when the user never rated it
+	Orders        int64
+}
+
+// score combines the signals, the reason being the one weighing the most
+func (sig recommendationSignals) score(lat, long float64, located bool) Recommendation {
+	var reco Recommendation
+	var best float64
+
+	reco.Establishment = sig.Establishment
+	add := func(value float64, reason string) {
+		reco.Score += value
+		if value > best {
+			best = value
+			reco.Reason = reason
+		}
+	}
+
+	if sig.Establishment.Rate > 0 {
+		add(rateWeight*sig.Establishment.Rate/5, fmt.Sprintf("Rated %.1f/5", sig.Establishment.Rate))
+	}
+	if sig.Preferred {
+		add(preferenceWeight, "You like "+sig.Establishment.Type)
+	}
+	if sig.Friends > 0 {
+		friends := sig.Friends
+		if friends > maxFriends {
+			friends = maxFriends
+		}
+		reason := "A friend went there"
+		if sig.Friends > 1 {
+			reason = fmt.Sprintf("%d of your friends went there", sig.Friends)
+		}
+		add(friendWeight*float64(friends), reason)
+	}
+	if sig.OwnRate > 0 {
+		add(ownRateWeight*float64(sig.OwnRate-3), fmt.Sprintf("You rated it %d/5", sig.OwnRate))
+	}
+	if sig.Orders > 0 {
+		orders := sig.Orders
+		if orders > maxOrders {
+			orders = maxOrders
+		}
+		reason := "You ordered there"
+		if sig.Orders > 1 {
+			reason = fmt.Sprintf("You ordered there %d times", sig.Orders)
+		}
+		add(orderWeight*float64(orders), reason)
+	}
+	if located {
+		reco.Distance = Distance(lat, long, sig.Establishment.Lat, sig.Establishment.Long)
+		if reco.Distance < MaxNearbyRadius {
+			add(distanceWeight*(1-reco.Distance/MaxNearbyRadius), fmt.Sprintf("%.0f m from you", reco.Distance))
+		}
+	}
+
+	if len(reco.Reason) == 0 {
+		reco.Reason = "New place to discover"
+	}
+	return reco
+}
+
+// rankRecommendations scores every establishment and keeps the best ones
+func rankRecommendations(signals []recommendationSignals, lat, long float64) []Recommendation {
+	var recos []Recommendation
+
+	located := lat != 0 || long != 0
+	for _, sig := range signals {
+		recos = append(recos, sig.score(lat, long, located))
+	}
+
+	sort.SliceStable(recos, func(i, j int) bool {
+		if recos[i].Score != recos[j].Score {
+			return recos[i].Score > recos[j].Score
+		}
+		return recos[i].Establishment.ID < recos[j].Establishment.ID
+	})
+	if len(recos) > RecommendationLimit {
+		recos = recos[:RecommendationLimit]
+	}
+	return recos
+}
+
+// LocationFromRequest reads the optional lat and long query parameters, both
+// at 0 meaning no location
+func LocationFromRequest(r *http.Request) (float64, float64, error) {
+	var values [2]float64
+
+	query := r.URL.Query()
+	for i, name := range []string{"lat", "long"} {
+		if len(query.Get(name)) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(query.Get(name), 64)
+		if err != nil {
+			return 0, 0, InvalidLocationErr
+		}
+		values[i] = value
+	}
+	if !validLocation(values[0], values[1], MaxNearbyRadius) {
+		return 0, 0, InvalidLocationErr
+	}
+	return values[0], values[1], nil
+}
+
+// EncodeLocationToRequest sets the query parameters read by LocationFromRequest
+func EncodeLocationToRequest(r *http.Request, lat, long float64) {
+	if lat == 0 && long == 0 {
+		return
+	}
+	query := r.URL.Query()
+	query.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	query.Set("long", strconv.FormatFloat(long, 'f', -1, 64))
+	r.URL.RawQuery = query.Encode()
+}
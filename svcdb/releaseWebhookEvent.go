@@ -0,0 +1,156 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// ReleaseWebhookEvent forgets an event claimed by ClaimWebhookEvent whose
+// handling failed, so that the retry of the provider is handled again
+func (s Service) ReleaseWebhookEvent(ctx context.Context, eventID string) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("ReleaseWebhookEvent (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	_, err = conn.ExecNeo(`
+		MATCH (e:WEBHOOK {EventID: {id}})
+		DELETE e`, map[string]interface{}{
+		"id": eventID,
+	})
+	if err != nil {
+		fmt.Println("ReleaseWebhookEvent (ExecNeo) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type releaseWebhookEventRequest struct {
+	EventID string `json:"event"`
+}
+
+type releaseWebhookEventResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func ReleaseWebhookEventEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(releaseWebhookEventRequest)
+		err := svc.ReleaseWebhookEvent(ctx, req.EventID)
+		if err != nil {
+			fmt.Println("Error ReleaseWebhookEventEndpoint : ", err.Error())
+			return releaseWebhookEventResponse{Err: err.Error()}, nil
+		}
+		return releaseWebhookEventResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPReleaseWebhookEventRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request releaseWebhookEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPReleaseWebhookEventRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPReleaseWebhookEventResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response releaseWebhookEventResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPReleaseWebhookEventResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ReleaseWebhookEventHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/webhooks/release").Handler(httptransport.NewServer(
+		endpoints.ReleaseWebhookEventEndpoint,
+		DecodeHTTPReleaseWebhookEventRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ReleaseWebhookEvent", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ReleaseWebhookEvent(ctx context.Context, eventID string) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "releaseWebhookEvent",
+			"eventID", eventID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.ReleaseWebhookEvent(ctx, eventID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ReleaseWebhookEvent(ctx context.Context, eventID string) error {
+	err := mw.next.ReleaseWebhookEvent(ctx, eventID)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildReleaseWebhookEventEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ReleaseWebhookEvent")
+		csLogger := log.With(logger, "method", "ReleaseWebhookEvent")
+
+		csEndpoint = ReleaseWebhookEventEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ReleaseWebhookEvent")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) ReleaseWebhookEvent(ctx context.Context, eventID string) error {
+	request := releaseWebhookEventRequest{EventID: eventID}
+	response, err := e.ReleaseWebhookEventEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error ReleaseWebhookEvent : ", err.Error())
+		return err
+	}
+	return str2err(response.(releaseWebhookEventResponse).Err)
+}
+
+func ClientReleaseWebhookEvent(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/webhooks/release"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPReleaseWebhookEventResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ReleaseWebhookEvent")(ceEndpoint)
+	return ceEndpoint, nil
+}
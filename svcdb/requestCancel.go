@@ -0,0 +1,201 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// RequestCancel records userID asking the pro to cancel the order, if step is
+// still its open step. A 0 userID withdraws the request, once the pro declined
+// it.
+func (s Service) RequestCancel(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+	var requester interface{}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("RequestCancel (WaitConnection) : " + err.Error())
+		return order, err
+	}
+	defer CloseConnection(conn)
+
+	// a null property is removed
+	if userID != 0 {
+		requester = userID
+	}
+
+	stmt, err := conn.PrepareNeo(`
+        MATCH (o:ORDER)-[:DONE]->(st:STEP)
+        WHERE ID(o) = {oid} AND (NOT EXISTS(o.Done) OR o.Done = "")
+        AND st.Name = {stname} AND (NOT EXISTS(st.Result) OR st.Result = "")
+        SET o.CancelRequest = {uid}
+        RETURN ID(o)
+    `)
+	defer stmt.Close()
+
+	if err != nil {
+		fmt.Println("RequestCancel (PrepareNeo) : " + err.Error())
+		return order, err
+	}
+
+	rows, err := stmt.QueryNeo(map[string]interface{}{
+		"oid":    orderID,
+		"stname": step,
+		"uid":    requester,
+	})
+	if err != nil {
+		fmt.Println("RequestCancel (QueryNeo) : " + err.Error())
+		return order, err
+	}
+
+	_, _, err = rows.NextNeo()
+	if err == io.EOF {
+		return order, StepNotOpenErr
+	} else if err != nil {
+		fmt.Println("RequestCancel (NextNeo) : " + err.Error())
+		return order, err
+	}
+
+	order, err = s.GetOrder(ctx, orderID)
+	if err != nil {
+		fmt.Println("RequestCancel (GetOrder) : " + err.Error())
+		return order, err
+	}
+	return order, nil
+}
+
+/*************** Endpoint ***************/
+type requestCancelRequest struct {
+	OrderID int64  `json:"order"`
+	UserID  int64  `json:"user"`
+	Step    string `json:"step"`
+}
+
+type requestCancelResponse struct {
+	Order Order  `json:"order"`
+	Err   string `json:"err,omitempty"`
+}
+
+func RequestCancelEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(requestCancelRequest)
+		order, err := svc.RequestCancel(ctx, req.OrderID, req.UserID, req.Step)
+		if err != nil {
+			fmt.Println("Error RequestCancelEndpoint : ", err.Error())
+			return requestCancelResponse{Order: order, Err: err.Error()}, nil
+		}
+		return requestCancelResponse{Order: order, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPRequestCancelRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request requestCancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPRequestCancelRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPRequestCancelResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response requestCancelResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPRequestCancelResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func RequestCancelHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/cancel/request").Handler(httptransport.NewServer(
+		endpoints.RequestCancelEndpoint,
+		DecodeHTTPRequestCancelRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "RequestCancel", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) RequestCancel(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "requestCancel",
+			"orderID", orderID,
+			"userID", userID,
+			"step", step,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.RequestCancel(ctx, orderID, userID, step)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) RequestCancel(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	v, err := mw.next.RequestCancel(ctx, orderID, userID, step)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildRequestCancelEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "RequestCancel")
+		csLogger := log.With(logger, "method", "RequestCancel")
+
+		csEndpoint = RequestCancelEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "RequestCancel")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) RequestCancel(ctx context.Context, orderID int64, userID int64, step string) (Order, error) {
+	var order Order
+
+	request := requestCancelRequest{OrderID: orderID, UserID: userID, Step: step}
+	response, err := e.RequestCancelEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error RequestCancel : ", err.Error())
+		return order, err
+	}
+	return response.(requestCancelResponse).Order, str2err(response.(requestCancelResponse).Err)
+}
+
+func ClientRequestCancel(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/cancel/request"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPRequestCancelResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "RequestCancel")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,194 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// ResetPassword uses the password reset token of hash to replace the password
+// of the account it was created for
+func (s Service) ResetPassword(ctx context.Context, hash string, password string) (AccountToken, error) {
+	var token AccountToken
+
+	hashed, err := hashPassword(password)
+	if err != nil {
+		fmt.Println("ResetPassword (hashPassword) : " + err.Error())
+		return token, err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("ResetPassword (WaitConnection) : " + err.Error())
+		return token, err
+	}
+	defer CloseConnection(conn)
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		token, err = useAccountToken(conn, hash, PurposeResetPassword)
+		if err != nil {
+			return err
+		}
+		kind, id, err := ParseTokenSubject(token.Subject)
+		if err != nil {
+			return err
+		}
+		label, err := subjectLabel(kind)
+		if err != nil {
+			return err
+		}
+
+		data, _, _, err := conn.QueryNeoAll(`
+			MATCH (n:`+label+`) WHERE ID(n) = {id}
+			SET n.Password = {password}
+			RETURN ID(n)`, map[string]interface{}{
+			"id":       id,
+			"password": hashed,
+		})
+		if err != nil {
+			return err
+		} else if len(data) == 0 {
+			return AccountTokenErr
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("ResetPassword (Transaction) : " + err.Error())
+		return token, err
+	}
+	return token, nil
+}
+
+/*************** Endpoint ***************/
+type resetPasswordRequest struct {
+	Hash     string `json:"hash"`
+	Password string `json:"password"`
+}
+
+type resetPasswordResponse struct {
+	Token AccountToken `json:"token"`
+	Err   string       `json:"err,omitempty"`
+}
+
+func ResetPasswordEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetPasswordRequest)
+		token, err := svc.ResetPassword(ctx, req.Hash, req.Password)
+		if err != nil {
+			fmt.Println("Error ResetPasswordEndpoint : ", err.Error())
+			return resetPasswordResponse{Token: token, Err: err.Error()}, nil
+		}
+		return resetPasswordResponse{Token: token, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPResetPasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPResetPasswordRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPResetPasswordResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response resetPasswordResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPResetPasswordResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func ResetPasswordHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/accounts/password/reset").Handler(httptransport.NewServer(
+		endpoints.ResetPasswordEndpoint,
+		DecodeHTTPResetPasswordRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ResetPassword", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ResetPassword(ctx context.Context, hash string, password string) (token AccountToken, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "resetPassword",
+			"subject", token.Subject,
+			"error", err,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.ResetPassword(ctx, hash, password)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ResetPassword(ctx context.Context, hash string, password string) (AccountToken, error) {
+	v, err := mw.next.ResetPassword(ctx, hash, password)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildResetPasswordEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ResetPassword")
+		csLogger := log.With(logger, "method", "ResetPassword")
+
+		csEndpoint = ResetPasswordEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ResetPassword")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) ResetPassword(ctx context.Context, hash string, password string) (AccountToken, error) {
+	var token AccountToken
+
+	response, err := e.ResetPasswordEndpoint(ctx, resetPasswordRequest{Hash: hash, Password: password})
+	if err != nil {
+		fmt.Println("Error ResetPassword : ", err.Error())
+		return token, err
+	}
+	r := response.(resetPasswordResponse)
+	return r.Token, str2err(r.Err)
+}
+
+func ClientResetPassword(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/accounts/password/reset"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPResetPasswordResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ResetPassword")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,195 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// RevokeTokens ends sessions. A revocation of an access token also deletes the
+// refresh token of refreshHash, its session. A revocation of every token of
+// the subject deletes all its refresh tokens.
+func (s Service) RevokeTokens(ctx context.Context, r Revocation, refreshHash string) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("RevokeTokens (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		if len(r.TokenID) == 0 {
+			return revokeSubject(conn, r)
+		}
+		_, err := conn.ExecNeo(`
+			CREATE (:REVOCATION {Subject: {subject}, TokenID: {tokenID}, Before: "", Until: {until}})`, map[string]interface{}{
+			"subject": r.Subject,
+			"tokenID": r.TokenID,
+			"until":   formatTime(r.Until),
+		})
+		if err != nil || len(refreshHash) == 0 {
+			return err
+		}
+		_, err = conn.ExecNeo(`
+			MATCH (t:REFRESH_TOKEN {Hash: {hash}, Subject: {subject}}) DELETE t`, map[string]interface{}{
+			"hash":    refreshHash,
+			"subject": r.Subject,
+		})
+		return err
+	})
+	if err != nil {
+		fmt.Println("RevokeTokens (Transaction) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// revokeSubject revokes every token of r.Subject issued before the second of
+// r.Before
+func revokeSubject(conn bolt.Conn, r Revocation) error {
+	_, err := conn.ExecNeo(`
+		CREATE (:REVOCATION {Subject: {subject}, TokenID: "", Before: {before}, Until: {until}})`, map[string]interface{}{
+		"subject": r.Subject,
+		"before":  formatTime(r.Before.Truncate(time.Second)),
+		"until":   formatTime(r.Until),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = conn.ExecNeo(`
+		MATCH (t:REFRESH_TOKEN {Subject: {subject}}) DELETE t`, map[string]interface{}{
+		"subject": r.Subject,
+	})
+	return err
+}
+
+/*************** Endpoint ***************/
+type revokeTokensRequest struct {
+	Revocation  Revocation `json:"revocation"`
+	RefreshHash string     `json:"refreshHash,omitempty"`
+}
+
+type revokeTokensResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func RevokeTokensEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokeTokensRequest)
+		err := svc.RevokeTokens(ctx, req.Revocation, req.RefreshHash)
+		if err != nil {
+			fmt.Println("Error RevokeTokensEndpoint : ", err.Error())
+			return revokeTokensResponse{Err: err.Error()}, nil
+		}
+		return revokeTokensResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPRevokeTokensRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request revokeTokensRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPRevokeTokensRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPRevokeTokensResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response revokeTokensResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPRevokeTokensResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func RevokeTokensHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/tokens/revoke").Handler(httptransport.NewServer(
+		endpoints.RevokeTokensEndpoint,
+		DecodeHTTPRevokeTokensRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "RevokeTokens", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) RevokeTokens(ctx context.Context, r Revocation, refreshHash string) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "revokeTokens",
+			"subject", r.Subject,
+			"tokenID", r.TokenID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.RevokeTokens(ctx, r, refreshHash)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) RevokeTokens(ctx context.Context, r Revocation, refreshHash string) error {
+	err := mw.next.RevokeTokens(ctx, r, refreshHash)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildRevokeTokensEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "RevokeTokens")
+		csLogger := log.With(logger, "method", "RevokeTokens")
+
+		csEndpoint = RevokeTokensEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "RevokeTokens")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) RevokeTokens(ctx context.Context, r Revocation, refreshHash string) error {
+	request := revokeTokensRequest{Revocation: r, RefreshHash: refreshHash}
+	response, err := e.RevokeTokensEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error RevokeTokens : ", err.Error())
+		return err
+	}
+	return str2err(response.(revokeTokensResponse).Err)
+}
+
+func ClientRevokeTokens(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/tokens/revoke"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPRevokeTokensResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "RevokeTokens")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -22,28 +22,35 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchEstablishments(_ context.Context, query string) ([]SearchResponse, error) {
+func (s Service) SearchEstablishments(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	var tmpEstablishment Establishment
 	var tmpResponse SearchResponse
 	var response []SearchResponse
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"query": query,
+	}
+	where, tail, err := page.keyset("ID(e)", false, args)
+	if err != nil {
+		return response, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("SearchEstablishments (WaitConnection) : " + err.Error())
-		return response, err
+		return response, "", err
 	}
 	defer CloseConnection(conn)
 
-	stmt, err := conn.PrepareNeo("MATCH(e:ESTABLISHMENT) WHERE LOWER(e.Name) CONTAINS LOWER({query}) RETURN e")
+	stmt, err := conn.PrepareNeo("MATCH(e:ESTABLISHMENT) WHERE LOWER(e.Name) CONTAINS LOWER({query}) AND " + where + " RETURN e" + tail)
 	defer stmt.Close()
 	if err != nil {
 		fmt.Println("SearchAllEstablishments (PrepareNeo)")
 		panic(err)
 	}
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"query": query,
-	})
+	rows, err := stmt.QueryNeo(args)
 
 	if err != nil {
 		fmt.Println("SearchAllEstablishments (QueryNeo)")
@@ -62,31 +69,36 @@ func (s Service) SearchEstablishments(_ context.Context, query string) ([]Search
 			tmpResponse.FromEstablishment(tmpEstablishment)
 
 			response = append(response, tmpResponse)
+			keys = append(keys, tmpEstablishment.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
-	return response, nil
+
+	count, next := page.trim(keys)
+	return response[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type searchEstablishmentsRequest struct {
 	query string `json:"query"`
+	Page  Page   `json:"page"`
 }
 
 type searchEstablishmentsResponse struct {
 	Establishments []SearchResponse `json:"establishments"`
+	NextCursor     string           `json:"next_cursor,omitempty"`
 	Err            string           `json:"err,omitempty"`
 }
 
 func SearchEstablishmentsEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchEstablishmentsRequest)
-		establishments, err := svc.SearchEstablishments(ctx, req.query)
+		establishments, next, err := svc.SearchEstablishments(ctx, req.query, req.Page)
 		if err != nil {
 			fmt.Println("Error SearchEstablishmentsEndpoint : ", err.Error())
-			return searchEstablishmentsResponse{establishments, err.Error()}, nil
+			return searchEstablishmentsResponse{establishments, next, err.Error()}, nil
 		}
-		return searchEstablishmentsResponse{establishments, ""}, nil
+		return searchEstablishmentsResponse{establishments, next, ""}, nil
 	}
 }
 
@@ -106,6 +118,13 @@ func DecodeHTTPSearchEstablishmentsRequest(_ context.Context, r *http.Request) (
 		return nil, err
 	}
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchEstablishmentsRequest : ", err.Error())
+		return nil, err
+	}
+	(&req).Page = page
+
 	return req, nil
 }
 
@@ -130,22 +149,22 @@ func SearchEstablishmentsHTTPHandler(endpoints Endpoints, tracer stdopentracing.
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchEstablishments(ctx context.Context, query string) ([]SearchResponse, error) {
+func (mw serviceLoggingMiddleware) SearchEstablishments(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchEstablishments",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchEstablishments(ctx, query)
+	return mw.next.SearchEstablishments(ctx, query, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchEstablishments(ctx context.Context, query string) ([]SearchResponse, error) {
-	v, err := mw.next.SearchEstablishments(ctx, query)
+func (mw serviceInstrumentingMiddleware) SearchEstablishments(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
+	v, next, err := mw.next.SearchEstablishments(ctx, query, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -167,17 +186,17 @@ func BuildSearchEstablishmentsEndpoint(svc IService, logger log.Logger, tracer s
 /*************** Client ***************/
 /* Client */
 // Forsearch limiter & circuitbreaker for now kthx
-func (e Endpoints) SearchEstablishments(ctx context.Context, query string) ([]SearchResponse, error) {
+func (e Endpoints) SearchEstablishments(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	var et []SearchResponse
 
-	request := searchEstablishmentsRequest{query: query}
+	request := searchEstablishmentsRequest{query: query, Page: page}
 	response, err := e.SearchEstablishmentsEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error Client SearchEstablishments : ", err.Error())
-		return et, err
+		return et, "", err
 	}
 	estabs := response.(searchEstablishmentsResponse).Establishments
-	return estabs, str2err(response.(searchEstablishmentsResponse).Err)
+	return estabs, response.(searchEstablishmentsResponse).NextCursor, str2err(response.(searchEstablishmentsResponse).Err)
 }
 
 func EncodeHTTPSearchEstablishmentsRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -190,6 +209,7 @@ func EncodeHTTPSearchEstablishmentsRequest(ctx context.Context, r *http.Request,
 		return err
 	}
 	r.URL.Path = encodedURL.Path
+	request.(searchEstablishmentsRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
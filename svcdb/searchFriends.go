@@ -23,12 +23,12 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchFriends(_ context.Context, query string, userID int64) ([]SearchResponse, error) {
+func (s Service) SearchFriends(ctx context.Context, query string, userID int64) ([]SearchResponse, error) {
 	var tmpUser User
 	var tmpResponse SearchResponse
 	var response []SearchResponse
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("SearchFriends (WaitConnection) : " + err.Error())
 		return nil, err
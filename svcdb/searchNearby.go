@@ -0,0 +1,280 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+func (s Service) SearchNearby(ctx context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error) {
+	var results []NearbyResult
+
+	if !validLocation(lat, long, radius) {
+		return results, InvalidLocationErr
+	}
+
+	minLat, maxLat, minLong, maxLong := boundingBox(lat, long, radius)
+	args := map[string]interface{}{
+		"minlat":  minLat,
+		"maxlat":  maxLat,
+		"minlong": minLong,
+		"maxlong": maxLong,
+	}
+	typeFilter := ""
+	if len(filters.Types) > 0 {
+		typeFilter = " WHERE t.Name IN {types}"
+		args["types"] = StrArrayToIArray(filters.Types)
+	}
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("SearchNearby (WaitConnection) : " + err.Error())
+		return results, err
+	}
+	defer CloseConnection(conn)
+
+	stmt, err := conn.PrepareNeo(`
+		MATCH (e:ESTABLISHMENT)
+		WHERE e.Lat >= {minlat} AND e.Lat <= {maxlat} AND e.Long >= {minlong} AND e.Long <= {maxlong}
+		OPTIONAL MATCH (e)-[:IS]->(t:ESTABLISHMENT_TYPE)
+		WITH e, t` + typeFilter + `
+		OPTIONAL MATCH (e)<-[:OWN]-(p:PRO)
+		OPTIONAL MATCH (e)<-[r:RATE]-(u:USER)
+		RETURN e, t.Name, ID(p), AVG(r.value)`)
+	if err != nil {
+		fmt.Println("SearchNearby (PrepareNeo 1) : " + err.Error())
+		return results, err
+	}
+
+	rows, err := stmt.QueryNeo(args)
+	if err != nil {
+		stmt.Close()
+		fmt.Println("SearchNearby (QueryNeo 1) : " + err.Error())
+		return results, err
+	}
+
+	// Candidates come from a bounding box, the real distance is checked here
+	var ids []interface{}
+	index := make(map[int64]int)
+
+	row, _, err := rows.NextNeo()
+	for row != nil && err == nil {
+		var tmpResult NearbyResult
+
+		(&tmpResult.Establishment).NodeToEstablishment(row[0].(graph.Node))
+		if estabType, ok := row[1].(string); ok {
+			tmpResult.Establishment.Type = estabType
+		}
+		if owner, ok := row[2].(int64); ok {
+			tmpResult.Establishment.Owner = owner
+		}
+		if rate, ok := row[3].(float64); ok {
+			tmpResult.Establishment.Rate = rate
+		}
+
+		tmpResult.Distance = Distance(lat, long, tmpResult.Establishment.Lat, tmpResult.Establishment.Long)
+		if _, seen := index[tmpResult.Establishment.ID]; !seen && tmpResult.Distance <= radius {
+			index[tmpResult.Establishment.ID] = len(results)
+			ids = append(ids, tmpResult.Establishment.ID)
+			results = append(results, tmpResult)
+		}
+		row, _, err = rows.NextNeo()
+	}
+	stmt.Close()
+	if err != nil && err != io.EOF {
+		fmt.Println("SearchNearby (NextNeo 1) : " + err.Error())
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		stmt, err = conn.PrepareNeo(`
+			MATCH (e:ESTABLISHMENT)-[:SPAWNED]->(s:SOIREE)
+			WHERE ID(e) IN {ids} AND s.Begin <= {now} AND s.End > {now}
+			RETURN ID(e), s`)
+		if err != nil {
+			fmt.Println("SearchNearby (PrepareNeo 2) : " + err.Error())
+			return nil, err
+		}
+		defer stmt.Close()
+
+		rows, err = stmt.QueryNeo(map[string]interface{}{
+			"ids": ids,
+			"now": formatTime(time.Now()),
+		})
+		if err != nil {
+			fmt.Println("SearchNearby (QueryNeo 2) : " + err.Error())
+			return nil, err
+		}
+
+		row, _, err = rows.NextNeo()
+		for row != nil && err == nil {
+			var tmpSoiree Soiree
+
+			(&tmpSoiree).NodeToSoiree(row[1].(graph.Node))
+			i := index[row[0].(int64)]
+			results[i].Soirees = append(results[i].Soirees, tmpSoiree)
+			row, _, err = rows.NextNeo()
+		}
+		if err != nil && err != io.EOF {
+			fmt.Println("SearchNearby (NextNeo 2) : " + err.Error())
+			return nil, err
+		}
+	}
+
+	kept := results[:0]
+	for _, result := range results {
+		if filters.keep(result) {
+			kept = append(kept, result)
+		}
+	}
+	sortNearby(kept)
+	return kept, nil
+}
+
+/*************** Endpoint ***************/
+type searchNearbyRequest struct {
+	Lat     float64       `json:"lat"`
+	Long    float64       `json:"long"`
+	Radius  float64       `json:"radius"`
+	Filters NearbyFilters `json:"filters"`
+}
+
+type searchNearbyResponse struct {
+	Results []NearbyResult `json:"results"`
+	Err     string         `json:"err,omitempty"`
+}
+
+func SearchNearbyEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchNearbyRequest)
+		results, err := svc.SearchNearby(ctx, req.Lat, req.Long, req.Radius, req.Filters)
+		if err != nil {
+			fmt.Println("Error SearchNearbyEndpoint : ", err.Error())
+			return searchNearbyResponse{results, err.Error()}, nil
+		}
+		return searchNearbyResponse{results, ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPSearchNearbyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request searchNearbyRequest
+
+	lat, long, radius, filters, err := NearbyFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchNearbyRequest : ", err.Error())
+		return nil, err
+	}
+	request = searchNearbyRequest{Lat: lat, Long: long, Radius: radius, Filters: filters}
+
+	return request, nil
+}
+
+func DecodeHTTPSearchNearbyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response searchNearbyResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPSearchNearbyResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func SearchNearbyHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/search/nearby").Handler(httptransport.NewServer(
+		endpoints.SearchNearbyEndpoint,
+		DecodeHTTPSearchNearbyRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SearchNearby", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) SearchNearby(ctx context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "searchNearby",
+			"lat", lat,
+			"long", long,
+			"radius", radius,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.SearchNearby(ctx, lat, long, radius, filters)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) SearchNearby(ctx context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error) {
+	v, err := mw.next.SearchNearby(ctx, lat, long, radius, filters)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildSearchNearbyEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "SearchNearby")
+		csLogger := log.With(logger, "method", "SearchNearby")
+
+		csEndpoint = SearchNearbyEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "SearchNearby")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forsearch limiter & circuitbreaker for now kthx
+func (e Endpoints) SearchNearby(ctx context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error) {
+	var results []NearbyResult
+
+	request := searchNearbyRequest{Lat: lat, Long: long, Radius: radius, Filters: filters}
+	response, err := e.SearchNearbyEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error Client SearchNearby : ", err.Error())
+		return results, err
+	}
+	results = response.(searchNearbyResponse).Results
+	return results, str2err(response.(searchNearbyResponse).Err)
+}
+
+func EncodeHTTPSearchNearbyRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(searchNearbyRequest)
+	EncodeNearbyToRequest(r, req.Lat, req.Long, req.Radius, req.Filters)
+	return nil
+}
+
+func ClientSearchNearby(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/search/nearby"),
+		EncodeHTTPSearchNearbyRequest,
+		DecodeHTTPSearchNearbyResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "SearchNearby")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -21,26 +21,28 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchOrders(ctx context.Context, order Order) (Orders, error) {
+func (s Service) SearchOrders(ctx context.Context, order Order, page Page) (Orders, string, error) {
 	var orders Orders
-    var req, reqMatch, reqWhere string
+	var keys []int64
+    var req, reqMatch, reqWhere, reqTail string
 
 	i := 0
 	args := make(map[string]interface{})
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("SearchOrders (WaitConnection) : " + err.Error())
-		return orders, err
+		return orders, "", err
 	}
 
 	/* Init */
 	reqMatch = `MATCH (o:ORDER)`
-	if len(order.Done) > 0 || len(order.Users) > 0 || len(order.Steps) > 0 || order.Soiree.ID > 0 {
-		reqWhere = `WHERE ID(o) > 0`
-	} else {
-		reqWhere = ``
+	reqWhere, reqTail, err = page.keyset("ID(o)", false, args)
+	if err != nil {
+		CloseConnection(conn)
+		return orders, "", err
 	}
+	reqWhere = `WHERE ` + reqWhere
 
 	/* Order Done */
 	if len(order.Done) > 0 {
@@ -90,29 +92,28 @@ func (s Service) SearchOrders(ctx context.Context, order Order) (Orders, error)
 	}
 
 	/* Final */
-	req = reqMatch + ` ` + reqWhere + ` RETURN ID(o)`
+	req = reqMatch + ` ` + reqWhere + ` RETURN ID(o)` + reqTail
 	stmt, err := conn.PrepareNeo(req)
 	fmt.Println("SearchOrders (request) : " + req)
 
 	if err != nil {
 		fmt.Println("SearchOrders (PrepareNeo) : " + err.Error())
-		return orders, err
+		return orders, "", err
 	}
 
 	rows, err := stmt.QueryNeo(args)
 	if err != nil {
 		fmt.Println("SearchOrders (QueryNeo) : " + err.Error())
-		return orders, err
+		return orders, "", err
 	}
 
 	row, _, err := rows.NextNeo()
 	for row != nil && err == nil {
 		if err != nil && err != io.EOF {
 			fmt.Println("SearchOrders (NextNeo) : " + err.Error())
-			return orders, err
+			return orders, "", err
 		} else if err != io.EOF {
-			tmpOrder, _ := s.GetOrder(ctx, row[0].(int64))
-			orders = append(orders, tmpOrder)
+			keys = append(keys, row[0].(int64))
 		}
 		row, _, err = rows.NextNeo()
 	}
@@ -120,28 +121,35 @@ func (s Service) SearchOrders(ctx context.Context, order Order) (Orders, error)
 	stmt.Close()
 	CloseConnection(conn)
 
-	return orders, nil
+	count, next := page.trim(keys)
+	for _, orderID := range keys[:count] {
+		tmpOrder, _ := s.GetOrder(ctx, orderID)
+		orders = append(orders, tmpOrder)
+	}
+	return orders, next, nil
 }
 
 /*************** Endpoint ***************/
 type searchOrdersRequest struct {
 	Order		Order	`json:"order"`
+	Page		Page	`json:"page"`
 }
 
 type searchOrdersResponse struct {
 	Orders		Orders	`json:"orders"`
+	NextCursor	string	`json:"next_cursor,omitempty"`
 	Err			string	`json:"err,omitempty"`
 }
 
 func SearchOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchOrdersRequest)
-		orders, err := svc.SearchOrders(ctx, req.Order)
+		orders, next, err := svc.SearchOrders(ctx, req.Order, req.Page)
 		if err != nil {
 			fmt.Println("Error SearchOrdersEndpoint : ", err.Error())
-			return searchOrdersResponse{orders, err.Error()}, nil
+			return searchOrdersResponse{orders, next, err.Error()}, nil
 		}
-		return searchOrdersResponse{orders, ""}, nil
+		return searchOrdersResponse{orders, next, ""}, nil
 	}
 }
 
@@ -176,22 +184,22 @@ func SearchOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order Order) (Orders, error) {
+func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order Order, page Page) (Orders, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchOrders",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchOrders(ctx, order)
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order Order) (Orders, error) {
-	v, err := mw.next.SearchOrders(ctx, order)
+func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order Order, page Page) (Orders, string, error) {
+	v, next, err := mw.next.SearchOrders(ctx, order, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -213,17 +221,17 @@ func BuildSearchOrdersEndpoint(svc IService, logger log.Logger, tracer stdopentr
 /*************** Client ***************/
 /* Client */
 // Forsearch limiter & circuitbreaker for now kthx
-func (e Endpoints) SearchOrders(ctx context.Context, order Order) (Orders, error) {
+func (e Endpoints) SearchOrders(ctx context.Context, order Order, page Page) (Orders, string, error) {
 	var orders Orders
 
-	request := searchOrdersRequest{Order: order}
+	request := searchOrdersRequest{Order: order, Page: page}
 	response, err := e.SearchOrdersEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error Client SearchOrders : ", err.Error())
-		return orders, err
+		return orders, "", err
 	}
 	orders = response.(searchOrdersResponse).Orders
-	return orders, str2err(response.(searchOrdersResponse).Err)
+	return orders, response.(searchOrdersResponse).NextCursor, str2err(response.(searchOrdersResponse).Err)
 }
 
 func ClientSearchOrders(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
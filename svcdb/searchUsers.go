@@ -22,32 +22,39 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchUsers(_ context.Context, query string) ([]SearchResponse, error) {
+func (s Service) SearchUsers(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	var tmpUser User
 	var tmpResponse SearchResponse
 	var response []SearchResponse
+	var keys []int64
 
-	conn, err := WaitConnection(5)
+	args := map[string]interface{}{
+		"query": query,
+	}
+	where, tail, err := page.keyset("ID(u)", false, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("SearchUsers (WaitConnection) : " + err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	defer CloseConnection(conn)
 
-	stmt, err := conn.PrepareNeo("MATCH(u:USER) WHERE LOWER(u.Pseudo) CONTAINS LOWER({query}) RETURN u")
+	stmt, err := conn.PrepareNeo("MATCH(u:USER) WHERE LOWER(u.Pseudo) CONTAINS LOWER({query}) AND " + where + " RETURN u" + tail)
 	defer stmt.Close()
 	if err != nil {
 		fmt.Println("SearchUsers (PrepareNeo)")
-		return nil, err
+		return nil, "", err
 	}
 
-	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"query": query,
-	})
+	rows, err := stmt.QueryNeo(args)
 
 	if err != nil {
 		fmt.Println("SearchUsers (QueryNeo)")
-		return nil, err
+		return nil, "", err
 	}
 
 	row, _, err := rows.NextNeo()
@@ -60,31 +67,36 @@ func (s Service) SearchUsers(_ context.Context, query string) ([]SearchResponse,
 			(&tmpUser).NodeToUser(row[0].(graph.Node))
 			tmpResponse.FromUser(tmpUser)
 			response = append(response, tmpResponse)
+			keys = append(keys, tmpUser.ID)
 		}
 		row, _, err = rows.NextNeo()
 	}
-	return response, nil
+
+	count, next := page.trim(keys)
+	return response[:count], next, nil
 }
 
 /*************** Endpoint ***************/
 type searchUsersRequest struct {
 	query string `json:"query"`
+	Page  Page   `json:"page"`
 }
 
 type searchUsersResponse struct {
-	Users []SearchResponse `json:"users"`
-	Err   string           `json:"err,omitempty"`
+	Users      []SearchResponse `json:"users"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Err        string           `json:"err,omitempty"`
 }
 
 func SearchUsersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchUsersRequest)
-		users, err := svc.SearchUsers(ctx, req.query)
+		users, next, err := svc.SearchUsers(ctx, req.query, req.Page)
 		if err != nil {
 			fmt.Println("Error SearchUsersEndpoint : " + err.Error())
-			return searchUsersResponse{users, err.Error()}, nil
+			return searchUsersResponse{users, next, err.Error()}, nil
 		}
-		return searchUsersResponse{users, ""}, nil
+		return searchUsersResponse{users, next, ""}, nil
 	}
 }
 
@@ -104,6 +116,13 @@ func DecodeHTTPSearchUsersRequest(_ context.Context, r *http.Request) (interface
 		return nil, err
 	}
 
+	page, err := PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPSearchUsersRequest : ", err.Error())
+		return nil, err
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -128,22 +147,22 @@ func SearchUsersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchUsers(ctx context.Context, query string) ([]SearchResponse, error) {
+func (mw serviceLoggingMiddleware) SearchUsers(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchUsers",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchUsers(ctx, query)
+	return mw.next.SearchUsers(ctx, query, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchUsers(ctx context.Context, query string) ([]SearchResponse, error) {
-	v, err := mw.next.SearchUsers(ctx, query)
+func (mw serviceInstrumentingMiddleware) SearchUsers(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
+	v, next, err := mw.next.SearchUsers(ctx, query, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -165,17 +184,17 @@ func BuildSearchUsersEndpoint(svc IService, logger log.Logger, tracer stdopentra
 /*************** Client ***************/
 /* Client */
 // Forsearch limiter & circuitbreaker for now kthx
-func (e Endpoints) SearchUsers(ctx context.Context, query string) ([]SearchResponse, error) {
+func (e Endpoints) SearchUsers(ctx context.Context, query string, page Page) ([]SearchResponse, string, error) {
 	var et []SearchResponse
 
-	request := searchUsersRequest{query: query}
+	request := searchUsersRequest{query: query, Page: page}
 	response, err := e.SearchUsersEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error SearchUsers : " + err.Error())
-		return et, err
+		return et, "", err
 	}
 	et = response.(searchUsersResponse).Users
-	return et, str2err(response.(searchUsersResponse).Err)
+	return et, response.(searchUsersResponse).NextCursor, str2err(response.(searchUsersResponse).Err)
 }
 
 func EncodeHTTPSearchUsersRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -188,6 +207,7 @@ func EncodeHTTPSearchUsersRequest(ctx context.Context, r *http.Request, request
 		return err
 	}
 	r.URL.Path = encodedURL.Path
+	request.(searchUsersRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
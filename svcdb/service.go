@@ -3,13 +3,14 @@ package svcdb
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 /* Service interface */
 type IService interface {
 	/* Users */
 	CreateUser(ctx context.Context, u User) (User, error)
-	SearchUsers(ctx context.Context, query string) ([]SearchResponse, error)
+	SearchUsers(ctx context.Context, query string, page Page) ([]SearchResponse, string, error)
 	UpdateUser(ctx context.Context, u User) (User, error)
 	GetUserByID(ctx context.Context, userID int64) (User, error)
 	GetUser(ctx context.Context, u User) (User, error)
@@ -17,12 +18,13 @@ type IService interface {
 	GetUserPreferences(ctx context.Context, userID int64) ([]Preference, error)
 	GetUserSuccess(ctx context.Context, userID int64) ([]Success, error)
 	UpdatePreference(ctx context.Context, userID int64, preferences []string) ([]string, error)
-	GetRecommendation(ctx context.Context, userID int64) ([]Establishment, error)
+	GetRecommendation(ctx context.Context, userID int64, lat, long float64) ([]Recommendation, error)
 
 	/* Friends */
 	InviteFriend(ctx context.Context, userID, friendID int64) (string, int64, int64, error)
-	GetUserFriends(ctx context.Context, userID int64) ([]Profile, error)
-	GetUsersInvitations(ctx context.Context, userID int64) ([]Invitation, error)
+	GetUserFriends(ctx context.Context, userID int64, page Page) ([]Profile, string, error)
+	GetUsersInvitations(ctx context.Context, userID int64, page Page) ([]Invitation, string, error)
+	GetInvitation(ctx context.Context, invitationID int64) (Invitation, error)
 	InvitationAccept(ctx context.Context, invitationID int64) (Profile, Profile, error)
 	InvitationDecline(ctx context.Context, invitationID int64) (Profile, Profile, error)
 	UsersConnected(ctx context.Context, userID, friendID int64) (bool, error)
@@ -35,12 +37,14 @@ type IService interface {
 	GetProByID(ctx context.Context, userID int64) (Pro, error)
 	GetProBySoiree(ctx context.Context, soireeID int64) (Pro, error)
 	UpdatePro(ctx context.Context, new Pro) (Pro, error)
+	UpdateProStripeStatus(ctx context.Context, stripeID string, status string) (Pro, error)
 	GetProEstablishments(ctx context.Context, soireeID int64) ([]Establishment, error)
 
 	/* Establishments */
 	CreateEstablishment(ctx context.Context, e Establishment, proID int64) (Establishment, error)
-	GetEstablishments(ctx context.Context) ([]Establishment, error)
-	SearchEstablishments(ctx context.Context, query string) ([]SearchResponse, error)
+	GetEstablishments(ctx context.Context, page Page) ([]Establishment, string, error)
+	SearchEstablishments(ctx context.Context, query string, page Page) ([]SearchResponse, string, error)
+	SearchNearby(ctx context.Context, lat, long, radius float64, filters NearbyFilters) ([]NearbyResult, error)
 	GetEstablishment(ctx context.Context, estabID int64) (Establishment, error)
 	GetEstablishmentFromMenu(ctx context.Context, menuID int64) (Establishment, error)
 	GetMenuFromEstablishment(ctx context.Context, menuID int64) ([]Menu, error)
@@ -68,6 +72,7 @@ type IService interface {
 	CreateMenu(ctx context.Context, establishmentID int64, u Menu) (Menu, error)
 	CreateConso(ctx context.Context, establishmentID, menuID int64, c Conso) (Conso, error)
 	GetMenuFromSoiree(ctx context.Context, soireeID int64) (Menu, error)
+	ImportMenus(ctx context.Context, estabID int64, menus []Menu) ([]Menu, error)
 
 	/* Groups */
 	GetGroup(ctx context.Context, groupID int64) (Group, error)
@@ -83,20 +88,26 @@ type IService interface {
 
 	/* Order */
 	GetOrder(ctx context.Context, orderID int64) (Order, error)
-	SearchOrders(ctx context.Context, order Order) (Orders, error)
+	SearchOrders(ctx context.Context, order Order, page Page) (Orders, string, error)
 	CreateOrder(ctx context.Context, o Order) (Order, error)
 	PutOrder(ctx context.Context, orderID int64, step string, flag bool) (Order, error)
 	AnswerOrder(ctx context.Context, orderID int64, userID int64, answer bool) (Order, error)
 	FailOrder(ctx context.Context, orderID int64) (Order, error)
+	CancelOrder(ctx context.Context, orderID int64, userID int64, step string) (Order, error)
+	RequestCancel(ctx context.Context, orderID int64, userID int64, step string) (Order, error)
+	GetPendingOrders(ctx context.Context) ([]Order, error)
 	UpdateOrderReference(ctx context.Context, orderID int64, userID int64, reference string) (error)
+	GetOrderByReference(ctx context.Context, reference string) (Order, error)
+	GetChargedOrders(ctx context.Context, from time.Time) ([]Order, error)
+	FlagOrder(ctx context.Context, orderID int64, issue string) error
 
 	UserOrder(ctx context.Context, user User, soiree Soiree, conso Conso) (int64, error)
 	GetOrdersBySoiree(ctx context.Context, soireeID int64) ([]Order, error)
-	GetSoireeOrders(ctx context.Context, soireeID int64) ([]Order, error)
+	GetSoireeOrders(ctx context.Context, soireeID int64, page Page) ([]Order, string, error)
 	GetConsoByOrderID(ctx context.Context, orderID int64) (Conso, error)
 
 	/* Conversation */
-	GetLastMessages(ctx context.Context, recipient, initiator int64) ([]Message, error)
+	GetLastMessages(ctx context.Context, recipient, initiator int64, page Page) ([]Message, string, error)
 	CreateMessage(c context.Context, nodeType string, m Message) (Message, error)
 	GetConversationByID(c context.Context, convID int64) (Conversation, error)
 
@@ -106,6 +117,33 @@ type IService interface {
 
 	/* Analyse */
 	GetAnalyseP(c context.Context, estabID int64, soireeID int64) ([]AnalyseP, error)
+	GetAnalyseC(c context.Context, estabID int64, soireeID int64) ([]AnalyseC, error)
+	GetAnalyseF(c context.Context, estabID int64, soireeID int64, from, to time.Time) ([]AnalyseF, error)
+	GetMenuStat(c context.Context, menuID int64, from, to time.Time) (MenuStat, error)
+
+	/* Webhook */
+	ClaimWebhookEvent(ctx context.Context, eventID string, eventType string) (bool, error)
+	ReleaseWebhookEvent(ctx context.Context, eventID string) error
+
+	/* Idempotency */
+	ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string) (IdempotentResponse, bool, error)
+	StoreIdempotencyKey(ctx context.Context, key string, response string) error
+
+	/* Ledger */
+	AddLedgerEntry(ctx context.Context, entry LedgerEntry) (LedgerEntry, error)
+	GetLedger(ctx context.Context, filter LedgerFilter) ([]LedgerEntry, error)
+
+	/* Tokens */
+	CreateRefreshToken(ctx context.Context, token RefreshToken) error
+	UseRefreshToken(ctx context.Context, hash string, next RefreshToken, until time.Time) (RefreshToken, error)
+	RevokeTokens(ctx context.Context, r Revocation, refreshHash string) error
+	IsTokenRevoked(ctx context.Context, subject string, tokenID string, issuedAt time.Time) (bool, error)
+	CreateAccountToken(ctx context.Context, token AccountToken) error
+
+	/* Accounts */
+	GetAccountByEmail(ctx context.Context, kind string, email string) (int64, error)
+	ResetPassword(ctx context.Context, hash string, password string) (AccountToken, error)
+	VerifyEmail(ctx context.Context, hash string) (AccountToken, error)
 }
 
 /* Errors definition */
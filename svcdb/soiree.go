@@ -23,8 +23,8 @@ type Soirees []Soiree
 func (s *Soiree) NodeToSoiree(node graph.Node) {
 	s.ID = node.NodeIdentity
 	s.Desc = node.Properties["Desc"].(string)
-	s.Begin, _ = time.Parse(timeForm, node.Properties["Begin"].(string))
-	s.End, _ = time.Parse(timeForm, node.Properties["End"].(string))
+	s.Begin = propertyTime("NodeToSoiree", node.Properties, "Begin")
+	s.End = propertyTime("NodeToSoiree", node.Properties, "End")
 }
 
 func (s Soiree) MarshalJSON() ([]byte, error) {
@@ -35,8 +35,8 @@ func (s Soiree) MarshalJSON() ([]byte, error) {
 		End   string `json:"end"`
 	}{
 		Alias: (Alias)(s),
-		Begin: s.Begin.Format(timeForm),
-		End:   s.End.Format(timeForm),
+		Begin: jsonTime(s.Begin),
+		End:   jsonTime(s.End),
 	})
 	return cstruct, err
 }
@@ -53,25 +53,14 @@ func (s *Soiree) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	if len(aux.Begin) > 0 {
-		begin, err := time.Parse(timeForm, aux.Begin)
-		if err != nil {
-			begin, err = time.Parse(timeFormFucked, aux.Begin)
-			if err != nil {
-				return err
-			}
-		}
-		s.Begin = begin
+	begin, err := parseJSONTime(aux.Begin)
+	if err != nil {
+		return err
 	}
-	if len(aux.End) > 0 {
-		end, err := time.Parse(timeForm, aux.End)
-		if err != nil {
-			end, err = time.Parse(timeFormFucked, aux.End)
-			if err != nil {
-				return err
-			}
-		}
-		s.End = end
+	end, err := parseJSONTime(aux.End)
+	if err != nil {
+		return err
 	}
+	s.Begin, s.End = begin, end
 	return nil
 }
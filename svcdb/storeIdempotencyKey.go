@@ -0,0 +1,161 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// StoreIdempotencyKey stores the response of the request that claimed key. An
+// empty response releases the key, for a failed request to be retried.
+func (s Service) StoreIdempotencyKey(ctx context.Context, key string, response string) error {
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("StoreIdempotencyKey (WaitConnection) : " + err.Error())
+		return err
+	}
+	defer CloseConnection(conn)
+
+	req := `MATCH (k:IDEMPOTENCY {Key: {key}}) SET k.Response = {response}`
+	if len(response) == 0 {
+		req = `MATCH (k:IDEMPOTENCY {Key: {key}}) WHERE k.Response = "" DELETE k`
+	}
+	_, err = conn.ExecNeo(req, map[string]interface{}{
+		"key":      key,
+		"response": response,
+	})
+	if err != nil {
+		fmt.Println("StoreIdempotencyKey (ExecNeo) : " + err.Error())
+		return err
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type storeIdempotencyKeyRequest struct {
+	Key      string `json:"key"`
+	Response string `json:"response"`
+}
+
+type storeIdempotencyKeyResponse struct {
+	Err string `json:"err,omitempty"`
+}
+
+func StoreIdempotencyKeyEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(storeIdempotencyKeyRequest)
+		err := svc.StoreIdempotencyKey(ctx, req.Key, req.Response)
+		if err != nil {
+			fmt.Println("Error StoreIdempotencyKeyEndpoint : ", err.Error())
+			return storeIdempotencyKeyResponse{Err: err.Error()}, nil
+		}
+		return storeIdempotencyKeyResponse{Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPStoreIdempotencyKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request storeIdempotencyKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPStoreIdempotencyKeyRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPStoreIdempotencyKeyResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response storeIdempotencyKeyResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPStoreIdempotencyKeyResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func StoreIdempotencyKeyHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/idempotency/store").Handler(httptransport.NewServer(
+		endpoints.StoreIdempotencyKeyEndpoint,
+		DecodeHTTPStoreIdempotencyKeyRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "StoreIdempotencyKey", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) StoreIdempotencyKey(ctx context.Context, key string, response string) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "storeIdempotencyKey",
+			"key", key,
+			"released", len(response) == 0,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.StoreIdempotencyKey(ctx, key, response)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) StoreIdempotencyKey(ctx context.Context, key string, response string) error {
+	err := mw.next.StoreIdempotencyKey(ctx, key, response)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildStoreIdempotencyKeyEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "StoreIdempotencyKey")
+		csLogger := log.With(logger, "method", "StoreIdempotencyKey")
+
+		csEndpoint = StoreIdempotencyKeyEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "StoreIdempotencyKey")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) StoreIdempotencyKey(ctx context.Context, key string, response string) error {
+	request := storeIdempotencyKeyRequest{Key: key, Response: response}
+	stored, err := e.StoreIdempotencyKeyEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error StoreIdempotencyKey : ", err.Error())
+		return err
+	}
+	return str2err(stored.(storeIdempotencyKeyResponse).Err)
+}
+
+func ClientStoreIdempotencyKey(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/idempotency/store"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPStoreIdempotencyKeyResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "StoreIdempotencyKey")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -1,4 +1,77 @@
 package svcdb
 
-const timeForm = "Jan 2, 2006 at 3:04pm (MST)"
-const timeFormFucked = "2001-12-31T23:59:59Z"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// storeForm is how times are stored in the graph : ISO-8601 in UTC with a
+// fixed width, so that comparing stored times as strings compares the times
+const storeForm = "2006-01-02T15:04:05.000Z07:00"
+
+// legacyTimeForm is the display format times used to be stored in, only read
+// back from nodes that were not migrated yet
+const legacyTimeForm = "Jan 2, 2006 at 3:04pm (MST)"
+
+var TimeErr = errors.New("Invalid time, expected RFC3339")
+
+// formatTime is the stored form of t
+func formatTime(t time.Time) string {
+	return t.UTC().Format(storeForm)
+}
+
+// parseTime reads a stored time, falling back on the legacy display format
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(legacyTimeForm, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, TimeErr
+}
+
+// propertyTime reads the time stored in a node or relation property, a
+// missing property being the zero time
+func propertyTime(from string, properties map[string]interface{}, key string) time.Time {
+	return storedTime(from, key, properties[key])
+}
+
+// storedTime reads a time returned by a request, logging the values that
+// cannot be parsed instead of silently using the zero time
+func storedTime(from, key string, value interface{}) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	str, ok := value.(string)
+	if !ok {
+		fmt.Println(from + " (" + key + ") : " + TimeErr.Error())
+		return time.Time{}
+	}
+	t, err := parseTime(str)
+	if err != nil {
+		fmt.Println(from + " (" + key + ") : " + err.Error() + ", got " + str)
+	}
+	return t
+}
+
+// jsonTime is the RFC3339 form of t, empty for the zero time
+func jsonTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseJSONTime reads an RFC3339 time, empty being the zero time
+func parseJSONTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, TimeErr
+	}
+	return t, nil
+}
@@ -0,0 +1,148 @@
+package svcdb
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+)
+
+// Kinds of token subjects
+const (
+	SubjectUser = "user"
+	SubjectPro  = "pro"
+)
+
+// Purposes of the account tokens
+const (
+	PurposeResetPassword = "reset_password"
+	PurposeVerifyEmail   = "verify_email"
+)
+
+var (
+	RefreshTokenErr = errors.New("The refresh token is invalid, expired or already used")
+	SubjectErr      = errors.New("Invalid token subject")
+	AccountTokenErr = errors.New("The token is invalid, expired or already used")
+)
+
+// Session is what the client gets when logging in or refreshing : a short
+// lived access token, and the refresh token to get the next one
+type Session struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+// RefreshToken is a stored refresh token. Only the hash of the token given to
+// the client is stored, Used is set once it was exchanged for a new one.
+type RefreshToken struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Expires time.Time `json:"expires"`
+	Used    bool      `json:"used"`
+}
+
+func (t *RefreshToken) NodeToRefreshToken(node graph.Node) {
+	t.Hash = node.Properties["Hash"].(string)
+	t.Subject = node.Properties["Subject"].(string)
+	t.Expires = propertyTime("NodeToRefreshToken", node.Properties, "Expires")
+	t.Used, _ = node.Properties["Used"].(bool)
+}
+
+// Revocation revokes the access token TokenID of Subject or, when TokenID is
+// empty, every token of Subject issued before the second of Before : the iat
+// of the tokens is in whole seconds, so the ones issued in that second, e.g.
+// by a login right after, are kept. It is kept until Until, when the tokens
+// it revokes have expired anyway.
+type Revocation struct {
+	Subject string    `json:"subject"`
+	TokenID string    `json:"tokenID,omitempty"`
+	Before  time.Time `json:"before"`
+	Until   time.Time `json:"until"`
+}
+
+// AccountToken is a stored token mailed to prove the ownership of Email, to
+// reset the password or verify the email of Subject. Only its hash is stored,
+// it is deleted once used.
+type AccountToken struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Purpose string    `json:"purpose"`
+	Email   string    `json:"email"`
+	Expires time.Time `json:"expires"`
+}
+
+func (t *AccountToken) NodeToAccountToken(node graph.Node) {
+	t.Hash = node.Properties["Hash"].(string)
+	t.Subject = node.Properties["Subject"].(string)
+	t.Purpose = node.Properties["Purpose"].(string)
+	t.Email, _ = node.Properties["Email"].(string)
+	t.Expires = propertyTime("NodeToAccountToken", node.Properties, "Expires")
+}
+
+// TokenSubject names the owner of a token, as "user:42"
+func TokenSubject(kind string, id int64) string {
+	return kind + ":" + strconv.FormatInt(id, 10)
+}
+
+// ParseTokenSubject splits a subject built by TokenSubject
+func ParseTokenSubject(subject string) (string, int64, error) {
+	parts := strings.SplitN(subject, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, SubjectErr
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, SubjectErr
+	}
+	return parts[0], id, nil
+}
+
+// subjectLabel is the label of the nodes of the subjects of kind
+func subjectLabel(kind string) (string, error) {
+	switch kind {
+	case SubjectUser:
+		return "USER", nil
+	case SubjectPro:
+		return "PRO", nil
+	}
+	return "", SubjectErr
+}
+
+// NewRefreshToken draws a refresh token valid for duration. The token goes to
+// the client, the RefreshToken holding its hash to svcdb.
+func NewRefreshToken(subject string, duration time.Duration) (string, RefreshToken, error) {
+	var stored RefreshToken
+
+	token, err := RandomToken()
+	if err != nil {
+		return "", stored, err
+	}
+	stored = RefreshToken{
+		Hash:    HashToken(token),
+		Subject: subject,
+		Expires: time.Now().Add(duration),
+	}
+	return token, stored, nil
+}
+
+// RandomToken draws 32 random bytes, URL safe
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken is the form a token given to a client is stored in
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
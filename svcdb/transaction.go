@@ -0,0 +1,60 @@
+package svcdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+)
+
+// Transaction runs fn inside a single Neo4j transaction on conn : everything
+// fn runs through conn is committed together, or rolled back as soon as fn
+// returns an error
+func Transaction(conn bolt.Conn, fn func(conn bolt.Conn) error) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return DriverErr{Op: "Transaction (Begin)", Err: err}
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				fmt.Println("Transaction (Rollback) : " + err.Error())
+			}
+		}
+	}()
+
+	if err = fn(conn); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return DriverErr{Op: "Transaction (Commit)", Err: err}
+	}
+	committed = true
+	return nil
+}
+
+// ExecBatch runs each request with its args, in order, and returns the first
+// row of each. A request matching nothing fails with io.EOF, so that an order
+// never gets written halfway once run inside a Transaction.
+func ExecBatch(conn bolt.Conn, reqs []string, argss []map[string]interface{}) ([][]interface{}, error) {
+	var rows [][]interface{}
+
+	if len(reqs) != len(argss) {
+		return rows, errors.New("ExecBatch : requests and arguments count mismatch")
+	}
+
+	for i := range reqs {
+		data, _, _, err := conn.QueryNeoAll(reqs[i], argss[i])
+		if err != nil {
+			return rows, err
+		} else if len(data) == 0 {
+			return rows, io.EOF
+		}
+		rows = append(rows, data[0])
+	}
+	return rows, nil
+}
@@ -40,6 +40,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	/* Friends */
 	GetUserFriendsHTTPHandler(endpoints, tracer, logger, r, options)
 	GetUsersInvitationsHTTPHandler(endpoints, tracer, logger, r, options)
+	GetInvitationHTTPHandler(endpoints, tracer, logger, r, options)
 	InviteFriendHTTPHandler(endpoints, tracer, logger, r, options)
 	InvitationAcceptHTTPHandler(endpoints, tracer, logger, r, options)
 	InvitationDeclineHTTPHandler(endpoints, tracer, logger, r, options)
@@ -51,6 +52,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	GetProHTTPHandler(endpoints, tracer, logger, r, options)
 	GetProByIDStripeHTTPHandler(endpoints, tracer, logger, r, options)
 	UpdateProHTTPHandler(endpoints, tracer, logger, r, options)
+	UpdateProStripeStatusHTTPHandler(endpoints, tracer, logger, r, options)
 	GetProByIDHTTPHandler(endpoints, tracer, logger, r, options)
 	GetProBySoireeHTTPHandler(endpoints, tracer, logger, r, options)
 	GetProEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
@@ -59,6 +61,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	CreateEstablishmentHTTPHandler(endpoints, tracer, logger, r, options)
 	GetEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
 	SearchEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
+	SearchNearbyHTTPHandler(endpoints, tracer, logger, r, options)
 	GetEstablishmentHTTPHandler(endpoints, tracer, logger, r, options)
 	GetEstablishmentFromMenuHTTPHandler(endpoints, tracer, logger, r, options)
 	GetMenuFromEstablishmentHTTPHandler(endpoints, tracer, logger, r, options)
@@ -86,6 +89,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	CreateMenuHTTPHandler(endpoints, tracer, logger, r, options)
 	CreateConsoHTTPHandler(endpoints, tracer, logger, r, options)
 	GetMenuFromSoireeHTTPHandler(endpoints, tracer, logger, r, options)
+	ImportMenusHTTPHandler(endpoints, tracer, logger, r, options)
 
 	/* Groups */
 	GetGroupHTTPHandler(endpoints, tracer, logger, r, options)
@@ -106,7 +110,13 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	PutOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	AnswerOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	FailOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	CancelOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	RequestCancelHTTPHandler(endpoints, tracer, logger, r, options)
+	GetPendingOrdersHTTPHandler(endpoints, tracer, logger, r, options)
 	UpdateOrderReferenceHTTPHandler(endpoints, tracer, logger, r, options)
+	GetOrderByReferenceHTTPHandler(endpoints, tracer, logger, r, options)
+	GetChargedOrdersHTTPHandler(endpoints, tracer, logger, r, options)
+	FlagOrderHTTPHandler(endpoints, tracer, logger, r, options)
 
 	GetConsoByOrderIDHTTPHandler(endpoints, tracer, logger, r, options)
 	GetOrdersBySoireeHTTPHandler(endpoints, tracer, logger, r, options)
@@ -124,6 +134,33 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 	/* Analyse */
 	GetAnalysePHTTPHandler(endpoints, tracer, logger, r, options)
+	GetAnalyseCHTTPHandler(endpoints, tracer, logger, r, options)
+	GetAnalyseFHTTPHandler(endpoints, tracer, logger, r, options)
+	GetMenuStatHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Webhook */
+	ClaimWebhookEventHTTPHandler(endpoints, tracer, logger, r, options)
+	ReleaseWebhookEventHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Idempotency */
+	ClaimIdempotencyKeyHTTPHandler(endpoints, tracer, logger, r, options)
+	StoreIdempotencyKeyHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Ledger */
+	AddLedgerEntryHTTPHandler(endpoints, tracer, logger, r, options)
+	GetLedgerHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Tokens */
+	CreateRefreshTokenHTTPHandler(endpoints, tracer, logger, r, options)
+	UseRefreshTokenHTTPHandler(endpoints, tracer, logger, r, options)
+	RevokeTokensHTTPHandler(endpoints, tracer, logger, r, options)
+	IsTokenRevokedHTTPHandler(endpoints, tracer, logger, r, options)
+	CreateAccountTokenHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Accounts */
+	GetAccountByEmailHTTPHandler(endpoints, tracer, logger, r, options)
+	ResetPasswordHTTPHandler(endpoints, tracer, logger, r, options)
+	VerifyEmailHTTPHandler(endpoints, tracer, logger, r, options)
 
 	return r
 }
@@ -24,7 +24,7 @@ import (
 func (s Service) UpdateEstablishment(c context.Context, new Establishment) (Establishment, error) {
 	var estab Establishment
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdateEstablishment (WaitConnection) : " + err.Error())
 		return estab, err
@@ -23,7 +23,7 @@ import (
 func (s Service) UpdateGroup(c context.Context, new Group) (Group, error) {
 	var group Group
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdateGroup (WaitConnection) : " + err.Error())
 		return group, err
@@ -22,7 +22,7 @@ import (
 /*************** Service ***************/
 func (s Service) UpdateOrderReference(c context.Context, orderID int64, userID int64, reference string) (error) {
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdateOrderReference (WaitConnection) : " + err.Error())
 		return err
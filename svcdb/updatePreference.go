@@ -24,7 +24,7 @@ import (
 func (s Service) UpdatePreference(c context.Context, userID int64, preferences []string) ([]string, error) {
 	IPrefs := StrArrayToIArray(preferences)
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdatePreference (WaitConnection) : " + err.Error())
 		return preferences, err
@@ -68,7 +68,7 @@ func (s Service) UpdatePreference(c context.Context, userID int64, preferences [
 /*************** Service ***************/
 func DeletePreferences(c context.Context, userID int64) error {
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("DeletePreferences (WaitConnection) : " + err.Error())
 		return err
@@ -24,7 +24,7 @@ import (
 func (s Service) UpdatePro(c context.Context, new Pro) (Pro, error) {
 	var pro Pro
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdatePro (WaitConnection) : " + err.Error())
 		return pro, err
@@ -39,13 +39,24 @@ func (s Service) UpdatePro(c context.Context, new Pro) (Pro, error) {
 
 	pro.UpdatePro(new)
 
+	// the stored password is only replaced when a new one is given
+	var password interface{}
+	if len(new.Password) > 0 {
+		if password, err = hashPassword(new.Password); err != nil {
+			fmt.Println("UpdatePro (hashPassword) : " + err.Error())
+			return pro, err
+		}
+	}
+
+	// a new email is not verified, until the link mailed to it is used
 	stmt, err := conn.PrepareNeo(`
 	MATCH (n:PRO)
 	WHERE ID(n) = {ID}
 	SET
+		n.Verified = (n.Email = {Email} AND coalesce(n.Verified, false)),
 		n.Email = {Email},
 		n.Pseudo = {Pseudo},
-		n.Password = {Password},
+		n.Password = coalesce({Password}, n.Password),
 		n.Firstname = {Firstname},
 		n.Surname = {Surname},
 		n.Number = {Number}
@@ -58,11 +69,10 @@ func (s Service) UpdatePro(c context.Context, new Pro) (Pro, error) {
 	}
 
 	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"ID":       pro.ID,
-		"Email":    pro.Email,
-		"Pseudo":   pro.Pseudo,
-		"Password": pro.Password,
-		// "Password":   encryptPassword(u.Password),
+		"ID":        pro.ID,
+		"Email":     pro.Email,
+		"Pseudo":    pro.Pseudo,
+		"Password":  password,
 		"Firstname": pro.Firstname,
 		"Surname":   pro.Surname,
 		"Number":    pro.Number,
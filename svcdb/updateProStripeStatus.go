@@ -0,0 +1,169 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// UpdateProStripeStatus sets the status of the pro owning the connected
+// account stripeID, a pro with ID 0 being returned when there is none
+func (s Service) UpdateProStripeStatus(ctx context.Context, stripeID string, status string) (Pro, error) {
+	var pro Pro
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("UpdateProStripeStatus (WaitConnection) : " + err.Error())
+		return pro, err
+	}
+	defer CloseConnection(conn)
+
+	data, _, _, err := conn.QueryNeoAll(`
+		MATCH (p:PRO {StripeID: {stripeID}})
+		SET p.StripeStatus = {status}
+		RETURN p`, map[string]interface{}{
+		"stripeID": stripeID,
+		"status":   status,
+	})
+	if err != nil {
+		fmt.Println("UpdateProStripeStatus (QueryNeoAll) : " + err.Error())
+		return pro, err
+	}
+	if len(data) > 0 {
+		(&pro).NodeToPro(data[0][0].(graph.Node))
+	}
+	return pro, nil
+}
+
+/*************** Endpoint ***************/
+type updateProStripeStatusRequest struct {
+	StripeID string `json:"stripeid"`
+	Status   string `json:"status"`
+}
+
+type updateProStripeStatusResponse struct {
+	Pro Pro    `json:"pro"`
+	Err string `json:"err,omitempty"`
+}
+
+func UpdateProStripeStatusEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateProStripeStatusRequest)
+		pro, err := svc.UpdateProStripeStatus(ctx, req.StripeID, req.Status)
+		if err != nil {
+			fmt.Println("Error UpdateProStripeStatusEndpoint : ", err.Error())
+			return updateProStripeStatusResponse{Pro: pro, Err: err.Error()}, nil
+		}
+		return updateProStripeStatusResponse{Pro: pro, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPUpdateProStripeStatusRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request updateProStripeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPUpdateProStripeStatusRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPUpdateProStripeStatusResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response updateProStripeStatusResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPUpdateProStripeStatusResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func UpdateProStripeStatusHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/pros/stripe/status").Handler(httptransport.NewServer(
+		endpoints.UpdateProStripeStatusEndpoint,
+		DecodeHTTPUpdateProStripeStatusRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateProStripeStatus", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) UpdateProStripeStatus(ctx context.Context, stripeID string, status string) (Pro, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "updateProStripeStatus",
+			"stripeID", stripeID,
+			"status", status,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.UpdateProStripeStatus(ctx, stripeID, status)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) UpdateProStripeStatus(ctx context.Context, stripeID string, status string) (Pro, error) {
+	v, err := mw.next.UpdateProStripeStatus(ctx, stripeID, status)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildUpdateProStripeStatusEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "UpdateProStripeStatus")
+		csLogger := log.With(logger, "method", "UpdateProStripeStatus")
+
+		csEndpoint = UpdateProStripeStatusEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "UpdateProStripeStatus")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) UpdateProStripeStatus(ctx context.Context, stripeID string, status string) (Pro, error) {
+	var pro Pro
+
+	request := updateProStripeStatusRequest{StripeID: stripeID, Status: status}
+	response, err := e.UpdateProStripeStatusEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error UpdateProStripeStatus : ", err.Error())
+		return pro, err
+	}
+	return response.(updateProStripeStatusResponse).Pro, str2err(response.(updateProStripeStatusResponse).Err)
+}
+
+func ClientUpdateProStripeStatus(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/pros/stripe/status"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPUpdateProStripeStatusResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "UpdateProStripeStatus")(ceEndpoint)
+	return ceEndpoint, nil
+}
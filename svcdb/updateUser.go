@@ -24,7 +24,7 @@ import (
 func (s Service) UpdateUser(c context.Context, new User) (User, error) {
 	var user User
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(c, 5)
 	if err != nil {
 		fmt.Println("UpdateUser (WaitConnection) : " + err.Error())
 		return user, err
@@ -40,13 +40,24 @@ func (s Service) UpdateUser(c context.Context, new User) (User, error) {
 
 	user.UpdateUser(new)
 
+	// the stored password is only replaced when a new one is given
+	var password interface{}
+	if len(new.Password) > 0 {
+		if password, err = hashPassword(new.Password); err != nil {
+			fmt.Println("UpdateUser (hashPassword) : " + err.Error())
+			return user, err
+		}
+	}
+
+	// a new email is not verified, until the link mailed to it is used
 	stmt, err := conn.PrepareNeo(`
 	MATCH (n:USER)
 	WHERE ID(n) = {ID}
 	SET
+		n.Verified = (n.Email = {Email} AND coalesce(n.Verified, false)),
 		n.Email = {Email},
 		n.Pseudo = {Pseudo},
-		n.Password = {Password},
+		n.Password = coalesce({Password}, n.Password),
 		n.Birthdate = {Birthdate},
 		n.Firstname = {Firstname},
 		n.Surname = {Surname},
@@ -63,12 +74,11 @@ func (s Service) UpdateUser(c context.Context, new User) (User, error) {
 	}
 
 	rows, err := stmt.QueryNeo(map[string]interface{}{
-		"ID":       user.ID,
-		"Email":    user.Email,
-		"Pseudo":   user.Pseudo,
-		"Password": user.Password,
-		// "Password":   encryptPassword(u.Password),
-		"Birthdate":     user.Birthdate.Format(timeForm),
+		"ID":            user.ID,
+		"Email":         user.Email,
+		"Pseudo":        user.Pseudo,
+		"Password":      password,
+		"Birthdate":     formatTime(user.Birthdate),
 		"Firstname":     user.Firstname,
 		"Surname":       user.Surname,
 		"Number":        user.Number,
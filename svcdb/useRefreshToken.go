@@ -0,0 +1,196 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+	"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// UseRefreshToken exchanges the refresh token of hash for next, stored for the
+// same subject, and returns the used one. A token used twice was stolen : every
+// token of its subject is revoked, until being when its access tokens expire.
+func (s Service) UseRefreshToken(ctx context.Context, hash string, next RefreshToken, until time.Time) (RefreshToken, error) {
+	var used RefreshToken
+	var reused bool
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("UseRefreshToken (WaitConnection) : " + err.Error())
+		return used, err
+	}
+	defer CloseConnection(conn)
+
+	now := time.Now()
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		data, _, _, err := conn.QueryNeoAll(`
+			MATCH (t:REFRESH_TOKEN {Hash: {hash}}) WHERE t.Expires >= {now}
+			WITH t, t.Used AS used
+			SET t.Used = true
+			RETURN t, used`, map[string]interface{}{
+			"hash": hash,
+			"now":  formatTime(now),
+		})
+		if err != nil {
+			return err
+		} else if len(data) == 0 {
+			return RefreshTokenErr
+		}
+		(&used).NodeToRefreshToken(data[0][0].(graph.Node))
+
+		if reused, _ = data[0][1].(bool); reused {
+			return revokeSubject(conn, Revocation{Subject: used.Subject, Before: now, Until: until})
+		}
+		_, err = conn.ExecNeo(`
+			CREATE (:REFRESH_TOKEN {Hash: {hash}, Subject: {subject}, Expires: {expires}, Used: false})`, map[string]interface{}{
+			"hash":    next.Hash,
+			"subject": used.Subject,
+			"expires": formatTime(next.Expires),
+		})
+		return err
+	})
+	if err != nil {
+		fmt.Println("UseRefreshToken (Transaction) : " + err.Error())
+		return used, err
+	}
+	if reused {
+		return used, RefreshTokenErr
+	}
+	return used, nil
+}
+
+/*************** Endpoint ***************/
+type useRefreshTokenRequest struct {
+	Hash  string       `json:"hash"`
+	Next  RefreshToken `json:"next"`
+	Until time.Time    `json:"until"`
+}
+
+type useRefreshTokenResponse struct {
+	Used RefreshToken `json:"used"`
+	Err  string       `json:"err,omitempty"`
+}
+
+func UseRefreshTokenEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(useRefreshTokenRequest)
+		used, err := svc.UseRefreshToken(ctx, req.Hash, req.Next, req.Until)
+		if err != nil {
+			fmt.Println("Error UseRefreshTokenEndpoint : ", err.Error())
+			return useRefreshTokenResponse{Used: used, Err: err.Error()}, nil
+		}
+		return useRefreshTokenResponse{Used: used, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPUseRefreshTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request useRefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPUseRefreshTokenRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPUseRefreshTokenResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response useRefreshTokenResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPUseRefreshTokenResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func UseRefreshTokenHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/tokens/refresh/use").Handler(httptransport.NewServer(
+		endpoints.UseRefreshTokenEndpoint,
+		DecodeHTTPUseRefreshTokenRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "UseRefreshToken", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) UseRefreshToken(ctx context.Context, hash string, next RefreshToken, until time.Time) (used RefreshToken, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "useRefreshToken",
+			"subject", used.Subject,
+			"error", err,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.UseRefreshToken(ctx, hash, next, until)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) UseRefreshToken(ctx context.Context, hash string, next RefreshToken, until time.Time) (RefreshToken, error) {
+	v, err := mw.next.UseRefreshToken(ctx, hash, next, until)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildUseRefreshTokenEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "UseRefreshToken")
+		csLogger := log.With(logger, "method", "UseRefreshToken")
+
+		csEndpoint = UseRefreshTokenEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "UseRefreshToken")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) UseRefreshToken(ctx context.Context, hash string, next RefreshToken, until time.Time) (RefreshToken, error) {
+	var used RefreshToken
+
+	request := useRefreshTokenRequest{Hash: hash, Next: next, Until: until}
+	response, err := e.UseRefreshTokenEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error UseRefreshToken : ", err.Error())
+		return used, err
+	}
+	r := response.(useRefreshTokenResponse)
+	return r.Used, str2err(r.Err)
+}
+
+func ClientUseRefreshToken(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/tokens/refresh/use"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPUseRefreshTokenResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "UseRefreshToken")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -12,7 +12,7 @@ type User struct {
 	ID            int64     `json:"id"`
 	Email         string    `json:"email"`
 	Pseudo        string    `json:"pseudo"`
-	Password      string    `json:"password"`
+	Password      string    `json:"password,omitempty"` // only sent to svcdb, never returned
 	Birthdate     time.Time `json:"birthdate,omitempty"`
 	Firstname     string    `json:"firstname,omitempty"`
 	Surname       string    `json:"surname,omitempty"`
@@ -21,6 +21,7 @@ type User struct {
 	SuccessPoints int64     `json:"success_points,omitempty"`
 	ConnectedTo   []User    `json:"connected_to,omitempty"`
 	StripeID      string    `json:"stripeID,omitempty"`
+	Verified      bool      `json:"verified"` // set by svcdb only, once the email is verified
 }
 
 // Users User array
@@ -30,7 +31,8 @@ func (u *User) NodeToUser(node graph.Node) {
 	u.ID = node.NodeIdentity
 	u.Email = node.Properties["Email"].(string)
 	u.Pseudo = node.Properties["Pseudo"].(string)
-	u.Password = node.Properties["Password"].(string)
+	u.Password = "" // the stored hash never leaves svcdb
+	u.Verified, _ = node.Properties["Verified"].(bool)
 
 	if node.Properties["Fistname"] != nil {
 		u.Firstname = node.Properties["Firstname"].(string)
@@ -50,9 +52,7 @@ func (u *User) NodeToUser(node graph.Node) {
 	// if node.Properties["ConnectedTo"] != nil {
 	// 	u.ConnectedTo = node.Properties["ConnectedTo"].(string)
 	// }
-	if node.Properties["Birthdate"] != nil {
-		u.Birthdate, _ = time.Parse(timeForm, node.Properties["Birthdate"].(string))
-	}
+	u.Birthdate = propertyTime("NodeToUser", node.Properties, "Birthdate")
 	if node.Properties["StripeID"] != nil {
 		u.StripeID = node.Properties["StripeID"].(string)
 	}
@@ -66,7 +66,7 @@ func (u *User) MarshalJSON() ([]byte, error) {
 		Birthdate string `json:"birthdate"`
 	}{
 		Alias:     (*Alias)(u),
-		Birthdate: u.Birthdate.Format(timeForm),
+		Birthdate: jsonTime(u.Birthdate),
 	})
 }
 
@@ -83,7 +83,11 @@ func (u *User) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	u.Birthdate, _ = time.Parse(timeForm, aux.Birthdate)
+	birthdate, err := parseJSONTime(aux.Birthdate)
+	if err != nil {
+		return err
+	}
+	u.Birthdate = birthdate
 	return nil
 }
 
@@ -123,8 +127,14 @@ func (u *User) UpdateUser(new User) {
 	}
 }
 
+// Redacted returns a copy of u without its password, safe to log
+func (u User) Redacted() User {
+	u.Password = ""
+	return u
+}
+
 func (u *User) Complete() bool {
-	if u.ID != 0 && u.Email != "" && u.Pseudo != "" && u.Password != "" &&
+	if u.ID != 0 && u.Email != "" && u.Pseudo != "" &&
 		u.Firstname != "" && u.Surname != "" && u.Number != "" {
 		return true
 	}
@@ -20,10 +20,10 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) UserLeaveSoiree(_ context.Context, userID int64, soireeID int64) (Soiree, bool, error) {
+func (s Service) UserLeaveSoiree(ctx context.Context, userID int64, soireeID int64) (Soiree, bool, error) {
 	var soiree Soiree
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("UserLeaveSoiree (WaitConnection) : " + err.Error())
 		return soiree, false, err
@@ -44,7 +44,7 @@ func (s Service) UserLeaveSoiree(_ context.Context, userID int64, soireeID int64
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"uid":  userID,
 		"sid":  soireeID,
-		"date": time.Now().Format(timeForm),
+		"date": formatTime(time.Now()),
 	})
 
 	if err != nil {
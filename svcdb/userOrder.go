@@ -20,9 +20,9 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) UserOrder(_ context.Context, user User, soiree Soiree, conso Conso) (int64, error) {
+func (s Service) UserOrder(ctx context.Context, user User, soiree Soiree, conso Conso) (int64, error) {
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("UserOrder (WaitConnection) : " + err.Error())
 		return 0, err
@@ -56,7 +56,7 @@ func (s Service) UserOrder(_ context.Context, user User, soiree Soiree, conso Co
 		"sid":       soiree.ID,
 		"cid":       conso.ID,
 		"price":     conso.Price,
-		"created":   time.Now().Format(timeForm),
+		"created":   formatTime(time.Now()),
 		"reference": "",
 		"paid":      nil,
 		"delivered": nil,
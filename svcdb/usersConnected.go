@@ -20,9 +20,9 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) UsersConnected(_ context.Context, userID, friendID int64) (bool, error) {
+func (s Service) UsersConnected(ctx context.Context, userID, friendID int64) (bool, error) {
 
-	conn, err := WaitConnection(5)
+	conn, err := WaitConnection(ctx, 5)
 	if err != nil {
 		fmt.Println("UsersConnected (WaitConnection) : " + err.Error())
 		return false, err
@@ -43,7 +43,7 @@ func (s Service) UsersConnected(_ context.Context, userID, friendID int64) (bool
 	rows, err := stmt.QueryNeo(map[string]interface{}{
 		"userID":   userID,
 		"friendID": friendID,
-		"date":     time.Now().Format(timeForm),
+		"date":     formatTime(time.Now()),
 	})
 
 	if err != nil {
@@ -0,0 +1,187 @@
+package svcdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+// VerifyEmail uses the email verification token of hash : the email it was
+// mailed to is verified, unless the account changed its email since
+func (s Service) VerifyEmail(ctx context.Context, hash string) (AccountToken, error) {
+	var token AccountToken
+
+	conn, err := WaitConnection(ctx, 5)
+	if err != nil {
+		fmt.Println("VerifyEmail (WaitConnection) : " + err.Error())
+		return token, err
+	}
+	defer CloseConnection(conn)
+
+	err = Transaction(conn, func(conn bolt.Conn) error {
+		token, err = useAccountToken(conn, hash, PurposeVerifyEmail)
+		if err != nil {
+			return err
+		}
+		kind, id, err := ParseTokenSubject(token.Subject)
+		if err != nil {
+			return err
+		}
+		label, err := subjectLabel(kind)
+		if err != nil {
+			return err
+		}
+
+		data, _, _, err := conn.QueryNeoAll(`
+			MATCH (n:`+label+`) WHERE ID(n) = {id} AND n.Email = {email}
+			SET n.Verified = true
+			RETURN ID(n)`, map[string]interface{}{
+			"id":    id,
+			"email": token.Email,
+		})
+		if err != nil {
+			return err
+		} else if len(data) == 0 {
+			return AccountTokenErr
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("VerifyEmail (Transaction) : " + err.Error())
+		return token, err
+	}
+	return token, nil
+}
+
+/*************** Endpoint ***************/
+type verifyEmailRequest struct {
+	Hash string `json:"hash"`
+}
+
+type verifyEmailResponse struct {
+	Token AccountToken `json:"token"`
+	Err   string       `json:"err,omitempty"`
+}
+
+func VerifyEmailEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(verifyEmailRequest)
+		token, err := svc.VerifyEmail(ctx, req.Hash)
+		if err != nil {
+			fmt.Println("Error VerifyEmailEndpoint : ", err.Error())
+			return verifyEmailResponse{Token: token, Err: err.Error()}, nil
+		}
+		return verifyEmailResponse{Token: token, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPVerifyEmailRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request verifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPVerifyEmailRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPVerifyEmailResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response verifyEmailResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPVerifyEmailResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func VerifyEmailHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/accounts/verify").Handler(httptransport.NewServer(
+		endpoints.VerifyEmailEndpoint,
+		DecodeHTTPVerifyEmailRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "VerifyEmail", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) VerifyEmail(ctx context.Context, hash string) (token AccountToken, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "verifyEmail",
+			"subject", token.Subject,
+			"error", err,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.VerifyEmail(ctx, hash)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) VerifyEmail(ctx context.Context, hash string) (AccountToken, error) {
+	v, err := mw.next.VerifyEmail(ctx, hash)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildVerifyEmailEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "VerifyEmail")
+		csLogger := log.With(logger, "method", "VerifyEmail")
+
+		csEndpoint = VerifyEmailEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "VerifyEmail")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) VerifyEmail(ctx context.Context, hash string) (AccountToken, error) {
+	var token AccountToken
+
+	response, err := e.VerifyEmailEndpoint(ctx, verifyEmailRequest{Hash: hash})
+	if err != nil {
+		fmt.Println("Error VerifyEmail : ", err.Error())
+		return token, err
+	}
+	r := response.(verifyEmailResponse)
+	return r.Token, str2err(r.Err)
+}
+
+func ClientVerifyEmail(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/accounts/verify"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPVerifyEmailResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "VerifyEmail")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,116 @@
+package svcdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkflowStep is a step of the order workflow. An order whose step stays open
+// longer than Timeout is expired, no Timeout meaning it may wait forever.
+type WorkflowStep struct {
+	Name    string        `json:"name"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Workflow lists the steps an order goes through, in order
+type Workflow []WorkflowStep
+
+// OrderWorkflow is the workflow of every order, used by svcdb to open the
+// steps and by svcpayment to run them and sweep the expired orders.
+// Stripe releases uncaptured charges after 7 days, so Verified and Ready must
+// time out well before : charges are only captured when Deliverpaid opens.
+var OrderWorkflow = Workflow{
+	{Name: "Issued", Timeout: 5 * time.Minute},
+	{Name: "Confirmed", Timeout: 10 * time.Minute},
+	{Name: "Verified", Timeout: 10 * time.Minute},
+	{Name: "Ready", Timeout: 2 * time.Hour},
+	{Name: "Deliverpaid"},
+	{Name: "Completed"},
+}
+
+// Step returns the workflow step of that name
+func (w Workflow) Step(name string) (WorkflowStep, bool) {
+	for _, step := range w {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return WorkflowStep{}, false
+}
+
+// Next returns the step following name, "" for the last one
+func (w Workflow) Next(name string) string {
+	for i, step := range w {
+		if step.Name == name && i+1 < len(w) {
+			return w[i+1].Name
+		}
+	}
+	return ""
+}
+
+// Index returns the position of the step of that name, -1 if unknown
+func (w Workflow) Index(name string) int {
+	for i, step := range w {
+		if step.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// OpenStep returns the step of a running order waiting for its result
+func (w Workflow) OpenStep(order Order) (StepOrder, bool) {
+	if len(order.Done) > 0 {
+		return StepOrder{}, false
+	}
+	for _, step := range order.Steps {
+		if len(step.Result) == 0 {
+			return step, true
+		}
+	}
+	return StepOrder{}, false
+}
+
+// Expired tells whether the open step of the order timed out at now
+func (w Workflow) Expired(order Order, now time.Time) (StepOrder, bool) {
+	open, ok := w.OpenStep(order)
+	if !ok {
+		return open, false
+	}
+	step, ok := w.Step(open.Name)
+	if !ok || step.Timeout <= 0 || open.Date.IsZero() {
+		return open, false
+	}
+	return open, now.Sub(open.Date) > step.Timeout
+}
+
+// SetTimeouts overrides timeouts from a "Confirmed=10m,Ready=1h" list, a zero
+// duration removing the timeout of the step
+func (w Workflow) SetTimeouts(spec string) error {
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid step timeout %q, expected Step=duration", item)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("Invalid step timeout %q", item)
+		}
+		name := strings.Title(strings.ToLower(strings.TrimSpace(parts[0])))
+		found := false
+		for i := range w {
+			if w[i].Name == name {
+				w[i].Timeout, found = timeout, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("Unknown order step %q", parts[0])
+		}
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ func AccessControl(h http.Handler) http.Handler {
 		setDefaultHeaders(w)
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, Idempotency-Key")
 
 		if r.Method == "OPTIONS" {
 			return
@@ -0,0 +1,193 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+	"svcpayment"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// AnswerCancel accepts or declines the cancellation a customer asked for once
+// the order went past svcpayment.LastCancellableStep
+func (s Service) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	order, err := s.svcpayment.AnswerCancel(ctx, orderID, accept)
+	if err != nil && err.Error() == svcpayment.NoCancelRequestErr.Error() {
+		return order, NoCancelRequestErr
+	}
+	return order, dbToHTTPErr(err)
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type AnswerCancelRequest struct {
+	OrderID int64 `json:"orderID"`
+	Accept  bool  `json:"accept"`
+}
+type AnswerCancelResponse struct {
+	Order svcdb.Order `json:"order"`
+}
+
+/* Endpoint - Create endpoint */
+func AnswerCancelEndpoint(s IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		csReq := request.(AnswerCancelRequest)
+		order, err := s.AnswerCancel(ctx, csReq.OrderID, csReq.Accept)
+		return AnswerCancelResponse{
+			Order: order,
+		}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPAnswerCancelRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req AnswerCancelRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPAnswerCancelRequest 1 : ", err.Error())
+		return nil, RequestError
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["OrderID"], 10, 64)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPAnswerCancelRequest 2 : ", err.Error())
+		return nil, RequestError
+	}
+	(&req).OrderID = orderID
+
+	return req, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPAnswerCancelResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp AnswerCancelResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func AnswerCancelHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/orders/{OrderID:[0-9]+}/cancel").Handler(httptransport.NewServer(
+		endpoints.AnswerCancelEndpoint,
+		DecodeHTTPAnswerCancelRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerCancel", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	order, err := mw.next.AnswerCancel(ctx, orderID, accept)
+
+	mw.logger.Log(
+		"method", "AnswerCancel",
+		"request", AnswerCancelRequest{OrderID: orderID, Accept: accept},
+		"error", err,
+		"took", time.Since(time.Now()),
+	)
+	return order, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	if err := mw.ownsOrder(ctx, orderID); err != nil {
+		return svcdb.Order{}, err
+	}
+	return mw.next.AnswerCancel(ctx, orderID, accept)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	return mw.next.AnswerCancel(ctx, orderID, accept)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildAnswerCancelEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "AnswerCancel")
+		csLogger := log.With(logger, "method", "AnswerCancel")
+
+		csEndpoint = AnswerCancelEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "AnswerCancel")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	request := AnswerCancelRequest{
+		OrderID: orderID,
+		Accept:  accept,
+	}
+	response, err := e.AnswerCancelEndpoint(ctx, request)
+	if err != nil {
+		return svcdb.Order{}, err
+	}
+	return response.(AnswerCancelResponse).Order, nil
+}
+
+func EncodeHTTPAnswerCancelRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(AnswerCancelRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("OrderID", fmt.Sprintf("%v", req.OrderID))
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	return EncodeHTTPGenericRequest(ctx, r, request)
+}
+
+func ClientAnswerCancel(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var gefmEndpoint endpoint.Endpoint
+
+	gefmEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/orders/{OrderID}/cancel"),
+		EncodeHTTPAnswerCancelRequest,
+		DecodeHTTPAnswerCancelResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	gefmEndpoint = opentracing.TraceClient(tracer, "AnswerCancel")(gefmEndpoint)
+	return gefmEndpoint, nil
+}
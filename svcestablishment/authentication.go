@@ -1,41 +1,328 @@
 package svcestablishment
 
 import (
+	"context"
+	"crypto/rsa"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"strconv"
+	"time"
+
+	stdjwt "github.com/dgrijalva/jwt-go"
+
+	"svcdb"
 )
 
 const (
 	privateKeyPath = "./config/app"
-	publicKeyPath  = "./config/app.pub"
 )
 
-var verifKey, signKey []byte
+// Roles carried by the tokens
+const (
+	RolePro = "pro"
+)
 
-// InitKey Initialize the key used in authentication thanks to a rsa key saved
-func InitKey() {
-	var err error
+// Lifetimes of the access tokens and of the refresh tokens of a session
+const (
+	tokenDuration   = time.Minute * 15
+	refreshDuration = time.Hour * 24 * 30
+)
+
+// Lifetimes of the tokens mailed to reset a password and to verify an email
+const (
+	resetDuration  = time.Hour
+	verifyDuration = time.Hour * 24
+)
+
+var verifKey *rsa.PublicKey
+var signKey *rsa.PrivateKey
+
+// revocations holds the revocation list checked by EndpointAuthenticationMiddleware
+var revocations svcdb.IService
 
-	signKey, err = ioutil.ReadFile(privateKeyPath)
+// InitKey Initialize the key used in authentication thanks to a rsa key saved.
+// The public key is the one of the private key, app.pub being in the ssh format.
+func InitKey() {
+	signBytes, err := ioutil.ReadFile(privateKeyPath)
 	if err != nil {
 		log.Fatal("Error reading private key at : " + privateKeyPath)
 	}
+	signKey, err = stdjwt.ParseRSAPrivateKeyFromPEM(signBytes)
+	if err != nil {
+		log.Fatal("Error parsing private key : " + err.Error())
+	}
+	verifKey = &signKey.PublicKey
+}
+
+// InitRevocationList sets where the revoked tokens are looked up
+func InitRevocationList(db svcdb.IService) {
+	revocations = db
+}
+
+/* Tokens */
+// Claims of the tokens signed by svcestablishment, the subject is the ID of
+// the pro
+type Claims struct {
+	Role string `json:"role"`
+	stdjwt.StandardClaims
+}
+
+// Identity is the authenticated pro, injected in the context of the requests
+// by EndpointAuthenticationMiddleware
+type Identity struct {
+	ProID     int64
+	Role      string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity authenticated for the request
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// NewToken signs a token of role for proID, valid for tokenDuration
+func NewToken(proID int64, role string) (string, time.Time, error) {
+	now := time.Now().UTC()
+	expires := now.Add(tokenDuration)
+
+	tokenID, err := svcdb.RandomToken()
+	if err != nil {
+		return "", expires, err
+	}
+	claims := Claims{
+		Role: role,
+		StandardClaims: stdjwt.StandardClaims{
+			Id:        tokenID,
+			Subject:   strconv.FormatInt(proID, 10),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expires.Unix(),
+			Issuer:    "NightLine",
+		},
+	}
+	token, err := stdjwt.NewWithClaims(stdjwt.SigningMethodRS256, claims).SignedString(signKey)
+	return token, expires, err
+}
+
+// ParseToken verifies the signature and the expiry of token
+func ParseToken(token string) (Identity, error) {
+	var claims Claims
+
+	parsed, err := stdjwt.ParseWithClaims(token, &claims, func(t *stdjwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*stdjwt.SigningMethodRSA); !ok {
+			return nil, TokenError
+		}
+		return verifKey, nil
+	})
+	if err != nil || !parsed.Valid {
+		return Identity{}, TokenError
+	}
+
+	proID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || claims.Role != RolePro || len(claims.Id) == 0 {
+		return Identity{}, TokenError
+	}
+	return Identity{
+		ProID:     proID,
+		Role:      claims.Role,
+		TokenID:   claims.Id,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// checkRevoked fails with TokenError when the token of identity was revoked,
+// by a logout or a logout of all the devices of the pro
+func checkRevoked(ctx context.Context, identity Identity) error {
+	if revocations == nil {
+		return nil
+	}
+	subject := svcdb.TokenSubject(svcdb.SubjectPro, identity.ProID)
+	revoked, err := revocations.IsTokenRevoked(ctx, subject, identity.TokenID, identity.IssuedAt)
+	if err != nil {
+		fmt.Println("checkRevoked (IsTokenRevoked) : " + err.Error())
+		return ConnError
+	} else if revoked {
+		return TokenError
+	}
+	return nil
+}
+
+/* Account tokens */
+// newAccountToken signs the token mailed to email for purpose, single use : it
+// is stored by svcdb until used or expired. The subject of the token is the
+// one of svcdb, the audience its purpose.
+func (s Service) newAccountToken(ctx context.Context, proID int64, email string, purpose string, duration time.Duration) (string, error) {
+	now := time.Now().UTC()
+	expires := now.Add(duration)
+	subject := svcdb.TokenSubject(svcdb.SubjectPro, proID)
+
+	tokenID, err := svcdb.RandomToken()
+	if err != nil {
+		return "", err
+	}
+	claims := stdjwt.StandardClaims{
+		Id:        tokenID,
+		Subject:   subject,
+		Audience:  purpose,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+		Issuer:    "NightLine",
+	}
+	token, err := stdjwt.NewWithClaims(stdjwt.SigningMethodRS256, claims).SignedString(signKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.svcdb.CreateAccountToken(ctx, svcdb.AccountToken{
+		Hash:    svcdb.HashToken(tokenID),
+		Subject: subject,
+		Purpose: purpose,
+		Email:   email,
+		Expires: expires,
+	})
+	if err != nil {
+		fmt.Println("newAccountToken (CreateAccountToken) : " + err.Error())
+		return "", dbToHTTPErr(err)
+	}
+	return token, nil
+}
 
-	verifKey, err = ioutil.ReadFile(publicKeyPath)
+// parseAccountToken verifies token was signed by newAccountToken for purpose
+// and a pro, and returns the hash under which svcdb stores it
+func parseAccountToken(token string, purpose string) (string, error) {
+	var claims stdjwt.StandardClaims
+
+	parsed, err := stdjwt.ParseWithClaims(token, &claims, func(t *stdjwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*stdjwt.SigningMethodRSA); !ok {
+			return nil, svcdb.AccountTokenErr
+		}
+		return verifKey, nil
+	})
+	if err != nil || !parsed.Valid || claims.Audience != purpose || len(claims.Id) == 0 {
+		return "", svcdb.AccountTokenErr
+	}
+	if kind, _, err := svcdb.ParseTokenSubject(claims.Subject); err != nil || kind != svcdb.SubjectPro {
+		return "", svcdb.AccountTokenErr
+	}
+	return svcdb.HashToken(claims.Id), nil
+}
+
+/* Sessions */
+// newSession opens a session of the pro : an access token and the refresh
+// token stored to renew it
+func (s Service) newSession(ctx context.Context, proID int64) (svcdb.Session, error) {
+	var session svcdb.Session
+
+	refresh, stored, err := svcdb.NewRefreshToken(svcdb.TokenSubject(svcdb.SubjectPro, proID), refreshDuration)
+	if err != nil {
+		return session, err
+	}
+	if err = s.svcdb.CreateRefreshToken(ctx, stored); err != nil {
+		fmt.Println("newSession (CreateRefreshToken) : " + err.Error())
+		return session, dbToHTTPErr(err)
+	}
+	return signSession(proID, refresh)
+}
+
+// signSession signs the access token going with the refresh token
+func signSession(proID int64, refresh string) (svcdb.Session, error) {
+	token, expires, err := NewToken(proID, RolePro)
+	if err != nil {
+		return svcdb.Session{}, err
+	}
+	return svcdb.Session{Token: token, RefreshToken: refresh, ExpiresAt: expires.Unix()}, nil
+}
+
+/* Ownership */
+// authenticatedPro returns the pro the request acts for : the authenticated
+// one. proID comes from the request, 0 when it doesn't name one, any other pro
+// is forbidden.
+func authenticatedPro(ctx context.Context, proID int64) (int64, error) {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok || identity.Role != RolePro {
+		return 0, TokenError
+	}
+	if proID != 0 && proID != identity.ProID {
+		return 0, AuthError
+	}
+	return identity.ProID, nil
+}
+
+// ownsEstablishment fails with AuthError when estabID isn't one of the
+// establishments the authenticated pro OWN
+func (mw serviceAuthenticationMiddleware) ownsEstablishment(ctx context.Context, estabID int64) error {
+	proID, err := authenticatedPro(ctx, 0)
+	if err != nil {
+		return err
+	}
+	estabs, err := mw.db.GetProEstablishments(ctx, proID)
+	if err != nil {
+		fmt.Println("ownsEstablishment (GetProEstablishments) : " + err.Error())
+		return dbToHTTPErr(err)
+	}
+	for _, estab := range estabs {
+		if estab.ID == estabID {
+			return nil
+		}
+	}
+	return AuthError
+}
+
+// ownsSoiree fails with AuthError when soireeID wasn't spawned by one of the
+// establishments the authenticated pro OWN
+func (mw serviceAuthenticationMiddleware) ownsSoiree(ctx context.Context, soireeID int64) error {
+	proID, err := authenticatedPro(ctx, 0)
+	if err != nil {
+		return err
+	}
+	pro, err := mw.db.GetProBySoiree(ctx, soireeID)
+	if err != nil {
+		if dbToHTTPErr(err) == NotFoundError {
+			return AuthError
+		}
+		fmt.Println("ownsSoiree (GetProBySoiree) : " + err.Error())
+		return dbToHTTPErr(err)
+	} else if pro.ID != proID {
+		return AuthError
+	}
+	return nil
+}
+
+// ownsOrder fails with AuthError when orderID isn't an order made during one of
+// the soirees of the authenticated pro
+func (mw serviceAuthenticationMiddleware) ownsOrder(ctx context.Context, orderID int64) error {
+	if _, err := authenticatedPro(ctx, 0); err != nil {
+		return err
+	}
+	order, err := mw.db.GetOrder(ctx, orderID)
 	if err != nil {
-		log.Fatal("Error reading public key : " + publicKeyPath)
+		fmt.Println("ownsOrder (GetOrder) : " + err.Error())
+		return dbToHTTPErr(err)
+	} else if order.ID == 0 {
+		return AuthError
 	}
+	return mw.ownsSoiree(ctx, order.Soiree.ID)
 }
 
 /* MW Authentication interface */
-func ServiceAuthenticationMiddleware() Middleware {
+// ServiceAuthenticationMiddleware checks the authenticated pro may act on the
+// resources of the request, db being where their owners are looked up
+func ServiceAuthenticationMiddleware(db svcdb.IService) Middleware {
 	return func(next IService) IService {
 		return serviceAuthenticationMiddleware{
 			next: next,
+			db:   db,
 		}
 	}
 }
 
 type serviceAuthenticationMiddleware struct {
 	next IService
+	db   svcdb.IService
 }
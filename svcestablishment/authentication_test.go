@@ -0,0 +1,271 @@
+package svcestablishment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"svcdb"
+)
+
+// offlineService is the business logic behind the middleware, with the calls
+// leaving the service (geocoder, svcsoiree, svcpayment) answered by svcdb
+type offlineService struct {
+	Service
+}
+
+func (s offlineService) CreateEstab(ctx context.Context, estab svcdb.Establishment, proID int64) (svcdb.Establishment, error) {
+	estab, err := s.svcdb.CreateEstablishment(ctx, estab, proID)
+	return estab, dbToHTTPErr(err)
+}
+
+func (s offlineService) UpdateEstab(ctx context.Context, estab svcdb.Establishment) (svcdb.Establishment, error) {
+	estab, err := s.svcdb.UpdateEstablishment(ctx, estab)
+	return estab, dbToHTTPErr(err)
+}
+
+func (s offlineService) CreateSoiree(ctx context.Context, establishmentID, menuID int64, soiree svcdb.Soiree) (int64, error) {
+	soiree, err := s.svcdb.CreateSoiree(ctx, menuID, establishmentID, soiree)
+	return soiree.ID, dbToHTTPErr(err)
+}
+
+func (s offlineService) PutOrder(ctx context.Context, orderID int64, step string, flag bool) (svcdb.Order, error) {
+	order, err := s.svcdb.PutOrder(ctx, orderID, step, flag)
+	return order, dbToHTTPErr(err)
+}
+
+func (s offlineService) DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error) {
+	return s.GetOrder(ctx, orderID)
+}
+
+func (s offlineService) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	return s.GetOrder(ctx, orderID)
+}
+
+func (s offlineService) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	orders, next, err := s.svcdb.SearchOrders(ctx, order, page)
+	return orders, next, dbToHTTPErr(err)
+}
+
+// ownership is a pro owning an establishment with a menu, a soiree and an
+// order, and a foreign pro owning another establishment
+type ownership struct {
+	db      svcdb.MemoryService
+	owner   int64
+	foreign int64
+	estab   int64 // of the owner
+	other   int64 // of the foreign pro
+	menu    int64
+	soiree  int64
+	order   int64
+}
+
+func newOwnership(t *testing.T) ownership {
+	var o ownership
+	ctx := context.Background()
+
+	o.db = svcdb.NewMemoryService()
+	o.db.SeedEstablishmentType("Bar")
+
+	soiree := o.db.SeedSoiree()
+	o.owner = o.db.SeedPro("owner@nightline.fr").ID
+	o.estab = o.db.SeedEstablishment(o.owner, soiree.ID).ID
+	o.foreign = o.db.SeedPro("foreign@nightline.fr").ID
+	o.other = o.db.SeedEstablishment(o.foreign).ID
+
+	menu, err := o.db.CreateMenu(ctx, o.estab, svcdb.Menu{Name: "Menu"})
+	if err != nil {
+		t.Fatal("CreateMenu : " + err.Error())
+	}
+	order, err := o.db.CreateOrder(ctx, svcdb.Order{
+		Price:  500,
+		Soiree: soiree,
+		Users:  svcdb.UserOrders{{User: o.db.SeedUser("user@nightline.fr"), Price: 500}},
+		Consos: svcdb.ConsoOrders{{Conso: o.db.SeedConso(500), Amount: 1}},
+	})
+	if err != nil {
+		t.Fatal("CreateOrder : " + err.Error())
+	}
+
+	o.menu, o.soiree, o.order = menu.ID, soiree.ID, order.ID
+	return o
+}
+
+func asPro(proID int64) context.Context {
+	return context.WithValue(context.Background(), identityKey{}, Identity{ProID: proID, Role: RolePro})
+}
+
+// guardedCalls are the methods acting on the resources of a pro, each one
+// only allowed to their owner
+var guardedCalls = map[string]func(context.Context, IService, ownership) error{
+	"UpdatePro": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.UpdatePro(ctx, svcdb.Pro{ID: o.owner, Pseudo: "pseudo"})
+		return err
+	},
+	"CreateEstab": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.CreateEstab(ctx, svcdb.Establishment{Name: "Other", Type: "Bar"}, o.owner)
+		return err
+	},
+	"GetPayouts": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetPayouts(ctx, o.owner, "day", time.Now().Add(-time.Hour), time.Now())
+		return err
+	},
+	"GetProEstablishments": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetProEstablishments(ctx, o.owner)
+		return err
+	},
+	"UpdateEstab": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.UpdateEstab(ctx, svcdb.Establishment{ID: o.estab, Name: "Renamed"})
+		return err
+	},
+	"DeleteEstab": func(ctx context.Context, svc IService, o ownership) error {
+		return svc.DeleteEstab(ctx, o.estab)
+	},
+	"CreateMenu": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.CreateMenu(ctx, o.estab, svcdb.Menu{Name: "Other"})
+		return err
+	},
+	"CreateConso": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.CreateConso(ctx, o.estab, o.menu, svcdb.Conso{Name: "Wine", Price: 700})
+		return err
+	},
+	"ImportMenus": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.ImportMenus(ctx, o.estab, []svcdb.Menu{{Name: "Imported"}})
+		return err
+	},
+	"GetMenu": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetMenu(ctx, o.estab)
+		return err
+	},
+	"GetConso": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetConso(ctx, o.estab)
+		return err
+	},
+	"GetSoirees": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetSoirees(ctx, o.estab)
+		return err
+	},
+	"CreateSoiree": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.CreateSoiree(ctx, o.estab, o.menu, svcdb.Soiree{Desc: "Other", Begin: time.Now(), End: time.Now().Add(time.Hour)})
+		return err
+	},
+	"DeleteSoiree": func(ctx context.Context, svc IService, o ownership) error {
+		return svc.DeleteSoiree(ctx, o.soiree)
+	},
+	"GetStat": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetStat(ctx, o.estab, o.menu, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+		return err
+	},
+	"GetAnalyseC": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetAnalyseC(ctx, o.estab, o.soiree)
+		return err
+	},
+	"GetAnalyseF": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetAnalyseF(ctx, o.estab, o.soiree, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+		return err
+	},
+	"GetAnalyseP": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetAnalyseP(ctx, o.estab, o.soiree)
+		return err
+	},
+	"GetSoireeOrders": func(ctx context.Context, svc IService, o ownership) error {
+		_, _, err := svc.GetSoireeOrders(ctx, o.soiree, svcdb.Page{})
+		return err
+	},
+	"GetOrdersBySoiree": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetOrdersBySoiree(ctx, o.soiree)
+		return err
+	},
+	"SearchOrders": func(ctx context.Context, svc IService, o ownership) error {
+		_, _, err := svc.SearchOrders(ctx, svcdb.Order{Soiree: svcdb.Soiree{ID: o.soiree}}, svcdb.Page{})
+		return err
+	},
+	"GetOrder": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetOrder(ctx, o.order)
+		return err
+	},
+	"GetConsoByOrderID": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.GetConsoByOrderID(ctx, o.order)
+		return err
+	},
+	"PutOrder": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.PutOrder(ctx, o.order, "Issued", true)
+		return err
+	},
+	"AnswerCancel": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.AnswerCancel(ctx, o.order, false)
+		return err
+	},
+	"DeliverOrder": func(ctx context.Context, svc IService, o ownership) error {
+		_, err := svc.DeliverOrder(ctx, o.estab, o.order)
+		return err
+	},
+}
+
+func TestServiceAuthenticationForeignPro(t *testing.T) {
+	// nothing is changed, the same resources are used by every call
+	o := newOwnership(t)
+	svc := ServiceAuthenticationMiddleware(o.db)(offlineService{Service{svcdb: o.db}})
+
+	for name, call := range guardedCalls {
+		if err := call(asPro(o.foreign), svc, o); err != AuthError {
+			t.Errorf("%s by a foreign pro : got %v, want %v", name, err, AuthError)
+		}
+	}
+}
+
+func TestServiceAuthenticationOwner(t *testing.T) {
+	for name, call := range guardedCalls {
+		o := newOwnership(t)
+		svc := ServiceAuthenticationMiddleware(o.db)(offlineService{Service{svcdb: o.db}})
+
+		if err := call(asPro(o.owner), svc, o); err != nil {
+			t.Errorf("%s by the owner : got %v, want no error", name, err)
+		}
+	}
+}
+
+func TestServiceAuthenticationNoIdentity(t *testing.T) {
+	// nothing is changed, the same resources are used by every call
+	o := newOwnership(t)
+	svc := ServiceAuthenticationMiddleware(o.db)(offlineService{Service{svcdb: o.db}})
+
+	for name, call := range guardedCalls {
+		if err := call(context.Background(), svc, o); err != TokenError {
+			t.Errorf("%s without token : got %v, want %v", name, err, TokenError)
+		}
+	}
+}
+
+func TestSearchOrdersNeedsSoiree(t *testing.T) {
+	o := newOwnership(t)
+	svc := ServiceAuthenticationMiddleware(o.db)(offlineService{Service{svcdb: o.db}})
+
+	if _, _, err := svc.SearchOrders(asPro(o.owner), svcdb.Order{}, svcdb.Page{}); err != AuthError {
+		t.Errorf("SearchOrders without soiree : got %v, want %v", err, AuthError)
+	}
+}
+
+func TestCreateSoireeForeignMenu(t *testing.T) {
+	o := newOwnership(t)
+	svc := ServiceAuthenticationMiddleware(o.db)(offlineService{Service{svcdb: o.db}})
+
+	// a menu of the foreign pro, for a soiree in the establishment of the owner
+	menu, err := o.db.CreateMenu(context.Background(), o.other, svcdb.Menu{Name: "Foreign"})
+	if err != nil {
+		t.Fatal("CreateMenu : " + err.Error())
+	}
+	if _, err = svc.CreateSoiree(asPro(o.owner), o.estab, menu.ID, svcdb.Soiree{}); err != AuthError {
+		t.Errorf("CreateSoiree with a foreign menu : got %v, want %v", err, AuthError)
+	}
+}
+
+func TestAuthErrorIsForbidden(t *testing.T) {
+	w := httptest.NewRecorder()
+	errorEncoder(context.Background(), AuthError, w)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("AuthError : got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
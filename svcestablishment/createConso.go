@@ -110,6 +110,9 @@ func (mw serviceLoggingMiddleware) CreateConso(ctx context.Context, establishmen
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateConso(ctx context.Context, establishmentID, menuID int64, conso svcdb.Conso) (svcdb.Conso, error) {
+	if err := mw.ownsEstablishment(ctx, establishmentID); err != nil {
+		return svcdb.Conso{}, err
+	}
 	return mw.next.CreateConso(ctx, establishmentID, menuID, conso)
 }
 
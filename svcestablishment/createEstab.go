@@ -27,7 +27,7 @@ func (s Service) CreateEstab(ctx context.Context, old svcdb.Establishment, proID
 	var estabs []svcdb.SearchResponse
 	var newEstab svcdb.Establishment
 
-	estabs, err := s.svcdb.SearchEstablishments(ctx, old.Name)
+	estabs, _, err := s.svcdb.SearchEstablishments(ctx, old.Name, svcdb.Page{})
 	if err != nil {
 		fmt.Println("Error CreateEstab 1 : ", err.Error())
 		return newEstab, dbToHTTPErr(err)
@@ -151,6 +151,10 @@ func (mw serviceLoggingMiddleware) CreateEstab(ctx context.Context, estab svcdb.
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateEstab(ctx context.Context, estab svcdb.Establishment, proID int64) (svcdb.Establishment, error) {
+	proID, err := authenticatedPro(ctx, proID)
+	if err != nil {
+		return svcdb.Establishment{}, err
+	}
 	return mw.next.CreateEstab(ctx, estab, proID)
 }
 
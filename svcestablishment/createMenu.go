@@ -100,6 +100,9 @@ func (mw serviceLoggingMiddleware) CreateMenu(ctx context.Context, establishment
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateMenu(ctx context.Context, establishmentID int64, menu svcdb.Menu) (svcdb.Menu, error) {
+	if err := mw.ownsEstablishment(ctx, establishmentID); err != nil {
+		return svcdb.Menu{}, err
+	}
 	return mw.next.CreateMenu(ctx, establishmentID, menu)
 }
 
@@ -112,6 +112,16 @@ func (mw serviceLoggingMiddleware) CreateSoiree(ctx context.Context, establishme
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) CreateSoiree(ctx context.Context, establishmentID, menuID int64, s svcdb.Soiree) (soireeID int64, err error) {
+	if err := mw.ownsEstablishment(ctx, establishmentID); err != nil {
+		return 0, err
+	}
+	// the menu must be one of the establishment too
+	estab, err := mw.db.GetEstablishmentFromMenu(ctx, menuID)
+	if err != nil {
+		return 0, dbToHTTPErr(err)
+	} else if estab.ID != establishmentID {
+		return 0, AuthError
+	}
 	return mw.next.CreateSoiree(ctx, establishmentID, menuID, s)
 }
 
@@ -111,6 +111,9 @@ func (mw serviceLoggingMiddleware) DeleteEstab(ctx context.Context, estabID int6
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) DeleteEstab(ctx context.Context, estabID int64) error {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return err
+	}
 	return mw.next.DeleteEstab(ctx, estabID)
 }
 
@@ -106,6 +106,9 @@ func (mw serviceLoggingMiddleware) DeleteSoiree(ctx context.Context, soireeID in
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) DeleteSoiree(ctx context.Context, soireeID int64) error {
+	if err := mw.ownsSoiree(ctx, soireeID); err != nil {
+		return err
+	}
 	return mw.next.DeleteSoiree(ctx, soireeID)
 }
 
@@ -18,15 +18,64 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /*************** Service ***************/
 /* Service - Business logic */
-func (s Service) DeliverOrder(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	// orderID, err := s.svcsoiree.DeliverOrder(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
-	// return orderID, dbToHTTPErr(err)
+// DeliverOrder is called by the bar staff when handing over the order : the
+// Ready step is closed if the waiter did not do it yet, then the Deliverpaid
+// one, whose handler in svcpayment (stepExecuteDeliverpaid) captures the
+// charges and completes the order
+func (s Service) DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error) {
+	order, err := s.svcdb.GetOrder(ctx, orderID)
+	if err != nil {
+		fmt.Println("DeliverOrder (GetOrder) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	} else if order.ID == 0 {
+		return order, NotFoundError
+	}
+
+	// check the order is one of the establishment
+	soirees, err := s.svcdb.GetSoireesByEstablishment(ctx, establishmentID)
+	if err != nil {
+		fmt.Println("DeliverOrder (GetSoireesByEstablishment) : " + err.Error())
+		return order, dbToHTTPErr(err)
+	}
+	found := false
+	for _, soiree := range soirees {
+		if soiree.ID == order.Soiree.ID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return order, NotFoundError
+	}
+
+	open, _ := svcdb.OrderWorkflow.OpenStep(order)
+	switch open.Name {
+	case "Ready":
+		order, err = s.svcpayment.PutOrder(ctx, orderID, "Ready", true)
+		if err != nil {
+			fmt.Println("DeliverOrder (PutOrder Ready) : " + err.Error())
+			return order, dbToHTTPErr(err)
+		}
+		fallthrough
+	case "Deliverpaid":
+		order, err = s.svcpayment.PutOrder(ctx, orderID, "Deliverpaid", true)
+		if err != nil {
+			fmt.Println("DeliverOrder (PutOrder Deliverpaid) : " + err.Error())
+			return order, dbToHTTPErr(err)
+		}
+	default:
+		return order, OrderNotReadyErr
+	}
 
-	return 0, dbToHTTPErr(nil)
+	// Completed is closed right after Deliverpaid, read it back
+	order, err = s.svcdb.GetOrder(ctx, orderID)
+	return order, dbToHTTPErr(err)
 }
 
 // Merged : Service definition
@@ -34,23 +83,20 @@ func (s Service) DeliverOrder(ctx context.Context, establishmentID, menuID int64
 /*************** Endpoint ***************/
 /* Endpoint - Req/Resp */
 type DeliverOrderRequest struct {
-	EstablishmentID int64
-	MenuID          int64
-	OrderID         int64
-	SoireeBegin     time.Time
-	SoireeEnd       time.Time
+	EstablishmentID int64 `json:"estabID"`
+	OrderID         int64 `json:"orderID"`
 }
 type DeliverOrderResponse struct {
-	SoireeID int64
+	Order svcdb.Order `json:"order"`
 }
 
 /* Endpoint - Create endpoint */
 func DeliverOrderEndpoint(s IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		csReq := request.(DeliverOrderRequest)
-		soireeID, err := s.DeliverOrder(ctx, csReq.EstablishmentID, csReq.MenuID, csReq.SoireeBegin, csReq.SoireeEnd)
+		order, err := s.DeliverOrder(ctx, csReq.EstablishmentID, csReq.OrderID)
 		return DeliverOrderResponse{
-			SoireeID: soireeID,
+			Order: order,
 		}, err
 	}
 }
@@ -62,23 +108,20 @@ func DeliverOrderEndpoint(s IService) endpoint.Endpoint {
 func DecodeHTTPDeliverOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var req DeliverOrderRequest
 
-	if err := r.ParseForm(); err != nil {
-		return nil, err
-	}
-
-	orderID, err := strconv.ParseInt(mux.Vars(r)["OrderID"], 10, 64)
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		fmt.Println("Error DecodeHTTPDeliverOrderRequest 1 : ", err.Error())
 		return nil, RequestError
 	}
-	(&req).OrderID = orderID
 
-	err = json.NewDecoder(r.Body).Decode(&req)
+	orderID, err := strconv.ParseInt(mux.Vars(r)["OrderID"], 10, 64)
 	if err != nil {
-		fmt.Println("Error DecodeHTTPDeliverOrderRequest 1 : ", err.Error())
+		fmt.Println("Error DecodeHTTPDeliverOrderRequest 2 : ", err.Error())
 		return nil, RequestError
 	}
-	return req, err
+	(&req).OrderID = orderID
+
+	return req, nil
 }
 
 /* Transport - *coder Response */
@@ -95,7 +138,7 @@ func DecodeHTTPDeliverOrderResponse(_ context.Context, r *http.Response) (interf
 }
 
 func DeliverOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
-	route := r.Methods("POST").Path("/orders/{OrderID}/deliver").Handler(httptransport.NewServer(
+	route := r.Methods("POST").Path("/orders/{OrderID:[0-9]+}/deliver").Handler(httptransport.NewServer(
 		endpoints.DeliverOrderEndpoint,
 		DecodeHTTPDeliverOrderRequest,
 		EncodeHTTPGenericResponse,
@@ -108,29 +151,31 @@ func DeliverOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) DeliverOrder(ctx context.Context, estabID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	soireeID, err := mw.next.DeliverOrder(ctx, estabID, menuID, soireeBegin, soireeEnd)
+func (mw serviceLoggingMiddleware) DeliverOrder(ctx context.Context, estabID, orderID int64) (svcdb.Order, error) {
+	order, err := mw.next.DeliverOrder(ctx, estabID, orderID)
 
 	mw.logger.Log(
 		"method", "DeliverOrder",
-		"request", DeliverOrderRequest{EstablishmentID: estabID, MenuID: menuID, SoireeBegin: soireeBegin, SoireeEnd: soireeEnd},
-		"soireeID", soireeID,
+		"request", DeliverOrderRequest{EstablishmentID: estabID, OrderID: orderID},
 		"error", err,
 		"took", time.Since(time.Now()),
 	)
-	return soireeID, err
+	return order, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) DeliverOrder(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (soireeID int64, err error) {
-	return mw.next.DeliverOrder(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
+func (mw serviceAuthenticationMiddleware) DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error) {
+	if err := mw.ownsEstablishment(ctx, establishmentID); err != nil {
+		return svcdb.Order{}, err
+	}
+	return mw.next.DeliverOrder(ctx, establishmentID, orderID)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) DeliverOrder(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	return mw.next.DeliverOrder(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
+func (mw serviceInstrumentingMiddleware) DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error) {
+	return mw.next.DeliverOrder(ctx, establishmentID, orderID)
 }
 
 /*************** Main ***************/
@@ -153,21 +198,27 @@ func BuildDeliverOrderEndpoint(svc IService, logger log.Logger, tracer stdopentr
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) DeliverOrder(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	var soireeID int64
-
+func (e Endpoints) DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error) {
 	request := DeliverOrderRequest{
 		EstablishmentID: establishmentID,
-		MenuID:          menuID,
-		SoireeBegin:     soireeBegin,
-		SoireeEnd:       soireeEnd,
+		OrderID:         orderID,
 	}
 	response, err := e.DeliverOrderEndpoint(ctx, request)
 	if err != nil {
-		return 0, err
+		return svcdb.Order{}, err
+	}
+	return response.(DeliverOrderResponse).Order, nil
+}
+
+func EncodeHTTPDeliverOrderRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(DeliverOrderRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("OrderID", fmt.Sprintf("%v", req.OrderID))
+	if err != nil {
+		return err
 	}
-	soireeID = response.(DeliverOrderResponse).SoireeID
-	return soireeID, err
+	r.URL.Path = encodedUrl.Path
+	return EncodeHTTPGenericRequest(ctx, r, request)
 }
 
 func ClientDeliverOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
@@ -176,7 +227,7 @@ func ClientDeliverOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tra
 	gefmEndpoint = httptransport.NewClient(
 		"POST",
 		copyURL(u, "/orders/{OrderID}/deliver"),
-		EncodeHTTPGenericRequest,
+		EncodeHTTPDeliverOrderRequest,
 		DecodeHTTPDeliverOrderResponse,
 		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
 	).Endpoint()
@@ -3,13 +3,15 @@ package svcestablishment
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
+	"github.com/go-kit/kit/auth/jwt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
+
+	"svcdb"
 )
 
 /* Endpoints definition */
@@ -22,6 +24,7 @@ type Endpoints struct {
 	/* Order */
 	GetOrderEndpoint          endpoint.Endpoint
 	DeliverOrderEndpoint      endpoint.Endpoint
+	AnswerCancelEndpoint      endpoint.Endpoint
 	GetOrdersBySoireeEndpoint endpoint.Endpoint
 	SearchOrdersEndpoint      endpoint.Endpoint
 	PutOrderEndpoint          endpoint.Endpoint
@@ -33,6 +36,8 @@ type Endpoints struct {
 	GetMenuEndpoint     endpoint.Endpoint
 	CreateMenuEndpoint  endpoint.Endpoint
 	CreateConsoEndpoint endpoint.Endpoint
+	ImportMenusEndpoint endpoint.Endpoint
+	ExportMenusEndpoint endpoint.Endpoint
 
 	/* Establishment */
 	CreateEstabEndpoint          endpoint.Endpoint
@@ -43,17 +48,22 @@ type Endpoints struct {
 	/* Pro */
 	LoginProEndpoint             endpoint.Endpoint
 	RegisterProEndpoint          endpoint.Endpoint
+	RefreshTokenEndpoint         endpoint.Endpoint
+	LogoutEndpoint               endpoint.Endpoint
+	LogoutAllEndpoint            endpoint.Endpoint
+	RequestPasswordResetEndpoint endpoint.Endpoint
+	ResetPasswordEndpoint        endpoint.Endpoint
+	VerifyEmailEndpoint          endpoint.Endpoint
+	SendVerificationEndpoint     endpoint.Endpoint
 	UpdateProEndpoint            endpoint.Endpoint
 	GetProEstablishmentsEndpoint endpoint.Endpoint
 
 	/* Stat */
 	GetStatEndpoint endpoint.Endpoint
 	GetAnalysePEndpoint endpoint.Endpoint
-}
-
-var claims = &stdjwt.StandardClaims{
-	ExpiresAt: time.Now().UTC().Add(time.Hour * 1).Unix(), // Add 1 Hour
-	Issuer:    "NightLine",
+	GetAnalyseCEndpoint endpoint.Endpoint
+	GetAnalyseFEndpoint endpoint.Endpoint
+	GetPayoutsEndpoint  endpoint.Endpoint
 }
 
 /* Logging Middleware */
@@ -71,15 +81,41 @@ func EndpointLoggingMiddleware(logger log.Logger) endpoint.Middleware {
 }
 
 /* Authentication Middleware */
+// EndpointAuthenticationMiddleware verifies the token put in the context by
+// jwt.HTTPToContext, checks it wasn't revoked and injects the identity it
+// carries
 func EndpointAuthenticationMiddleware() endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-			// defer func() {
-			// 	kf := func(token *stdjwt.Token) (interface{}, error) { return verifKey, nil }
-			// 	next = jwt.NewParser(kf, stdjwt.SigningMethodHS256, claims)(next)
-			// }()
+			token, ok := ctx.Value(jwt.JWTTokenContextKey).(string)
+			if !ok {
+				return nil, TokenError
+			}
+			identity, err := ParseToken(token)
+			if err != nil {
+				return nil, err
+			}
+			if err = checkRevoked(ctx, identity); err != nil {
+				return nil, err
+			}
+			return next(context.WithValue(ctx, identityKey{}, identity), request)
+		}
+	}
+}
 
-			return next(ctx, request)
+/* Idempotency Middleware */
+// EndpointIdempotencyMiddleware looks the idempotency key of the request up,
+// the keys of each pro being kept apart. It goes inside
+// EndpointAuthenticationMiddleware, which injects the identity it reads.
+func EndpointIdempotencyMiddleware(db svcdb.IService, scope string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			identity, ok := IdentityFromContext(ctx)
+			if !ok {
+				return nil, TokenError
+			}
+			proScope := scope + ":" + strconv.FormatInt(identity.ProID, 10)
+			return svcdb.EndpointIdempotencyMiddleware(db, proScope)(next)(ctx, request)
 		}
 	}
 }
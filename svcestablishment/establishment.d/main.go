@@ -25,6 +25,8 @@ import (
 	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/prometheus"
 
+	"mailer"
+	"svcdb"
 	csvcdb "svcdb/client"
 	csvcpayment "svcpayment/client"
 	csvcsoiree "svcsoiree/client"
@@ -42,6 +44,12 @@ func main() {
 		zipkinKafkaAddr = flag.String("zipkin.kafka.addr", "", "Enable Zipkin tracing via a Kafka server host:port")
 		appdashAddr     = flag.String("appdash.addr", "", "Enable Appdash tracing via an Appdash server host:port")
 		lightstepToken  = flag.String("lightstep.token", "", "Enable LightStep tracing via a LightStep access token")
+		smtpAddr        = flag.String("mail.smtp.addr", "", "Send the emails via a SMTP server host:port")
+		smtpUser        = flag.String("mail.smtp.user", "", "User authenticating to the SMTP server")
+		smtpPassword    = flag.String("mail.smtp.password", "", "Password authenticating to the SMTP server")
+		mailFrom        = flag.String("mail.from", "NightLine <no-reply@nightline.fr>", "Sender of the emails")
+		mailFile        = flag.String("mail.file", "", "Without SMTP server, append the emails to this file instead of logging them")
+		appURL          = flag.String("app.url", "http://localhost:8081", "Base URL of the links mailed to the pros")
 	)
 	flag.Parse()
 
@@ -72,7 +80,7 @@ func main() {
 	defer logger.Log("msg", "[SVCESTABLISHMENT END]")
 
 	/* Authentication */
-	// svcestablishment.InitKey()
+	svcestablishment.InitKey()
 
 	/* Geocoding */
 	svcestablishment.InitMapQuest()
@@ -160,9 +168,11 @@ func main() {
 	}
 
 	/* Business domain */
+	var db svcdb.IService
 	var service svcestablishment.IService
 	{
-		svcdb, err := csvcdb.New("localhost:8044", tracer, logger)
+		var err error
+		db, err = csvcdb.New("localhost:8044", tracer, logger)
 		svcsoiree, err := csvcsoiree.New("localhost:8045", tracer, logger)
 		svcpayment, err := csvcpayment.New("localhost:8047", tracer, logger)
 		if err != nil {
@@ -170,8 +180,19 @@ func main() {
 			os.Exit(1)
 		}
 
-		service = svcestablishment.NewService(svcdb, svcsoiree, svcpayment)
+		svcestablishment.InitRevocationList(db)
+
+		mail := mailer.New(mailer.Config{
+			SMTPAddr:     *smtpAddr,
+			SMTPUser:     *smtpUser,
+			SMTPPassword: *smtpPassword,
+			From:         *mailFrom,
+			File:         *mailFile,
+		}, logger)
+
+		service = svcestablishment.NewService(db, svcsoiree, svcpayment, mail, *appURL)
 		service = svcestablishment.ServiceLoggingMiddleware(logger)(service)
+		service = svcestablishment.ServiceAuthenticationMiddleware(db)(service)
 		service = svcestablishment.ServiceInstrumentingMiddleware(
 			createSoiree_all,
 		)(service)
@@ -180,14 +201,22 @@ func main() {
 	/* Endpoints domain */
 	createSoireeEndpoint := svcestablishment.BuildCreateSoireeEndpoint(service, logger, tracer, duration)
 	deliverOrderEndpoint := svcestablishment.BuildDeliverOrderEndpoint(service, logger, tracer, duration)
+	answerCancelEndpoint := svcestablishment.BuildAnswerCancelEndpoint(service, logger, tracer, duration)
 	getOrderEndpoint := svcestablishment.BuildGetOrderEndpoint(service, logger, tracer, duration)
 	getOrdersBySoireeEndpoint := svcestablishment.BuildGetOrdersBySoireeEndpoint(service, logger, tracer, duration)
 	searchOrdersEndpoint := svcestablishment.BuildSearchOrdersEndpoint(service, logger, tracer, duration)
-	putOrderEndpoint := svcestablishment.BuildPutOrderEndpoint(service, logger, tracer, duration)
+	putOrderEndpoint := svcestablishment.BuildPutOrderEndpoint(service, db, logger, tracer, duration)
 	getSoireesEndpoint := svcestablishment.BuildGetSoireesEndpoint(service, logger, tracer, duration)
 	getStatEndpoint := svcestablishment.BuildGetStatEndpoint(service, logger, tracer, duration)
 	loginProEndpoint := svcestablishment.BuildLoginProEndpoint(service, logger, tracer, duration)
 	registerProEndpoint := svcestablishment.BuildRegisterProEndpoint(service, logger, tracer, duration)
+	refreshTokenEndpoint := svcestablishment.BuildRefreshTokenEndpoint(service, logger, tracer, duration)
+	logoutEndpoint := svcestablishment.BuildLogoutEndpoint(service, logger, tracer, duration)
+	logoutAllEndpoint := svcestablishment.BuildLogoutAllEndpoint(service, logger, tracer, duration)
+	requestPasswordResetEndpoint := svcestablishment.BuildRequestPasswordResetEndpoint(service, logger, tracer, duration)
+	resetPasswordEndpoint := svcestablishment.BuildResetPasswordEndpoint(service, logger, tracer, duration)
+	verifyEmailEndpoint := svcestablishment.BuildVerifyEmailEndpoint(service, logger, tracer, duration)
+	sendVerificationEndpoint := svcestablishment.BuildSendVerificationEndpoint(service, logger, tracer, duration)
 	createEstabEndpoint := svcestablishment.BuildCreateEstabEndpoint(service, logger, tracer, duration)
 	updateEstabEndpoint := svcestablishment.BuildUpdateEstabEndpoint(service, logger, tracer, duration)
 	deleteEstabEndpoint := svcestablishment.BuildDeleteEstabEndpoint(service, logger, tracer, duration)
@@ -197,15 +226,21 @@ func main() {
 	getConsoEndpoint := svcestablishment.BuildGetConsoEndpoint(service, logger, tracer, duration)
 	GetConsoByOrderIDEndpoint := svcestablishment.BuildGetConsoByOrderIDEndpoint(service, logger, tracer, duration)
 	getMenuEndpoint := svcestablishment.BuildGetMenuEndpoint(service, logger, tracer, duration)
+	importMenusEndpoint := svcestablishment.BuildImportMenusEndpoint(service, logger, tracer, duration)
+	exportMenusEndpoint := svcestablishment.BuildExportMenusEndpoint(service, logger, tracer, duration)
 	getEstablishmentTypeEndpoint := svcestablishment.BuildGetEstablishmentTypeEndpoint(service, logger, tracer, duration)
 	getSoireeOrdersEndpoint := svcestablishment.BuildGetSoireeOrdersEndpoint(service, logger, tracer, duration)
 	getProEstablishmentEndpoint := svcestablishment.BuildGetProEstablishmentsEndpoint(service, logger, tracer, duration)
 	deleteSoireeEndpoint := svcestablishment.BuildDeleteSoireeEndpoint(service, logger, tracer, duration)
 	getAnalysePEndpoint := svcestablishment.BuildGetAnalysePEndpoint(service, logger, tracer, duration)
+	getAnalyseCEndpoint := svcestablishment.BuildGetAnalyseCEndpoint(service, logger, tracer, duration)
+	getAnalyseFEndpoint := svcestablishment.BuildGetAnalyseFEndpoint(service, logger, tracer, duration)
+	getPayoutsEndpoint := svcestablishment.BuildGetPayoutsEndpoint(service, logger, tracer, duration)
 
 	endpoints := svcestablishment.Endpoints{
 		CreateSoireeEndpoint:         createSoireeEndpoint,
 		DeliverOrderEndpoint:         deliverOrderEndpoint,
+		AnswerCancelEndpoint:         answerCancelEndpoint,
 		GetOrderEndpoint:             getOrderEndpoint,
 		GetOrdersBySoireeEndpoint:    getOrdersBySoireeEndpoint,
 		SearchOrdersEndpoint:         searchOrdersEndpoint,
@@ -214,6 +249,13 @@ func main() {
 		GetStatEndpoint:              getStatEndpoint,
 		LoginProEndpoint:             loginProEndpoint,
 		RegisterProEndpoint:          registerProEndpoint,
+		RefreshTokenEndpoint:         refreshTokenEndpoint,
+		LogoutEndpoint:               logoutEndpoint,
+		LogoutAllEndpoint:            logoutAllEndpoint,
+		RequestPasswordResetEndpoint: requestPasswordResetEndpoint,
+		ResetPasswordEndpoint:        resetPasswordEndpoint,
+		VerifyEmailEndpoint:          verifyEmailEndpoint,
+		SendVerificationEndpoint:     sendVerificationEndpoint,
 		CreateEstabEndpoint:          createEstabEndpoint,
 		UpdateEstabEndpoint:          updateEstabEndpoint,
 		UpdateProEndpoint:            updateProEndpoint,
@@ -222,12 +264,17 @@ func main() {
 		GetConsoEndpoint:             getConsoEndpoint,
 		GetConsoByOrderIDEndpoint:    GetConsoByOrderIDEndpoint,
 		GetMenuEndpoint:              getMenuEndpoint,
+		ImportMenusEndpoint:          importMenusEndpoint,
+		ExportMenusEndpoint:          exportMenusEndpoint,
 		GetEstablishmentTypeEndpoint: getEstablishmentTypeEndpoint,
 		GetSoireeOrdersEndpoint:      getSoireeOrdersEndpoint,
 		GetProEstablishmentsEndpoint: getProEstablishmentEndpoint,
 		DeleteEstabEndpoint:          deleteEstabEndpoint,
 		DeleteSoireeEndpoint:         deleteSoireeEndpoint,
 		GetAnalysePEndpoint:		  getAnalysePEndpoint,
+		GetAnalyseCEndpoint:		  getAnalyseCEndpoint,
+		GetAnalyseFEndpoint:		  getAnalyseFEndpoint,
+		GetPayoutsEndpoint:		  getPayoutsEndpoint,
 	}
 
 	/* Mechanical domain */
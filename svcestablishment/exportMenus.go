@@ -0,0 +1,155 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+// Merged : the menus are the ones of GetMenu, only their encoding differs
+
+/*************** Endpoint ***************/
+type exportMenusRequest struct {
+	EstabID int64  `json:"id"`
+	Format  string `json:"format"`
+}
+
+type exportMenusResponse struct {
+	EstabID int64
+	Format  string
+	Menus   []svcdb.Menu
+}
+
+func ExportMenusEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportMenusRequest)
+		menus, err := svc.GetMenu(ctx, req.EstabID)
+		return exportMenusResponse{EstabID: req.EstabID, Format: req.Format, Menus: menus}, err
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPExportMenusRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request exportMenusRequest
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["EstabID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).EstabID = estabID
+
+	(&request).Format = strings.ToLower(r.URL.Query().Get("format"))
+	if len(request.Format) == 0 {
+		(&request).Format = MenuFileCSV
+	} else if request.Format != MenuFileCSV && request.Format != MenuFileJSON {
+		return nil, RequestError
+	}
+
+	return request, nil
+}
+
+// EncodeHTTPExportMenusResponse writes the menu file as an attachment
+func EncodeHTTPExportMenusResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(exportMenusResponse)
+
+	setDefaultHeaders(w)
+	if resp.Format == MenuFileCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="menus-%d.%s"`, resp.EstabID, resp.Format))
+	return writeMenuFile(w, resp.Menus, resp.Format)
+}
+
+func DecodeHTTPExportMenusResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response exportMenusResponse
+
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	if err := json.NewDecoder(r.Body).Decode(&response.Menus); err != nil {
+		return nil, RequestError
+	}
+	return response, nil
+}
+
+func ExportMenusHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/establishments/{EstabID:[0-9]+}/menus/export").Handler(httptransport.NewServer(
+		endpoints.ExportMenusEndpoint,
+		DecodeHTTPExportMenusRequest,
+		EncodeHTTPExportMenusResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ExportMenus", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildExportMenusEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ExportMenus")
+		csLogger := log.With(logger, "method", "ExportMenus")
+
+		csEndpoint = ExportMenusEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ExportMenus")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) ExportMenus(ctx context.Context, estabID int64) ([]svcdb.Menu, error) {
+	request := exportMenusRequest{EstabID: estabID, Format: MenuFileJSON}
+	response, err := e.ExportMenusEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(exportMenusResponse).Menus, nil
+}
+
+func EncodeHTTPExportMenusRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(exportMenusRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("EstabID", fmt.Sprintf("%v", req.EstabID))
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	r.URL.RawQuery = url.Values{"format": {req.Format}}.Encode()
+	return nil
+}
+
+func ClientExportMenus(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/establishments/{EstabID}/menus/export"),
+		EncodeHTTPExportMenusRequest,
+		DecodeHTTPExportMenusResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ExportMenus")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,177 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+func (s Service) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error) {
+	var analyses []svcdb.AnalyseC
+	tmpAnalyses, err := s.svcdb.GetAnalyseC(ctx, estabID, soireeID)
+	analyses = append(analyses, tmpAnalyses...)
+	return analyses, dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type GetAnalyseCRequest struct {
+	EstabID  int64 `json:"estabID"`
+	SoireeID int64 `json:"soireeID"`
+}
+
+type GetAnalyseCResponse struct {
+	Analyses []svcdb.AnalyseC `json:"analyses"`
+	Err      error            `json:"err,omitempty"`
+}
+
+func GetAnalyseCEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetAnalyseCRequest)
+		analyses, err := svc.GetAnalyseC(ctx, req.EstabID, req.SoireeID)
+		return GetAnalyseCResponse{Analyses: analyses, Err: err}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetAnalyseCRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request GetAnalyseCRequest
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["estabID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).EstabID = estabID
+	soireeID, err := strconv.ParseInt(mux.Vars(r)["soireeID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).SoireeID = soireeID
+
+	return request, nil
+}
+
+func DecodeHTTPGetAnalyseCResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response GetAnalyseCResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, RequestError
+	}
+	return response, nil
+}
+
+func GetAnalyseCHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/analyses/Consommation/{estabID:[0-9]+}/{soireeID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetAnalyseCEndpoint,
+		DecodeHTTPGetAnalyseCRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetAnalyseC", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error) {
+	analyses, err := mw.next.GetAnalyseC(ctx, estabID, soireeID)
+
+	mw.logger.Log(
+		"method", "GetAnalyseC",
+		"response", analyses,
+		"took", time.Since(time.Now()),
+	)
+	return analyses, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
+	return mw.next.GetAnalyseC(ctx, estabID, soireeID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error) {
+	v, err := mw.next.GetAnalyseC(ctx, estabID, soireeID)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetAnalyseCEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetAnalyseC")
+		csLogger := log.With(logger, "method", "GetAnalyseC")
+
+		csEndpoint = GetAnalyseCEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetAnalyseC")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error) {
+	var analyses []svcdb.AnalyseC
+
+	request := GetAnalyseCRequest{EstabID: estabID, SoireeID: soireeID}
+	response, err := e.GetAnalyseCEndpoint(ctx, request)
+	if err != nil {
+		return analyses, err
+	}
+	analyses = response.(GetAnalyseCResponse).Analyses
+	return analyses, response.(GetAnalyseCResponse).Err
+}
+
+func EncodeHTTPGetAnalyseCRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	estabID := fmt.Sprintf("%v", request.(GetAnalyseCRequest).EstabID)
+	soireeID := fmt.Sprintf("%v", request.(GetAnalyseCRequest).SoireeID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("estabID", estabID, "soireeID", soireeID)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	return nil
+}
+
+func ClientGetAnalyseC(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/analyses/Consommation/{estabID:[0-9]+}/{soireeID:[0-9]+}"),
+		EncodeHTTPGetAnalyseCRequest,
+		DecodeHTTPGetAnalyseCResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetAnalyseC")(ceEndpoint)
+	return ceEndpoint, nil
+}
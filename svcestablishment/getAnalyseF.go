@@ -0,0 +1,187 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+func (s Service) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error) {
+	var analyses []svcdb.AnalyseF
+	tmpAnalyses, err := s.svcdb.GetAnalyseF(ctx, estabID, soireeID, from, to)
+	analyses = append(analyses, tmpAnalyses...)
+	return analyses, dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type GetAnalyseFRequest struct {
+	EstabID  int64     `json:"estabID"`
+	SoireeID int64     `json:"soireeID"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+type GetAnalyseFResponse struct {
+	Analyses []svcdb.AnalyseF `json:"analyses"`
+	Err      error            `json:"err,omitempty"`
+}
+
+func GetAnalyseFEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetAnalyseFRequest)
+		analyses, err := svc.GetAnalyseF(ctx, req.EstabID, req.SoireeID, req.From, req.To)
+		return GetAnalyseFResponse{Analyses: analyses, Err: err}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPGetAnalyseFRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request GetAnalyseFRequest
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["estabID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).EstabID = estabID
+	soireeID, err := strconv.ParseInt(mux.Vars(r)["soireeID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).SoireeID = soireeID
+
+	from, to, err := svcdb.RangeFromRequest(r)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).From, (&request).To = from, to
+
+	return request, nil
+}
+
+func DecodeHTTPGetAnalyseFResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response GetAnalyseFResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, RequestError
+	}
+	return response, nil
+}
+
+func GetAnalyseFHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/analyses/Frequentation/{estabID:[0-9]+}/{soireeID:[0-9]+}").Handler(httptransport.NewServer(
+		endpoints.GetAnalyseFEndpoint,
+		DecodeHTTPGetAnalyseFRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetAnalyseF", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error) {
+	analyses, err := mw.next.GetAnalyseF(ctx, estabID, soireeID, from, to)
+
+	mw.logger.Log(
+		"method", "GetAnalyseF",
+		"response", analyses,
+		"took", time.Since(time.Now()),
+	)
+	return analyses, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
+	return mw.next.GetAnalyseF(ctx, estabID, soireeID, from, to)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error) {
+	v, err := mw.next.GetAnalyseF(ctx, estabID, soireeID, from, to)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetAnalyseFEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetAnalyseF")
+		csLogger := log.With(logger, "method", "GetAnalyseF")
+
+		csEndpoint = GetAnalyseFEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetAnalyseF")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error) {
+	var analyses []svcdb.AnalyseF
+
+	request := GetAnalyseFRequest{EstabID: estabID, SoireeID: soireeID, From: from, To: to}
+	response, err := e.GetAnalyseFEndpoint(ctx, request)
+	if err != nil {
+		return analyses, err
+	}
+	analyses = response.(GetAnalyseFResponse).Analyses
+	return analyses, response.(GetAnalyseFResponse).Err
+}
+
+func EncodeHTTPGetAnalyseFRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(GetAnalyseFRequest)
+	estabID := fmt.Sprintf("%v", req.EstabID)
+	soireeID := fmt.Sprintf("%v", req.SoireeID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("estabID", estabID, "soireeID", soireeID)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	svcdb.EncodeRangeToRequest(r, req.From, req.To)
+	return nil
+}
+
+func ClientGetAnalyseF(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/analyses/Frequentation/{estabID:[0-9]+}/{soireeID:[0-9]+}"),
+		EncodeHTTPGetAnalyseFRequest,
+		DecodeHTTPGetAnalyseFResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "GetAnalyseF")(ceEndpoint)
+	return ceEndpoint, nil
+}
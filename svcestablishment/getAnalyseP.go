@@ -105,6 +105,9 @@ func (mw serviceLoggingMiddleware) GetAnalyseP(ctx context.Context, estabID int6
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetAnalyseP(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseP, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetAnalyseP(ctx, estabID, soireeID)
 }
 
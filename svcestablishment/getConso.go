@@ -93,6 +93,9 @@ func (mw serviceLoggingMiddleware) GetConso(ctx context.Context, estabID int64)
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetConso(ctx context.Context, estabID int64) ([]svcdb.Conso, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetConso(ctx, estabID)
 }
 
@@ -98,6 +98,9 @@ func (mw serviceLoggingMiddleware) GetConsoByOrderID(ctx context.Context, orderI
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetConsoByOrderID(ctx context.Context, orderID int64) (svcdb.Conso, error) {
+	if err := mw.ownsOrder(ctx, orderID); err != nil {
+		return svcdb.Conso{}, err
+	}
 	return mw.next.GetConsoByOrderID(ctx, orderID)
 }
 
@@ -93,6 +93,9 @@ func (mw serviceLoggingMiddleware) GetMenu(ctx context.Context, estabID int64) (
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetMenu(ctx context.Context, estabID int64) ([]svcdb.Menu, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetMenu(ctx, estabID)
 }
 
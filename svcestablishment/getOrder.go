@@ -98,6 +98,9 @@ func (mw serviceLoggingMiddleware) GetOrder(ctx context.Context, orderID int64)
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetOrder(ctx context.Context, orderID int64) (svcdb.Order, error) {
+	if err := mw.ownsOrder(ctx, orderID); err != nil {
+		return svcdb.Order{}, err
+	}
 	return mw.next.GetOrder(ctx, orderID)
 }
 
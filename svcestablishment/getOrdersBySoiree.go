@@ -100,6 +100,9 @@ func (mw serviceLoggingMiddleware) GetOrdersBySoiree(ctx context.Context, soiree
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetOrdersBySoiree(ctx context.Context, soireeID int64) ([]svcdb.Order, error) {
+	if err := mw.ownsSoiree(ctx, soireeID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetOrdersBySoiree(ctx, soireeID)
 }
 
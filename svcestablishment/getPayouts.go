@@ -0,0 +1,291 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+// Groupings of the payout report
+const (
+	PayoutsByDay    = "day"
+	PayoutsBySoiree = "soiree"
+)
+
+// Payout sums the money movements of a day (UTC) or of a soiree, in cents.
+// Net is what the pro keeps : Gross - Fees - Refunds.
+type Payout struct {
+	Day      string `json:"day,omitempty"`
+	Soiree   int64  `json:"soiree,omitempty"`
+	Gross    int64  `json:"gross"`
+	Fees     int64  `json:"fees"`
+	Refunds  int64  `json:"refunds"`
+	Net      int64  `json:"net"`
+	Currency string `json:"currency"`
+}
+
+/*************** Service ***************/
+/* Service - Business logic */
+// GetPayouts reports the ledger of the pro between from and to. Only captured
+// charges count : the release of a charge never captured is not a refund.
+func (s Service) GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) ([]Payout, error) {
+	var payouts []Payout
+	rows := make(map[string]*Payout)
+
+	entries, err := s.svcdb.GetLedger(ctx, svcdb.LedgerFilter{ProID: proID, From: from, To: to})
+	if err != nil {
+		fmt.Println("GetPayouts (GetLedger) : " + err.Error())
+		return payouts, dbToHTTPErr(err)
+	}
+
+	captured := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Kind == svcdb.LedgerCapture {
+			captured[entry.Reference] = true
+		}
+	}
+
+	for _, entry := range entries {
+		amount := entry.Amount
+		if entry.Kind == svcdb.LedgerRefund && !captured[entry.Reference] {
+			// captured before from, or never
+			if captured[entry.Reference], err = s.capturedBefore(ctx, entry); err != nil {
+				return payouts, dbToHTTPErr(err)
+			} else if !captured[entry.Reference] {
+				continue
+			}
+		}
+
+		key := entry.Date.UTC().Format("2006-01-02")
+		if by == PayoutsBySoiree {
+			key = strconv.FormatInt(entry.SoireeID, 10)
+		}
+		row, ok := rows[key]
+		if !ok {
+			row = &Payout{Currency: entry.Currency}
+			if by == PayoutsBySoiree {
+				row.Soiree = entry.SoireeID
+			} else {
+				row.Day = key
+			}
+			rows[key] = row
+		}
+
+		switch entry.Kind {
+		case svcdb.LedgerCapture:
+			row.Gross += amount
+		case svcdb.LedgerFee:
+			row.Fees += amount
+		case svcdb.LedgerRefund:
+			row.Refunds += amount
+		}
+		row.Net = row.Gross - row.Fees - row.Refunds
+	}
+
+	for _, row := range rows {
+		payouts = append(payouts, *row)
+	}
+	sort.Slice(payouts, func(i, j int) bool {
+		if payouts[i].Day != payouts[j].Day {
+			return payouts[i].Day < payouts[j].Day
+		}
+		return payouts[i].Soiree < payouts[j].Soiree
+	})
+	return payouts, nil
+}
+
+// capturedBefore tells whether the charge refunded by entry was captured, its
+// capture being out of the range of the report
+func (s Service) capturedBefore(ctx context.Context, entry svcdb.LedgerEntry) (bool, error) {
+	entries, err := s.svcdb.GetLedger(ctx, svcdb.LedgerFilter{OrderID: entry.OrderID})
+	if err != nil {
+		fmt.Println("GetPayouts (GetLedger) : " + err.Error())
+		return false, err
+	}
+	for _, other := range entries {
+		if other.Kind == svcdb.LedgerCapture && other.Reference == entry.Reference {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type GetPayoutsRequest struct {
+	ProID int64     `json:"proID"`
+	By    string    `json:"by"`
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+}
+type GetPayoutsResponse struct {
+	Payouts []Payout `json:"payouts"`
+}
+
+/* Endpoint - Create endpoint */
+func GetPayoutsEndpoint(s IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		csReq := request.(GetPayoutsRequest)
+		payouts, err := s.GetPayouts(ctx, csReq.ProID, csReq.By, csReq.From, csReq.To)
+		return GetPayoutsResponse{
+			Payouts: payouts,
+		}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPGetPayoutsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req GetPayoutsRequest
+
+	proID, err := strconv.ParseInt(mux.Vars(r)["ProID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&req).ProID = proID
+	(&req).By = mux.Vars(r)["By"]
+
+	from, to, err := svcdb.RangeFromRequest(r)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&req).From, (&req).To = from, to
+
+	return req, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPGetPayoutsResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp GetPayoutsResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+func GetPayoutsHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("GET").Path("/pros/{ProID:[0-9]+}/payouts/{By:(?:day|soiree)}").Handler(httptransport.NewServer(
+		endpoints.GetPayoutsEndpoint,
+		DecodeHTTPGetPayoutsRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "GetPayouts", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) (payouts []Payout, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "GetPayouts",
+			"proID", proID, "by", by,
+			"from", from, "to", to,
+			"rows", len(payouts),
+			"error", err,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.GetPayouts(ctx, proID, by, from, to)
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) ([]Payout, error) {
+	proID, err := authenticatedPro(ctx, proID)
+	if err != nil {
+		return nil, err
+	}
+	return mw.next.GetPayouts(ctx, proID, by, from, to)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) ([]Payout, error) {
+	return mw.next.GetPayouts(ctx, proID, by, from, to)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildGetPayoutsEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "GetPayouts")
+		csLogger := log.With(logger, "method", "GetPayouts")
+
+		csEndpoint = GetPayoutsEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "GetPayouts")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) ([]Payout, error) {
+	request := GetPayoutsRequest{
+		ProID: proID,
+		By:    by,
+		From:  from,
+		To:    to,
+	}
+	response, err := e.GetPayoutsEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(GetPayoutsResponse).Payouts, nil
+}
+
+func EncodeHTTPGetPayoutsRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(GetPayoutsRequest)
+	proID := fmt.Sprintf("%v", req.ProID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("ProID", proID, "By", req.By)
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+	svcdb.EncodeRangeToRequest(r, req.From, req.To)
+	return nil
+}
+
+func ClientGetPayouts(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var gpEndpoint endpoint.Endpoint
+
+	gpEndpoint = httptransport.NewClient(
+		"GET",
+		copyURL(u, "/pros/{ProID}/payouts/{By}"),
+		EncodeHTTPGetPayoutsRequest,
+		DecodeHTTPGetPayoutsResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	gpEndpoint = opentracing.TraceClient(tracer, "GetPayouts")(gpEndpoint)
+	return gpEndpoint, nil
+}
@@ -93,6 +93,10 @@ func (mw serviceLoggingMiddleware) GetProEstablishments(ctx context.Context, est
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetProEstablishments(ctx context.Context, estabID int64) ([]svcdb.Establishment, error) {
+	// estabID is the ID of the pro
+	if _, err := authenticatedPro(ctx, estabID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetProEstablishments(ctx, estabID)
 }
 
@@ -104,6 +104,9 @@ func (mw serviceLoggingMiddleware) GetSoirees(ctx context.Context, estabID int64
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) GetSoirees(ctx context.Context, estabID int64) ([]svcdb.Soiree, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
 	return mw.next.GetSoirees(ctx, estabID)
 }
 
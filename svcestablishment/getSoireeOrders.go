@@ -23,29 +23,31 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) GetSoireeOrders(ctx context.Context, SoireeID int64) ([]svcdb.Order, error) {
-	orders, err := s.svcdb.GetSoireeOrders(ctx, SoireeID)
-	return orders, dbToHTTPErr(err)
+func (s Service) GetSoireeOrders(ctx context.Context, SoireeID int64, page svcdb.Page) ([]svcdb.Order, string, error) {
+	orders, next, err := s.svcdb.GetSoireeOrders(ctx, SoireeID, page.Bounded())
+	return orders, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type GetSoireeOrdersRequest struct {
-	SoireeID int64 `json:"id"`
+	SoireeID int64      `json:"id"`
+	Page     svcdb.Page `json:"page"`
 }
 
 type GetSoireeOrdersResponse struct {
-	Orders []svcdb.Order `json:"Orders"`
+	Orders     []svcdb.Order `json:"Orders"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 func GetSoireeOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(GetSoireeOrdersRequest)
-		Orders, err := svc.GetSoireeOrders(ctx, req.SoireeID)
+		Orders, next, err := svc.GetSoireeOrders(ctx, req.SoireeID, req.Page)
 		if err != nil {
 			fmt.Println("Error GetSoireeOrdersEndpoint : ", err.Error())
 			return nil, err
 		}
-		return GetSoireeOrdersResponse{Orders: Orders}, nil
+		return GetSoireeOrdersResponse{Orders: Orders, NextCursor: next}, nil
 	}
 }
 
@@ -65,6 +67,13 @@ func DecodeHTTPGetSoireeOrdersRequest(_ context.Context, r *http.Request) (inter
 	}
 	(&request).SoireeID = SoireeID
 
+	page, err := svcdb.PageFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPGetSoireeOrdersRequest 3 : ", err.Error())
+		return nil, RequestError
+	}
+	(&request).Page = page
+
 	return request, nil
 }
 
@@ -89,8 +98,8 @@ func GetSoireeOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Trace
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64) ([]svcdb.Order, error) {
-	Orders, err := mw.next.GetSoireeOrders(ctx, SoireeID)
+func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64, page svcdb.Page) ([]svcdb.Order, string, error) {
+	Orders, next, err := mw.next.GetSoireeOrders(ctx, SoireeID, page)
 
 	mw.logger.Log(
 		"method", "GetSoireeOrders",
@@ -98,19 +107,22 @@ func (mw serviceLoggingMiddleware) GetSoireeOrders(ctx context.Context, SoireeID
 		"response", Orders,
 		"took", time.Since(time.Now()),
 	)
-	return Orders, err
+	return Orders, next, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64) ([]svcdb.Order, error) {
-	return mw.next.GetSoireeOrders(ctx, SoireeID)
+func (mw serviceAuthenticationMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64, page svcdb.Page) ([]svcdb.Order, string, error) {
+	if err := mw.ownsSoiree(ctx, SoireeID); err != nil {
+		return nil, "", err
+	}
+	return mw.next.GetSoireeOrders(ctx, SoireeID, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64) ([]svcdb.Order, error) {
-	return mw.next.GetSoireeOrders(ctx, SoireeID)
+func (mw serviceInstrumentingMiddleware) GetSoireeOrders(ctx context.Context, SoireeID int64, page svcdb.Page) ([]svcdb.Order, string, error) {
+	return mw.next.GetSoireeOrders(ctx, SoireeID, page)
 }
 
 /*************** Main ***************/
@@ -133,17 +145,17 @@ func BuildGetSoireeOrdersEndpoint(svc IService, logger log.Logger, tracer stdope
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetSoireeOrders(ctx context.Context, etID int64) ([]svcdb.Order, error) {
+func (e Endpoints) GetSoireeOrders(ctx context.Context, etID int64, page svcdb.Page) ([]svcdb.Order, string, error) {
 	var Orders []svcdb.Order
 
-	request := GetSoireeOrdersRequest{SoireeID: etID}
+	request := GetSoireeOrdersRequest{SoireeID: etID, Page: page}
 	response, err := e.GetSoireeOrdersEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error GetSoireeOrders : ", err.Error())
-		return Orders, err
+		return Orders, "", err
 	}
 	Orders = response.(GetSoireeOrdersResponse).Orders
-	return Orders, err
+	return Orders, response.(GetSoireeOrdersResponse).NextCursor, err
 }
 
 func EncodeHTTPGetSoireeOrdersRequest(ctx context.Context, r *http.Request, request interface{}) error {
@@ -155,6 +167,7 @@ func EncodeHTTPGetSoireeOrdersRequest(ctx context.Context, r *http.Request, requ
 		return err
 	}
 	r.URL.Path = encodedUrl.Path
+	request.(GetSoireeOrdersRequest).Page.EncodeToRequest(r)
 	return nil
 }
 
@@ -3,8 +3,10 @@ package svcestablishment
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/go-kit/kit/auth/jwt"
@@ -16,13 +18,26 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /*************** Service ***************/
 /* Service - Business logic */
-func (s Service) GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	// return s.svcsoiree.GetStat(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
-	return 0, dbToHTTPErr(nil)
+func (s Service) GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (svcdb.MenuStat, error) {
+	var stat svcdb.MenuStat
+
+	// check the menu is one of the establishment
+	estab, err := s.svcdb.GetEstablishmentFromMenu(ctx, menuID)
+	if err != nil {
+		fmt.Println("GetStat (GetEstablishmentFromMenu) : " + err.Error())
+		return stat, dbToHTTPErr(err)
+	} else if estab.ID != establishmentID {
+		return stat, NotFoundError
+	}
+
+	stat, err = s.svcdb.GetMenuStat(ctx, menuID, from, to)
+	return stat, dbToHTTPErr(err)
 }
 
 // Merged : Service definition
@@ -30,22 +45,22 @@ func (s Service) GetStat(ctx context.Context, establishmentID, menuID int64, soi
 /*************** Endpoint ***************/
 /* Endpoint - Req/Resp */
 type GetStatRequest struct {
-	EstablishmentID int64
-	MenuID          int64
-	SoireeBegin     time.Time
-	SoireeEnd       time.Time
+	EstablishmentID int64     `json:"estabID"`
+	MenuID          int64     `json:"menuID"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
 }
 type GetStatResponse struct {
-	SoireeID int64
+	Stat svcdb.MenuStat `json:"stat"`
 }
 
 /* Endpoint - Create endpoint */
 func GetStatEndpoint(s IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		csReq := request.(GetStatRequest)
-		soireeID, err := s.GetStat(ctx, csReq.EstablishmentID, csReq.MenuID, csReq.SoireeBegin, csReq.SoireeEnd)
+		stat, err := s.GetStat(ctx, csReq.EstablishmentID, csReq.MenuID, csReq.From, csReq.To)
 		return GetStatResponse{
-			SoireeID: soireeID,
+			Stat: stat,
 		}, err
 	}
 }
@@ -56,11 +71,26 @@ func GetStatEndpoint(s IService) endpoint.Endpoint {
 /* Transport - *coder Request */
 func DecodeHTTPGetStatRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var req GetStatRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["EstabID"], 10, 64)
 	if err != nil {
 		return nil, RequestError
 	}
-	return req, err
+	(&req).EstablishmentID = estabID
+
+	menuID, err := strconv.ParseInt(mux.Vars(r)["MenuID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&req).MenuID = menuID
+
+	from, to, err := svcdb.RangeFromRequest(r)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&req).From, (&req).To = from, to
+
+	return req, nil
 }
 
 /* Transport - *coder Response */
@@ -74,7 +104,7 @@ func DecodeHTTPGetStatResponse(_ context.Context, r *http.Response) (interface{}
 }
 
 func GetStatHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
-	route := r.Methods("POST").Path("/stats").Handler(httptransport.NewServer(
+	route := r.Methods("GET").Path("/establishments/{EstabID:[0-9]+}/menus/{MenuID:[0-9]+}/stats").Handler(httptransport.NewServer(
 		endpoints.GetStatEndpoint,
 		DecodeHTTPGetStatRequest,
 		EncodeHTTPGenericResponse,
@@ -87,30 +117,33 @@ func GetStatHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logge
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (soireeID int64, err error) {
+func (mw serviceLoggingMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (stat svcdb.MenuStat, err error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "GetStat",
 			"establishmentID", establishmentID, "menuID", menuID,
-			"soireeBegin", soireeBegin, "soireeEnd", soireeEnd,
-			"soireeID", soireeID,
+			"from", from, "to", to,
+			"orders", stat.Orders,
 			"error", err,
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.GetStat(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
+	return mw.next.GetStat(ctx, establishmentID, menuID, from, to)
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (soireeID int64, err error) {
-	return mw.next.GetStat(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
+func (mw serviceAuthenticationMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (svcdb.MenuStat, error) {
+	if err := mw.ownsEstablishment(ctx, establishmentID); err != nil {
+		return svcdb.MenuStat{}, err
+	}
+	return mw.next.GetStat(ctx, establishmentID, menuID, from, to)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	return mw.next.GetStat(ctx, establishmentID, menuID, soireeBegin, soireeEnd)
+func (mw serviceInstrumentingMiddleware) GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (svcdb.MenuStat, error) {
+	return mw.next.GetStat(ctx, establishmentID, menuID, from, to)
 }
 
 /*************** Main ***************/
@@ -133,30 +166,41 @@ func BuildGetStatEndpoint(svc IService, logger log.Logger, tracer stdopentracing
 /*************** Client ***************/
 /* Client */
 // Forget limiter & circuitbreaker for now kthx
-func (e Endpoints) GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error) {
-	var soireeID int64
-
+func (e Endpoints) GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (svcdb.MenuStat, error) {
 	request := GetStatRequest{
 		EstablishmentID: establishmentID,
 		MenuID:          menuID,
-		SoireeBegin:     soireeBegin,
-		SoireeEnd:       soireeEnd,
+		From:            from,
+		To:              to,
 	}
 	response, err := e.GetStatEndpoint(ctx, request)
 	if err != nil {
-		return 0, err
+		return svcdb.MenuStat{}, err
+	}
+	return response.(GetStatResponse).Stat, nil
+}
+
+func EncodeHTTPGetStatRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	route := mux.NewRouter()
+	req := request.(GetStatRequest)
+	estabID := fmt.Sprintf("%v", req.EstablishmentID)
+	menuID := fmt.Sprintf("%v", req.MenuID)
+	encodedUrl, err := route.Path(r.URL.Path).URL("EstabID", estabID, "MenuID", menuID)
+	if err != nil {
+		return err
 	}
-	soireeID = response.(GetStatResponse).SoireeID
-	return soireeID, err
+	r.URL.Path = encodedUrl.Path
+	svcdb.EncodeRangeToRequest(r, req.From, req.To)
+	return nil
 }
 
 func ClientGetStat(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
 	var gefmEndpoint endpoint.Endpoint
 
 	gefmEndpoint = httptransport.NewClient(
-		"POST",
-		copyURL(u, "/GetStat"),
-		EncodeHTTPGenericRequest,
+		"GET",
+		copyURL(u, "/establishments/{EstabID}/menus/{MenuID}/stats"),
+		EncodeHTTPGetStatRequest,
 		DecodeHTTPGetStatResponse,
 		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
 	).Endpoint()
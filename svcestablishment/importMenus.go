@@ -0,0 +1,234 @@
+package svcestablishment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+func (s Service) ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error) {
+	imported, err := s.svcdb.ImportMenus(ctx, estabID, menus)
+	return imported, dbToHTTPErr(err)
+}
+
+/*************** Endpoint ***************/
+type importMenusRequest struct {
+	EstabID int64        `json:"estabID"`
+	Menus   []svcdb.Menu `json:"menus"`
+}
+
+type importMenusResponse struct {
+	Menus []svcdb.Menu `json:"menus"`
+}
+
+func ImportMenusEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importMenusRequest)
+		menus, err := svc.ImportMenus(ctx, req.EstabID, req.Menus)
+		return importMenusResponse{Menus: menus}, err
+	}
+}
+
+/*************** Transport ***************/
+// DecodeHTTPImportMenusRequest reads the menu file from the body, or from the
+// "file" field of a multipart form. The format is the "format" query
+// parameter, else guessed from the content type or the file extension.
+func DecodeHTTPImportMenusRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request importMenusRequest
+
+	estabID, err := strconv.ParseInt(mux.Vars(r)["EstabID"], 10, 64)
+	if err != nil {
+		return nil, RequestError
+	}
+	(&request).EstabID = estabID
+
+	file, format, err := menuFileFromRequest(r)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPImportMenusRequest 1 : ", err.Error())
+		return nil, RequestError
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(file, maxMenuFileSize+1))
+	if err != nil {
+		fmt.Println("Error DecodeHTTPImportMenusRequest 2 : ", err.Error())
+		return nil, RequestError
+	} else if len(data) > maxMenuFileSize {
+		return nil, MenuImportErr{Rows: []MenuRowError{{Error: "The menu file is too large"}}}
+	}
+
+	menus, rowErrs := readMenuFile(bytes.NewReader(data), format)
+	if len(rowErrs) > 0 {
+		return nil, MenuImportErr{Rows: rowErrs}
+	}
+	(&request).Menus = menus
+
+	return request, nil
+}
+
+func menuFileFromRequest(r *http.Request) (io.ReadCloser, string, error) {
+	var file io.ReadCloser
+	var guess string
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		part, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file = part
+		guess = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		mediaType, _, _ = mime.ParseMediaType(header.Header.Get("Content-Type"))
+	} else {
+		file = r.Body
+	}
+
+	switch {
+	case len(r.URL.Query().Get("format")) > 0:
+		return file, strings.ToLower(r.URL.Query().Get("format")), nil
+	case guess == MenuFileCSV || guess == MenuFileJSON:
+		return file, guess, nil
+	case mediaType == "text/csv" || mediaType == "application/csv":
+		return file, MenuFileCSV, nil
+	case mediaType == "application/json":
+		return file, MenuFileJSON, nil
+	}
+	file.Close()
+	return nil, "", errors.New("Unknown menu file format")
+}
+
+func DecodeHTTPImportMenusResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var response importMenusResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, RequestError
+	}
+	return response, nil
+}
+
+func ImportMenusHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/establishments/{EstabID:[0-9]+}/menus/import").Handler(httptransport.NewServer(
+		endpoints.ImportMenusEndpoint,
+		DecodeHTTPImportMenusRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "ImportMenus", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error) {
+	imported, err := mw.next.ImportMenus(ctx, estabID, menus)
+
+	mw.logger.Log(
+		"method", "ImportMenus",
+		"estabID", estabID,
+		"menus", len(menus),
+		"took", time.Since(time.Now()),
+	)
+	return imported, err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error) {
+	if err := mw.ownsEstablishment(ctx, estabID); err != nil {
+		return nil, err
+	}
+	return mw.next.ImportMenus(ctx, estabID, menus)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error) {
+	return mw.next.ImportMenus(ctx, estabID, menus)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildImportMenusEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "ImportMenus")
+		csLogger := log.With(logger, "method", "ImportMenus")
+
+		csEndpoint = ImportMenusEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "ImportMenus")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+// Forget limiter & circuitbreaker for now kthx
+func (e Endpoints) ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error) {
+	request := importMenusRequest{EstabID: estabID, Menus: menus}
+	response, err := e.ImportMenusEndpoint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.(importMenusResponse).Menus, nil
+}
+
+func EncodeHTTPImportMenusRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	var buf bytes.Buffer
+
+	route := mux.NewRouter()
+	req := request.(importMenusRequest)
+	encodedUrl, err := route.Path(r.URL.Path).URL("EstabID", fmt.Sprintf("%v", req.EstabID))
+	if err != nil {
+		return err
+	}
+	r.URL.Path = encodedUrl.Path
+
+	if err := writeMenuFile(&buf, req.Menus, MenuFileJSON); err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func ClientImportMenus(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/establishments/{EstabID}/menus/import"),
+		EncodeHTTPImportMenusRequest,
+		DecodeHTTPImportMenusResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "ImportMenus")(ceEndpoint)
+	return ceEndpoint, nil
+}
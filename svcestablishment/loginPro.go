@@ -13,8 +13,6 @@ import (
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -23,30 +21,29 @@ import (
 
 /*************** Service ***************/
 /* Service - Business logic */
-func (s Service) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (s Service) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	var pro svcdb.Pro
+	var session svcdb.Session
 
 	err := checkMandatoryProParams(old)
 	if err != nil {
 		fmt.Println("Error LoginPro (checkMandatoryProParams)" + err.Error())
-		return pro, "", err
+		return pro, session, err
 	}
 
 	pro, err = s.svcdb.GetPro(ctx, old)
 	if err != nil {
 		fmt.Println("Error LoginPro (GetPro)" + err.Error())
-		return pro, "", dbToHTTPErr(err)
+		return pro, session, dbToHTTPErr(err)
 	}
 
-	// Create a jwt token
-	signer := stdjwt.NewWithClaims(stdjwt.SigningMethodHS256, claims)
-	token, err := signer.SignedString(signKey)
+	session, err = s.newSession(ctx, pro.ID)
 	if err != nil {
-		fmt.Println("Error LoginPro (SignedString)" + err.Error())
-		return pro, "", err
+		fmt.Println("Error LoginPro (newSession)" + err.Error())
+		return pro, session, err
 	}
 
-	return pro, token, nil
+	return pro, session, nil
 }
 
 func checkMandatoryProParams(pro svcdb.Pro) error {
@@ -64,22 +61,22 @@ type LoginProRequest struct {
 }
 
 type LoginProResponse struct {
-	Pro   svcdb.Pro `json:"pro"`
-	Token string    `json:"token"`
+	Pro svcdb.Pro `json:"pro"`
+	svcdb.Session
 }
 
 /* Endpoint - Create endpoint */
 func LoginProEndpoint(s IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		csReq := request.(LoginProRequest)
-		pro, token, err := s.LoginPro(ctx, csReq.Pro)
+		pro, session, err := s.LoginPro(ctx, csReq.Pro)
 		if err != nil {
 			return nil, err
 		}
 
 		return LoginProResponse{
-			Pro:   pro,
-			Token: token,
+			Pro:     pro,
+			Session: session,
 		}, err
 	}
 }
@@ -122,27 +119,27 @@ func LoginProHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logg
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
-	pro, token, err := mw.next.LoginPro(ctx, old)
+func (mw serviceLoggingMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
+	pro, session, err := mw.next.LoginPro(ctx, old)
 
 	mw.logger.Log(
 		"method", "LoginPro",
-		"request", old,
-		"response", LoginProResponse{Pro: pro, Token: token},
+		"request", old.Redacted(),
+		"response", pro.Redacted(),
 		"took", time.Since(time.Now()),
 	)
-	return pro, token, err
+	return pro, session, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (mw serviceAuthenticationMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	return mw.next.LoginPro(ctx, old)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (mw serviceInstrumentingMiddleware) LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	return mw.next.LoginPro(ctx, old)
 }
 
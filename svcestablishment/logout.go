@@ -0,0 +1,141 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"svcdb"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// Logout ends the session of the request : its access token is revoked until
+// it expires, and refreshToken, when given, can't renew it anymore
+func (s Service) Logout(ctx context.Context, refreshToken string) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return TokenError
+	}
+
+	var refreshHash string
+	if len(refreshToken) > 0 {
+		refreshHash = svcdb.HashToken(refreshToken)
+	}
+	err := s.svcdb.RevokeTokens(ctx, svcdb.Revocation{
+		Subject: svcdb.TokenSubject(svcdb.SubjectPro, identity.ProID),
+		TokenID: identity.TokenID,
+		Until:   identity.ExpiresAt,
+	}, refreshHash)
+	return dbToHTTPErr(err)
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+type LogoutResponse struct {
+}
+
+/* Endpoint - Create endpoint */
+func LogoutEndpoint(s IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		csReq := request.(LogoutRequest)
+		err := s.Logout(ctx, csReq.RefreshToken)
+		return LogoutResponse{}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPLogoutRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req LogoutRequest
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, RequestError
+	}
+	return req, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPLogoutResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp LogoutResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func LogoutHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/logout").Handler(httptransport.NewServer(
+		endpoints.LogoutEndpoint,
+		DecodeHTTPLogoutRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "Logout", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	err := mw.next.Logout(ctx, refreshToken)
+
+	mw.logger.Log(
+		"method", "Logout",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	return mw.next.Logout(ctx, refreshToken)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) Logout(ctx context.Context, refreshToken string) error {
+	return mw.next.Logout(ctx, refreshToken)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildLogoutEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "Logout")
+		csLogger := log.With(logger, "method", "Logout")
+
+		csEndpoint = LogoutEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "Logout")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
@@ -0,0 +1,129 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"svcdb"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// LogoutAll ends every session of the pro, on all its devices : the tokens
+// issued until now are revoked and the refresh tokens deleted
+func (s Service) LogoutAll(ctx context.Context) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return TokenError
+	}
+
+	now := time.Now()
+	err := s.svcdb.RevokeTokens(ctx, svcdb.Revocation{
+		Subject: svcdb.TokenSubject(svcdb.SubjectPro, identity.ProID),
+		Before:  now,
+		Until:   now.Add(tokenDuration),
+	}, "")
+	return dbToHTTPErr(err)
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type LogoutAllRequest struct {
+}
+type LogoutAllResponse struct {
+}
+
+/* Endpoint - Create endpoint */
+func LogoutAllEndpoint(s IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		err := s.LogoutAll(ctx)
+		return LogoutAllResponse{}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPLogoutAllRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return LogoutAllRequest{}, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPLogoutAllResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp LogoutAllResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func LogoutAllHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/logout/all").Handler(httptransport.NewServer(
+		endpoints.LogoutAllEndpoint,
+		DecodeHTTPLogoutAllRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "LogoutAll", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) LogoutAll(ctx context.Context) error {
+	err := mw.next.LogoutAll(ctx)
+
+	mw.logger.Log(
+		"method", "LogoutAll",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) LogoutAll(ctx context.Context) error {
+	return mw.next.LogoutAll(ctx)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) LogoutAll(ctx context.Context) error {
+	return mw.next.LogoutAll(ctx)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildLogoutAllEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "LogoutAll")
+		csLogger := log.With(logger, "method", "LogoutAll")
+
+		csEndpoint = LogoutAllEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "LogoutAll")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
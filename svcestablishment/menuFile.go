@@ -0,0 +1,256 @@
+package svcestablishment
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"svcdb"
+)
+
+// A menu file holds the menus of an establishment with their consos, either
+// as CSV with one row per conso or as the JSON array returned by GetMenu.
+// CSV prices are written in euros, JSON ones in cents as price_cents.
+// Exported files can be edited in a spreadsheet and imported back.
+const (
+	MenuFileCSV  = "csv"
+	MenuFileJSON = "json"
+
+	maxMenuFileSize = 1 << 20
+)
+
+var menuFileColumns = []string{"menu", "menu_description", "name", "price", "description", "picture"}
+
+// MenuRowError is a problem found on one row of a menu file
+type MenuRowError struct {
+	Row   int    `json:"row"`             // CSV row, header included, or JSON menu position, from 1
+	Conso int    `json:"conso,omitempty"` // JSON conso position in its menu, from 1
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// MenuImportErr lists every row of a menu file that cannot be imported
+type MenuImportErr struct {
+	Rows []MenuRowError
+}
+
+func (e MenuImportErr) Error() string {
+	return fmt.Sprintf("Invalid menu file, %d row(s) in error", len(e.Rows))
+}
+
+// readMenuFile parses and validates a menu file, nothing of it should be
+// imported when errors are returned
+func readMenuFile(r io.Reader, format string) ([]svcdb.Menu, []MenuRowError) {
+	var menus []svcdb.Menu
+	var rowErrs []MenuRowError
+
+	switch format {
+	case MenuFileCSV:
+		menus, rowErrs = readMenusCSV(r)
+	case MenuFileJSON:
+		menus, rowErrs = readMenusJSON(r)
+	default:
+		return nil, []MenuRowError{{Error: "Unknown menu file format " + strconv.Quote(format)}}
+	}
+	if len(rowErrs) == 0 && len(menus) == 0 {
+		rowErrs = append(rowErrs, MenuRowError{Error: "The menu file is empty"})
+	}
+	return menus, rowErrs
+}
+
+func readMenusCSV(r io.Reader) ([]svcdb.Menu, []MenuRowError) {
+	var menus []svcdb.Menu
+	var rowErrs []MenuRowError
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []MenuRowError{{Row: 1, Error: "Cannot read the header : " + err.Error()}}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range []string{"menu", "name", "price"} {
+		if _, ok := columns[name]; !ok {
+			rowErrs = append(rowErrs, MenuRowError{Row: 1, Field: name, Error: "Missing column"})
+		}
+	}
+	if len(rowErrs) > 0 {
+		return nil, rowErrs
+	}
+
+	index := make(map[string]int)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			rowErrs = append(rowErrs, MenuRowError{Row: row, Error: err.Error()})
+			break
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if len(strings.Join(record, "")) == 0 {
+			continue
+		}
+
+		menuName := field("menu")
+		if len(menuName) == 0 {
+			rowErrs = append(rowErrs, MenuRowError{Row: row, Field: "menu", Error: "Required"})
+			continue
+		}
+		i, ok := index[menuName]
+		if !ok {
+			i = len(menus)
+			index[menuName] = i
+			menus = append(menus, svcdb.Menu{Name: menuName})
+		}
+		if desc := field("menu_description"); len(desc) > 0 {
+			if len(menus[i].Desc) > 0 && menus[i].Desc != desc {
+				rowErrs = append(rowErrs, MenuRowError{Row: row, Field: "menu_description", Error: "Differs from a previous row of " + strconv.Quote(menuName)})
+			}
+			menus[i].Desc = desc
+		}
+
+		// A row without any conso field only declares the menu
+		if len(field("name")+field("price")+field("description")+field("picture")) == 0 {
+			continue
+		}
+		conso := svcdb.Conso{
+			Name:        field("name"),
+			Description: field("description"),
+			Picture:     field("picture"),
+		}
+		if price, err := parsePrice(field("price")); err != nil {
+			rowErrs = append(rowErrs, MenuRowError{Row: row, Field: "price", Error: err.Error()})
+		} else {
+			conso.Price = price
+		}
+		for _, rowErr := range validateConso(conso, menus[i].Consommations) {
+			rowErr.Row = row
+			rowErrs = append(rowErrs, rowErr)
+		}
+		menus[i].Consommations = append(menus[i].Consommations, conso)
+	}
+	return menus, rowErrs
+}
+
+func readMenusJSON(r io.Reader) ([]svcdb.Menu, []MenuRowError) {
+	var menus []svcdb.Menu
+	var rowErrs []MenuRowError
+
+	if err := json.NewDecoder(r).Decode(&menus); err != nil {
+		return nil, []MenuRowError{{Error: "Cannot read the file : " + err.Error()}}
+	}
+
+	names := make(map[string]bool)
+	for i := range menus {
+		menus[i].Name = strings.TrimSpace(menus[i].Name)
+		if len(menus[i].Name) == 0 {
+			rowErrs = append(rowErrs, MenuRowError{Row: i + 1, Field: "name", Error: "Required"})
+		} else if names[menus[i].Name] {
+			rowErrs = append(rowErrs, MenuRowError{Row: i + 1, Field: "name", Error: "Duplicate menu " + strconv.Quote(menus[i].Name)})
+		}
+		names[menus[i].Name] = true
+
+		consos := menus[i].Consommations
+		for j := range consos {
+			consos[j].Name = strings.TrimSpace(consos[j].Name)
+			for _, rowErr := range validateConso(consos[j], consos[:j]) {
+				rowErr.Row, rowErr.Conso = i+1, j+1
+				rowErrs = append(rowErrs, rowErr)
+			}
+		}
+	}
+	return menus, rowErrs
+}
+
+// validateConso checks a conso against the ones already read in its menu
+func validateConso(conso svcdb.Conso, previous []svcdb.Conso) []MenuRowError {
+	var rowErrs []MenuRowError
+
+	if len(conso.Name) == 0 {
+		rowErrs = append(rowErrs, MenuRowError{Field: "name", Error: "Required"})
+	}
+	for _, other := range previous {
+		if len(conso.Name) > 0 && other.Name == conso.Name {
+			rowErrs = append(rowErrs, MenuRowError{Field: "name", Error: "Duplicate conso " + strconv.Quote(conso.Name) + " in this menu"})
+			break
+		}
+	}
+	if conso.Price < 0 {
+		rowErrs = append(rowErrs, MenuRowError{Field: "price", Error: "Must not be negative"})
+	}
+	return rowErrs
+}
+
+// parsePrice reads a price written in euros into cents, accepting a comma as
+// decimal separator as spreadsheets often write
+func parsePrice(value string) (int64, error) {
+	if len(value) == 0 {
+		return 0, fmt.Errorf("Required")
+	}
+	price, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || math.IsInf(price, 0) || math.IsNaN(price) {
+		return 0, fmt.Errorf("Not a number : %q", value)
+	}
+	cents := math.Round(price * 100)
+	if math.Abs(cents-price*100) > 1e-6 {
+		return 0, fmt.Errorf("More than 2 decimals : %q", value)
+	}
+	return int64(cents), nil
+}
+
+// formatPrice writes cents in euros, the way parsePrice reads them
+func formatPrice(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// writeMenuFile writes menus in a format readMenuFile reads back
+func writeMenuFile(w io.Writer, menus []svcdb.Menu, format string) error {
+	if format == MenuFileJSON {
+		if menus == nil {
+			menus = []svcdb.Menu{}
+		}
+		return json.NewEncoder(w).Encode(menus)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(menuFileColumns); err != nil {
+		return err
+	}
+	for _, menu := range menus {
+		if len(menu.Consommations) == 0 {
+			writer.Write([]string{menu.Name, menu.Desc, "", "", "", ""})
+		}
+		for _, conso := range menu.Consommations {
+			writer.Write([]string{
+				menu.Name,
+				menu.Desc,
+				conso.Name,
+				formatPrice(conso.Price),
+				conso.Description,
+				conso.Picture,
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -40,6 +40,11 @@ type putOrderResponse struct {
 	Err   error `json:"err,omitempty"`
 }
 
+// Failed keeps the failed step updates out of the idempotency store
+func (r putOrderResponse) Failed() error {
+	return r.Err
+}
+
 func PutOrderEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(putOrderRequest)
@@ -87,7 +92,7 @@ func PutOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logg
 		endpoints.PutOrderEndpoint,
 		DecodeHTTPPutOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "PutOrder", logger), jwt.HTTPToContext()))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "PutOrder", logger), jwt.HTTPToContext(), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -110,6 +115,9 @@ func (mw serviceLoggingMiddleware) PutOrder(ctx context.Context, orderID int64,
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) PutOrder(ctx context.Context, orderID int64, step string, flag bool) (svcdb.Order, error) {
+	if err := mw.ownsOrder(ctx, orderID); err != nil {
+		return svcdb.Order{}, err
+	}
 	return mw.next.PutOrder(ctx, orderID, step, flag)
 }
 
@@ -121,7 +129,7 @@ func (mw serviceInstrumentingMiddleware) PutOrder(ctx context.Context, orderID i
 
 /*************** Main ***************/
 /* Main */
-func BuildPutOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+func BuildPutOrderEndpoint(svc IService, db svcdb.IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
 	var csEndpoint endpoint.Endpoint
 	{
 		csDuration := duration.With("method", "PutOrder")
@@ -130,6 +138,7 @@ func BuildPutOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracin
 		csEndpoint = PutOrderEndpoint(svc)
 		csEndpoint = opentracing.TraceServer(tracer, "PutOrder")(csEndpoint)
 		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointIdempotencyMiddleware(db, "svcestablishment.PutOrder")(csEndpoint)
 		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
 		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
 	}
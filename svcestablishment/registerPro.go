@@ -13,8 +13,6 @@ import (
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
 
-	stdjwt "github.com/dgrijalva/jwt-go"
-
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -23,36 +21,40 @@ import (
 
 /*************** Service ***************/
 /* Service - Business logic */
-func (s Service) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (s Service) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	var pro svcdb.Pro
+	var session svcdb.Session
 
 	err := checkMandatoryProParams(old)
 	if err != nil {
 		fmt.Println("Error RegisterPro (checkMandatoryProParams)")
-		return pro, "", err
+		return pro, session, err
 	}
 
 	pro, err = s.svcpayment.RegisterPro(ctx, old)
 	if err != nil {
 		fmt.Println("Error RegisterPro (SvcPaymentRegisterPro) : " + err.Error())
-		return pro, "", err
+		return pro, session, err
 	}
 	
 	pro, err = s.svcdb.CreatePro(ctx, pro)
 	if err != nil {
 		fmt.Println("Error RegisterPro (CreatePro)")
-		return pro, "", dbToHTTPErr(err)
+		return pro, session, dbToHTTPErr(err)
 	}
 
-	// Create a jwt token
-	signer := stdjwt.NewWithClaims(stdjwt.SigningMethodHS256, claims)
-	token, err := signer.SignedString(signKey)
+	session, err = s.newSession(ctx, pro.ID)
 	if err != nil {
-		fmt.Println("Error RegisterPro (SignedString)")
-		return pro, "", err
+		fmt.Println("Error RegisterPro (newSession)")
+		return pro, session, err
+	}
+
+	// the account exists anyway, the link can be mailed again by SendVerification
+	if err = s.sendVerification(ctx, pro); err != nil {
+		fmt.Println("Error RegisterPro (sendVerification) : " + err.Error())
 	}
 
-	return pro, token, err
+	return pro, session, nil
 }
 
 // Merged : Service definition
@@ -63,18 +65,18 @@ type RegisterProRequest struct {
 	Pro svcdb.Pro `json:"pro"`
 }
 type RegisterProResponse struct {
-	Pro   svcdb.Pro
-	Token string
+	Pro svcdb.Pro
+	svcdb.Session
 }
 
 /* Endpoint - Create endpoint */
 func RegisterProEndpoint(s IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		csReq := request.(RegisterProRequest)
-		pro, token, err := s.RegisterPro(ctx, csReq.Pro)
+		pro, session, err := s.RegisterPro(ctx, csReq.Pro)
 		return RegisterProResponse{
-			Pro:   pro,
-			Token: token,
+			Pro:     pro,
+			Session: session,
 		}, err
 	}
 }
@@ -117,27 +119,27 @@ func RegisterProHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
-	new, token, err := mw.next.RegisterPro(ctx, old)
+func (mw serviceLoggingMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
+	new, session, err := mw.next.RegisterPro(ctx, old)
 
 	mw.logger.Log(
 		"method", "RegisterPro",
-		"request", old,
-		"response", RegisterProResponse{Pro: new, Token: token},
+		"request", old.Redacted(),
+		"response", new.Redacted(),
 		"took", time.Since(time.Now()),
 	)
-	return new, token, err
+	return new, session, err
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (mw serviceAuthenticationMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	return mw.next.RegisterPro(ctx, old)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error) {
+func (mw serviceInstrumentingMiddleware) RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error) {
 	return mw.next.RegisterPro(ctx, old)
 }
 
@@ -0,0 +1,156 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"mailer"
+	"svcdb"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// RequestPasswordReset mails a link to reset the password of the pro of
+// email. An unknown email succeeds too, not to tell which emails have an account.
+func (s Service) RequestPasswordReset(ctx context.Context, email string) error {
+	if len(email) == 0 {
+		return RequestError
+	}
+
+	proID, err := s.svcdb.GetAccountByEmail(ctx, svcdb.SubjectPro, email)
+	if err != nil {
+		if dbToHTTPErr(err) == NotFoundError {
+			return nil
+		}
+		fmt.Println("RequestPasswordReset (GetAccountByEmail) : " + err.Error())
+		return dbToHTTPErr(err)
+	}
+
+	token, err := s.newAccountToken(ctx, proID, email, svcdb.PurposeResetPassword, resetDuration)
+	if err != nil {
+		return err
+	}
+	err = s.mail.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "NightLine Pro - Reset your password",
+		Body: "To choose a new password, open the link below within the hour :\n\n" +
+			s.appURL + "/password/reset?token=" + url.QueryEscape(token) + "\n\n" +
+			"If you didn't ask for it, just ignore this email : your password is unchanged.\n",
+	})
+	if err != nil {
+		fmt.Println("RequestPasswordReset (Send) : " + err.Error())
+		return ConnError
+	}
+	return nil
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type RequestPasswordResetRequest struct {
+	Email string `json:"email"`
+}
+type RequestPasswordResetResponse struct {
+}
+
+/* Endpoint - Create endpoint */
+func RequestPasswordResetEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RequestPasswordResetRequest)
+		err := svc.RequestPasswordReset(ctx, req.Email)
+		return RequestPasswordResetResponse{}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPRequestPasswordResetRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req RequestPasswordResetRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPRequestPasswordResetRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPRequestPasswordResetResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp RequestPasswordResetResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func RequestPasswordResetHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/password/reset").Handler(httptransport.NewServer(
+		endpoints.RequestPasswordResetEndpoint,
+		DecodeHTTPRequestPasswordResetRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "RequestPasswordReset", logger)))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	err := mw.next.RequestPasswordReset(ctx, email)
+
+	mw.logger.Log(
+		"method", "RequestPasswordReset",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	return mw.next.RequestPasswordReset(ctx, email)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) RequestPasswordReset(ctx context.Context, email string) error {
+	return mw.next.RequestPasswordReset(ctx, email)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildRequestPasswordResetEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "RequestPasswordReset")
+		csLogger := log.With(logger, "method", "RequestPasswordReset")
+
+		csEndpoint = RequestPasswordResetEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "RequestPasswordReset")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
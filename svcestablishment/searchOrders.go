@@ -22,26 +22,28 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	orders, err := s.svcpayment.SearchOrders(ctx, order)
-	return orders, dbToHTTPErr(err)
+func (s Service) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	orders, next, err := s.svcpayment.SearchOrders(ctx, order, page.Bounded())
+	return orders, next, dbToHTTPErr(err)
 }
 
 /*************** Endpoint ***************/
 type searchOrdersRequest struct {
 	Order		svcdb.Order	`json:"order"`
+	Page		svcdb.Page	`json:"page"`
 }
 
 type searchOrdersResponse struct {
 	Orders		[]svcdb.Order	`json:"orders"`
+	NextCursor	string	`json:"next_cursor,omitempty"`
 	Err			error `json:"err,omitempty"`
 }
 
 func SearchOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchOrdersRequest)
-		orders, err := svc.SearchOrders(ctx, req.Order)
-		return searchOrdersResponse{Orders: orders, Err: err}, nil
+		orders, next, err := svc.SearchOrders(ctx, req.Order, req.Page)
+		return searchOrdersResponse{Orders: orders, NextCursor: next, Err: err}, nil
 	}
 }
 
@@ -74,26 +76,33 @@ func SearchOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
+func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchOrders",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchOrders(ctx, order)
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Authentication ***************/
 /* Authentication */
-func (mw serviceAuthenticationMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	return mw.next.SearchOrders(ctx, order)
+func (mw serviceAuthenticationMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	// a pro only searches the orders of one of its soirees
+	if order.Soiree.ID == 0 {
+		return nil, "", AuthError
+	}
+	if err := mw.ownsSoiree(ctx, order.Soiree.ID); err != nil {
+		return nil, "", err
+	}
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error) {
-	return mw.next.SearchOrders(ctx, order)
+func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error) {
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Main ***************/
@@ -116,17 +125,17 @@ func BuildSearchOrdersEndpoint(svc IService, logger log.Logger, tracer stdopentr
 /*************** Client ***************/
 /* Client */
 // Forsearch limiter & circuitbreaker for now kthx
-func (e Endpoints) SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error) {
+func (e Endpoints) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error) {
 	var orders svcdb.Orders
 
-	request := searchOrdersRequest{Order: order}
+	request := searchOrdersRequest{Order: order, Page: page}
 	response, err := e.SearchOrdersEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error Client SearchOrders : ", err.Error())
-		return orders, err
+		return orders, "", err
 	}
 	orders = response.(searchOrdersResponse).Orders
-	return orders, response.(searchOrdersResponse).Err
+	return orders, response.(searchOrdersResponse).NextCursor, response.(searchOrdersResponse).Err
 }
 
 func ClientSearchOrders(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
@@ -0,0 +1,157 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"mailer"
+	"svcdb"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// SendVerification mails again the link verifying the email of the
+// authenticated pro, the link mailed before can't be used anymore
+func (s Service) SendVerification(ctx context.Context) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return TokenError
+	}
+
+	pro, err := s.svcdb.GetProByID(ctx, identity.ProID)
+	if err != nil {
+		fmt.Println("SendVerification (GetProByID) : " + err.Error())
+		return dbToHTTPErr(err)
+	} else if pro.Verified {
+		return nil
+	}
+	return s.sendVerification(ctx, pro)
+}
+
+// sendVerification mails the link verifying the email of pro
+func (s Service) sendVerification(ctx context.Context, pro svcdb.Pro) error {
+	token, err := s.newAccountToken(ctx, pro.ID, pro.Email, svcdb.PurposeVerifyEmail, verifyDuration)
+	if err != nil {
+		return err
+	}
+	err = s.mail.Send(ctx, mailer.Message{
+		To:      pro.Email,
+		Subject: "NightLine Pro - Verify your email",
+		Body: "Welcome on NightLine Pro " + pro.Firstname + " !\n\n" +
+			"To verify your email, open the link below within 24 hours :\n\n" +
+			s.appURL + "/email/verify?token=" + url.QueryEscape(token) + "\n",
+	})
+	if err != nil {
+		fmt.Println("sendVerification (Send) : " + err.Error())
+		return ConnError
+	}
+	return nil
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type SendVerificationRequest struct {
+}
+type SendVerificationResponse struct {
+}
+
+/* Endpoint - Create endpoint */
+func SendVerificationEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		err := svc.SendVerification(ctx)
+		return SendVerificationResponse{}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPSendVerificationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return SendVerificationRequest{}, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPSendVerificationResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp SendVerificationResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func SendVerificationHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/email/verify/send").Handler(httptransport.NewServer(
+		endpoints.SendVerificationEndpoint,
+		DecodeHTTPSendVerificationRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "SendVerification", logger), jwt.HTTPToContext()))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) SendVerification(ctx context.Context) error {
+	err := mw.next.SendVerification(ctx)
+
+	mw.logger.Log(
+		"method", "SendVerification",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) SendVerification(ctx context.Context) error {
+	if _, err := authenticatedPro(ctx, 0); err != nil {
+		return err
+	}
+	return mw.next.SendVerification(ctx)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) SendVerification(ctx context.Context) error {
+	return mw.next.SendVerification(ctx)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildSendVerificationEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "SendVerification")
+		csLogger := log.With(logger, "method", "SendVerification")
+
+		csEndpoint = SendVerificationEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "SendVerification")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointAuthenticationMiddleware()(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"mailer"
 	"svcdb"
 	"svcpayment"
 	"svcsoiree"
@@ -13,15 +14,23 @@ import (
 /* Service interface */
 type IService interface {
 	CreateSoiree(ctx context.Context, establishmentID, menuID int64, s svcdb.Soiree) (int64, error)
-	DeliverOrder(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error)
+	DeliverOrder(ctx context.Context, establishmentID, orderID int64) (svcdb.Order, error)
+	AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error)
 	GetOrder(ctx context.Context, orderID int64) (svcdb.Order, error)
 	GetOrdersBySoiree(ctx context.Context, soireeID int64) ([]svcdb.Order, error)
-	SearchOrders(ctx context.Context, order svcdb.Order) ([]svcdb.Order, error)
+	SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) ([]svcdb.Order, string, error)
 	PutOrder(ctx context.Context, orderID int64, step string, flag bool) (svcdb.Order, error)
 	GetSoirees(ctx context.Context, estabID int64) ([]svcdb.Soiree, error)
-	GetStat(ctx context.Context, establishmentID, menuID int64, soireeBegin, soireeEnd time.Time) (int64, error)
-	LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error)
-	RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, string, error)
+	GetStat(ctx context.Context, establishmentID, menuID int64, from, to time.Time) (svcdb.MenuStat, error)
+	LoginPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error)
+	RegisterPro(ctx context.Context, old svcdb.Pro) (svcdb.Pro, svcdb.Session, error)
+	RefreshToken(ctx context.Context, refreshToken string) (svcdb.Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	SendVerification(ctx context.Context) error
 	CreateEstab(ctx context.Context, estab svcdb.Establishment, proID int64) (svcdb.Establishment, error)
 	UpdateEstab(ctx context.Context, establishment svcdb.Establishment) (svcdb.Establishment, error)
 	DeleteEstab(ctx context.Context, estabID int64) error
@@ -32,18 +41,25 @@ type IService interface {
 	GetConso(ctx context.Context, estabID int64) ([]svcdb.Conso, error)
 	GetConsoByOrderID(ctx context.Context, consoID int64) (svcdb.Conso, error)
 	GetMenu(ctx context.Context, estabID int64) ([]svcdb.Menu, error)
+	ImportMenus(ctx context.Context, estabID int64, menus []svcdb.Menu) ([]svcdb.Menu, error)
 	GetEstablishmentType(ctx context.Context) ([]string, error)
-	GetSoireeOrders(ctx context.Context, estabID int64) ([]svcdb.Order, error)
+	GetSoireeOrders(ctx context.Context, estabID int64, page svcdb.Page) ([]svcdb.Order, string, error)
 	GetProEstablishments(ctx context.Context, estabID int64) ([]svcdb.Establishment, error)
 	GetAnalyseP(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseP, error)
+	GetAnalyseC(ctx context.Context, estabID int64, soireeID int64) ([]svcdb.AnalyseC, error)
+	GetAnalyseF(ctx context.Context, estabID int64, soireeID int64, from, to time.Time) ([]svcdb.AnalyseF, error)
+	GetPayouts(ctx context.Context, proID int64, by string, from, to time.Time) ([]Payout, error)
 }
 
 /* Errors definition */
 var (
-	ConnError     = errors.New("The server was acting as a gateway or proxy and received an invalid response from the upstream server")
-	RequestError  = errors.New("The server cannot or will not process the request due to an apparent client error")
-	AuthError     = errors.New("The user might not have the necessary permissions for a resource, or may need an account of some sort")
-	NotFoundError = errors.New("The requested resource could not be found.")
+	ConnError          = errors.New("The server was acting as a gateway or proxy and received an invalid response from the upstream server")
+	RequestError       = errors.New("The server cannot or will not process the request due to an apparent client error")
+	AuthError          = errors.New("The user might not have the necessary permissions for a resource, or may need an account of some sort")
+	NotFoundError      = errors.New("The requested resource could not be found.")
+	OrderNotReadyErr   = errors.New("The order is not waiting to be delivered")
+	NoCancelRequestErr = errors.New("No cancellation of the order is waiting for an answer")
+	TokenError         = errors.New("The request needs a valid and unexpired authentication token")
 )
 
 /* Misc */
@@ -68,19 +84,30 @@ func dbToHTTPErr(err error) error {
 	switch err.Error() {
 	case "EOF":
 		return NotFoundError
+	case svcdb.IdempotencyConflictErr.Error():
+		return svcdb.IdempotencyConflictErr
+	case svcdb.IdempotencyInProgressErr.Error():
+		return svcdb.IdempotencyInProgressErr
+	case svcdb.RefreshTokenErr.Error():
+		return TokenError
+	case svcdb.AccountTokenErr.Error():
+		return svcdb.AccountTokenErr
 	}
 	return err
 }
 
 /* Service implementation */
-func NewService(db svcdb.IService, soiree svcsoiree.IService, payment svcpayment.IService) IService {
-	return Service{svcdb: db, svcsoiree: soiree, svcpayment: payment}
+// NewService builds the service, the links mailed to the pros point to appURL
+func NewService(db svcdb.IService, soiree svcsoiree.IService, payment svcpayment.IService, mail mailer.Sender, appURL string) IService {
+	return Service{svcdb: db, svcsoiree: soiree, svcpayment: payment, mail: mail, appURL: appURL}
 }
 
 type Service struct {
 	svcdb      svcdb.IService
 	svcsoiree  svcsoiree.IService
 	svcpayment svcpayment.IService
+	mail       mailer.Sender
+	appURL     string
 }
 
 /* Middleware interface */
@@ -14,6 +14,8 @@ import (
 
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /* HTTP handlers & routing */
@@ -26,6 +28,7 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 	CreateSoireeHTTPHandler(endpoints, tracer, logger, r, options)
 	DeliverOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	AnswerCancelHTTPHandler(endpoints, tracer, logger, r, options)
 	GetOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	GetOrdersBySoireeHTTPHandler(endpoints, tracer, logger, r, options)
 	SearchOrdersHTTPHandler(endpoints, tracer, logger, r, options)
@@ -34,6 +37,13 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	GetStatHTTPHandler(endpoints, tracer, logger, r, options)
 	LoginProHTTPHandler(endpoints, tracer, logger, r, options)
 	RegisterProHTTPHandler(endpoints, tracer, logger, r, options)
+	RefreshTokenHTTPHandler(endpoints, tracer, logger, r, options)
+	LogoutHTTPHandler(endpoints, tracer, logger, r, options)
+	LogoutAllHTTPHandler(endpoints, tracer, logger, r, options)
+	RequestPasswordResetHTTPHandler(endpoints, tracer, logger, r, options)
+	ResetPasswordHTTPHandler(endpoints, tracer, logger, r, options)
+	VerifyEmailHTTPHandler(endpoints, tracer, logger, r, options)
+	SendVerificationHTTPHandler(endpoints, tracer, logger, r, options)
 	CreateEstabHTTPHandler(endpoints, tracer, logger, r, options)
 	UpdateEstabHTTPHandler(endpoints, tracer, logger, r, options)
 	UpdateProHTTPHandler(endpoints, tracer, logger, r, options)
@@ -42,10 +52,15 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	GetConsoHTTPHandler(endpoints, tracer, logger, r, options)
 	GetConsoByOrderIDHTTPHandler(endpoints, tracer, logger, r, options)
 	GetMenuHTTPHandler(endpoints, tracer, logger, r, options)
+	ImportMenusHTTPHandler(endpoints, tracer, logger, r, options)
+	ExportMenusHTTPHandler(endpoints, tracer, logger, r, options)
 	GetEstablishmentTypeHTTPHandler(endpoints, tracer, logger, r, options)
 	GetSoireeOrdersHTTPHandler(endpoints, tracer, logger, r, options)
 	GetProEstablishmentsHTTPHandler(endpoints, tracer, logger, r, options)
 	GetAnalysePHTTPHandler(endpoints, tracer, logger, r, options)
+	GetAnalyseCHTTPHandler(endpoints, tracer, logger, r, options)
+	GetAnalyseFHTTPHandler(endpoints, tracer, logger, r, options)
+	GetPayoutsHTTPHandler(endpoints, tracer, logger, r, options)
 	DeleteEstabHTTPHandler(endpoints, tracer, logger, r, options)
 	DeleteSoireeHTTPHandler(endpoints, tracer, logger, r, options)
 
@@ -54,7 +69,8 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /* Errors *coders */
 type errorWrapper struct {
-	Error string `json:"error"`
+	Error string         `json:"error"`
+	Rows  []MenuRowError `json:"rows,omitempty"`
 }
 
 func setDefaultHeaders(w http.ResponseWriter) http.ResponseWriter {
@@ -62,7 +78,7 @@ func setDefaultHeaders(w http.ResponseWriter) http.ResponseWriter {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Idempotency-Key")
 	return w
 }
 
@@ -71,13 +87,27 @@ func errorEncoder(_ context.Context, err error, w http.ResponseWriter) {
 	code := http.StatusInternalServerError
 	msg := err.Error()
 
+	if importErr, ok := err.(MenuImportErr); ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorWrapper{Error: msg, Rows: importErr.Rows})
+		return
+	}
+
 	switch err {
 	case ConnError:
 		code = http.StatusBadGateway
 	case AuthError:
 		code = http.StatusForbidden
-	case RequestError:
+	case TokenError:
+		code = http.StatusUnauthorized
+	case RequestError, svcdb.AccountTokenErr:
 		code = http.StatusBadRequest
+	case NotFoundError:
+		code = http.StatusNotFound
+	case OrderNotReadyErr, NoCancelRequestErr, svcdb.IdempotencyInProgressErr:
+		code = http.StatusConflict
+	case svcdb.IdempotencyConflictErr:
+		code = http.StatusUnprocessableEntity
 	}
 
 	w.WriteHeader(code)
@@ -89,6 +119,9 @@ func errorDecoder(r *http.Response) error {
 	if err := json.NewDecoder(r.Body).Decode(&w); err != nil {
 		return err
 	}
+	if len(w.Rows) > 0 {
+		return MenuImportErr{Rows: w.Rows}
+	}
 	return errors.New(w.Error)
 }
 
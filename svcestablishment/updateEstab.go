@@ -121,6 +121,9 @@ func (mw serviceLoggingMiddleware) UpdateEstab(ctx context.Context, oldEstab svc
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdateEstab(ctx context.Context, establishment svcdb.Establishment) (svcdb.Establishment, error) {
+	if err := mw.ownsEstablishment(ctx, establishment.ID); err != nil {
+		return svcdb.Establishment{}, err
+	}
 	return mw.next.UpdateEstab(ctx, establishment)
 }
 
@@ -101,7 +101,7 @@ func (mw serviceLoggingMiddleware) UpdatePro(ctx context.Context, oldPro svcdb.P
 
 	mw.logger.Log(
 		"method", "UpdatePro",
-		"request", oldPro,
+		"request", oldPro.Redacted(),
 		"response", newPro,
 		"took", time.Since(time.Now()),
 	)
@@ -111,6 +111,11 @@ func (mw serviceLoggingMiddleware) UpdatePro(ctx context.Context, oldPro svcdb.P
 /*************** Authentication ***************/
 /* Authentication */
 func (mw serviceAuthenticationMiddleware) UpdatePro(ctx context.Context, pro svcdb.Pro) (svcdb.Pro, error) {
+	proID, err := authenticatedPro(ctx, pro.ID)
+	if err != nil {
+		return svcdb.Pro{}, err
+	}
+	pro.ID = proID
 	return mw.next.UpdatePro(ctx, pro)
 }
 
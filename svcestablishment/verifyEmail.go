@@ -0,0 +1,137 @@
+package svcestablishment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+/* Service - Business logic */
+// VerifyEmail verifies the email of the pro with the token mailed to it
+func (s Service) VerifyEmail(ctx context.Context, token string) error {
+	if len(token) == 0 {
+		return RequestError
+	}
+
+	hash, err := parseAccountToken(token, svcdb.PurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+	_, err = s.svcdb.VerifyEmail(ctx, hash)
+	if err != nil {
+		fmt.Println("VerifyEmail (VerifyEmail) : " + err.Error())
+	}
+	return dbToHTTPErr(err)
+}
+
+// Merged : Service definition
+
+/*************** Endpoint ***************/
+/* Endpoint - Req/Resp */
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+type VerifyEmailResponse struct {
+}
+
+/* Endpoint - Create endpoint */
+func VerifyEmailEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(VerifyEmailRequest)
+		err := svc.VerifyEmail(ctx, req.Token)
+		return VerifyEmailResponse{}, err
+	}
+}
+
+// Merged : endpoints struct
+
+/*************** Transport ***************/
+/* Transport - *coder Request */
+func DecodeHTTPVerifyEmailRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req VerifyEmailRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		fmt.Println("Error DecodeHTTPVerifyEmailRequest : ", err.Error())
+		return req, RequestError
+	}
+	return req, nil
+}
+
+/* Transport - *coder Response */
+func DecodeHTTPVerifyEmailResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	var resp VerifyEmailResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	if err != nil {
+		return nil, RequestError
+	}
+	return resp, err
+}
+
+func VerifyEmailHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/email/verify").Handler(httptransport.NewServer(
+		endpoints.VerifyEmailEndpoint,
+		DecodeHTTPVerifyEmailRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "VerifyEmail", logger)))...,
+	))
+	return route
+}
+
+// Merged : HTTPHandler, errorWrapper struct */
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) VerifyEmail(ctx context.Context, token string) error {
+	err := mw.next.VerifyEmail(ctx, token)
+
+	mw.logger.Log(
+		"method", "VerifyEmail",
+		"took", time.Since(time.Now()),
+	)
+	return err
+}
+
+/*************** Authentication ***************/
+/* Authentication */
+func (mw serviceAuthenticationMiddleware) VerifyEmail(ctx context.Context, token string) error {
+	return mw.next.VerifyEmail(ctx, token)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) VerifyEmail(ctx context.Context, token string) error {
+	return mw.next.VerifyEmail(ctx, token)
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildVerifyEmailEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "VerifyEmail")
+		csLogger := log.With(logger, "method", "VerifyEmail")
+
+		csEndpoint = VerifyEmailEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "VerifyEmail")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
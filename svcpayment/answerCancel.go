@@ -0,0 +1,202 @@
+package svcpayment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+var NoCancelRequestErr = errors.New("No cancellation of the order is waiting for an answer")
+
+/*************** Service ***************/
+// AnswerCancel is the answer of the pro to the cancellation an user asked for
+// past LastCancellableStep : accepting it cancels the order and gives the
+// charges back, declining it lets the order go on
+func (s Service) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	var notifs []orderProgressNotif
+
+	order, err := s.svcdb.GetOrder(ctx, orderID)
+	if err != nil {
+		fmt.Println("AnswerCancel (GetOrder) : " + err.Error())
+		return order, err
+	} else if order.ID == 0 {
+		return order, errors.New("Target order not found")
+	}
+
+	open, ok := svcdb.OrderWorkflow.OpenStep(order)
+	if !ok || order.CancelRequest == 0 {
+		return order, NoCancelRequestErr
+	}
+	requester := order.CancelRequest
+
+	if !accept {
+		declined, err := s.svcdb.RequestCancel(ctx, orderID, 0, open.Name)
+		if err != nil && err.Error() == svcdb.StepNotOpenErr.Error() {
+			return order, NoCancelRequestErr
+		} else if err != nil {
+			fmt.Println("AnswerCancel (RequestCancel) : " + err.Error())
+			return order, err
+		}
+		notifs = append(notifs, orderProgressNotif{
+			Order:   declined,
+			UserID:  requester,
+			Step:    open.Name,
+			Message: "The establishment declined to cancel the order",
+		})
+		return s.returnSendNotifs(ctx, notifs, declined, nil)
+	}
+
+	/* Close the order before releasing the funds, so that they can't be captured meanwhile */
+	cancelled, err := s.svcdb.CancelOrder(ctx, orderID, requester, open.Name)
+	if err != nil && err.Error() == svcdb.StepNotOpenErr.Error() {
+		return order, NoCancelRequestErr
+	} else if err != nil {
+		fmt.Println("AnswerCancel (CancelOrder) : " + err.Error())
+		return order, err
+	}
+	pending := s.releaseCharges(ctx, cancelled)
+
+	for _, user := range cancelled.Users {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   cancelled,
+			UserID:  user.User.ID,
+			Step:    svcdb.CancelledStep,
+			Message: refundNotice("Order cancelled by the establishment, as asked by "+participantName(cancelled, requester), pending, user.User.ID),
+		})
+	}
+	return s.returnSendNotifs(ctx, notifs, cancelled, nil)
+}
+
+/*************** Endpoint ***************/
+type answerCancelRequest struct {
+	OrderID int64 `json:"order"`
+	Accept  bool  `json:"accept"`
+}
+
+type answerCancelResponse struct {
+	Order svcdb.Order `json:"order"`
+	Err   string      `json:"err,omitempty"`
+}
+
+func AnswerCancelEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(answerCancelRequest)
+		order, err := svc.AnswerCancel(ctx, req.OrderID, req.Accept)
+		if err != nil {
+			fmt.Println("Error AnswerCancelEndpoint : ", err.Error())
+			return answerCancelResponse{Order: order, Err: err.Error()}, nil
+		}
+		return answerCancelResponse{Order: order, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPAnswerCancelRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request answerCancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPAnswerCancelRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPAnswerCancelResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response answerCancelResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPAnswerCancelResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func AnswerCancelHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/cancel/answer").Handler(httptransport.NewServer(
+		endpoints.AnswerCancelEndpoint,
+		DecodeHTTPAnswerCancelRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerCancel", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "answerCancel",
+			"orderID", orderID,
+			"accept", accept,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.AnswerCancel(ctx, orderID, accept)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	v, err := mw.next.AnswerCancel(ctx, orderID, accept)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildAnswerCancelEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "AnswerCancel")
+		csLogger := log.With(logger, "method", "AnswerCancel")
+
+		csEndpoint = AnswerCancelEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "AnswerCancel")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error) {
+	var order svcdb.Order
+
+	request := answerCancelRequest{OrderID: orderID, Accept: accept}
+	response, err := e.AnswerCancelEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error AnswerCancel : ", err.Error())
+		return order, err
+	}
+	return response.(answerCancelResponse).Order, str2err(response.(answerCancelResponse).Err)
+}
+
+func ClientAnswerCancel(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/cancel/answer"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPAnswerCancelResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "AnswerCancel")(ceEndpoint)
+	return ceEndpoint, nil
+}
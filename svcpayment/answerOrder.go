@@ -65,6 +65,10 @@ type answerOrderResponse struct {
 	Err		string `json:"err,omitempty"`
 }
 
+func (r answerOrderResponse) Failed() error {
+	return str2err(r.Err)
+}
+
 func AnswerOrderEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(answerOrderRequest)
@@ -92,6 +96,9 @@ func DecodeHTTPAnswerOrderRequest(_ context.Context, r *http.Request) (interface
 
 func DecodeHTTPAnswerOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
 	var response answerOrderResponse
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
 	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
 		fmt.Println("Error DecodeHTTPAnswerOrderResponse : ", err.Error())
 		return nil, err
@@ -104,7 +111,7 @@ func AnswerOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.AnswerOrderEndpoint,
 		DecodeHTTPAnswerOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerOrder", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "AnswerOrder", logger), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -165,7 +172,7 @@ func ClientAnswerOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Trac
 		copyURL(u, "/order/answer"),
 		EncodeHTTPGenericRequest,
 		DecodeHTTPAnswerOrderResponse,
-		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger), svcdb.IdempotencyKeyToHTTP()),
 	).Endpoint()
 	ceEndpoint = opentracing.TraceClient(tracer, "AnswerOrder")(ceEndpoint)
 	return ceEndpoint, nil
@@ -0,0 +1,292 @@
+package svcpayment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+// LastCancellableStep is the last step of svcdb.OrderWorkflow during which an
+// user may cancel the order, once it is closed the cancellation waits for the
+// pro to accept it with AnswerCancel
+var LastCancellableStep = "Ready"
+
+var (
+	NotParticipantErr      = errors.New("The user is not part of the order")
+	OrderNotCancellableErr = errors.New("The order can't be cancelled anymore")
+)
+
+/*************** Service ***************/
+// CancelOrder cancels the order for one of its users, releasing the charges
+// held on the cards of every participant. Past LastCancellableStep it only
+// asks the pro to, the order is returned with its CancelRequest set.
+func (s Service) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	var notifs []orderProgressNotif
+
+	order, err := s.svcdb.GetOrder(ctx, orderID)
+	if err != nil {
+		fmt.Println("CancelOrder (GetOrder) : " + err.Error())
+		return order, err
+	} else if order.ID == 0 {
+		return order, errors.New("Target order not found")
+	}
+
+	participant := false
+	for _, user := range order.Users {
+		participant = participant || user.User.ID == userID
+	}
+	if !participant {
+		return order, NotParticipantErr
+	}
+
+	open, ok := svcdb.OrderWorkflow.OpenStep(order)
+	if !ok {
+		return order, OrderNotCancellableErr
+	} else if svcdb.OrderWorkflow.Index(open.Name) > svcdb.OrderWorkflow.Index(LastCancellableStep) {
+		return s.requestCancel(ctx, order, userID, open.Name)
+	}
+
+	/* Close the order before releasing the funds, so that they can't be captured meanwhile */
+	cancelled, err := s.svcdb.CancelOrder(ctx, orderID, userID, open.Name)
+	if err != nil && err.Error() == svcdb.StepNotOpenErr.Error() {
+		return order, OrderNotCancellableErr
+	} else if err != nil {
+		fmt.Println("CancelOrder (CancelOrder) : " + err.Error())
+		return order, err
+	}
+	pending := s.releaseCharges(ctx, cancelled)
+
+	for _, user := range cancelled.Users {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   cancelled,
+			UserID:  user.User.ID,
+			Step:    svcdb.CancelledStep,
+			Message: refundNotice("Order cancelled by "+participantName(cancelled, userID), pending, user.User.ID),
+		})
+	}
+	pro, err := s.svcdb.GetProBySoiree(ctx, cancelled.Soiree.ID)
+	if err != nil {
+		fmt.Println("CancelOrder (GetProBySoiree) : " + err.Error())
+	} else if pro.ID != 0 {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   cancelled,
+			UserID:  pro.ID,
+			Step:    svcdb.CancelledStep,
+			Message: "Order cancelled by the customer",
+		})
+	}
+	return s.returnSendNotifs(ctx, notifs, cancelled, nil)
+}
+
+/*************** Helper ***************/
+// requestCancel records userID asking the pro to cancel the order and notifies
+// the pro and the participants
+func (s Service) requestCancel(ctx context.Context, order svcdb.Order, userID int64, step string) (svcdb.Order, error) {
+	var notifs []orderProgressNotif
+
+	requested, err := s.svcdb.RequestCancel(ctx, order.ID, userID, step)
+	if err != nil && err.Error() == svcdb.StepNotOpenErr.Error() {
+		return order, OrderNotCancellableErr
+	} else if err != nil {
+		fmt.Println("requestCancel (RequestCancel) : " + err.Error())
+		return order, err
+	}
+
+	for _, user := range requested.Users {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   requested,
+			UserID:  user.User.ID,
+			Step:    step,
+			Message: "Cancellation asked by " + participantName(requested, userID) + ", waiting for the establishment",
+		})
+	}
+	pro, err := s.svcdb.GetProBySoiree(ctx, requested.Soiree.ID)
+	if err != nil {
+		fmt.Println("requestCancel (GetProBySoiree) : " + err.Error())
+	} else if pro.ID != 0 {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   requested,
+			UserID:  pro.ID,
+			Step:    step,
+			Message: "The customer asks to cancel the order",
+		})
+	}
+	return s.returnSendNotifs(ctx, notifs, requested, nil)
+}
+
+// releaseCharges refunds the charge of every user : a charge still held on
+// the card is released, a captured one is given back. It returns the users
+// whose refund failed, RetryRefunds refunds them later.
+func (s Service) releaseCharges(ctx context.Context, order svcdb.Order) map[int64]bool {
+	var proID int64
+	pending := make(map[int64]bool)
+
+	for _, user := range order.Users {
+		if len(user.Reference) == 0 {
+			continue
+		}
+		refundID, err := s.provider.Refund(ctx, user.Reference)
+		if err != nil {
+			fmt.Println("releaseCharges (Refund) : " + err.Error())
+			pending[user.User.ID] = true
+			continue
+		}
+		if proID == 0 {
+			proID = s.orderProID(ctx, order)
+		}
+		s.recordRefund(ctx, order, proID, user, refundID, user.Price)
+	}
+	return pending
+}
+
+// refundNotice completes the message sent to userID when the refund of his
+// charge is pending
+func refundNotice(message string, pending map[int64]bool, userID int64) string {
+	if pending[userID] {
+		return message + ", the refund of your payment is pending"
+	}
+	return message
+}
+
+func participantName(order svcdb.Order, userID int64) string {
+	for _, user := range order.Users {
+		if user.User.ID == userID && len(user.User.Firstname) > 0 {
+			return user.User.Firstname
+		}
+	}
+	return "a participant"
+}
+
+/*************** Endpoint ***************/
+type cancelOrderRequest struct {
+	OrderID int64 `json:"order"`
+	UserID  int64 `json:"user"`
+}
+
+type cancelOrderResponse struct {
+	Order svcdb.Order `json:"order"`
+	Err   string      `json:"err,omitempty"`
+}
+
+func CancelOrderEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(cancelOrderRequest)
+		order, err := svc.CancelOrder(ctx, req.OrderID, req.UserID)
+		if err != nil {
+			fmt.Println("Error CancelOrderEndpoint : ", err.Error())
+			return cancelOrderResponse{Order: order, Err: err.Error()}, nil
+		}
+		return cancelOrderResponse{Order: order, Err: ""}, nil
+	}
+}
+
+/*************** Transport ***************/
+func DecodeHTTPCancelOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request cancelOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fmt.Println("Error DecodeHTTPCancelOrderRequest : ", err.Error())
+		return nil, err
+	}
+	return request, nil
+}
+
+func DecodeHTTPCancelOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response cancelOrderResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPCancelOrderResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func CancelOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/order/cancel").Handler(httptransport.NewServer(
+		endpoints.CancelOrderEndpoint,
+		DecodeHTTPCancelOrderRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CancelOrder", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "cancelOrder",
+			"orderID", orderID,
+			"userID", userID,
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.CancelOrder(ctx, orderID, userID)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	v, err := mw.next.CancelOrder(ctx, orderID, userID)
+	mw.ints.Add(1)
+	return v, err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildCancelOrderEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "CancelOrder")
+		csLogger := log.With(logger, "method", "CancelOrder")
+
+		csEndpoint = CancelOrderEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "CancelOrder")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error) {
+	var order svcdb.Order
+
+	request := cancelOrderRequest{OrderID: orderID, UserID: userID}
+	response, err := e.CancelOrderEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error CancelOrder : ", err.Error())
+		return order, err
+	}
+	return response.(cancelOrderResponse).Order, str2err(response.(cancelOrderResponse).Err)
+}
+
+func ClientCancelOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/order/cancel"),
+		EncodeHTTPGenericRequest,
+		DecodeHTTPCancelOrderResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "CancelOrder")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,114 @@
+package svcpayment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"svcdb"
+)
+
+// preparedOrder returns a charged order whose Ready step is closed, the
+// charges waiting to be captured at Deliverpaid
+func preparedOrder(t *testing.T, db svcdb.MemoryService) svcdb.Order {
+	order := newChargedOrder(t, db, "ch_held")
+	for _, step := range []string{"Issued", "Confirmed", "Verified", "Ready"} {
+		if _, err := db.PutOrder(context.Background(), order.ID, step, true); err != nil {
+			t.Fatal("PutOrder " + step + " : " + err.Error())
+		}
+	}
+	order, err := db.GetOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatal("GetOrder : " + err.Error())
+	}
+	if open, _ := svcdb.OrderWorkflow.OpenStep(order); open.Name != "Deliverpaid" {
+		t.Fatalf("preparedOrder : got open step %q, want Deliverpaid", open.Name)
+	}
+	return order
+}
+
+func TestCancelOrderPastReadyWaitsForThePro(t *testing.T) {
+	var refunded []string
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: refundProvider{refunded: &refunded}}
+	order := preparedOrder(t, db)
+	userID := order.Users[0].User.ID
+
+	requested, err := s.CancelOrder(context.Background(), order.ID, userID)
+	if err != nil {
+		t.Fatal("CancelOrder : " + err.Error())
+	}
+	if requested.CancelRequest != userID || len(requested.Done) > 0 || len(refunded) > 0 {
+		t.Fatalf("CancelOrder : got request %d, done %q, refunds %v, want a pending request of %d",
+			requested.CancelRequest, requested.Done, refunded, userID)
+	}
+
+	declined, err := s.AnswerCancel(context.Background(), order.ID, false)
+	if err != nil {
+		t.Fatal("AnswerCancel declined : " + err.Error())
+	}
+	if declined.CancelRequest != 0 || len(declined.Done) > 0 || len(refunded) > 0 {
+		t.Fatalf("AnswerCancel declined : got request %d, done %q, refunds %v, want the order going on",
+			declined.CancelRequest, declined.Done, refunded)
+	}
+	if _, err = s.AnswerCancel(context.Background(), order.ID, true); err != NoCancelRequestErr {
+		t.Fatalf("AnswerCancel without request : got %v, want %v", err, NoCancelRequestErr)
+	}
+
+	if _, err = s.CancelOrder(context.Background(), order.ID, userID); err != nil {
+		t.Fatal("CancelOrder : " + err.Error())
+	}
+	cancelled, err := s.AnswerCancel(context.Background(), order.ID, true)
+	if err != nil {
+		t.Fatal("AnswerCancel accepted : " + err.Error())
+	}
+	if cancelled.Done != "false" || cancelled.CancelRequest != 0 {
+		t.Errorf("AnswerCancel accepted : got done %q, request %d, want a cancelled order", cancelled.Done, cancelled.CancelRequest)
+	}
+	if len(refunded) != 1 || refunded[0] != "ch_held" {
+		t.Errorf("AnswerCancel accepted : got refunds %v, want [ch_held]", refunded)
+	}
+}
+
+// flakyRefunds fails the refunds while failing is set
+type flakyRefunds struct {
+	refundProvider
+	failing *bool
+}
+
+func (p flakyRefunds) Refund(ctx context.Context, reference string) (string, error) {
+	if *p.failing {
+		return "", RefundFailedErr
+	}
+	return p.refundProvider.Refund(ctx, reference)
+}
+
+func TestCancelOrderRefundRetried(t *testing.T) {
+	var refunded []string
+	failing := true
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: flakyRefunds{refundProvider{refunded: &refunded}, &failing}}
+	order := newChargedOrder(t, db, "ch_held")
+
+	cancelled, err := s.CancelOrder(context.Background(), order.ID, order.Users[0].User.ID)
+	if err != nil {
+		t.Fatal("CancelOrder : " + err.Error())
+	}
+	if cancelled.Done != "false" || len(refunded) > 0 {
+		t.Fatalf("CancelOrder : got done %q, refunds %v, want a cancelled order with its refund pending", cancelled.Done, refunded)
+	}
+
+	failing = false
+	for i, want := range []int{1, 0} {
+		retried, err := s.RetryRefunds(context.Background(), time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatal("RetryRefunds : " + err.Error())
+		}
+		if len(retried) != want {
+			t.Errorf("RetryRefunds %d : got %d orders, want %d", i+1, len(retried), want)
+		}
+	}
+	if len(refunded) != 1 || refunded[0] != "ch_held" {
+		t.Errorf("RetryRefunds : got refunds %v, want [ch_held]", refunded)
+	}
+}
@@ -25,9 +25,14 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcp
 	clientPutOrder, err := svcpayment.ClientPutOrder(u, logger, tracer)
 	clientSearchOrders, err := svcpayment.ClientSearchOrders(u, logger, tracer)
 	clientAnswerOrder, err := svcpayment.ClientAnswerOrder(u, logger, tracer)
+	clientCancelOrder, err := svcpayment.ClientCancelOrder(u, logger, tracer)
+	clientAnswerCancel, err := svcpayment.ClientAnswerCancel(u, logger, tracer)
 
 	/* Pro */
 	clientRegisterPro, err := svcpayment.ClientRegisterPro(u, logger, tracer)
+
+	/* Webhook */
+	clientStripeWebhook, err := svcpayment.ClientStripeWebhook(u, logger, tracer)
 	
     return svcpayment.Endpoints{
 		/* Order */
@@ -36,8 +41,13 @@ func New(instance string, tracer stdopentracing.Tracer, logger log.Logger) (svcp
 		PutOrderEndpoint:				clientPutOrder,		
 		SearchOrdersEndpoint:			clientSearchOrders,
 		AnswerOrderEndpoint:			clientAnswerOrder,
+		CancelOrderEndpoint:			clientCancelOrder,
+		AnswerCancelEndpoint:			clientAnswerCancel,
 
 		/* Pro */
 		RegisterProEndpoint:			clientRegisterPro,
+
+		/* Webhook */
+		StripeWebhookEndpoint:			clientStripeWebhook,
 	}, nil
 }
@@ -46,6 +46,10 @@ type createOrderResponse struct {
 	Err		string `json:"err,omitempty"`
 }
 
+func (r createOrderResponse) Failed() error {
+	return str2err(r.Err)
+}
+
 func CreateOrderEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createOrderRequest)
@@ -73,6 +77,9 @@ func DecodeHTTPCreateOrderRequest(_ context.Context, r *http.Request) (interface
 
 func DecodeHTTPCreateOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
 	var response createOrderResponse
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
 	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
 		fmt.Println("Error DecodeHTTPCreateOrderResponse : ", err.Error())
 		return nil, err
@@ -85,7 +92,7 @@ func CreateOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, l
 		endpoints.CreateOrderEndpoint,
 		DecodeHTTPCreateOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateOrder", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "CreateOrder", logger), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -146,7 +153,7 @@ func ClientCreateOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Trac
 		copyURL(u, "/orders"),
 		EncodeHTTPGenericRequest,
 		DecodeHTTPCreateOrderResponse,
-		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger), svcdb.IdempotencyKeyToHTTP()),
 	).Endpoint()
 	ceEndpoint = opentracing.TraceClient(tracer, "CreateOrder")(ceEndpoint)
 	return ceEndpoint, nil
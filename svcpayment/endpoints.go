@@ -19,9 +19,14 @@ type Endpoints struct {
 	PutOrderEndpoint		endpoint.Endpoint
 	SearchOrdersEndpoint	endpoint.Endpoint
 	AnswerOrderEndpoint		endpoint.Endpoint
+	CancelOrderEndpoint		endpoint.Endpoint
+	AnswerCancelEndpoint	endpoint.Endpoint
 
 	/* Pro */
 	RegisterProEndpoint		endpoint.Endpoint
+
+	/* Webhook */
+	StripeWebhookEndpoint	endpoint.Endpoint
 }
 
 /* Logging Middleware */
@@ -0,0 +1,181 @@
+package svcpayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	CardDeclinedErr   = errors.New("Your card was declined")
+	NoCustomerErr     = errors.New("The user has no payment method")
+	ChargeNotFoundErr = errors.New("No such charge")
+	CaptureFailedErr  = errors.New("The charge couldn't be captured")
+	RefundFailedErr   = errors.New("The charge couldn't be refunded")
+	ChargeRefundedErr = errors.New("The charge has already been refunded")
+)
+
+// FakeProvider keeps the charges in memory for local runs. It is deterministic :
+// references and accounts are numbered in call order and every failure comes
+// from its options.
+type FakeProvider struct {
+	delay       time.Duration
+	declined    map[string]bool
+	failCapture map[string]bool
+	failRefund  map[string]bool
+
+	mtx      *sync.Mutex
+	charges  map[string]*fakeCharge
+	sequence int64
+}
+
+type fakeCharge struct {
+	Charge
+	Captured bool
+	Refunded bool
+}
+
+// NewFakeProvider reads its options from a "delay=2s,decline=cus_a|cus_b" list :
+//
+//	delay   : time taken by every call
+//	decline : customers whose card is declined on Authorize
+//	capture : customers whose charges fail to be captured
+//	refund  : customers whose charges fail to be refunded
+//
+// Declining a single participant makes an order fail halfway, after the
+// charges of the others were authorised.
+func NewFakeProvider(options string) (PaymentProvider, error) {
+	p := &FakeProvider{
+		declined:    map[string]bool{},
+		failCapture: map[string]bool{},
+		failRefund:  map[string]bool{},
+		mtx:         &sync.Mutex{},
+		charges:     map[string]*fakeCharge{},
+	}
+
+	for _, item := range strings.Split(options, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return p, fmt.Errorf("Invalid fake provider option %q, expected name=value", item)
+		}
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "delay":
+			delay, err := time.ParseDuration(value)
+			if err != nil || delay < 0 {
+				return p, fmt.Errorf("Invalid fake provider delay %q", value)
+			}
+			p.delay = delay
+		case "decline":
+			addCustomers(p.declined, value)
+		case "capture":
+			addCustomers(p.failCapture, value)
+		case "refund":
+			addCustomers(p.failRefund, value)
+		default:
+			return p, fmt.Errorf("Unknown fake provider option %q", parts[0])
+		}
+	}
+	return p, nil
+}
+
+func addCustomers(set map[string]bool, list string) {
+	for _, customer := range strings.Split(list, "|") {
+		if customer = strings.TrimSpace(customer); len(customer) > 0 {
+			set[customer] = true
+		}
+	}
+}
+
+func (p *FakeProvider) Authorize(ctx context.Context, c Charge) (string, error) {
+	if err := p.wait(ctx); err != nil {
+		return "", err
+	}
+	if len(c.Customer) == 0 || c.Customer == "null" {
+		return "", NoCustomerErr
+	}
+	if p.declined[c.Customer] {
+		return "", CardDeclinedErr
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.sequence++
+	reference := fmt.Sprintf("ch_fake_%d", p.sequence)
+	p.charges[reference] = &fakeCharge{Charge: c}
+	return reference, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, reference string) error {
+	if err := p.wait(ctx); err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	ch, ok := p.charges[reference]
+	if !ok {
+		return ChargeNotFoundErr
+	} else if ch.Refunded {
+		return ChargeRefundedErr
+	} else if p.failCapture[ch.Customer] {
+		return CaptureFailedErr
+	}
+	ch.Captured = true
+	return nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, reference string) (string, error) {
+	if err := p.wait(ctx); err != nil {
+		return "", err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	ch, ok := p.charges[reference]
+	if !ok {
+		return "", ChargeNotFoundErr
+	} else if ch.Refunded {
+		return "", ChargeRefundedErr
+	} else if p.failRefund[ch.Customer] {
+		return "", RefundFailedErr
+	}
+	ch.Refunded = true
+	p.sequence++
+	return fmt.Sprintf("re_fake_%d", p.sequence), nil
+}
+
+func (p *FakeProvider) CreateAccount(ctx context.Context, email string) (ProviderAccount, error) {
+	if err := p.wait(ctx); err != nil {
+		return ProviderAccount{}, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.sequence++
+	return ProviderAccount{
+		ID:             fmt.Sprintf("acct_fake_%d", p.sequence),
+		SecretKey:      fmt.Sprintf("sk_fake_%d", p.sequence),
+		PublishableKey: fmt.Sprintf("pk_fake_%d", p.sequence),
+	}, nil
+}
+
+// wait simulates the latency of the provider, giving up with the request
+func (p *FakeProvider) wait(ctx context.Context) error {
+	if p.delay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(p.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+package svcpayment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"svcdb"
+)
+
+/*************** Recording ***************/
+// recordLedger adds an entry for the charge of user. Failures are only logged,
+// the ledger never blocks a payment : ReconcileOrders flags what is missing.
+func (s Service) recordLedger(ctx context.Context, order svcdb.Order, proID int64, user svcdb.UserOrder, kind string, amount int64) {
+	s.addLedgerEntry(ctx, svcdb.LedgerEntry{
+		Kind:      kind,
+		Reference: user.Reference,
+		Amount:    amount,
+		Currency:  svcdb.DefaultCurrency,
+		OrderID:   order.ID,
+		UserID:    user.User.ID,
+		ProID:     proID,
+		SoireeID:  order.Soiree.ID,
+	})
+}
+
+// recordRefund adds the entry of the refund refundID of the charge of user,
+// each refund of a charge having its own, as recordLedger does
+func (s Service) recordRefund(ctx context.Context, order svcdb.Order, proID int64, user svcdb.UserOrder, refundID string, amount int64) {
+	s.addLedgerEntry(ctx, svcdb.LedgerEntry{
+		Kind:      svcdb.LedgerRefund,
+		Reference: user.Reference,
+		Refund:    refundID,
+		Amount:    amount,
+		Currency:  svcdb.DefaultCurrency,
+		OrderID:   order.ID,
+		UserID:    user.User.ID,
+		ProID:     proID,
+		SoireeID:  order.Soiree.ID,
+	})
+}
+
+func (s Service) addLedgerEntry(ctx context.Context, entry svcdb.LedgerEntry) {
+	if len(entry.Reference) == 0 {
+		return
+	}
+	if _, err := s.svcdb.AddLedgerEntry(ctx, entry); err != nil {
+		fmt.Println("addLedgerEntry (AddLedgerEntry) : " + err.Error())
+	}
+}
+
+// recordCapture records a captured charge of amount : the capture, the fee
+// kept by NightLine and the transfer of the rest to the pro
+func (s Service) recordCapture(ctx context.Context, order svcdb.Order, proID int64, user svcdb.UserOrder, amount int64) {
+	fee := applicationFee(amount)
+	s.recordLedger(ctx, order, proID, user, svcdb.LedgerCapture, amount)
+	s.recordLedger(ctx, order, proID, user, svcdb.LedgerFee, fee)
+	s.recordLedger(ctx, order, proID, user, svcdb.LedgerTransfer, amount-fee)
+}
+
+// orderProID returns the ID of the pro the order pays, 0 when unknown
+func (s Service) orderProID(ctx context.Context, order svcdb.Order) int64 {
+	pro, err := s.svcdb.GetProBySoiree(ctx, order.Soiree.ID)
+	if err != nil {
+		fmt.Println("orderProID (GetProBySoiree) : " + err.Error())
+		return 0
+	}
+	return pro.ID
+}
+
+/*************** Reconciliation ***************/
+// ledgerIssues lists what the ledger of the order disagrees on with its charge
+// references, "" when they match. Pending orders are only checked for what
+// happened already.
+func ledgerIssues(order svcdb.Order, entries []svcdb.LedgerEntry) string {
+	var issues []string
+	var unknown []string
+	charges := make(map[string]map[string]svcdb.LedgerEntry)
+	references := make(map[string]bool)
+
+	for _, entry := range entries {
+		if charges[entry.Reference] == nil {
+			charges[entry.Reference] = make(map[string]svcdb.LedgerEntry)
+		}
+		charges[entry.Reference][entry.Kind] = entry
+	}
+
+	for _, user := range order.Users {
+		if len(user.Reference) == 0 {
+			continue
+		}
+		references[user.Reference] = true
+		kinds := charges[user.Reference]
+
+		if _, ok := kinds[svcdb.LedgerAuthorization]; !ok {
+			issues = append(issues, "no authorization of "+user.Reference)
+		}
+		capture, captured := kinds[svcdb.LedgerCapture]
+		_, refunded := kinds[svcdb.LedgerRefund]
+		if captured {
+			if capture.Amount != user.Price {
+				issues = append(issues, "capture of "+user.Reference+" is "+strconv.FormatInt(capture.Amount, 10)+
+					" instead of "+strconv.FormatInt(user.Price, 10))
+			}
+			fee, feeOk := kinds[svcdb.LedgerFee]
+			transfer, transferOk := kinds[svcdb.LedgerTransfer]
+			if !feeOk || !transferOk {
+				issues = append(issues, "no fee or transfer of "+user.Reference)
+			} else if fee.Amount+transfer.Amount != capture.Amount {
+				issues = append(issues, "fee and transfer of "+user.Reference+" don't add up to its capture")
+			}
+		}
+
+		switch order.Done {
+		case "true":
+			if !captured {
+				issues = append(issues, user.Reference+" not captured on a completed order")
+			}
+		case "false":
+			if !refunded {
+				issues = append(issues, user.Reference+" not released on a failed order")
+			}
+		}
+	}
+
+	for reference := range charges {
+		if !references[reference] {
+			unknown = append(unknown, reference)
+		}
+	}
+	sort.Strings(unknown)
+	for _, reference := range unknown {
+		issues = append(issues, reference+" is not a charge of the order")
+	}
+	return strings.Join(issues, "; ")
+}
@@ -1,10 +0,0 @@
-package svcpayment
-
-var nextAllowedSteps = map[string]string{
-	"Issued": "Confirmed",
-	"Confirmed": "Verified",
-	"Verified": "Ready",
-	"Ready": "Deliverpaid",
-	"Deliverpaid": "Completed",
-	"Completed": "",
-}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	lightstep "github.com/lightstep/lightstep-tracer-go"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -22,6 +24,7 @@ import (
 	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/prometheus"
 
+	"svcdb"
 	csvcdb "svcdb/client"
 	csvcevent "svcevent/client"
 	
@@ -36,6 +39,14 @@ func main() {
 		zipkinKafkaAddr = flag.String("zipkin.kafka.addr", "", "Enable Zipkin tracing via a Kafka server host:port")
 		appdashAddr     = flag.String("appdash.addr", "", "Enable Appdash tracing via an Appdash server host:port")
 		lightstepToken  = flag.String("lightstep.token", "", "Enable LightStep tracing via a LightStep access token")
+		sweepInterval   = flag.Duration("sweep.interval", time.Minute, "Interval between two sweeps of the expired orders, 0 to disable")
+		reconcileEvery  = flag.Duration("reconcile.interval", time.Hour, "Interval between two reconciliations of the ledger, 0 to disable")
+		reconcileWindow = flag.Duration("reconcile.window", 7*24*time.Hour, "Age of the oldest orders reconciled with the ledger")
+		stepTimeouts    = flag.String("order.timeouts", "", "Order step timeouts overriding the defaults, as Confirmed=10m,Ready=2h")
+		providerName    = flag.String("payment.provider", "stripe", "Payment provider charging the orders, stripe or fake")
+		stripeKey       = flag.String("stripe.key", svcpayment.STRIPESKEY, "Stripe secret key of the stripe provider")
+		webhookSecret   = flag.String("stripe.webhook.secret", os.Getenv("STRIPE_WEBHOOK_SECRET"), "Signing secret of the Stripe webhook endpoint")
+		fakeOptions     = flag.String("fake.options", "", "Failures of the fake provider, as delay=2s,decline=cus_a|cus_b,capture=cus_c,refund=cus_d")
 	)
 	flag.Parse()
 	
@@ -49,6 +60,23 @@ func main() {
 	logger.Log("msg", "[SVCPAYMENT BEGIN]")
 	defer logger.Log("msg", "[SVCPAYMENT END]")
 
+	/* Order workflow */
+	if err := svcdb.OrderWorkflow.SetTimeouts(*stepTimeouts); err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+
+	/* Payment provider */
+	provider, err := svcpayment.NewProvider(*providerName, *stripeKey, *fakeOptions)
+	if err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+	logger.Log("provider", *providerName)
+	if len(*webhookSecret) == 0 {
+		logger.Log("msg", "no Stripe webhook secret, the webhook events will be rejected")
+	}
+
 	/* Metrics */
 	var ints metrics.Counter
 	{
@@ -131,9 +159,11 @@ func main() {
 	}
 
 	/* Business domain */
+	var db svcdb.IService
 	var service svcpayment.IService
 	{
-		svcdb, err := csvcdb.New("localhost:8044", tracer, logger)
+		var err error
+		db, err = csvcdb.New("localhost:8044", tracer, logger)
 		if err != nil {
 			logger.Log("err", err)
 			os.Exit(1)
@@ -144,9 +174,18 @@ func main() {
 			os.Exit(1)
 		}
 		
-		service = svcpayment.NewService(svcdb, svcevent)
+		service = svcpayment.NewService(db, svcevent, provider, *webhookSecret)
 		service = svcpayment.ServiceLoggingMiddleware(logger)(service)
 		service = svcpayment.ServiceInstrumentingMiddleware(ints)(service)
+
+		if *sweepInterval > 0 {
+			sweeper := svcpayment.NewSweeper(db, svcevent, provider, *sweepInterval, log.With(logger, "component", "sweeper"))
+			go sweeper.Run(context.Background())
+		}
+		if *reconcileEvery > 0 {
+			reconciler := svcpayment.NewReconciler(db, *reconcileEvery, *reconcileWindow, log.With(logger, "component", "reconciler"))
+			go reconciler.Run(context.Background())
+		}
 	}
 	
 	/* Endpoints domain */
@@ -156,9 +195,19 @@ func main() {
 	putOrderEndpoint := svcpayment.BuildPutOrderEndpoint(service, logger, tracer, duration)
 	searchOrdersEndpoint := svcpayment.BuildSearchOrdersEndpoint(service, logger, tracer, duration)
 	answerOrderEndpoint := svcpayment.BuildAnswerOrderEndpoint(service, logger, tracer, duration)
+	cancelOrderEndpoint := svcpayment.BuildCancelOrderEndpoint(service, logger, tracer, duration)
+	answerCancelEndpoint := svcpayment.BuildAnswerCancelEndpoint(service, logger, tracer, duration)
+
+	// Retried requests carrying an Idempotency-Key get their first response
+	createOrderEndpoint = svcdb.EndpointIdempotencyMiddleware(db, "svcpayment.CreateOrder")(createOrderEndpoint)
+	putOrderEndpoint = svcdb.EndpointIdempotencyMiddleware(db, "svcpayment.PutOrder")(putOrderEndpoint)
+	answerOrderEndpoint = svcdb.EndpointIdempotencyMiddleware(db, "svcpayment.AnswerOrder")(answerOrderEndpoint)
 
 	/* Pro */
 	registerProEndpoint := svcpayment.BuildRegisterProEndpoint(service, logger, tracer, duration)
+
+	/* Webhook */
+	stripeWebhookEndpoint := svcpayment.BuildStripeWebhookEndpoint(service, logger, tracer, duration)
 	
 	endpoints := svcpayment.Endpoints{
 		/* Order */
@@ -167,9 +216,14 @@ func main() {
 		PutOrderEndpoint:					putOrderEndpoint,
 		SearchOrdersEndpoint:				searchOrdersEndpoint,
 		AnswerOrderEndpoint:				answerOrderEndpoint,
+		CancelOrderEndpoint:				cancelOrderEndpoint,
+		AnswerCancelEndpoint:				answerCancelEndpoint,
 
 		/* Pro */
 		RegisterProEndpoint:				registerProEndpoint,
+
+		/* Webhook */
+		StripeWebhookEndpoint:				stripeWebhookEndpoint,
 	}
 
 	/* Mechanical domain */
@@ -0,0 +1,51 @@
+package svcpayment
+
+import (
+	"context"
+	"errors"
+)
+
+var UnknownProviderErr = errors.New("Unknown payment provider, expected stripe or fake")
+
+// Charge is the payment of one user for an order, held on his card until it
+// is captured. Amounts are in minor units of Currency. Order and User are
+// sent along to find the order back from the webhook events.
+type Charge struct {
+	Amount      int64
+	Fee         int64
+	Currency    string
+	Customer    string
+	Account     string
+	Description string
+	Order       int64
+	User        int64
+}
+
+// ProviderAccount is the connected account created for a pro, where the
+// charges of his soirees are transferred
+type ProviderAccount struct {
+	ID             string
+	SecretKey      string
+	PublishableKey string
+}
+
+// PaymentProvider moves the money of the orders. Charges are referenced by the
+// string Authorize returns, stored as svcdb.UserOrder.Reference, and refunds
+// by the one Refund returns, stored as svcdb.LedgerEntry.Refund.
+type PaymentProvider interface {
+	Authorize(ctx context.Context, charge Charge) (string, error)
+	Capture(ctx context.Context, reference string) error
+	Refund(ctx context.Context, reference string) (string, error)
+	CreateAccount(ctx context.Context, email string) (ProviderAccount, error)
+}
+
+// NewProvider returns the provider named by the payment.provider flag
+func NewProvider(name string, stripeKey string, fakeOptions string) (PaymentProvider, error) {
+	switch name {
+	case "stripe":
+		return NewStripeProvider(stripeKey), nil
+	case "fake":
+		return NewFakeProvider(fakeOptions)
+	}
+	return nil, UnknownProviderErr
+}
@@ -12,11 +12,6 @@ import (
 	"errors"
 	"time"
 
-	"github.com/stripe/stripe-go"
-	// "github.com/stripe/stripe-go/token"
-	"github.com/stripe/stripe-go/charge"
-	"github.com/stripe/stripe-go/refund"
-
 	"github.com/go-kit/kit/tracing/opentracing"
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
@@ -32,6 +27,7 @@ import (
 
 /************ Steps Handling ***********/
 /*** Specific ***/
+// Every step of svcdb.OrderWorkflow needs an Execute and a Condition handler
 type specificStepHandler func(Service, context.Context, svcdb.Order, svcdb.Pro, bool) ([]orderProgressNotif, bool, bool, error)
 var specificStepHandlerMap = map[string]map[string]specificStepHandler{
 	"Issued": { "Execute": stepExecuteIssued, "Condition": stepConditionIssued },
@@ -42,6 +38,14 @@ var specificStepHandlerMap = map[string]map[string]specificStepHandler{
 	"Completed": { "Execute": stepExecuteCompleted, "Condition": stepConditionCompleted },
 }
 
+func init() {
+	for _, step := range svcdb.OrderWorkflow {
+		if _, ok := specificStepHandlerMap[step.Name]; !ok {
+			panic("svcpayment : no handler for the order step " + step.Name)
+		}
+	}
+}
+
 /*** Execute ***/
 /* Nothing */
 func stepExecuteIssued(s Service, ctx context.Context, order svcdb.Order, pro svcdb.Pro, flag bool) ([]orderProgressNotif, bool, bool, error) {
@@ -65,46 +69,46 @@ func stepExecuteConfirmed(s Service, ctx context.Context, order svcdb.Order, pro
 	return notifs, flag, false, nil
 }
 
-/* Create stripe requests */
+/* Authorize the charges */
 func stepExecuteVerified(s Service, ctx context.Context, order svcdb.Order, pro svcdb.Pro, flag bool) ([]orderProgressNotif, bool, bool, error) {
 	var notifs []orderProgressNotif
 	var chargesID []string
-	stripe.Key = STRIPESKEY
+
+	if pro.StripeStatus == ProStatusRestricted || pro.StripeStatus == ProStatusDeauthorized {
+		for _, user := range order.Users {
+			notifs = append(notifs, orderProgressNotif{
+				Order: order,
+				UserID: user.User.ID,
+				Step: "Verified",
+				Message: "The establishment can't take payments for now",
+			})
+		}
+		return notifs, false, false, ProCannotChargeErr
+	}
 
 	for _, user := range order.Users {
-		var chID string
-		// tokenParams := &stripe.TokenParams{
-		// 	Customer: user.User.StripeID,
-		// }
-		// tokenParams.SetStripeAccount(pro.StripeID)
-		// token, err := token.New(tokenParams)
-		// if err == nil {
-		chargeParams := &stripe.ChargeParams{
-			Amount: uint64(user.Price),
-			Desc: "NightLine fee",
-			Statement: "NightLine fee",
-			Currency: "eur",
-			Fee: uint64(float64(user.Price) * STRIPEFEES),
-			NoCapture: true,
-			Destination: &stripe.DestinationParams{
-				Account: pro.StripeID,
-			},
+		chID, err := s.provider.Authorize(ctx, Charge{
+			Amount: user.Price,
+			Fee: applicationFee(user.Price),
+			Currency: svcdb.DefaultCurrency,
 			Customer: user.User.StripeID,
-		}
-		// chargeParams.SetStripeAccount(pro.StripeID)
-		// chargeParams.SetSource(user.User.StripeID)
-		// chargeParams.SetSource(token.ID)
-		ch, err := charge.New(chargeParams)
+			Account: pro.StripeID,
+			Description: "NightLine fee",
+			Order: order.ID,
+			User: user.User.ID,
+		})
 		if err == nil {
-			chID = (*ch).ID
 			chargesID = append(chargesID, chID)
 			user.Reference = chID
+			s.recordLedger(ctx, order, pro.ID, user, svcdb.LedgerAuthorization, user.Price)
 			err = s.svcdb.UpdateOrderReference(ctx, order.ID, user.User.ID, chID)
 		}
 		if err != nil {
 			fmt.Println("Error on executeVerified : ", err)
-			for _, chargeID := range chargesID {
-				refund.New(&stripe.RefundParams{Charge: chargeID})
+			for i, chargeID := range chargesID {
+				if refundID, err := s.provider.Refund(ctx, chargeID); err == nil {
+					s.recordRefund(ctx, order, pro.ID, svcdb.UserOrder{User: order.Users[i].User, Reference: chargeID}, refundID, order.Users[i].Price)
+				}
 			}
 			for _, user := range order.Users {
 				notifs = append(notifs, orderProgressNotif{
@@ -123,7 +127,6 @@ func stepExecuteVerified(s Service, ctx context.Context, order svcdb.Order, pro
 			Message: "Order price reserved on bank account",
 		})
 	}
-    // }
 
 	return notifs, flag, true, nil
 }
@@ -134,17 +137,17 @@ func stepExecuteReady(s Service, ctx context.Context, order svcdb.Order, pro svc
 	return notifs, flag, false, nil
 }
 
-/* capture stripe then pass to completed */
+/* capture the charges then pass to completed */
 func stepExecuteDeliverpaid(s Service, ctx context.Context, order svcdb.Order, pro svcdb.Pro, flag bool) ([]orderProgressNotif, bool, bool, error) {
 	var notifs []orderProgressNotif
 	for _, user := range order.Users {
-		_, err := charge.Capture(user.Reference, nil)
+		err := s.provider.Capture(ctx, user.Reference)
 		if err != nil {
-			for _, user := range order.Users {
-				refund.New(&stripe.RefundParams{Charge: user.Reference})
-			}
-			return notifs, flag, false, err
+			/* Nothing is held anymore, the order fails */
+			s.releaseCharges(ctx, order)
+			return notifs, false, false, err
 		}
+		s.recordCapture(ctx, order, pro.ID, user, user.Price)
 	}
 
 	return notifs, flag, false, nil
@@ -201,13 +204,17 @@ func stepConditionVerified(s Service, ctx context.Context, order svcdb.Order, pr
 func stepConditionReady(s Service, ctx context.Context, order svcdb.Order, pro svcdb.Pro, flag bool) ([]orderProgressNotif, bool, bool, error) {
 	var notifs []orderProgressNotif
 	if !flag {
+		pending := s.releaseCharges(ctx, order)
 		for _, user := range order.Users {
-			refund.New(&stripe.RefundParams{Charge: user.Reference})
+			message := "Order refunded"
+			if pending[user.User.ID] {
+				message = "Order refused, the refund of your payment is pending"
+			}
 			notifs = append(notifs, orderProgressNotif{
 				Order: order,
 				UserID: user.User.ID,
 				Step: "Ready",
-				Message: "Order refunded",
+				Message: message,
 			})
 		}
 	} else {
@@ -336,13 +343,13 @@ func (s Service) PutOrder(ctx context.Context, orderID int64, step string, flag
 		}
 
 		/* Handler Execute */
-		nextStep := nextAllowedSteps[step]
-		nextStepHandlerFn, ok := specificStepHandlerMap[nextStep]
-		if !ok {
-			fmt.Println("PutOrder (StepHandlerMap) : step.Name not in StepHandler")
-			return s.returnSendNotifs(ctx, notifs, order, errors.New("Internal Error : step.Name not in StepHandler"))
-		}
+		nextStep := svcdb.OrderWorkflow.Next(step)
 		if len(nextStep) > 0 {
+			nextStepHandlerFn, ok := specificStepHandlerMap[nextStep]
+			if !ok {
+				fmt.Println("PutOrder (StepHandlerMap) : step.Name not in StepHandler")
+				return s.returnSendNotifs(ctx, notifs, order, errors.New("Internal Error : step.Name not in StepHandler"))
+			}
 			tmpNotifs, validateOrder, goNextStep, err := nextStepHandlerFn["Execute"](s, ctx, order, pro, flag)
 			notifs = append(notifs, tmpNotifs...)
 			if validateOrder == false {
@@ -379,6 +386,10 @@ type putOrderResponse struct {
 	Err   string `json:"err,omitempty"`
 }
 
+func (r putOrderResponse) Failed() error {
+	return str2err(r.Err)
+}
+
 func PutOrderEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(putOrderRequest)
@@ -418,6 +429,9 @@ func DecodeHTTPPutOrderRequest(_ context.Context, r *http.Request) (interface{},
 
 func DecodeHTTPPutOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
 	var response putOrderResponse
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
 	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
 		return nil, err
 	}
@@ -429,7 +443,7 @@ func PutOrderHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logg
 		endpoints.PutOrderEndpoint,
 		DecodeHTTPPutOrderRequest,
 		EncodeHTTPGenericResponse,
-		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "PutOrder", logger)))...,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "PutOrder", logger), svcdb.HTTPToIdempotencyKey()))...,
 	))
 	return route
 }
@@ -509,7 +523,7 @@ func ClientPutOrder(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer)
 		copyURL(u, `/orders/{OrderID:[0-9]+}/{Step}/{Flag:(?:true|false)}`),
 		EncodeHTTPPutOrderRequest,
 		DecodeHTTPPutOrderResponse,
-		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger), svcdb.IdempotencyKeyToHTTP()),
 	).Endpoint()
 	ceEndpoint = opentracing.TraceClient(tracer, "PutOrder")(ceEndpoint)
 	return ceEndpoint, nil
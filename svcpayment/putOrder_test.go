@@ -0,0 +1,41 @@
+package svcpayment
+
+import (
+	"context"
+	"testing"
+
+	"svcdb"
+)
+
+func TestPutOrderFailsWhenACaptureFails(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewFakeProvider("capture=cus_broke")
+	if err != nil {
+		t.Fatal("NewFakeProvider : " + err.Error())
+	}
+	reference, err := provider.Authorize(ctx, Charge{Amount: 500, Customer: "cus_broke"})
+	if err != nil {
+		t.Fatal("Authorize : " + err.Error())
+	}
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: provider}
+	order := newChargedOrder(t, db, reference)
+	for _, step := range []string{"Issued", "Confirmed", "Verified"} {
+		if _, err = db.PutOrder(ctx, order.ID, step, true); err != nil {
+			t.Fatal("PutOrder " + step + " : " + err.Error())
+		}
+	}
+
+	if _, err = s.PutOrder(ctx, order.ID, "Ready", true); err != CaptureFailedErr {
+		t.Errorf("PutOrder Ready : got %v, want %v", err, CaptureFailedErr)
+	}
+	if order, err = db.GetOrder(ctx, order.ID); err != nil {
+		t.Fatal("GetOrder : " + err.Error())
+	}
+	if order.Done != "false" {
+		t.Errorf("PutOrder Ready : got done %q, want a failed order", order.Done)
+	}
+	if _, err = provider.Refund(ctx, reference); err != ChargeRefundedErr {
+		t.Errorf("Refund : got %v, want the charge refunded already", err)
+	}
+}
@@ -0,0 +1,87 @@
+package svcpayment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"svcdb"
+)
+
+/*************** Service ***************/
+// ReconcileOrders checks the ledger of the orders charged since from against
+// their charge references. The orders it disagrees with are flagged in svcdb
+// and returned, the flag of the ones matching again is cleared.
+func (s Service) ReconcileOrders(ctx context.Context, from time.Time) ([]svcdb.Order, error) {
+	var flagged []svcdb.Order
+
+	orders, err := s.svcdb.GetChargedOrders(ctx, from)
+	if err != nil {
+		fmt.Println("ReconcileOrders (GetChargedOrders) : " + err.Error())
+		return flagged, err
+	}
+
+	for _, order := range orders {
+		entries, err := s.svcdb.GetLedger(ctx, svcdb.LedgerFilter{OrderID: order.ID})
+		if err != nil {
+			fmt.Println("ReconcileOrders (GetLedger) : " + err.Error())
+			continue
+		}
+		issue := ledgerIssues(order, entries)
+		if issue != order.Reconciliation {
+			if err = s.svcdb.FlagOrder(ctx, order.ID, issue); err != nil {
+				fmt.Println("ReconcileOrders (FlagOrder) : " + err.Error())
+				continue
+			}
+			order.Reconciliation = issue
+		}
+		if len(issue) > 0 {
+			flagged = append(flagged, order)
+		}
+	}
+	return flagged, nil
+}
+
+/*************** Reconciler ***************/
+// Reconciler periodically reconciles the orders charged during the last window
+type Reconciler struct {
+	service  Service
+	interval time.Duration
+	window   time.Duration
+	logger   log.Logger
+}
+
+func NewReconciler(db svcdb.IService, interval, window time.Duration, logger log.Logger) Reconciler {
+	return Reconciler{
+		service:  Service{svcdb: db},
+		interval: interval,
+		window:   window,
+		logger:   logger,
+	}
+}
+
+// Run reconciles every interval until ctx is done
+func (rc Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			begin := time.Now()
+			flagged, err := rc.service.ReconcileOrders(ctx, now.Add(-rc.window))
+			if err != nil || len(flagged) > 0 {
+				rc.logger.Log(
+					"method", "ReconcileOrders",
+					"flagged", len(flagged),
+					"error", err,
+					"took", time.Since(begin),
+				)
+			}
+		}
+	}
+}
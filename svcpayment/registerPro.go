@@ -13,9 +13,6 @@ import (
 	mux "github.com/gorilla/mux"
 	stdopentracing "github.com/opentracing/opentracing-go"
 
-	"github.com/stripe/stripe-go"
-	"github.com/stripe/stripe-go/account"
-	
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
@@ -25,22 +22,16 @@ import (
 )
  
 /*************** Service ***************/
-func (s Service) RegisterPro(_ context.Context, p svcdb.Pro) (svcdb.Pro, error) {
-	stripe.Key = STRIPESKEY
-	acct, err := account.New(&stripe.AccountParams{
-		Type: "standard",
-		Country: "FR",
-		Email: p.Email,
-	})
-
+func (s Service) RegisterPro(ctx context.Context, p svcdb.Pro) (svcdb.Pro, error) {
+	acct, err := s.provider.CreateAccount(ctx, p.Email)
 	if err != nil {
-		fmt.Println("RegisterPro (StripeAccountNew) : " + err.Error())
+		fmt.Println("RegisterPro (CreateAccount) : " + err.Error())
 		return p, err
 	}
 
-	p.StripeID = (*acct).ID
-	p.StripeSKey = (*acct).Keys.Secret
-	p.StripePKey = (*acct).Keys.Publish
+	p.StripeID = acct.ID
+	p.StripeSKey = acct.SecretKey
+	p.StripePKey = acct.PublishableKey
 	return p, nil
 }
 
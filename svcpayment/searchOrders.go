@@ -21,29 +21,31 @@ import (
 )
 
 /*************** Service ***************/
-func (s Service) SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error) {
-	return s.svcdb.SearchOrders(ctx, order)
+func (s Service) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error) {
+	return s.svcdb.SearchOrders(ctx, order, page)
 }
 
 /*************** Endpoint ***************/
 type searchOrdersRequest struct {
 	Order		svcdb.Order	`json:"order"`
+	Page		svcdb.Page	`json:"page"`
 }
 
 type searchOrdersResponse struct {
 	Orders		svcdb.Orders	`json:"orders"`
+	NextCursor	string	`json:"next_cursor,omitempty"`
 	Err			string	`json:"err,omitempty"`
 }
 
 func SearchOrdersEndpoint(svc IService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(searchOrdersRequest)
-		orders, err := svc.SearchOrders(ctx, req.Order)
+		orders, next, err := svc.SearchOrders(ctx, req.Order, req.Page)
 		if err != nil {
 			fmt.Println("Error SearchOrdersEndpoint : ", err.Error())
-			return searchOrdersResponse{orders, err.Error()}, nil
+			return searchOrdersResponse{orders, next, err.Error()}, nil
 		}
-		return searchOrdersResponse{orders, ""}, nil
+		return searchOrdersResponse{orders, next, ""}, nil
 	}
 }
 
@@ -78,22 +80,22 @@ func SearchOrdersHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 
 /*************** Logger ***************/
 /* Logger */
-func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error) {
+func (mw serviceLoggingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error) {
 	defer func(begin time.Time) {
 		mw.logger.Log(
 			"method", "searchOrders",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return mw.next.SearchOrders(ctx, order)
+	return mw.next.SearchOrders(ctx, order, page)
 }
 
 /*************** Instrumenting ***************/
 /* Instrumenting */
-func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error) {
-	v, err := mw.next.SearchOrders(ctx, order)
+func (mw serviceInstrumentingMiddleware) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error) {
+	v, next, err := mw.next.SearchOrders(ctx, order, page)
 	mw.ints.Add(1)
-	return v, err
+	return v, next, err
 }
 
 /*************** Main ***************/
@@ -115,17 +117,17 @@ func BuildSearchOrdersEndpoint(svc IService, logger log.Logger, tracer stdopentr
 /*************** Client ***************/
 /* Client */
 // Forsearch limiter & circuitbreaker for now kthx
-func (e Endpoints) SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error) {
+func (e Endpoints) SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error) {
 	var orders svcdb.Orders
 
-	request := searchOrdersRequest{Order: order}
+	request := searchOrdersRequest{Order: order, Page: page}
 	response, err := e.SearchOrdersEndpoint(ctx, request)
 	if err != nil {
 		fmt.Println("Error Client SearchOrders : ", err.Error())
-		return orders, err
+		return orders, "", err
 	}
 	orders = response.(searchOrdersResponse).Orders
-	return orders, str2err(response.(searchOrdersResponse).Err)
+	return orders, response.(searchOrdersResponse).NextCursor, str2err(response.(searchOrdersResponse).Err)
 }
 
 func ClientSearchOrders(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
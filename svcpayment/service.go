@@ -14,11 +14,16 @@ type IService interface {
 	GetOrder(ctx context.Context, orderID int64) (svcdb.Order, error)
 	CreateOrder(ctx context.Context, order svcdb.Order) (svcdb.Order, error)
 	PutOrder(ctx context.Context, orderID int64, step string, flag bool) (svcdb.Order, error)
- 	SearchOrders(ctx context.Context, order svcdb.Order) (svcdb.Orders, error)
+ 	SearchOrders(ctx context.Context, order svcdb.Order, page svcdb.Page) (svcdb.Orders, string, error)
 	AnswerOrder(ctx context.Context, orderID int64, userID int64, answer bool) (svcdb.Order, error)
+	CancelOrder(ctx context.Context, orderID int64, userID int64) (svcdb.Order, error)
+	AnswerCancel(ctx context.Context, orderID int64, accept bool) (svcdb.Order, error)
 
 	/* Pro */
 	RegisterPro(ctx context.Context, p svcdb.Pro) (svcdb.Pro, error)
+
+	/* Webhook */
+	StripeWebhook(ctx context.Context, payload []byte, signature string) error
 }
 
 /* Errors definition */
@@ -44,16 +49,20 @@ func err2str(err error) string {
 }
 
 /* Service implementation */
-func NewService(db svcdb.IService, event svcevent.IService) IService {
+func NewService(db svcdb.IService, event svcevent.IService, provider PaymentProvider, webhookSecret string) IService {
 	return Service{
 		svcdb: db,
 		svcevent: event,
+		provider: provider,
+		webhookSecret: webhookSecret,
 	}
 }
 
 type Service struct{
 	svcdb		svcdb.IService
 	svcevent    svcevent.IService
+	provider    PaymentProvider
+	webhookSecret string
 }
 
 /* Middleware interface */
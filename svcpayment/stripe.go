@@ -1,4 +1,84 @@
 package svcpayment
 
+import (
+	"context"
+	"strconv"
+
+	"github.com/stripe/stripe-go"
+	"github.com/stripe/stripe-go/account"
+	"github.com/stripe/stripe-go/charge"
+	"github.com/stripe/stripe-go/refund"
+)
+
 var STRIPEFEES = 0.05
 var STRIPESKEY = "sk_test_gs5myv9fGkIMYd0EcQUAnxEf"
+
+// applicationFee is the NightLine fee kept on a charge of amount cents
+func applicationFee(amount int64) int64 {
+	return int64(float64(amount) * STRIPEFEES)
+}
+
+// StripeProvider charges the cards through Stripe, the money going to the
+// standard account of the pro minus the NightLine fee
+type StripeProvider struct {
+	key string
+}
+
+func NewStripeProvider(key string) PaymentProvider {
+	return StripeProvider{key: key}
+}
+
+func (p StripeProvider) Authorize(_ context.Context, c Charge) (string, error) {
+	stripe.Key = p.key
+	params := &stripe.ChargeParams{
+		Amount:    uint64(c.Amount),
+		Desc:      c.Description,
+		Statement: c.Description,
+		Currency:  stripe.Currency(c.Currency),
+		Fee:       uint64(c.Fee),
+		NoCapture: true,
+		Destination: &stripe.DestinationParams{
+			Account: c.Account,
+		},
+		Customer: c.Customer,
+	}
+	params.AddMeta("order", strconv.FormatInt(c.Order, 10))
+	params.AddMeta("user", strconv.FormatInt(c.User, 10))
+	ch, err := charge.New(params)
+	if err != nil {
+		return "", err
+	}
+	return (*ch).ID, nil
+}
+
+func (p StripeProvider) Capture(_ context.Context, reference string) error {
+	stripe.Key = p.key
+	_, err := charge.Capture(reference, nil)
+	return err
+}
+
+func (p StripeProvider) Refund(_ context.Context, reference string) (string, error) {
+	stripe.Key = p.key
+	re, err := refund.New(&stripe.RefundParams{Charge: reference})
+	if err != nil {
+		return "", err
+	}
+	return (*re).ID, nil
+}
+
+func (p StripeProvider) CreateAccount(_ context.Context, email string) (ProviderAccount, error) {
+	stripe.Key = p.key
+	acct, err := account.New(&stripe.AccountParams{
+		Type:    "standard",
+		Country: "FR",
+		Email:   email,
+	})
+	if err != nil {
+		return ProviderAccount{}, err
+	}
+	return ProviderAccount{
+		ID:             (*acct).ID,
+		SecretKey:      (*acct).Keys.Secret,
+		PublishableKey: (*acct).Keys.Publish,
+	}, nil
+}
@@ -0,0 +1,543 @@
+package svcpayment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"time"
+
+	"github.com/go-kit/kit/tracing/opentracing"
+	mux "github.com/gorilla/mux"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
+)
+
+// StripeWebhookTolerance is the max age of a signed payload, older ones are
+// replays
+var StripeWebhookTolerance = 5 * time.Minute
+
+const maxWebhookPayload = 64 * 1024
+
+// PaymentStep is the step of the notifications sent once an order is over
+const PaymentStep = "Payment"
+
+// Status of the connected account of a pro, see svcdb.Pro.StripeStatus
+const (
+	ProStatusEnabled      = "enabled"
+	ProStatusPending      = "pending"
+	ProStatusRestricted   = "restricted"
+	ProStatusDeauthorized = "deauthorized"
+)
+
+var (
+	WebhookNotConfiguredErr = errors.New("No webhook secret configured")
+	InvalidSignatureErr     = errors.New("Invalid webhook signature")
+	InvalidPayloadErr       = errors.New("Invalid webhook payload")
+	PayloadTooLargeErr      = errors.New("Webhook payload too large")
+	ProCannotChargeErr      = errors.New("The account of the pro can't take payments")
+)
+
+/************ Events Handling ***********/
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Account string `json:"account,omitempty"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeCharge holds the fields used of a charge, or of a dispute whose Charge
+// is the disputed one
+type stripeCharge struct {
+	ID             string            `json:"id"`
+	Charge         string            `json:"charge,omitempty"`
+	Amount         int64             `json:"amount"`
+	AmountRefunded int64             `json:"amount_refunded"`
+	Captured       bool              `json:"captured"`
+	Refunded       bool              `json:"refunded"`
+	FailureMessage string            `json:"failure_message,omitempty"`
+	Metadata       map[string]string `json:"metadata"`
+	Refunds        struct {
+		Data []stripeRefund `json:"data"`
+	} `json:"refunds"`
+}
+
+type stripeRefund struct {
+	ID     string `json:"id"`
+	Amount int64  `json:"amount"`
+}
+
+type stripeAccount struct {
+	ID               string `json:"id"`
+	ChargesEnabled   bool   `json:"charges_enabled"`
+	PayoutsEnabled   bool   `json:"payouts_enabled"`
+	DetailsSubmitted bool   `json:"details_submitted"`
+}
+
+// Events missing from the map are acknowledged and ignored
+type stripeEventHandler func(Service, context.Context, stripeEvent) error
+
+var stripeEventHandlerMap = map[string]stripeEventHandler{
+	"charge.succeeded":                 eventChargeSucceeded,
+	"charge.captured":                  eventChargeSucceeded,
+	"charge.failed":                    eventChargeFailed,
+	"charge.expired":                   eventChargeExpired,
+	"charge.refunded":                  eventChargeRefunded,
+	"charge.dispute.created":           eventChargeDisputed,
+	"account.updated":                  eventAccountUpdated,
+	"account.application.deauthorized": eventAccountDeauthorized,
+}
+
+/* Record the reference of a charge the order missed, refund it when the order failed meanwhile */
+func eventChargeSucceeded(s Service, ctx context.Context, event stripeEvent) error {
+	ch, order, user, err := s.eventCharge(ctx, event)
+	if err != nil || order.ID == 0 || user == nil {
+		return err
+	}
+
+	if user.Reference != ch.ID {
+		err = s.svcdb.UpdateOrderReference(ctx, order.ID, user.User.ID, ch.ID)
+		if err != nil {
+			fmt.Println("eventChargeSucceeded (UpdateOrderReference) : " + err.Error())
+			return err
+		}
+		user.Reference = ch.ID
+	}
+
+	/* Entries recorded by svcpayment already are kept as is */
+	proID := s.orderProID(ctx, order)
+	amount := chargeAmount(ch, *user)
+	s.recordLedger(ctx, order, proID, *user, svcdb.LedgerAuthorization, amount)
+	if ch.Captured {
+		s.recordCapture(ctx, order, proID, *user, amount)
+	}
+
+	if order.Done == "false" && !ch.Refunded {
+		if refundID, err := s.provider.Refund(ctx, ch.ID); err != nil {
+			fmt.Println("eventChargeSucceeded (Refund) : " + err.Error())
+		} else {
+			s.recordRefund(ctx, order, proID, *user, refundID, amount)
+		}
+	}
+	return nil
+}
+
+func eventChargeFailed(s Service, ctx context.Context, event stripeEvent) error {
+	ch, order, _, err := s.eventCharge(ctx, event)
+	if err != nil || order.ID == 0 {
+		return err
+	}
+	message := "Payment failed"
+	if len(ch.FailureMessage) > 0 {
+		message += " : " + ch.FailureMessage
+	}
+	return s.cancelRunningOrder(ctx, order, message)
+}
+
+/* The funds of an expired charge are back on the card, as after a refund */
+func eventChargeExpired(s Service, ctx context.Context, event stripeEvent) error {
+	ch, order, user, err := s.eventCharge(ctx, event)
+	if err != nil || order.ID == 0 {
+		return err
+	}
+	if user != nil && user.Reference == ch.ID {
+		s.recordLedger(ctx, order, s.orderProID(ctx, order), *user, svcdb.LedgerRefund, chargeAmount(ch, *user))
+	}
+	return s.cancelRunningOrder(ctx, order, "Order cancelled, the funds reserved on bank account expired")
+}
+
+func eventChargeRefunded(s Service, ctx context.Context, event stripeEvent) error {
+	ch, order, user, err := s.eventCharge(ctx, event)
+	if err != nil || order.ID == 0 {
+		return err
+	}
+	/* Each refund of the charge has its entry, the ones svcpayment made are kept as is */
+	if user != nil && user.Reference == ch.ID && len(ch.Refunds.Data) > 0 {
+		proID := s.orderProID(ctx, order)
+		for _, refund := range ch.Refunds.Data {
+			s.recordRefund(ctx, order, proID, *user, refund.ID, refund.Amount)
+		}
+	}
+	if len(order.Done) == 0 {
+		return s.cancelRunningOrder(ctx, order, "Order cancelled, the payment was refunded")
+	}
+	if user != nil {
+		s.returnSendNotifs(ctx, []orderProgressNotif{{
+			Order:   order,
+			UserID:  user.User.ID,
+			Step:    PaymentStep,
+			Message: "Your payment was refunded",
+		}}, order, nil)
+	}
+	return nil
+}
+
+func eventChargeDisputed(s Service, ctx context.Context, event stripeEvent) error {
+	_, order, user, err := s.eventCharge(ctx, event)
+	if err != nil || order.ID == 0 {
+		return err
+	}
+	if len(order.Done) == 0 {
+		err = s.cancelRunningOrder(ctx, order, "Order cancelled, a payment is disputed")
+		if err != nil {
+			return err
+		}
+	} else if user != nil {
+		s.returnSendNotifs(ctx, []orderProgressNotif{{
+			Order:   order,
+			UserID:  user.User.ID,
+			Step:    PaymentStep,
+			Message: "Your payment is disputed",
+		}}, order, nil)
+	}
+
+	pro, err := s.svcdb.GetProBySoiree(ctx, order.Soiree.ID)
+	if err != nil {
+		fmt.Println("eventChargeDisputed (GetProBySoiree) : " + err.Error())
+		return err
+	} else if pro.ID != 0 {
+		s.returnSendNotifs(ctx, []orderProgressNotif{{
+			Order:   order,
+			UserID:  pro.ID,
+			Step:    PaymentStep,
+			Message: "A payment of the order is disputed",
+		}}, order, nil)
+	}
+	return nil
+}
+
+func eventAccountUpdated(s Service, ctx context.Context, event stripeEvent) error {
+	var account stripeAccount
+
+	if err := json.Unmarshal(event.Data.Object, &account); err != nil {
+		return InvalidPayloadErr
+	}
+
+	status := ProStatusEnabled
+	if !account.DetailsSubmitted {
+		status = ProStatusPending
+	} else if !account.ChargesEnabled || !account.PayoutsEnabled {
+		status = ProStatusRestricted
+	}
+	return s.updateProStatus(ctx, account.ID, status)
+}
+
+func eventAccountDeauthorized(s Service, ctx context.Context, event stripeEvent) error {
+	return s.updateProStatus(ctx, event.Account, ProStatusDeauthorized)
+}
+
+/*************** Helper ***************/
+// eventCharge returns the charge of the event with its order and the user who
+// paid with it. The order is read from the metadata set by Authorize, charges
+// made before are found by reference. An order with ID 0 is returned for the
+// charges of no order.
+func (s Service) eventCharge(ctx context.Context, event stripeEvent) (stripeCharge, svcdb.Order, *svcdb.UserOrder, error) {
+	var ch stripeCharge
+	var order svcdb.Order
+	var err error
+
+	if err = json.Unmarshal(event.Data.Object, &ch); err != nil {
+		return ch, order, nil, InvalidPayloadErr
+	}
+	if len(ch.Charge) > 0 {
+		ch.ID = ch.Charge
+	}
+
+	orderID, _ := strconv.ParseInt(ch.Metadata["order"], 10, 64)
+	userID, _ := strconv.ParseInt(ch.Metadata["user"], 10, 64)
+	if orderID != 0 {
+		order, err = s.svcdb.GetOrder(ctx, orderID)
+	} else {
+		order, err = s.svcdb.GetOrderByReference(ctx, ch.ID)
+	}
+	if err != nil {
+		fmt.Println("eventCharge (GetOrder) : " + err.Error())
+		return ch, order, nil, err
+	} else if order.ID == 0 {
+		fmt.Println("eventCharge : no order for the charge " + ch.ID)
+		return ch, order, nil, nil
+	}
+
+	for i := range order.Users {
+		if order.Users[i].Reference == ch.ID || (userID != 0 && order.Users[i].User.ID == userID) {
+			return ch, order, &order.Users[i], nil
+		}
+	}
+	return ch, order, nil, nil
+}
+
+// chargeAmount is the amount Stripe reports for the charge, the price of the
+// user in the order when the event has none
+func chargeAmount(ch stripeCharge, user svcdb.UserOrder) int64 {
+	if ch.Amount > 0 {
+		return ch.Amount
+	}
+	return user.Price
+}
+
+// cancelRunningOrder cancels the order at its open step, orders already done
+// are left as is
+func (s Service) cancelRunningOrder(ctx context.Context, order svcdb.Order, message string) error {
+	open, ok := svcdb.OrderWorkflow.OpenStep(order)
+	if !ok {
+		return nil
+	}
+	_, err := s.cancelOrder(ctx, order, open.Name, message)
+	return err
+}
+
+func (s Service) updateProStatus(ctx context.Context, stripeID string, status string) error {
+	if len(stripeID) == 0 {
+		return InvalidPayloadErr
+	}
+	pro, err := s.svcdb.UpdateProStripeStatus(ctx, stripeID, status)
+	if err != nil {
+		fmt.Println("updateProStatus (UpdateProStripeStatus) : " + err.Error())
+		return err
+	} else if pro.ID == 0 {
+		fmt.Println("updateProStatus : no pro for the account " + stripeID)
+	}
+	return nil
+}
+
+// SignStripePayload returns the Stripe-Signature header Stripe would send with
+// payload at t, to replay events locally
+func SignStripePayload(secret string, payload []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + stripeSignature(secret, timestamp, payload)
+}
+
+func stripeSignature(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyStripeSignature checks the header against every v1 signature, the
+// header of a payload signed more than StripeWebhookTolerance from now is
+// rejected
+func verifyStripeSignature(secret string, payload []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+
+	if len(secret) == 0 {
+		return WebhookNotConfiguredErr
+	}
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "t":
+			timestamp = parts[1]
+		case "v1":
+			signatures = append(signatures, parts[1])
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return InvalidSignatureErr
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > StripeWebhookTolerance || age < -StripeWebhookTolerance {
+		return InvalidSignatureErr
+	}
+
+	expected := []byte(stripeSignature(secret, timestamp, payload))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return InvalidSignatureErr
+}
+
+/*************** Service ***************/
+// StripeWebhook handles an event sent by Stripe. Every event is handled once :
+// it is claimed in svcdb before being handled, and released when the handling
+// fails so that Stripe retries it.
+func (s Service) StripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	var event stripeEvent
+
+	if err := verifyStripeSignature(s.webhookSecret, payload, signature, time.Now()); err != nil {
+		fmt.Println("StripeWebhook (verifyStripeSignature) : " + err.Error())
+		return err
+	}
+	if err := json.Unmarshal(payload, &event); err != nil || len(event.ID) == 0 {
+		return InvalidPayloadErr
+	}
+
+	handler, ok := stripeEventHandlerMap[event.Type]
+	if !ok {
+		return nil
+	}
+
+	claimed, err := s.svcdb.ClaimWebhookEvent(ctx, event.ID, event.Type)
+	if err != nil {
+		fmt.Println("StripeWebhook (ClaimWebhookEvent) : " + err.Error())
+		return err
+	} else if !claimed {
+		return nil
+	}
+
+	if err = handler(s, ctx, event); err != nil {
+		fmt.Println("StripeWebhook (" + event.Type + ") : " + err.Error())
+		if err := s.svcdb.ReleaseWebhookEvent(ctx, event.ID); err != nil {
+			fmt.Println("StripeWebhook (ReleaseWebhookEvent) : " + err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+/*************** Endpoint ***************/
+type stripeWebhookRequest struct {
+	Payload   []byte
+	Signature string
+}
+
+type stripeWebhookResponse struct {
+	Received bool `json:"received"`
+}
+
+// Errors are returned as is for Stripe to retry the event
+func StripeWebhookEndpoint(svc IService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(stripeWebhookRequest)
+		err := svc.StripeWebhook(ctx, req.Payload, req.Signature)
+		if err != nil {
+			return nil, err
+		}
+		return stripeWebhookResponse{Received: true}, nil
+	}
+}
+
+/*************** Transport ***************/
+// The payload is kept as received, the signature covers its exact bytes. One
+// byte over maxWebhookPayload is read to refuse the payloads cut by the limit.
+func DecodeHTTPStripeWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookPayload+1))
+	if err != nil {
+		fmt.Println("Error DecodeHTTPStripeWebhookRequest : ", err.Error())
+		return nil, InvalidPayloadErr
+	} else if len(payload) > maxWebhookPayload {
+		return nil, PayloadTooLargeErr
+	}
+	return stripeWebhookRequest{Payload: payload, Signature: r.Header.Get("Stripe-Signature")}, nil
+}
+
+func EncodeHTTPStripeWebhookRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(stripeWebhookRequest)
+	r.Body = ioutil.NopCloser(bytes.NewReader(req.Payload))
+	r.ContentLength = int64(len(req.Payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Stripe-Signature", req.Signature)
+	return nil
+}
+
+func DecodeHTTPStripeWebhookResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var response stripeWebhookResponse
+	if r.StatusCode != http.StatusOK {
+		return nil, errorDecoder(r)
+	}
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		fmt.Println("Error DecodeHTTPStripeWebhookResponse : ", err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+func StripeWebhookHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer, logger log.Logger, r *mux.Router, options []httptransport.ServerOption) *mux.Route {
+	route := r.Methods("POST").Path("/webhooks/stripe").Handler(httptransport.NewServer(
+		endpoints.StripeWebhookEndpoint,
+		DecodeHTTPStripeWebhookRequest,
+		EncodeHTTPGenericResponse,
+		append(options, httptransport.ServerBefore(opentracing.HTTPToContext(tracer, "StripeWebhook", logger)))...,
+	))
+	return route
+}
+
+/*************** Logger ***************/
+/* Logger */
+func (mw serviceLoggingMiddleware) StripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	defer func(begin time.Time) {
+		mw.logger.Log(
+			"method", "stripeWebhook",
+			"size", len(payload),
+			"took", time.Since(begin),
+		)
+	}(time.Now())
+	return mw.next.StripeWebhook(ctx, payload, signature)
+}
+
+/*************** Instrumenting ***************/
+/* Instrumenting */
+func (mw serviceInstrumentingMiddleware) StripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	err := mw.next.StripeWebhook(ctx, payload, signature)
+	mw.ints.Add(1)
+	return err
+}
+
+/*************** Main ***************/
+/* Main */
+func BuildStripeWebhookEndpoint(svc IService, logger log.Logger, tracer stdopentracing.Tracer, duration metrics.Histogram) endpoint.Endpoint {
+	var csEndpoint endpoint.Endpoint
+	{
+		csDuration := duration.With("method", "StripeWebhook")
+		csLogger := log.With(logger, "method", "StripeWebhook")
+
+		csEndpoint = StripeWebhookEndpoint(svc)
+		csEndpoint = opentracing.TraceServer(tracer, "StripeWebhook")(csEndpoint)
+		csEndpoint = EndpointLoggingMiddleware(csLogger)(csEndpoint)
+		csEndpoint = EndpointInstrumentingMiddleware(csDuration)(csEndpoint)
+	}
+	return csEndpoint
+}
+
+/*************** Client ***************/
+/* Client */
+func (e Endpoints) StripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	request := stripeWebhookRequest{Payload: payload, Signature: signature}
+	_, err := e.StripeWebhookEndpoint(ctx, request)
+	if err != nil {
+		fmt.Println("Error StripeWebhook : ", err.Error())
+		return err
+	}
+	return nil
+}
+
+func ClientStripeWebhook(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
+	var ceEndpoint endpoint.Endpoint
+
+	ceEndpoint = httptransport.NewClient(
+		"POST",
+		copyURL(u, "/webhooks/stripe"),
+		EncodeHTTPStripeWebhookRequest,
+		DecodeHTTPStripeWebhookResponse,
+		httptransport.ClientBefore(opentracing.ContextToHTTP(tracer, logger)),
+	).Endpoint()
+	ceEndpoint = opentracing.TraceClient(tracer, "StripeWebhook")(ceEndpoint)
+	return ceEndpoint, nil
+}
@@ -0,0 +1,62 @@
+package svcpayment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"svcdb"
+)
+
+func TestDecodeHTTPStripeWebhookRequestLimit(t *testing.T) {
+	cases := []struct {
+		size int
+		want error
+	}{
+		{maxWebhookPayload, nil},
+		{maxWebhookPayload + 1, PayloadTooLargeErr},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("POST", "/webhooks/stripe", bytes.NewReader(make([]byte, c.size)))
+		request, err := DecodeHTTPStripeWebhookRequest(context.Background(), r)
+		if err != c.want {
+			t.Errorf("%d bytes : got %v, want %v", c.size, err, c.want)
+		} else if err == nil && len(request.(stripeWebhookRequest).Payload) != c.size {
+			t.Errorf("%d bytes : got a payload of %d bytes", c.size, len(request.(stripeWebhookRequest).Payload))
+		}
+	}
+}
+
+func TestEventChargeRefundedRecordsEachRefund(t *testing.T) {
+	var refunded []string
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: refundProvider{refunded: &refunded}}
+	order := newChargedOrder(t, db, "ch_held")
+
+	var event stripeEvent
+	event.Data.Object = json.RawMessage(`{"id": "ch_held", "amount": 500, "amount_refunded": 500,
+		"refunds": {"data": [{"id": "re_first", "amount": 200}, {"id": "re_second", "amount": 300}]}}`)
+	// replayed events add nothing
+	for i := 0; i < 2; i++ {
+		if err := eventChargeRefunded(s, context.Background(), event); err != nil {
+			t.Fatal("eventChargeRefunded : " + err.Error())
+		}
+	}
+
+	entries, err := db.GetLedger(context.Background(), svcdb.LedgerFilter{OrderID: order.ID})
+	if err != nil {
+		t.Fatal("GetLedger : " + err.Error())
+	}
+	amounts := make(map[string]int64)
+	for _, entry := range entries {
+		if entry.Kind == svcdb.LedgerRefund {
+			amounts[entry.Refund] += entry.Amount
+		}
+	}
+	if amounts["re_first"] != 200 || amounts["re_second"] != 300 {
+		t.Errorf("eventChargeRefunded : got refunds %v, want re_first of 200 and re_second of 300", amounts)
+	}
+}
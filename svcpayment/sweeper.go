@@ -0,0 +1,175 @@
+package svcpayment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"svcdb"
+	"svcevent"
+)
+
+// RefundRetryWindow is how long after their issue the failed orders get their
+// pending refunds retried by the sweeper
+var RefundRetryWindow = 24 * time.Hour
+
+/*************** Service ***************/
+// SweepOrders cancels the orders whose open step outlived its timeout in
+// svcdb.OrderWorkflow and returns them
+func (s Service) SweepOrders(ctx context.Context, now time.Time) ([]svcdb.Order, error) {
+	var swept []svcdb.Order
+
+	orders, err := s.svcdb.GetPendingOrders(ctx)
+	if err != nil {
+		fmt.Println("SweepOrders (GetPendingOrders) : " + err.Error())
+		return swept, err
+	}
+
+	for _, order := range orders {
+		step, expired := svcdb.OrderWorkflow.Expired(order, now)
+		if !expired {
+			continue
+		}
+		order, err = s.cancelOrder(ctx, order, step.Name, "Order cancelled, nothing happened at the "+step.Name+" step in time")
+		if err != nil {
+			fmt.Println("SweepOrders (cancelOrder) : " + err.Error())
+			continue
+		}
+		swept = append(swept, order)
+	}
+	return swept, nil
+}
+
+// RetryRefunds refunds the charges of the failed orders issued since from that
+// the ledger has no refund of, releaseCharges having failed on them. The
+// orders refunded are returned.
+func (s Service) RetryRefunds(ctx context.Context, from time.Time) ([]svcdb.Order, error) {
+	var retried []svcdb.Order
+
+	orders, err := s.svcdb.GetChargedOrders(ctx, from)
+	if err != nil {
+		fmt.Println("RetryRefunds (GetChargedOrders) : " + err.Error())
+		return retried, err
+	}
+
+	for _, order := range orders {
+		if order.Done != "false" {
+			continue
+		}
+		entries, err := s.svcdb.GetLedger(ctx, svcdb.LedgerFilter{OrderID: order.ID})
+		if err != nil {
+			fmt.Println("RetryRefunds (GetLedger) : " + err.Error())
+			continue
+		}
+		refunded := make(map[string]bool)
+		for _, entry := range entries {
+			refunded[entry.Reference] = refunded[entry.Reference] || entry.Kind == svcdb.LedgerRefund
+		}
+
+		var notifs []orderProgressNotif
+		for _, user := range order.Users {
+			if len(user.Reference) == 0 || refunded[user.Reference] {
+				continue
+			}
+			refundID, err := s.provider.Refund(ctx, user.Reference)
+			if err != nil {
+				fmt.Println("RetryRefunds (Refund) : " + err.Error())
+				continue
+			}
+			s.recordRefund(ctx, order, s.orderProID(ctx, order), user, refundID, user.Price)
+			notifs = append(notifs, orderProgressNotif{
+				Order:   order,
+				UserID:  user.User.ID,
+				Step:    PaymentStep,
+				Message: "Your payment was refunded",
+			})
+		}
+		if len(notifs) > 0 {
+			s.returnSendNotifs(ctx, notifs, order, nil)
+			retried = append(retried, order)
+		}
+	}
+	return retried, nil
+}
+
+/*************** Helper ***************/
+// cancelOrder fails the order if step is still its open step, then releases
+// the charges held on the cards, refunds the captured ones and notifies its
+// users. An order moved past step meanwhile is left as is, with
+// svcdb.StepNotOpenErr.
+func (s Service) cancelOrder(ctx context.Context, order svcdb.Order, step, message string) (svcdb.Order, error) {
+	var notifs []orderProgressNotif
+
+	/* Close the order before releasing the funds, so that they can't be captured meanwhile */
+	// nobody but the service cancels it, hence the 0 user
+	failed, err := s.svcdb.CancelOrder(ctx, order.ID, 0, step)
+	if err != nil {
+		fmt.Println("cancelOrder (CancelOrder) : " + err.Error())
+		return order, err
+	}
+	pending := s.releaseCharges(ctx, failed)
+
+	for _, user := range failed.Users {
+		notifs = append(notifs, orderProgressNotif{
+			Order:   failed,
+			UserID:  user.User.ID,
+			Step:    step,
+			Message: refundNotice(message, pending, user.User.ID),
+		})
+	}
+	return s.returnSendNotifs(ctx, notifs, failed, nil)
+}
+
+/*************** Sweeper ***************/
+// Sweeper periodically cancels the orders stuck in a step and retries the
+// refunds that failed
+type Sweeper struct {
+	service  Service
+	interval time.Duration
+	logger   log.Logger
+}
+
+func NewSweeper(db svcdb.IService, event svcevent.IService, provider PaymentProvider, interval time.Duration, logger log.Logger) Sweeper {
+	return Sweeper{
+		service:  Service{svcdb: db, svcevent: event, provider: provider},
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run sweeps every interval until ctx is done
+func (sw Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(sw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			begin := time.Now()
+			swept, err := sw.service.SweepOrders(ctx, now)
+			if err != nil || len(swept) > 0 {
+				sw.logger.Log(
+					"method", "SweepOrders",
+					"swept", len(swept),
+					"error", err,
+					"took", time.Since(begin),
+				)
+			}
+
+			begin = time.Now()
+			retried, err := sw.service.RetryRefunds(ctx, now.Add(-RefundRetryWindow))
+			if err != nil || len(retried) > 0 {
+				sw.logger.Log(
+					"method", "RetryRefunds",
+					"refunded", len(retried),
+					"error", err,
+					"took", time.Since(begin),
+				)
+			}
+		}
+	}
+}
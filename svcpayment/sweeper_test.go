@@ -0,0 +1,93 @@
+package svcpayment
+
+import (
+	"context"
+	"testing"
+
+	"svcdb"
+	"svcevent"
+)
+
+// refundProvider records the references refunded
+type refundProvider struct {
+	PaymentProvider
+	refunded *[]string
+}
+
+func (p refundProvider) Refund(_ context.Context, reference string) (string, error) {
+	*p.refunded = append(*p.refunded, reference)
+	return "re_" + reference, nil
+}
+
+// silentEvents drops the notifications
+type silentEvents struct {
+	svcevent.IService
+}
+
+func (silentEvents) Push(_ context.Context, _ string, _ interface{}, _ int64) (int32, int64, error) {
+	return 0, 0, nil
+}
+
+// newChargedOrder returns an order of one user whose charge is held under
+// reference, waiting at its Issued step during a soiree of a pro
+func newChargedOrder(t *testing.T, db svcdb.MemoryService, reference string) svcdb.Order {
+	ctx := context.Background()
+
+	soiree := db.SeedSoiree()
+	db.SeedEstablishment(db.SeedPro("pro@nightline.fr").ID, soiree.ID)
+	user := db.SeedUser("user@nightline.fr")
+	order, err := db.CreateOrder(ctx, svcdb.Order{
+		Price:  500,
+		Soiree: soiree,
+		Users:  svcdb.UserOrders{{User: user, Price: 500}},
+		Consos: svcdb.ConsoOrders{{Conso: db.SeedConso(500), Amount: 1}},
+	})
+	if err != nil {
+		t.Fatal("CreateOrder : " + err.Error())
+	}
+	if err = db.UpdateOrderReference(ctx, order.ID, user.ID, reference); err != nil {
+		t.Fatal("UpdateOrderReference : " + err.Error())
+	}
+	order, err = db.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal("GetOrder : " + err.Error())
+	}
+	return order
+}
+
+func TestCancelOrderReleasesOnceClosed(t *testing.T) {
+	var refunded []string
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: refundProvider{refunded: &refunded}}
+	order := newChargedOrder(t, db, "ch_held")
+
+	cancelled, err := s.cancelOrder(context.Background(), order, "Issued", "Order cancelled")
+	if err != nil {
+		t.Fatal("cancelOrder : " + err.Error())
+	}
+	if cancelled.Done != "false" {
+		t.Errorf("cancelOrder : got Done %q, want %q", cancelled.Done, "false")
+	}
+	if len(refunded) != 1 || refunded[0] != "ch_held" {
+		t.Errorf("cancelOrder : got refunds %v, want [ch_held]", refunded)
+	}
+}
+
+func TestCancelOrderStepClosedMeanwhile(t *testing.T) {
+	var refunded []string
+	db := svcdb.NewMemoryService()
+	s := Service{svcdb: db, svcevent: silentEvents{}, provider: refundProvider{refunded: &refunded}}
+	order := newChargedOrder(t, db, "ch_held")
+
+	// the order moved past Issued since it was read
+	if _, err := db.PutOrder(context.Background(), order.ID, "Issued", true); err != nil {
+		t.Fatal("PutOrder : " + err.Error())
+	}
+
+	if _, err := s.cancelOrder(context.Background(), order, "Issued", "Order cancelled"); err != svcdb.StepNotOpenErr {
+		t.Errorf("cancelOrder : got %v, want %v", err, svcdb.StepNotOpenErr)
+	}
+	if len(refunded) != 0 {
+		t.Errorf("cancelOrder : got refunds %v, want none", refunded)
+	}
+}
@@ -1,4 +0,0 @@
-package svcpayment
-
-const timeForm = "Jan 2, 2006 at 3:04pm (MST)"
-const timeFormFucked = "2001-12-31T23:59:59Z"
@@ -14,6 +14,8 @@ import (
 
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /* HTTP handlers & routing */
@@ -31,9 +33,14 @@ func MakeHTTPHandler(endpoints Endpoints, tracer stdopentracing.Tracer,
 	PutOrderHTTPHandler(endpoints, tracer, logger, r, options)
 	SearchOrdersHTTPHandler(endpoints, tracer, logger, r, options)
 	AnswerOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	CancelOrderHTTPHandler(endpoints, tracer, logger, r, options)
+	AnswerCancelHTTPHandler(endpoints, tracer, logger, r, options)
 
 	/* Pro */
 	RegisterProHTTPHandler(endpoints, tracer, logger, r, options)
+
+	/* Webhook */
+	StripeWebhookHTTPHandler(endpoints, tracer, logger, r, options)
 	
 	return r
 }
@@ -48,8 +55,14 @@ func errorEncoder(_ context.Context, err error, w http.ResponseWriter) {
 	msg := err.Error()
 
 	switch err {
-	case Err:
+	case Err, InvalidSignatureErr, InvalidPayloadErr:
 		code = http.StatusBadRequest
+	case PayloadTooLargeErr:
+		code = http.StatusRequestEntityTooLarge
+	case svcdb.IdempotencyInProgressErr:
+		code = http.StatusConflict
+	case svcdb.IdempotencyConflictErr:
+		code = http.StatusUnprocessableEntity
 	}
 
 	w.WriteHeader(code)
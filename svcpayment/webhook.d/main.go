@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	stdopentracing "github.com/opentracing/opentracing-go"
+
+	"svcpayment"
+	csvcpayment "svcpayment/client"
+)
+
+// Sends a Stripe event read from a file, or stdin, to the webhook of
+// svcpayment, signed like Stripe does with the secret of the endpoint.
+// e.g. webhook -secret whsec_local -file charge_refunded.json
+func main() {
+	var (
+		paymentAddr = flag.String("payment.addr", "localhost:8047", "svcpayment host:port")
+		secret      = flag.String("secret", os.Getenv("STRIPE_WEBHOOK_SECRET"), "Signing secret of the webhook, as given to svcpayment")
+		file        = flag.String("file", "", "JSON event to send, stdin when empty")
+		age         = flag.Duration("age", 0, "Age of the signature, over 5m it is rejected as a replay")
+	)
+	flag.Parse()
+
+	/* Logger */
+	var logger log.Logger
+	{
+		logger = log.NewJSONLogger(os.Stdout)
+		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+	}
+
+	var payload []byte
+	var err error
+	if len(*file) > 0 {
+		payload, err = ioutil.ReadFile(*file)
+	} else {
+		payload, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+
+	service, err := csvcpayment.New(*paymentAddr, stdopentracing.GlobalTracer(), logger)
+	if err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+
+	signature := svcpayment.SignStripePayload(*secret, payload, time.Now().Add(-*age))
+	if err = service.StripeWebhook(context.Background(), payload, signature); err != nil {
+		logger.Log("err", err)
+		os.Exit(1)
+	}
+	logger.Log("msg", "event received", "size", len(payload))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"svcdb"
+	"svcpayment"
 )
 
 /* Service interface */
@@ -18,6 +19,10 @@ var (
 	Err = errors.New("One basic error")
 	SoireeDateErr = errors.New("Error on soiree dates")
 	InvalidSoireeErr = errors.New("Invalid soiree parameters provided")
+	SoireeClosedErr = errors.New("The soiree is not running")
+	UserNotInSoireeErr = errors.New("The user has not joined the soiree")
+	ConsoNotInMenuErr = errors.New("The conso is not on the menu of the soiree")
+	UnverifiedErr = errors.New("The email of the account must be verified first.")
 )
 
 /* Misc */
@@ -36,12 +41,13 @@ func err2str(err error) string {
 }
 
 /* Service implementation */
-func NewService(db svcdb.IService) IService {
-	return Service{svcdb: db}
+func NewService(db svcdb.IService, payment svcpayment.IService) IService {
+	return Service{svcdb: db, svcpayment: payment}
 }
 
 type Service struct {
-	svcdb svcdb.IService
+	svcdb      svcdb.IService
+	svcpayment svcpayment.IService
 }
 
 /* Middleware interface */
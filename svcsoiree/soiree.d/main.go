@@ -23,6 +23,7 @@ import (
 	"github.com/go-kit/kit/metrics/prometheus"
 
 	csvcdb "svcdb/client"
+	csvcpayment "svcpayment/client"
 	
 	"svcsoiree"
 )
@@ -147,9 +148,15 @@ func main() {
 		if err != nil {
 			logger.Log("err", err)
 			os.Exit(1)
-		}		
-		
-		service = svcsoiree.NewService(svcdb)
+		}
+
+		svcpayment, err := csvcpayment.New("localhost:8047", tracer, logger)
+		if err != nil {
+			logger.Log("err", err)
+			os.Exit(1)
+		}
+
+		service = svcsoiree.NewService(svcdb, svcpayment)
 		service = svcsoiree.ServiceLoggingMiddleware(logger)(service)
 		service = svcsoiree.ServiceInstrumentingMiddleware(
 			createSoiree_all,
@@ -3,6 +3,7 @@ package svcsoiree
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -15,23 +16,87 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics"
 	httptransport "github.com/go-kit/kit/transport/http"
+
+	"svcdb"
 )
 
 /*************** Service ***************/
 /* Service - Business logic */
-func (s Service) UserOrderConso(_ context.Context, userID, soireeID, consoID int64) (int64, error) {
-	var orderID int64
+func (s Service) UserOrderConso(ctx context.Context, userID, soireeID, consoID int64) (int64, error) {
+	var conso svcdb.Conso
+	var onMenu bool
+
+	user, err := s.svcdb.GetUserByID(ctx, userID)
+	if err != nil {
+		fmt.Println("UserOrderConso (GetUserByID) : " + err.Error())
+		return 0, err
+	} else if !user.Verified {
+		return 0, UnverifiedErr
+	}
+
+	soiree, err := s.svcdb.GetSoireeByID(ctx, soireeID)
+	if err != nil {
+		fmt.Println("UserOrderConso (GetSoireeByID) : " + err.Error())
+		return 0, InvalidSoireeErr
+	}
+
+	// check if soiree is running
+	now := time.Now()
+	if now.Before(soiree.Begin) || !now.Before(soiree.End) {
+		return 0, SoireeClosedErr
+	}
 
 	// check if user in soiree
-	if false {
-		return 0, Err
+	present, err := s.svcdb.GetConnectedFriends(ctx, soireeID)
+	if err != nil {
+		fmt.Println("UserOrderConso (GetConnectedFriends) : " + err.Error())
+		return 0, err
+	}
+	joined := false
+	for _, profile := range present {
+		if profile.ID == userID {
+			joined = true
+			break
+		}
+	}
+	if !joined {
+		return 0, UserNotInSoireeErr
 	}
-	// check if conso in soiree->menu
 
-	// proceed
-	orderID = 10
+	// check if conso in soiree->menu
+	menu, err := s.svcdb.GetMenuFromSoiree(ctx, soireeID)
+	if err != nil {
+		fmt.Println("UserOrderConso (GetMenuFromSoiree) : " + err.Error())
+		return 0, err
+	}
+	consos, err := s.svcdb.GetMenuConsos(ctx, menu.ID)
+	if err != nil {
+		fmt.Println("UserOrderConso (GetMenuConsos) : " + err.Error())
+		return 0, err
+	}
+	for _, c := range consos {
+		if c.ID == consoID {
+			conso, onMenu = c, true
+			break
+		}
+	}
+	if !onMenu {
+		return 0, ConsoNotInMenuErr
+	}
 
-	return orderID, nil
+	// proceed, svcpayment issuing the order as it does the ones of svcapi
+	price := conso.Price
+	order, err := s.svcpayment.CreateOrder(ctx, svcdb.Order{
+		Price:  price,
+		Soiree: soiree,
+		Users:  []svcdb.UserOrder{{User: user, Price: price}},
+		Consos: []svcdb.ConsoOrder{{Conso: conso, Amount: 1}},
+	})
+	if err != nil {
+		fmt.Println("UserOrderConso (CreateOrder) : " + err.Error())
+		return order.ID, err
+	}
+	return order.ID, nil
 }
 
 // Merged : Service definition
@@ -40,8 +105,8 @@ func (s Service) UserOrderConso(_ context.Context, userID, soireeID, consoID int
 /* Endpoint - Req/Resp */
 type userOrderConsoRequest struct{ UserID, SoireeID, ConsoID int64 }
 type userOrderConsoResponse struct {
-	OrderID	 int64
-	Err      error
+	OrderID int64  `json:"orderID"`
+	Err     string `json:"err,omitempty"`
 }
 
 /* Endpoint - Create endpoint */
@@ -50,8 +115,8 @@ func UserOrderConsoEndpoint(s IService) endpoint.Endpoint {
 		uocReq := request.(userOrderConsoRequest)
 		orderID, err := s.UserOrderConso(ctx, uocReq.UserID, uocReq.SoireeID, uocReq.ConsoID)
 		return userOrderConsoResponse{
-			OrderID:  orderID, 
-			Err:      err,
+			OrderID: orderID,
+			Err:     err2str(err),
 		}, nil
 	}
 }
@@ -142,7 +207,7 @@ func (e Endpoints) UserOrderConso(ctx context.Context, userID, soireeID, consoID
 		return 0, err
 	}
 	orderID = response.(userOrderConsoResponse).OrderID
-	return orderID, response.(userOrderConsoResponse).Err
+	return orderID, str2err(response.(userOrderConsoResponse).Err)
 }
 
 func ClientUserOrderConso(u *url.URL, logger log.Logger, tracer stdopentracing.Tracer) (endpoint.Endpoint, error) {
@@ -19,27 +19,34 @@ func (s Service) LastMessage(ctx context.Context, v interface{}) (lastMessageRes
 
 	req.Initiator = int64(mp["initiator"].(float64))
 	req.Recipient = int64(mp["recipient"].(float64))
+	if limit, ok := mp["limit"].(float64); ok {
+		req.Page.Limit = int64(limit)
+	}
+	if cursor, ok := mp["cursor"].(string); ok {
+		req.Page.Cursor = cursor
+	}
 
 	fmt.Println(req)
 
-	messages, err := s.svcdb.GetLastMessages(ctx, req.Recipient, req.Initiator)
+	messages, next, err := s.svcdb.GetLastMessages(ctx, req.Recipient, req.Initiator, req.Page.Bounded())
 	if err != nil {
 		fmt.Println("LastMessage  : " + err.Error())
 		return lastMessageResponse{}, err
 	}
 
-	return lastMessageResponse{messages}, nil
+	return lastMessageResponse{messages, next}, nil
 }
 
 // /*************** Endpoint ***************/
 type lastMessageRequest struct {
-	Initiator int64 `json:"initiator"`
-	Recipient int64 `json:"recipient"`
-	// messageCount int64 `json:"messageCount"`
+	Initiator int64      `json:"initiator"`
+	Recipient int64      `json:"recipient"`
+	Page      svcdb.Page `json:"page"`
 }
 
 type lastMessageResponse struct {
-	Messages []svcdb.Message `json:"messages"`
+	Messages   []svcdb.Message `json:"messages"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 /*************** Logger ***************/